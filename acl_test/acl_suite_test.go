@@ -0,0 +1,50 @@
+package acl_test
+
+import (
+	"testing"
+
+	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/actors"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var factory *actors.Factory
+
+var _ = BeforeSuite(func() {
+	config, err := LoadConfig()
+	Expect(err).NotTo(HaveOccurred())
+
+	factory, err = actors.FromConfig(config)
+	Expect(err).NotTo(HaveOccurred())
+})
+
+var _ = AfterSuite(func() {
+	if factory != nil {
+		Expect(factory.Close()).To(Succeed())
+	}
+})
+
+var _ = RegisterReporting("ACL Enforcement Test Suite")
+var _ = RegisterContractValidation()
+var _ = RegisterHistoryChecks()
+
+func TestACL(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "ACL Enforcement Test Suite")
+}
+
+// actorKind mints a new actor of one kind.
+type actorKind struct {
+	name string
+	mint func(f *actors.Factory) (*actors.Identity, error)
+}
+
+var (
+	mtlsApp   = actorKind{name: "mTLS app", mint: (*actors.Factory).MTLSApp}
+	uaaClient = actorKind{name: "UAA client", mint: func(f *actors.Factory) (*actors.Identity, error) { return f.UAAClient() }}
+)
+
+func (k actorKind) newActor() *actors.Identity {
+	return actors.Mint(func() (*actors.Identity, error) { return k.mint(factory) })
+}
@@ -0,0 +1,49 @@
+package acl_test
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/acl"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Permission enforcement", func() {
+	args := []interface{}{checkEnforcement}
+	for _, kind := range []actorKind{mtlsApp, uaaClient} {
+		for _, scope := range []acl.Scope{acl.Exact, acl.Wildcard} {
+			for _, granted := range acl.Combinations(acl.Permissions) {
+				args = append(args, Entry(
+					fmt.Sprintf("%s granted %s on %s paths", kind.name, acl.Describe(granted), scope),
+					kind, scope, granted,
+				))
+			}
+		}
+	}
+
+	DescribeTable("allows exactly the operations an actor was granted", args...)
+})
+
+// checkEnforcement grants a new actor permissions on a new fixture, then
+// attempts every operation as it.
+func checkEnforcement(kind actorKind, scope acl.Scope, granted []acl.Permission) {
+	actor := kind.newActor()
+	admin := factory.Admin()
+
+	fixture, err := acl.NewFixture(admin)
+	DeferCleanup(fixture.Cleanup, admin)
+	Expect(err).NotTo(HaveOccurred())
+	fixture.Track(actor.Actor)
+	Expect(fixture.Grant(admin, actor.Actor, scope, granted)).To(Succeed())
+
+	mismatches := acl.Run(acl.Operations, actor.Client, fixture, granted)
+	Expect(mismatches).To(BeEmpty(), "%s granted %s on %v:\n%s", actor.Actor, acl.Describe(granted), fixture.Paths(scope), format(mismatches))
+}
+
+func format(results []acl.Result) string {
+	var s string
+	for _, result := range results {
+		s += "  " + result.String() + "\n"
+	}
+	return s
+}
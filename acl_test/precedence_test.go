@@ -0,0 +1,169 @@
+package acl_test
+
+import (
+	"net/url"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/acl"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/actors"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Overlapping grants", func() {
+	for _, api := range acl.APIs {
+		api := api
+
+		Describe("granted through "+string(api), func() {
+			var (
+				root, rootID string
+				actor        *actors.Identity
+				granted      []string
+			)
+
+			// grant grants through the API under test. The v1 API cannot
+			// grant on wildcards, so those go through v2, which also checks
+			// that grants made through each version combine.
+			grant := func(path string, permissions ...acl.Permission) {
+				through := api
+				if !api.Grants(path) {
+					through = acl.V2
+				}
+				Expect(through.Grant(factory.Admin(), actor.Actor, path, permissions)).To(Succeed())
+				granted = append(granted, path)
+			}
+
+			can := func(permission acl.Permission, name string) bool {
+				allowed, err := acl.Can(actor.Client, permission, root+name)
+				Expect(err).NotTo(HaveOccurred())
+				return allowed
+			}
+
+			BeforeEach(func() {
+				rootID = uuid.NewString()
+				root = "/acl/" + rootID
+				granted = nil
+				actor = mtlsApp.newActor()
+
+				admin := factory.Admin()
+				for _, name := range []string{"/a", "/a/b", "/a/b/c", "/a/b/d", "/a/e", "/ab"} {
+					_, err := admin.SetValue(root+name, "some-value")
+					Expect(err).NotTo(HaveOccurred())
+				}
+				DeferCleanup(func() {
+					for _, path := range granted {
+						acl.V2.Revoke(admin, actor.Actor, path)
+					}
+					for _, name := range []string{"/a", "/a/b", "/a/b/c", "/a/b/d", "/a/e", "/ab"} {
+						admin.Delete(root + name)
+					}
+				})
+			})
+
+			It("combines an exact grant with a wildcard over it", func() {
+				grant(root+"/a/*", acl.Read)
+				grant(root+"/a/b/c", acl.Write, acl.ReadACL)
+
+				Expect(can(acl.Read, "/a/b/c")).To(BeTrue())
+				Expect(can(acl.Write, "/a/b/c")).To(BeTrue())
+				Expect(can(acl.ReadACL, "/a/b/c")).To(BeTrue())
+
+				Expect(can(acl.Read, "/a/b/d")).To(BeTrue())
+				Expect(can(acl.Write, "/a/b/d")).To(BeFalse())
+				Expect(can(acl.ReadACL, "/a/b/d")).To(BeFalse())
+			})
+
+			It("covers every depth beneath a wildcard, but not the path itself or its siblings", func() {
+				grant(root+"/a/*", acl.Read)
+
+				Expect(can(acl.Read, "/a/b")).To(BeTrue())
+				Expect(can(acl.Read, "/a/b/c")).To(BeTrue())
+				Expect(can(acl.Read, "/a/e")).To(BeTrue())
+
+				Expect(can(acl.Read, "/a")).To(BeFalse())
+				Expect(can(acl.Read, "/ab")).To(BeFalse())
+			})
+
+			It("does not treat a grant on a name as a grant on what it prefixes", func() {
+				grant(root+"/a", acl.Read)
+
+				Expect(can(acl.Read, "/a")).To(BeTrue())
+				Expect(can(acl.Read, "/a/b")).To(BeFalse())
+				Expect(can(acl.Read, "/a/e")).To(BeFalse())
+				Expect(can(acl.Read, "/ab")).To(BeFalse())
+			})
+
+			It("keeps the wildcard in force when the exact grant under it is revoked", func() {
+				grant(root+"/a/*", acl.Read)
+				grant(root+"/a/b/c", acl.Read, acl.Write)
+				Expect(api.Revoke(factory.Admin(), actor.Actor, root+"/a/b/c")).To(Succeed())
+
+				Expect(can(acl.Read, "/a/b/c")).To(BeTrue())
+				Expect(can(acl.Write, "/a/b/c")).To(BeFalse())
+			})
+
+			It("grants nothing beyond the literal path when it has a trailing slash", func() {
+				// Whether CredHub accepts the grant at all is up to it; it
+				// must not grant the credential or anything beneath it.
+				through := api
+				if api == acl.V1 {
+					// v1 grants on credential names, and no credential
+					// has a trailing slash.
+					through = acl.V2
+				}
+				if through.Grant(factory.Admin(), actor.Actor, root+"/a/b/", []acl.Permission{acl.Read}) == nil {
+					granted = append(granted, root+"/a/b/")
+				}
+
+				Expect(can(acl.Read, "/a/b")).To(BeFalse())
+				Expect(can(acl.Read, "/a/b/c")).To(BeFalse())
+			})
+
+			if api == acl.V1 {
+				It("adds to the operations of an existing grant", func() {
+					grant(root+"/a/b/c", acl.Read)
+					grant(root+"/a/b/c", acl.Write)
+
+					Expect(can(acl.Read, "/a/b/c")).To(BeTrue())
+					Expect(can(acl.Write, "/a/b/c")).To(BeTrue())
+				})
+			} else {
+				It("refuses a second grant for the same actor and path", func() {
+					grant(root+"/a/b/c", acl.Read)
+					err := api.Grant(factory.Admin(), actor.Actor, root+"/a/b/c", []acl.Permission{acl.Write})
+					Expect(err).To(MatchError(ContainSubstring("got 409")))
+
+					Expect(can(acl.Write, "/a/b/c")).To(BeFalse())
+				})
+			}
+
+			Describe("find", func() {
+				It("lists only what the actor can read", func() {
+					grant(root+"/a/b/*", acl.Read)
+					grant(root+"/a/e", acl.Read)
+					grant(root+"/ab", acl.Write)
+
+					byPath, err := acl.Find(actor.Client, url.Values{"path": {root}})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(byPath).To(ConsistOf(root+"/a/b/c", root+"/a/b/d", root+"/a/e"))
+
+					byName, err := acl.Find(actor.Client, url.Values{"name-like": {rootID}})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(byName).To(ConsistOf(root+"/a/b/c", root+"/a/b/d", root+"/a/e"))
+				})
+
+				It("lists nothing beneath a path the actor can only write", func() {
+					grant(root+"/a/b/*", acl.Write, acl.Delete)
+
+					found, err := acl.Find(actor.Client, url.Values{"path": {root + "/a/b"}})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeEmpty())
+
+					found, err = acl.Find(factory.Admin(), url.Values{"path": {root + "/a/b"}})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(ConsistOf(root+"/a/b/c", root+"/a/b/d"))
+				})
+			})
+		})
+	}
+})
@@ -40,8 +40,13 @@ var _ = BeforeEach(func() {
 	)
 
 	Expect(err).ToNot(HaveOccurred())
+	InstrumentClient(credhubClient)
 })
 
+var _ = RegisterReporting("Api Client Suite")
+var _ = RegisterContractValidation()
+var _ = RegisterHistoryChecks()
+
 func TestCredhub(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Api Client Suite")
@@ -0,0 +1,95 @@
+package acceptance_test
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials/generate"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials/values"
+	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/consistency"
+)
+
+// consistencyClient makes the harness's requests with the Go client, setting
+// and generating password and user credentials.
+type consistencyClient struct {
+	ch *credhub.CredHub
+}
+
+func (c consistencyClient) Set(name, credType string) (consistency.Version, error) {
+	var value interface{} = values.Password(GenerateUniqueCredentialName())
+	if credType == "user" {
+		value = values.User{Username: "some-user", Password: GenerateUniqueCredentialName()}
+	}
+	return toVersion(c.ch.SetCredential(name, credType, value))
+}
+
+func (c consistencyClient) Generate(name, credType string, overwrite bool) (consistency.Version, error) {
+	var parameters interface{} = generate.Password{}
+	if credType == "user" {
+		parameters = generate.User{}
+	}
+	mode := credhub.NoOverwrite
+	if overwrite {
+		mode = credhub.Overwrite
+	}
+	return toVersion(c.ch.GenerateCredential(name, credType, parameters, mode))
+}
+
+func (c consistencyClient) Regenerate(name string) (consistency.Version, error) {
+	return toVersion(c.ch.Regenerate(name))
+}
+
+func (c consistencyClient) Delete(name string) error {
+	return c.ch.Delete(name)
+}
+
+func (c consistencyClient) Get(name string) (consistency.Version, error) {
+	return toVersion(c.ch.GetLatestVersion(name))
+}
+
+func (c consistencyClient) CountVersions(name string) (int, error) {
+	versions, err := c.ch.GetAllVersions(name)
+	return len(versions), err
+}
+
+func toVersion(credential credentials.Credential, err error) (consistency.Version, error) {
+	return consistency.Version{ID: credential.Id, Type: credential.Type}, err
+}
+
+var _ = Describe("Concurrent writes", func() {
+	It("are linearizable", func() {
+		randomizer := time.Now().UnixNano()
+		var names []string
+		for i := 0; i < 3; i++ {
+			names = append(names, testCredentialPath(randomizer, fmt.Sprintf("contended-%d", i)))
+		}
+		DeferCleanup(func() {
+			for _, name := range names {
+				credhubClient.Delete(name)
+			}
+		})
+
+		history := consistency.Run(consistencyClient{credhubClient}, consistency.Options{
+			Actors:      8,
+			OpsPerActor: 25,
+			Names:       names,
+			Types:       []string{"password", "user"},
+			Weights:     consistency.DefaultWeights,
+			Seed:        GinkgoRandomSeed(),
+		})
+
+		violations := consistency.Check(history)
+		messages := make([]string, len(violations))
+		for i, violation := range violations {
+			messages[i] = violation.String()
+		}
+		Expect(violations).To(BeEmpty(), strings.Join(messages, "\n\n"))
+	})
+})
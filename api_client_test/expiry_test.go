@@ -0,0 +1,138 @@
+package acceptance_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/expiry"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// expiryZones are the zones the specs run in, as the local zone of this
+// process and in TZ: UTC, and the zones furthest ahead of and behind it.
+var expiryZones = []string{"UTC", "Pacific/Kiritimati", "Etc/GMT+12"}
+
+var _ = Describe("Finding certificates by expiry", func() {
+	var (
+		root     string
+		clock    expiry.Clock
+		fixtures []expiry.Fixture
+	)
+
+	// The fixtures are generated once the zone is set, with certificates on
+	// the day boundaries of the local zone.
+	JustBeforeEach(func() {
+		root = testCredentialPath(time.Now().UnixNano(), "expiry")
+
+		var err error
+		clock, err = expiry.Sync(credhubClient)
+		Expect(err).NotTo(HaveOccurred())
+
+		now := clock.Now()
+		fixtures, err = expiry.Generate(root, now, expiry.DefaultBuckets(now, time.Local))
+		Expect(err).NotTo(HaveOccurred())
+
+		DeferCleanup(func() {
+			for _, fixture := range fixtures {
+				credhubClient.Delete(fixture.Name)
+			}
+		})
+		Expect(expiry.Set(credhubClient, fixtures)).To(Succeed())
+	})
+
+	// findExpiring returns the expiry dates of the credentials a find with
+	// expires-within-days returns, by name.
+	findExpiring := func(query url.Values, days int) map[string]string {
+		query.Set("expires-within-days", strconv.Itoa(days))
+		response, err := credhubClient.Request(http.MethodGet, "/api/v1/data", query, nil, true)
+		Expect(err).NotTo(HaveOccurred())
+		defer response.Body.Close()
+
+		var results struct {
+			Credentials []struct {
+				Name       string `json:"name"`
+				ExpiryDate string `json:"expiry_date"`
+			} `json:"credentials"`
+		}
+		Expect(json.NewDecoder(response.Body).Decode(&results)).To(Succeed())
+
+		found := map[string]string{}
+		for _, credential := range results.Credentials {
+			found[credential.Name] = credential.ExpiryDate
+		}
+		return found
+	}
+
+	checkWindows := func(query url.Values) {
+		expiries := map[string]time.Time{}
+		for _, fixture := range fixtures {
+			expiries[fixture.Name] = fixture.Expiry()
+		}
+
+		for _, days := range expiry.Windows {
+			By("finding certificates expiring within " + strconv.Itoa(days) + " days")
+			found := findExpiring(query, days)
+			expected, uncertain := expiry.Expect(fixtures, days, clock.Now())
+
+			var names []string
+			for name, expiryDate := range found {
+				notAfter, err := time.Parse(time.RFC3339, expiryDate)
+				Expect(err).NotTo(HaveOccurred(), "expiry date of %s", name)
+				Expect(notAfter).To(BeTemporally("==", expiries[name]), "expiry date of %s", name)
+
+				if !contains(uncertain, name) {
+					names = append(names, name)
+				}
+			}
+			sort.Strings(names)
+			Expect(names).To(Equal(expected), "within %d days", days)
+		}
+	}
+
+	for _, name := range expiryZones {
+		name := name
+
+		Context("in "+name, func() {
+			BeforeEach(func() {
+				zone, err := time.LoadLocation(name)
+				Expect(err).NotTo(HaveOccurred())
+				local := time.Local
+				tz, hadTZ := os.LookupEnv("TZ")
+				time.Local = zone
+				os.Setenv("TZ", name)
+				DeferCleanup(func() {
+					time.Local = local
+					if hadTZ {
+						os.Setenv("TZ", tz)
+					} else {
+						os.Unsetenv("TZ")
+					}
+				})
+			})
+
+			It("finds exactly the certificates under a path whose latest version expires within each window", func() {
+				checkWindows(url.Values{"path": {root}})
+			})
+
+			It("finds exactly the certificates with a name like the query whose latest version expires within each window", func() {
+				checkWindows(url.Values{"name-like": {root}})
+			})
+		})
+	}
+})
+
+func contains(names []string, name string) bool {
+	for _, candidate := range names {
+		if candidate == name {
+			return true
+		}
+	}
+	return false
+}
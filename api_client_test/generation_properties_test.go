@@ -0,0 +1,38 @@
+package acceptance_test
+
+import (
+	"math/rand"
+	"time"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/generation"
+	. "github.com/onsi/ginkgo/v2"
+)
+
+var _ = Describe("Generation parameters", func() {
+	// Keys take a while to generate, so fewer of them are sampled.
+	runs := map[string]int{"password": 50, "user": 50, "ssh": 10, "rsa": 10}
+
+	for _, credType := range generation.Types {
+		credType := credType
+
+		It("generates "+credType+" credentials that keep every promise of random parameters", func() {
+			name := testCredentialPath(time.Now().UnixNano(), "generation-"+credType)
+			DeferCleanup(func() { credhubClient.Delete(name) })
+
+			property := func(c generation.Case) error {
+				credential, err := credhubClient.GenerateCredential(name, c.Type, c.Parameters(), credhub.Overwrite)
+				if err != nil {
+					return err
+				}
+				return generation.Verify(c, credential.Value)
+			}
+
+			r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			if failure := generation.Check(r, credType, runs[credType], property); failure != nil {
+				Fail(failure.Error())
+			}
+		})
+	}
+})
@@ -0,0 +1,145 @@
+package acceptance_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/certificates"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/rotation"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CA rotation", func() {
+	var (
+		driver                         *rotation.Driver
+		root, intermediate, leaf, side string
+	)
+
+	// check compares CredHub with the rotation model after a step.
+	check := func(step string) {
+		mismatches, err := driver.Check()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mismatches).To(BeEmpty(), "after %s:\n%s", step, fmt.Sprint(mismatches))
+	}
+
+	step := func(description string, action func() error) {
+		By(description)
+		Expect(action()).To(Succeed())
+		check(description)
+	}
+
+	BeforeEach(func() {
+		config, err := test_helpers.LoadConfig()
+		Expect(err).NotTo(HaveOccurred())
+
+		driver = rotation.New(certificates.HTTP{Client: credhubClient}, config.ConcatenateCas)
+		DeferCleanup(func() {
+			Expect(driver.Cleanup()).To(Succeed())
+		})
+
+		randomizer := time.Now().UnixNano()
+		root = testCredentialPath(randomizer, "root-ca")
+		intermediate = testCredentialPath(randomizer, "intermediate-ca")
+		leaf = testCredentialPath(randomizer, "leaf")
+		side = testCredentialPath(randomizer, "root-leaf")
+
+		step("generating a root CA", func() error { return driver.RootCA(root) })
+		step("generating an intermediate CA", func() error { return driver.IntermediateCA(intermediate, root) })
+		step("generating a leaf signed by the intermediate", func() error { return driver.Leaf(leaf, intermediate) })
+		step("generating a leaf signed by the root", func() error { return driver.Leaf(side, root) })
+	})
+
+	rotate := func(ca string) {
+		original := driver.Signing(ca)
+
+		step("starting the rotation of "+ca, func() error { return driver.Start(ca) })
+		Expect(driver.Signing(ca)).To(Equal(original))
+
+		step("switching "+ca+" to its new version", func() error { return driver.Switch(ca) })
+		Expect(driver.Signing(ca)).NotTo(Equal(original))
+
+		step("finishing the rotation of "+ca, func() error { return driver.Finish(ca) })
+	}
+
+	It("rotates a root CA and everything beneath it", func() {
+		rotate(root)
+	})
+
+	It("rotates an intermediate CA without touching its parent", func() {
+		rootVersions := len(driver.Versions(root))
+		rotate(intermediate)
+		Expect(driver.Versions(root)).To(HaveLen(rootVersions))
+	})
+
+	It("rotates the same CA twice", func() {
+		rotate(root)
+		rotate(root)
+	})
+
+	It("rotates a root CA and then its intermediate", func() {
+		rotate(root)
+		rotate(intermediate)
+	})
+
+	It("abandons a rotation that was only started", func() {
+		original := driver.Signing(root)
+		step("starting the rotation", func() error { return driver.Start(root) })
+		step("finishing it without switching", func() error { return driver.Finish(root) })
+		Expect(driver.Signing(root)).NotTo(Equal(original))
+	})
+
+	It("abandons a rotation by dropping the new version before switching", func() {
+		original := driver.Signing(root)
+		step("starting the rotation", func() error { return driver.Start(root) })
+		step("marking no version as transitional", func() error { return driver.SetTransitional(root, "") })
+		step("regenerating what the root signs", func() error { return driver.BulkRegenerate(root) })
+		Expect(driver.Signing(root)).NotTo(Equal(original))
+	})
+
+	It("rolls a rotation back after switching", func() {
+		original := driver.Signing(root)
+		step("starting the rotation", func() error { return driver.Start(root) })
+		step("switching to the new version", func() error { return driver.Switch(root) })
+		step("rolling back to the old version", func() error { return driver.RollBack(root) })
+		Expect(driver.Signing(root)).To(Equal(original))
+		step("finishing the rolled back rotation", func() error { return driver.Finish(root) })
+		Expect(driver.Signing(root)).NotTo(Equal(original))
+	})
+
+	It("refuses to start a second rotation of a CA mid-rotation", func() {
+		step("starting the rotation", func() error { return driver.Start(root) })
+
+		By("starting it again")
+		err := driver.Start(root)
+		Expect(err).To(BeAssignableToTypeOf(&certificates.StatusError{}))
+		Expect(err.(*certificates.StatusError).Status).To(Equal(400))
+		check("a refused second start")
+
+		step("switching to the first new version", func() error { return driver.Switch(root) })
+		step("finishing the rotation", func() error { return driver.Finish(root) })
+	})
+
+	It("signs certificates generated mid-rotation with the signing version", func() {
+		step("starting the rotation", func() error { return driver.Start(root) })
+		step("generating a leaf before the switch", func() error { return driver.Leaf(root+"-before-switch", root) })
+		step("switching to the new version", func() error { return driver.Switch(root) })
+		step("generating a leaf after the switch", func() error { return driver.Leaf(root+"-after-switch", root) })
+		step("finishing the rotation", func() error { return driver.Finish(root) })
+	})
+
+	It("keeps the transitional version through a plain regenerate mid-rotation", func() {
+		step("starting the rotation", func() error { return driver.Start(root) })
+		step("regenerating the root without the transitional flag", func() error { return driver.RegenerateCA(root, false) })
+		step("regenerating what the root signs", func() error { return driver.BulkRegenerate(root) })
+		step("finishing the rotation", func() error { return driver.Finish(root) })
+	})
+
+	It("rotates an intermediate CA while its parent is mid-rotation", func() {
+		step("starting the rotation of the root", func() error { return driver.Start(root) })
+		rotate(intermediate)
+		step("switching the root", func() error { return driver.Switch(root) })
+		step("finishing the root", func() error { return driver.Finish(root) })
+	})
+})
@@ -2,115 +2,51 @@ package api_integration_test
 
 import (
 	"bytes"
-	"crypto/tls"
-	"crypto/x509"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
-	"path/filepath"
 	"testing"
 	"time"
 
-	"code.cloudfoundry.org/credhub-cli/credhub"
-	"code.cloudfoundry.org/credhub-cli/credhub/auth"
 	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
-	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/certs"
-	"github.com/google/uuid"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/actors"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
 
 var (
 	config         Config
-	credhubCA      []byte
-	uaaCA          []byte
-	clientCACert   []byte
-	clientCAKey    []byte
+	factory        *actors.Factory
 	credentialName string
-	appGuid        string
 )
 var _ = BeforeSuite(func() {
 	var err error
 	config, err = LoadConfig()
 	Expect(err).NotTo(HaveOccurred())
 
-	credhubCA, err = ioutil.ReadFile(filepath.Join(config.CredentialRoot, "server_ca_cert.pem"))
-	Expect(err).NotTo(HaveOccurred())
-
-	uaaCA, err = ioutil.ReadFile(filepath.Join(config.UAACa))
+	factory, err = actors.FromConfig(config)
 	Expect(err).NotTo(HaveOccurred())
+})
 
-	clientCACert, err = ioutil.ReadFile(filepath.Join(config.CredentialRoot, "client_ca_cert.pem"))
-	Expect(err).NotTo(HaveOccurred())
-	clientCAKey, err = ioutil.ReadFile(filepath.Join(config.CredentialRoot, "client_ca_private.pem"))
-	Expect(err).NotTo(HaveOccurred())
+var _ = AfterSuite(func() {
+	if factory != nil {
+		Expect(factory.Close()).To(Succeed())
+	}
 })
 
 var _ = Describe("mutual TLS authentication", func() {
-	const CredhubClientCommonName = "credhub_test_client"
-
 	BeforeEach(func() {
 		credentialName = fmt.Sprintf("api-integration-test-%d", time.Now().UnixNano())
-		appGuid = uuid.NewString()
 	})
 
 	Describe("with a certificate signed by a trusted CA", func() {
-		var (
-			adminCredHubClient *credhub.CredHub
-			permissionUuid     string
-		)
-
-		BeforeEach(func() {
-			permissions := map[string]interface{}{
-				"actor":      "mtls-app:" + appGuid,
-				"path":       "/*",
-				"operations": []string{"read", "write", "delete"},
-			}
-
-			var err error
-			adminCredHubClient, err = credhub.New(config.ApiUrl,
-				credhub.CaCerts(string(credhubCA), string(uaaCA)),
-				credhub.Auth(
-					auth.UaaClientCredentials(config.ClientName, config.ClientSecret),
-				))
-			Expect(err).ToNot(HaveOccurred())
-
-			resp, err := adminCredHubClient.Request("POST", "/api/v2/permissions", nil, permissions, false)
-			Expect(err).ToNot(HaveOccurred())
-			defer resp.Body.Close()
-
-			var resBody []byte
-			resBody, err = ioutil.ReadAll(resp.Body)
-			Expect(err).NotTo(HaveOccurred())
-
-			type body struct {
-				Uuid string `json:"uuid"`
-			}
-
-			var postBody body
-			err = json.Unmarshal(resBody, &postBody)
-			Expect(err).ToNot(HaveOccurred())
-			permissionUuid = postBody.Uuid
-			Expect(resp.StatusCode).To(Equal(201))
-		})
-
-		AfterEach(func() {
-			resp, err := adminCredHubClient.Request("DELETE", "/api/v2/permissions/"+permissionUuid, nil, nil, false)
-			Expect(err).ToNot(HaveOccurred())
-			Expect(resp.StatusCode).To(Equal(200))
-		})
-
 		It("allows the client to hit an authenticated endpoint", func() {
-			cert, key, err := GenerateSigned(CertOptions{
-				CommonName:         CredhubClientCommonName,
-				OrganizationalUnit: "app:" + appGuid,
-			}, clientCACert, clientCAKey)
-			Expect(err).NotTo(HaveOccurred())
+			app := actors.Mint(factory.MTLSApp)
+			Expect(factory.Grant(app, "/*", "read", "write", "delete")).To(Succeed())
 
 			postData := map[string]string{"name": credentialName, "type": "password"}
-			result, err := mtlsPost(config.ApiUrl+"/api/v1/data", postData, credhubCA, cert, key)
+			result, err := mtlsPost(app.HTTP, config.ApiUrl+"/api/v1/data", postData)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(result).To(MatchRegexp(`"type":"password"`))
 		})
@@ -118,15 +54,10 @@ var _ = Describe("mutual TLS authentication", func() {
 
 	Describe("with an expired certificate", func() {
 		It("prevents the client from hitting an authenticated endpoint", func() {
-			cert, key, err := GenerateSigned(CertOptions{
-				CommonName: CredhubClientCommonName,
-				NotBefore:  time.Now().Add(time.Hour * 24 * -10),
-				NotAfter:   time.Now().Add(time.Hour * 24 * -5),
-			}, clientCACert, clientCAKey)
-			Expect(err).NotTo(HaveOccurred())
+			expired := actors.Mint(factory.ExpiredCertificate)
 
 			postData := map[string]string{"name": credentialName, "type": "password"}
-			result, err := mtlsPost(config.ApiUrl+"/api/v1/data", postData, credhubCA, cert, key)
+			result, err := mtlsPost(expired.HTTP, config.ApiUrl+"/api/v1/data", postData)
 			Expect(err).To(MatchError(ContainSubstring("unknown certificate")))
 			Expect(result).To(BeEmpty())
 		})
@@ -134,11 +65,10 @@ var _ = Describe("mutual TLS authentication", func() {
 
 	Describe("with a self-signed certificate", func() {
 		It("prevents the client from hitting an authenticated endpoint", func() {
-			cert, key, err := GenerateSelfSigned(CertOptions{})
-			Expect(err).NotTo(HaveOccurred())
+			selfSigned := actors.Mint(factory.SelfSignedCertificate)
 
 			postData := map[string]string{"name": credentialName, "type": "password"}
-			result, err := mtlsPost(config.ApiUrl+"/api/v1/data", postData, credhubCA, cert, key)
+			result, err := mtlsPost(selfSigned.HTTP, config.ApiUrl+"/api/v1/data", postData)
 
 			// golang doesn't seem to send self-signed certs
 			// server.ssl.client-auth=want (https://tools.ietf.org/html/rfc5246#section-7.4.4)
@@ -150,13 +80,10 @@ var _ = Describe("mutual TLS authentication", func() {
 
 	Describe("with a certificate signed by an unknown CA", func() {
 		It("prevents the client from hitting an authenticated endpoint", func() {
-			caCert, caKey, err := GenerateSelfSigned(CertOptions{})
-			Expect(err).NotTo(HaveOccurred())
-			cert, key, err := GenerateSigned(CertOptions{}, caCert, caKey)
-			Expect(err).NotTo(HaveOccurred())
+			untrusted := actors.Mint(factory.UntrustedCertificate)
 
 			postData := map[string]string{"name": credentialName, "type": "password"}
-			result, err := mtlsPost(config.ApiUrl+"/api/v1/data", postData, credhubCA, cert, key)
+			result, err := mtlsPost(untrusted.HTTP, config.ApiUrl+"/api/v1/data", postData)
 
 			// Okay, so golang 1.7.x **sometimes** doesn't seem to send certs that the server won't accept...
 			// Here we assert that, if there was an error, it should be the server rejecting the cert, and
@@ -170,29 +97,16 @@ var _ = Describe("mutual TLS authentication", func() {
 	})
 })
 
+var _ = RegisterReporting("mTLS Test Suite")
+var _ = RegisterContractValidation()
+var _ = RegisterHistoryChecks()
+
 func TestMTLS(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "mTLS Test Suite")
 }
 
-func mtlsPost(url string, postData map[string]string, serverCA, clientCert, clientKey []byte) (string, error) {
-	clientCertificate, err := tls.X509KeyPair(clientCert, clientKey)
-	ExpectWithOffset(1, err).NotTo(HaveOccurred())
-
-	trustedCAs := x509.NewCertPool()
-	ok := trustedCAs.AppendCertsFromPEM([]byte(serverCA))
-	if !ok {
-		return "", errors.New("failed to parse server CA")
-	}
-
-	tlsConf := &tls.Config{
-		Certificates: []tls.Certificate{clientCertificate},
-		RootCAs:      trustedCAs,
-	}
-
-	transport := &http.Transport{TLSClientConfig: tlsConf}
-	client := &http.Client{Transport: transport}
-
+func mtlsPost(client *http.Client, url string, postData map[string]string) (string, error) {
 	jsonValue, err := json.Marshal(postData)
 	if err != nil {
 		return "", err
@@ -1,20 +1,19 @@
 package bbr_integration
 
 import (
-	"fmt"
 	"os"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/onsi/gomega/gexec"
 
 	"io/ioutil"
 	"testing"
 	"time"
 
-	"os/exec"
-
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/auth"
 	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/bbr"
 )
 
 func TestBbrIntegrationTest(t *testing.T) {
@@ -22,8 +21,20 @@ func TestBbrIntegrationTest(t *testing.T) {
 	RunSpecs(t, "Backup and Restore integration suite")
 }
 
-var config test_helpers.Config
-var tmpDir string
+var _ = test_helpers.RegisterReporting("Backup and Restore integration suite")
+
+// Version histories are not checked after each spec: a restore brings back
+// credentials the spec deleted, and the spec compares every version before
+// and after the restore itself.
+
+const bbrTestPath = "/bbr_test"
+
+var (
+	config        test_helpers.Config
+	credhubClient *credhub.CredHub
+	runner        bbr.Runner
+	tmpDir        string
+)
 
 var _ = BeforeSuite(func() {
 	SetDefaultEventuallyTimeout(15 * time.Minute)
@@ -35,19 +46,20 @@ var _ = BeforeSuite(func() {
 	tmpDir, err = ioutil.TempDir("", "BBR_CREDHUB_TEST")
 	Expect(err).NotTo(HaveOccurred())
 
+	credhubClient, err = credhub.New(config.ApiUrl,
+		credhub.SkipTLSValidation(true),
+		credhub.Auth(auth.UaaClientCredentials(config.ClientName, config.ClientSecret)),
+	)
+	Expect(err).NotTo(HaveOccurred())
+	test_helpers.InstrumentClient(credhubClient)
+
+	if config.BBR != nil && config.BBR.Skip {
+		Skip("skip is set in the bbr section")
+	}
+	runner, err = bbr.FromConfig(config, GinkgoWriter)
+	Expect(err).NotTo(HaveOccurred())
 })
 
 var _ = AfterSuite(func() {
 	Expect(os.RemoveAll(tmpDir)).To(Succeed())
 })
-
-func RunCommand(args ...string) *gexec.Session {
-	fmt.Printf("Running %s", args)
-	cmd := exec.Command(args[0], args[1:]...)
-
-	session, err := gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
-	Expect(err).NotTo(HaveOccurred())
-	<-session.Exited
-
-	return session
-}
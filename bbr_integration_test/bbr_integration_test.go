@@ -1,40 +1,41 @@
 package bbr_integration
 
 import (
-	"fmt"
-
 	"io/ioutil"
+	"net/http"
 	"os"
 
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials/generate"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials/values"
 	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/bbr"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/snapshot"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/onsi/gomega/gbytes"
-	. "github.com/onsi/gomega/gexec"
 )
 
+// bbrTestActor is granted permissions on test credentials and on the path
+// they are under so that a restore must bring them back.
+const bbrTestActor = "mtls-app:bbr-test-reader"
+
 var _ = Describe("Backup and Restore", func() {
 	var credentialName string
-	var bbrTestPath = "bbr_test"
 	var bbrDirectory string
 
 	BeforeEach(func() {
-		credentialName = fmt.Sprintf("%s/%s", bbrTestPath, test_helpers.GenerateUniqueCredentialName())
-
-		By("authenticating and targeting against credhub")
-		session := RunCommand("credhub", "login", "--client-name", config.ClientName, "--client-secret", config.ClientSecret, "--server", config.ApiUrl, "--skip-tls-validation")
-		Eventually(session).Should(Exit(0))
+		credentialName = bbrTestPath + "/" + test_helpers.GenerateUniqueCredentialName()
 
 		CleanupCredhub(bbrTestPath)
 
 		var err error
-		bbrDirectory, err = ioutil.TempDir("", "bbr")
+		bbrDirectory, err = ioutil.TempDir(tmpDir, "bbr")
 		Expect(err).NotTo(HaveOccurred())
 	})
 
 	AfterEach(func() {
 		CleanupCredhub(bbrTestPath)
-		CleanupArtifacts()
 		os.RemoveAll(bbrDirectory)
 	})
 
@@ -43,79 +44,110 @@ var _ = Describe("Backup and Restore", func() {
 		credhubCredentialNames := createCredhubCredentials(bbrTestPath)
 
 		By("adding a test password that will be edited after backup")
-		session := RunCommand("credhub", "set", "--name", credentialName, "--type", "password", "-w", "originalsecret")
-		Eventually(session).Should(Exit(0))
+		_, err := credhubClient.SetPassword(credentialName, values.Password("originalsecret"))
+		Expect(err).NotTo(HaveOccurred())
+
+		By("recording the state to restore")
+		before, err := snapshot.Take(credhubClient, bbrTestPath, bbrTestActor)
+		Expect(err).NotTo(HaveOccurred())
 
 		By("running bbr backup")
-		session = RunCommand("bbr", "deployment", "--username", config.Bosh.Client, "--password", config.Bosh.ClientSecret, "--deployment", config.DeploymentName, "--target", config.Bosh.Environment, "--ca-cert", config.Bosh.CaCertPath, "backup", "--artifact-path", bbrDirectory)
-		Eventually(session).Should(Exit(0))
+		Expect(runner.Backup(bbrDirectory)).To(Succeed())
 
 		By("asserting that the backup archive exists and contains a database dump file")
-		session = RunCommand("sh", "-c", fmt.Sprintf("tar tf %s/%s*/*credhubdb.tar ./credhubdb_dump", bbrDirectory, config.DeploymentName))
-		Eventually(session).Should(Exit(0))
+		artifactPath, err := bbr.FindArtifact(bbrDirectory, bbr.Deployment(config))
+		Expect(err).NotTo(HaveOccurred())
+		archive, err := bbr.FindArchive(artifactPath, "credhubdb.tar")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bbr.ArchiveContains(archive, bbr.DumpFile)).To(BeTrue(), "%s has no %s", archive, bbr.DumpFile)
 
 		By("editing the test password")
-		session = RunCommand("credhub", "set", "--name", credentialName, "--type", "password", "-w", "updatedsecret")
-		Eventually(session).Should(Exit(0))
+		_, err = credhubClient.SetPassword(credentialName, values.Password("updatedsecret"))
+		Expect(err).NotTo(HaveOccurred())
 
-		editSession := RunCommand("credhub", "get", "--name", credentialName)
-		Eventually(editSession).Should(Exit(0))
-		Eventually(editSession.Out).Should(gbytes.Say("value: updatedsecret"))
+		password, err := credhubClient.GetLatestPassword(credentialName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(password.Value).To(Equal(values.Password("updatedsecret")))
 
-		By("deleting all of the other test credentials")
+		By("deleting all of the other test credentials and the permission on their path")
 		for _, credentialName := range credhubCredentialNames {
-			session = RunCommand("credhub", "delete", "-n", credentialName)
-			Eventually(session).Should(Exit(0))
+			Expect(credhubClient.Delete(credentialName)).To(Succeed())
 		}
+		deleteWildcardPermission(bbrTestPath)
 
 		By("running bbr restore")
-		session = RunCommand("sh", "-c",
-			fmt.Sprintf("bbr deployment --username %s --password %s --deployment %s --target %s --ca-cert %s restore --artifact-path %s/%s*",
-				config.Bosh.Client, config.Bosh.ClientSecret, config.DeploymentName, config.Bosh.Environment, config.Bosh.CaCertPath, bbrDirectory, config.DeploymentName),
-		)
-		Eventually(session).Should(Exit(0))
+		Expect(runner.Restore(artifactPath)).To(Succeed())
 
 		By("checking if the test password was restored")
-		getSession := RunCommand("credhub", "get", "--name", credentialName)
-		Eventually(getSession).Should(Exit(0))
-		Eventually(getSession.Out).Should(gbytes.Say("value: originalsecret"))
+		password, err = credhubClient.GetLatestPassword(credentialName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(password.Value).To(Equal(values.Password("originalsecret")))
 
 		By("checking if the other test credentials were restored")
-		findSession := RunCommand("credhub", "find")
-		Eventually(findSession).Should(Exit(0))
-		for _, credentialName := range credhubCredentialNames {
-			Expect(findSession.Out.Contents()).To(ContainSubstring(credentialName))
+		results, err := credhubClient.FindByPath(bbrTestPath)
+		Expect(err).NotTo(HaveOccurred())
+		var restored []string
+		for _, credential := range results.Credentials {
+			restored = append(restored, credential.Name)
 		}
+		Expect(restored).To(ContainElements(credhubCredentialNames))
+
+		By("comparing everything restored with the state before the backup")
+		after, err := snapshot.Take(credhubClient, bbrTestPath, bbrTestActor)
+		Expect(err).NotTo(HaveOccurred())
+		differences := snapshot.Diff(before, after)
+		AddReportEntry("restore differences", differences)
+		Expect(differences).To(BeEmpty(), "the restore changed:\n%s", snapshot.Format(differences))
 	})
 })
 
+// createCredhubCredentials creates a credential of every type. Some have
+// several versions, metadata or permissions, the actor is granted read on
+// every credential under the prefix, and the certificates are a CA
+// with a transitional version and a certificate it signs, so that a restore
+// must bring all of those back.
 func createCredhubCredentials(credentialPrefix string) []string {
-	passwordName := fmt.Sprintf("%s/%s", credentialPrefix, test_helpers.GenerateUniqueCredentialName())
-	certificateName := fmt.Sprintf("%s/%s", credentialPrefix, test_helpers.GenerateUniqueCredentialName())
-	sshName := fmt.Sprintf("%s/%s", credentialPrefix, test_helpers.GenerateUniqueCredentialName())
-	rsaName := fmt.Sprintf("%s/%s", credentialPrefix, test_helpers.GenerateUniqueCredentialName())
-	jsonName := fmt.Sprintf("%s/%s", credentialPrefix, test_helpers.GenerateUniqueCredentialName())
-	valueName := fmt.Sprintf("%s/%s", credentialPrefix, test_helpers.GenerateUniqueCredentialName())
-	userName := fmt.Sprintf("%s/%s", credentialPrefix, test_helpers.GenerateUniqueCredentialName())
-
-	session := RunCommand("credhub", "generate", "--name", passwordName, "--type", "password")
-	Eventually(session).Should(Exit(0))
-	session = RunCommand("credhub", "generate", "--name", certificateName, "--type", "certificate", "-c", "cn", "--is-ca")
-	Eventually(session).Should(Exit(0))
-	session = RunCommand("credhub", "generate", "--name", sshName, "--type", "ssh")
-	Eventually(session).Should(Exit(0))
-	session = RunCommand("credhub", "generate", "--name", rsaName, "--type", "rsa")
-	Eventually(session).Should(Exit(0))
-	session = RunCommand("credhub", "generate", "--name", userName, "--type", "user")
-	Eventually(session).Should(Exit(0))
-	session = RunCommand("credhub", "set", "--name", jsonName, "--type", "json", "-v", `{"test": "secret"}`)
-	Eventually(session).Should(Exit(0))
-	session = RunCommand("credhub", "set", "--name", valueName, "--type", "value", "-v", "some-value")
-	Eventually(session).Should(Exit(0))
+	name := func() string {
+		return credentialPrefix + "/" + test_helpers.GenerateUniqueCredentialName()
+	}
+	passwordName, certificateName, leafName, sshName, rsaName := name(), name(), name(), name(), name()
+	jsonName, valueName, userName := name(), name(), name()
+
+	_, err := credhubClient.GeneratePassword(passwordName, generate.Password{}, credhub.Overwrite)
+	Expect(err).NotTo(HaveOccurred())
+	_, err = credhubClient.GeneratePassword(passwordName, generate.Password{}, credhub.Overwrite)
+	Expect(err).NotTo(HaveOccurred())
+	_, err = credhubClient.GenerateCertificate(certificateName, generate.Certificate{CommonName: "cn", IsCA: true}, credhub.Overwrite)
+	Expect(err).NotTo(HaveOccurred())
+	_, err = credhubClient.GenerateCertificate(leafName, generate.Certificate{CommonName: "leaf", Ca: certificateName}, credhub.Overwrite)
+	Expect(err).NotTo(HaveOccurred())
+	_, err = credhubClient.Regenerate(leafName)
+	Expect(err).NotTo(HaveOccurred())
+	regenerateAsTransitional(certificateName)
+	_, err = credhubClient.GenerateSSH(sshName, generate.SSH{}, credhub.Overwrite)
+	Expect(err).NotTo(HaveOccurred())
+	_, err = credhubClient.GenerateRSA(rsaName, generate.RSA{}, credhub.Overwrite)
+	Expect(err).NotTo(HaveOccurred())
+	_, err = credhubClient.GenerateUser(userName, generate.User{}, credhub.Overwrite)
+	Expect(err).NotTo(HaveOccurred())
+	_, err = credhubClient.SetJSON(jsonName, values.JSON{"test": "secret"})
+	Expect(err).NotTo(HaveOccurred())
+	_, err = credhubClient.SetValue(valueName, values.Value("some-value"), func(options *credhub.SetOptions) error {
+		options.Metadata = credentials.Metadata{"owner": "bbr", "rotation": map[string]interface{}{"days": 30.0}}
+		return nil
+	})
+	Expect(err).NotTo(HaveOccurred())
+	_, err = credhubClient.AddPermission(jsonName, bbrTestActor, []string{"read", "write"})
+	Expect(err).NotTo(HaveOccurred())
+	_, err = credhubClient.AddPermission(certificateName, bbrTestActor, []string{"read"})
+	Expect(err).NotTo(HaveOccurred())
+	_, err = credhubClient.AddPermission(credentialPrefix+"/*", bbrTestActor, []string{"read"})
+	Expect(err).NotTo(HaveOccurred())
 
 	return []string{
 		passwordName,
 		certificateName,
+		leafName,
 		sshName,
 		valueName,
 		jsonName,
@@ -124,16 +156,35 @@ func createCredhubCredentials(credentialPrefix string) []string {
 	}
 }
 
+// regenerateAsTransitional adds a transitional version to a CA, as the
+// first step of rotating it does.
+func regenerateAsTransitional(name string) {
+	certificate, err := credhubClient.GetCertificateMetadataByName(name)
+	Expect(err).NotTo(HaveOccurred())
+	response, err := credhubClient.Request(http.MethodPost, "/api/v1/certificates/"+certificate.Id+"/regenerate", nil,
+		map[string]interface{}{"set_as_transitional": true}, true)
+	Expect(err).NotTo(HaveOccurred())
+	response.Body.Close()
+}
+
 func CleanupCredhub(path string) {
 	By("Cleaning up credhub bbr test passwords")
-	RunCommand(
-		"sh", "-c",
-		fmt.Sprintf("credhub find -p /%s --output-json | jq -r '.credentials[].name' | xargs -IN credhub delete --name N", path),
-	)
+	results, err := credhubClient.FindByPath(path)
+	Expect(err).NotTo(HaveOccurred())
+	for _, credential := range results.Credentials {
+		if permission, err := credhubClient.GetPermissionByPathActor(credential.Name, bbrTestActor); err == nil {
+			credhubClient.DeletePermission(permission.UUID)
+		}
+		Expect(credhubClient.Delete(credential.Name)).To(Succeed())
+	}
+	deleteWildcardPermission(path)
 }
 
-func CleanupArtifacts() {
-	By("Cleaning up bbr test artifacts")
-	RunCommand("rm", "-rf", "credhubdb_dump")
-	RunCommand("sh", "-c", fmt.Sprintf("rm -rf %s*Z", config.DirectorHost))
+// deleteWildcardPermission deletes the actor's permission on every
+// credential under path, if there is one.
+func deleteWildcardPermission(path string) {
+	if permission, err := credhubClient.GetPermissionByPathActor(path+"/*", bbrTestActor); err == nil {
+		_, err = credhubClient.DeletePermission(permission.UUID)
+		Expect(err).NotTo(HaveOccurred())
+	}
 }
@@ -0,0 +1,101 @@
+package conformance_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/bulkimport"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+// bulkImportSize is how many entries each bulk import file has, and
+// bulkImportInvalid how many of them CredHub must reject.
+const (
+	bulkImportSize    = 100
+	bulkImportInvalid = 8
+)
+
+var _ = Describe("Bulk import", func() {
+	orders := []struct {
+		name  string
+		order bulkimport.Order
+	}{
+		{"with each CA before the certificates it signs", bulkimport.DependencyOrder},
+		{"with each CA after the certificates it signs", bulkimport.ReverseOrder},
+	}
+
+	for _, order := range orders {
+		order := order
+
+		It("reports the result of every entry of a file "+order.name, func() {
+			root := fmt.Sprintf("/bulk-import/%d/%s", time.Now().UnixNano(), GenerateUniqueCredentialName())
+			DeferCleanup(func() { RunCLI("delete", "-p", root) })
+
+			entries, err := bulkimport.Generate(rand.New(rand.NewSource(GinkgoRandomSeed())), bulkimport.Options{
+				Root: root, Count: bulkImportSize, Invalid: bulkImportInvalid, Order: order.order,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			importFile, err := ioutil.TempFile("", "bulk-import")
+			Expect(err).NotTo(HaveOccurred())
+			importFile.Close()
+			DeferCleanup(func() { os.Remove(importFile.Name()) })
+			Expect(bulkimport.WriteFile(importFile.Name(), entries)).To(Succeed())
+
+			By("importing it")
+			session := RunCommand("import", "-f", importFile.Name())
+			Expect(session.ExitCode()).To(Equal(1))
+			Expect(session.Err).To(gbytes.Say("One or more credentials failed to import."))
+
+			summary, err := bulkimport.ParseSummary(session.Out.Contents())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bulkimport.Check(entries, summary)).To(Succeed())
+
+			By("finding what was stored")
+			found, err := credhubClient.FindByPath(root)
+			Expect(err).NotTo(HaveOccurred())
+			names := make([]string, len(found.Credentials))
+			for i, credential := range found.Credentials {
+				names[i] = credential.Name
+			}
+			Expect(bulkimport.Stored(entries, names)).To(Succeed())
+
+			for _, entry := range entries {
+				if entry.Invalid != "" {
+					continue
+				}
+				stored, err := credhubClient.GetLatestVersion(entry.Name)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stored.Type).To(Equal(entry.Type), entry.Name)
+				Expect(bulkimport.Matches(entry, stored.Value)).To(BeTrue(), "%s was stored as %v", entry.Name, stored.Value)
+			}
+		})
+	}
+
+	It("succeeds when every entry is valid", func() {
+		root := fmt.Sprintf("/bulk-import/%d/%s", time.Now().UnixNano(), GenerateUniqueCredentialName())
+		DeferCleanup(func() { RunCLI("delete", "-p", root) })
+
+		entries, err := bulkimport.Generate(rand.New(rand.NewSource(GinkgoRandomSeed())), bulkimport.Options{
+			Root: root, Count: 30, Order: bulkimport.ReverseOrder,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		importFile, err := ioutil.TempFile("", "bulk-import")
+		Expect(err).NotTo(HaveOccurred())
+		importFile.Close()
+		DeferCleanup(func() { os.Remove(importFile.Name()) })
+		Expect(bulkimport.WriteFile(importFile.Name(), entries)).To(Succeed())
+
+		output, err := RunCLI("import", "-f", importFile.Name())
+		Expect(err).NotTo(HaveOccurred())
+		summary, err := bulkimport.ParseSummary(output)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(summary.Failed).To(BeZero())
+		Expect(bulkimport.Check(entries, summary)).To(Succeed())
+	})
+})
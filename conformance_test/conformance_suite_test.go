@@ -0,0 +1,112 @@
+package conformance_test
+
+import (
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path"
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+
+	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/conformance"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gexec"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/auth"
+)
+
+var (
+	homeDir       string
+	cfg           Config
+	credhubClient *credhub.CredHub
+)
+
+var _ = RegisterReporting("Conformance Suite")
+var _ = RegisterContractValidation()
+var _ = RegisterHistoryChecks()
+
+func TestConformance(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Conformance Suite")
+}
+
+var _ = BeforeEach(func() {
+	var err error
+	homeDir, err = ioutil.TempDir("", "cm-test")
+	Expect(err).NotTo(HaveOccurred())
+
+	if runtime.GOOS == "windows" {
+		os.Setenv("USERPROFILE", homeDir)
+	} else {
+		os.Setenv("HOME", homeDir)
+	}
+
+	os.Unsetenv("CREDHUB_DEBUG")
+
+	cfg, err = LoadConfig()
+	Expect(err).NotTo(HaveOccurred())
+
+	TargetAndLoginWithClientCredentials(cfg)
+
+	credhub_ca, err := ioutil.ReadFile(path.Join(cfg.CredentialRoot, "server_ca_cert.pem"))
+	Expect(err).NotTo(HaveOccurred())
+
+	uaa_ca, err := ioutil.ReadFile(path.Join(cfg.UAACa))
+	Expect(err).NotTo(HaveOccurred())
+
+	credhubClient, err = credhub.New(cfg.ApiUrl,
+		credhub.CaCerts(string(credhub_ca), string(uaa_ca)),
+		credhub.Auth(
+			auth.UaaClientCredentials(cfg.ClientName, cfg.ClientSecret),
+		),
+	)
+	Expect(err).ToNot(HaveOccurred())
+	InstrumentClient(credhubClient)
+})
+
+var _ = AfterEach(func() {
+	CleanEnv()
+	os.RemoveAll(homeDir)
+})
+
+var _ = SynchronizedBeforeSuite(func() []byte {
+	path, err := Build("code.cloudfoundry.org/credhub-cli", "-mod=mod")
+	Expect(err).NotTo(HaveOccurred())
+
+	return []byte(path)
+}, func(data []byte) {
+	CommandPath = string(data)
+
+	rand.Seed(GinkgoRandomSeed() + int64(GinkgoParallelNode()))
+})
+
+var _ = SynchronizedAfterSuite(func() {}, func() {
+	CleanupBuildArtifacts()
+})
+
+// drivers are the clients every conformance spec runs through. They are
+// functions as the Go client is only made in BeforeEach.
+var drivers = []struct {
+	name   string
+	driver func() conformance.Driver
+}{
+	{"the CLI", func() conformance.Driver { return conformance.CLI{Run: RunCLI} }},
+	{"the Go client", func() conformance.Driver { return conformance.GoClient{Client: credhubClient} }},
+}
+
+func serverSupportsMetadata() (bool, error) {
+	serverVersion, err := credhubClient.ServerVersion()
+	if err != nil {
+		return false, err
+	}
+	checkVersion, err := version.NewVersion("2.6.0")
+	if err != nil {
+		return false, err
+	}
+	return serverVersion.GreaterThanOrEqual(checkVersion), nil
+}
@@ -0,0 +1,218 @@
+package conformance_test
+
+import (
+	"fmt"
+	"time"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+
+	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/apierrors"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/conformance"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Credential type conformance", func() {
+	for _, d := range drivers {
+		d := d
+		for _, t := range conformance.Types {
+			t := t
+
+			Describe(fmt.Sprintf("%s credentials through %s", t.Name, d.name), func() {
+				var (
+					driver conformance.Driver
+					name   string
+					n      int
+				)
+
+				BeforeEach(func() {
+					driver = d.driver()
+					name = fmt.Sprintf("/conformance/%d/%s/%s", time.Now().UnixNano(), t.Name, GenerateUniqueCredentialName())
+					DeferCleanup(func() { driver.Delete(name) })
+				})
+
+				value := func() interface{} {
+					n++
+					v, err := t.Value(n)
+					Expect(err).NotTo(HaveOccurred())
+					return v
+				}
+
+				set := func() (interface{}, credentials.Credential) {
+					v := value()
+					credential, err := driver.Set(name, t, v, nil)
+					Expect(err).NotTo(HaveOccurred())
+					return v, credential
+				}
+
+				It("gets what was set", func() {
+					v, setCredential := set()
+					Expect(setCredential.Name).To(Equal(name))
+					Expect(setCredential.Type).To(Equal(t.Name))
+					Expect(conformance.Matches(v, setCredential.Value)).To(BeTrue(), "set returned %#v", setCredential.Value)
+
+					credential, err := driver.Get(name)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(credential.Id).To(Equal(setCredential.Id))
+					Expect(credential.Type).To(Equal(t.Name))
+					Expect(conformance.Matches(v, credential.Value)).To(BeTrue(), "get returned %#v", credential.Value)
+				})
+
+				It("gets a version by id", func() {
+					v, setCredential := set()
+					set()
+
+					credential, err := driver.GetByID(setCredential.Id)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(credential.Name).To(Equal(name))
+					Expect(conformance.Matches(v, credential.Value)).To(BeTrue(), "get by id returned %#v", credential.Value)
+				})
+
+				It("lists versions newest first", func() {
+					var values []interface{}
+					var ids []string
+					for i := 0; i < 3; i++ {
+						v := value()
+						credential, err := driver.Set(name, t, v, nil)
+						Expect(err).NotTo(HaveOccurred())
+						values = append([]interface{}{v}, values...)
+						ids = append([]string{credential.Id}, ids...)
+					}
+
+					versions, err := driver.Versions(name, 3)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(versions).To(HaveLen(3))
+					for i, version := range versions {
+						Expect(version.Id).To(Equal(ids[i]))
+						Expect(conformance.Matches(values[i], version.Value)).To(BeTrue(), "version %d was %#v", i, version.Value)
+					}
+				})
+
+				if t.Generatable {
+					It("regenerates a generated value", func() {
+						generated, err := driver.Generate(name, t, credhub.Overwrite, nil)
+						Expect(err).NotTo(HaveOccurred())
+
+						credential, err := driver.Regenerate(name, nil)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(credential.Id).NotTo(Equal(generated.Id))
+						Expect(credential.Type).To(Equal(t.Name))
+						Expect(credential.Value).NotTo(Equal(generated.Value), "regenerate kept the value")
+					})
+				} else {
+					It("refuses to regenerate", func() {
+						set()
+
+						_, err := driver.Regenerate(name, nil)
+						Expect(err).To(apierrors.MatchErrorMessage(apierrors.NotRegeneratable))
+					})
+				}
+
+				if t.RefusesStaticRegenerate {
+					It("refuses to regenerate a statically set value", func() {
+						set()
+
+						_, err := driver.Regenerate(name, nil)
+						Expect(err).To(apierrors.MatchErrorMessage(apierrors.StaticallySet))
+					})
+				}
+
+				It("deletes every version", func() {
+					set()
+					set()
+
+					Expect(driver.Delete(name)).To(Succeed())
+					_, err := driver.Get(name)
+					Expect(err).To(HaveOccurred())
+				})
+
+				It("round-trips metadata", func() {
+					supported, err := serverSupportsMetadata()
+					Expect(err).NotTo(HaveOccurred())
+					if !supported {
+						Skip("Server does not support metadata")
+					}
+
+					metadata := credentials.Metadata{"type": t.Name, "nested": map[string]interface{}{"list": []interface{}{"a", float64(1)}}}
+					credential, err := driver.Set(name, t, value(), metadata)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(credential.Metadata).To(Equal(metadata))
+
+					credential, err = driver.Get(name)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(credential.Metadata).To(Equal(metadata))
+
+					if t.Generatable {
+						generated, err := driver.Generate(name, t, credhub.Overwrite, credentials.Metadata{"generated": true})
+						Expect(err).NotTo(HaveOccurred())
+						Expect(generated.Metadata).To(Equal(credentials.Metadata{"generated": true}))
+					}
+				})
+
+				It("selects each field of the latest value", func() {
+					v, _ := set()
+
+					if t.Scalar() {
+						field, err := driver.Field(name, "value")
+						Expect(err).NotTo(HaveOccurred())
+						Expect(field).To(BeEmpty())
+						return
+					}
+					for _, key := range t.Fields {
+						field, err := driver.Field(name, key)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(field).To(Equal(v.(map[string]interface{})[key]), "field %s", key)
+					}
+				})
+
+				It("prints only the value when quiet", func() {
+					v, _ := set()
+
+					quiet, err := driver.Quiet(name)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(conformance.Matches(v, quiet)).To(BeTrue(), "quiet returned %#v", quiet)
+				})
+
+				if !t.Generatable {
+					return
+				}
+
+				Context("when generating over an existing credential", func() {
+					var existing credentials.Credential
+
+					BeforeEach(func() {
+						var err error
+						existing, err = driver.Generate(name, t, credhub.Overwrite, nil)
+						Expect(err).NotTo(HaveOccurred())
+					})
+
+					It("overwrites it by default", func() {
+						credential, err := driver.Generate(name, t, credhub.Overwrite, nil)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(credential.Id).NotTo(Equal(existing.Id))
+						Expect(credential.Value).NotTo(Equal(existing.Value))
+					})
+
+					It("keeps it with no-overwrite", func() {
+						credential, err := driver.Generate(name, t, credhub.NoOverwrite, nil)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(credential.Id).To(Equal(existing.Id))
+						Expect(credential.Value).To(Equal(existing.Value))
+					})
+
+					It("keeps it when converging with the same parameters", func() {
+						credential, err := driver.Generate(name, t, credhub.Converge, nil)
+						if err == conformance.ErrUnsupported {
+							Skip(d.name + " cannot converge")
+						}
+						Expect(err).NotTo(HaveOccurred())
+						Expect(credential.Id).To(Equal(existing.Id))
+						Expect(credential.Value).To(Equal(existing.Value))
+					})
+				})
+			})
+		}
+	}
+})
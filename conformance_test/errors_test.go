@@ -0,0 +1,194 @@
+package conformance_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials/values"
+
+	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/actors"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/apierrors"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// trigger causes a catalogued error through every client. setup, when set,
+// runs first with the admin client.
+type trigger struct {
+	condition apierrors.Condition
+	setup     func(name string)
+	http      func(name string) (*http.Response, error)
+	client    func(name string) error
+	cli       func(name string) []string
+}
+
+// setCertificate sets a certificate with a private key.
+func setCertificate(condition apierrors.Condition, certificate, privateKey string) trigger {
+	value := values.Certificate{Certificate: certificate, PrivateKey: privateKey}
+	return trigger{
+		condition: condition,
+		http: func(name string) (*http.Response, error) {
+			return credhubClient.Request(http.MethodPut, "/api/v1/data", nil, map[string]interface{}{"name": name, "type": "certificate", "value": value}, false)
+		},
+		client: func(name string) error {
+			_, err := credhubClient.SetCertificate(name, value)
+			return err
+		},
+		cli: func(name string) []string {
+			return []string{"set", "-n", name, "-t", "certificate", "--certificate=" + value.Certificate, "--private=" + value.PrivateKey}
+		},
+	}
+}
+
+// regenerate regenerates a credential that set sets.
+func regenerate(condition apierrors.Condition, set func(name string) error) trigger {
+	return trigger{
+		condition: condition,
+		setup:     func(name string) { Expect(set(name)).To(Succeed()) },
+		http: func(name string) (*http.Response, error) {
+			return credhubClient.Request(http.MethodPost, "/api/v1/data", nil, map[string]interface{}{"name": name, "regenerate": true}, false)
+		},
+		client: func(name string) error {
+			_, err := credhubClient.Regenerate(name)
+			return err
+		},
+		cli: func(name string) []string { return []string{"regenerate", "-n", name} },
+	}
+}
+
+var triggers = func() []trigger {
+	badUsage := map[string]interface{}{"common_name": "errors", "self_sign": true, "extended_key_usage": []string{"code_sinning"}}
+
+	return []trigger{
+		{
+			condition: apierrors.NotFound,
+			http: func(name string) (*http.Response, error) {
+				return credhubClient.Request(http.MethodGet, "/api/v1/data", url.Values{"name": {name}, "current": {"true"}}, nil, false)
+			},
+			client: func(name string) error {
+				_, err := credhubClient.GetLatestVersion(name)
+				return err
+			},
+			cli: func(name string) []string { return []string{"get", "-n", name} },
+		},
+		{
+			condition: apierrors.TypeMismatch,
+			setup: func(name string) {
+				_, err := credhubClient.SetValue(name, values.Value("some-value"))
+				Expect(err).NotTo(HaveOccurred())
+			},
+			http: func(name string) (*http.Response, error) {
+				return credhubClient.Request(http.MethodPut, "/api/v1/data", nil, map[string]interface{}{"name": name, "type": "password", "value": "some-password"}, false)
+			},
+			client: func(name string) error {
+				_, err := credhubClient.SetPassword(name, values.Password("some-password"))
+				return err
+			},
+			cli: func(name string) []string {
+				return []string{"set", "-n", name, "-t", "password", "-w", "some-password"}
+			},
+		},
+		{
+			condition: apierrors.InvalidParameter,
+			http: func(name string) (*http.Response, error) {
+				return credhubClient.Request(http.MethodPost, "/api/v1/data", nil, map[string]interface{}{"name": name, "type": "certificate", "parameters": badUsage}, false)
+			},
+			client: func(name string) error {
+				_, err := credhubClient.GenerateCredential(name, "certificate", badUsage, credhub.Overwrite)
+				return err
+			},
+			cli: func(name string) []string {
+				return []string{"generate", "-n", name, "-t", "certificate", "-c", "errors", "--self-sign", "-e", "code_sinning"}
+			},
+		},
+		regenerate(apierrors.NotRegeneratable, func(name string) error {
+			_, err := credhubClient.SetValue(name, values.Value("some-value"))
+			return err
+		}),
+		regenerate(apierrors.StaticallySet, func(name string) error {
+			_, err := credhubClient.SetPassword(name, values.Password("some-password"))
+			return err
+		}),
+		setCertificate(apierrors.MalformedKey, OTHER_VALID_CERTIFICATE, EC_PRIVATE_KEY),
+		setCertificate(apierrors.EncryptedKey, OTHER_VALID_CERTIFICATE, OTHER_PRIVATE_KEY_PKCS8_ENCRYPTED),
+		setCertificate(apierrors.MalformedCertificate, "not a certificate", OTHER_VALID_PRIVATE_KEY_PKCS1),
+		setCertificate(apierrors.MismatchedKey, VALID_CERTIFICATE, OTHER_VALID_PRIVATE_KEY_PKCS1),
+	}
+}()
+
+var _ = Describe("Error conditions", func() {
+	var name string
+
+	BeforeEach(func() {
+		name = fmt.Sprintf("/conformance/%d/errors/%s", time.Now().UnixNano(), GenerateUniqueCredentialName())
+		DeferCleanup(func() { credhubClient.Delete(name) })
+	})
+
+	for _, t := range triggers {
+		t := t
+
+		Describe(t.condition.Name, func() {
+			BeforeEach(func() {
+				if t.setup != nil {
+					t.setup(name)
+				}
+			})
+
+			It("is reported over HTTP with its status and error body", func() {
+				response, err := t.http(name)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response).To(apierrors.MatchResponse(t.condition))
+			})
+
+			It("is returned by the Go client as a CredHub error", func() {
+				Expect(t.client(name)).To(apierrors.MatchClientError(t.condition))
+			})
+
+			It("makes the CLI exit 1 with the error", func() {
+				Expect(RunCommand(t.cli(name)...)).To(apierrors.MatchCLIError(t.condition))
+			})
+		})
+	}
+
+	Describe(apierrors.Forbidden.Name, func() {
+		var factory *actors.Factory
+
+		BeforeEach(func() {
+			var err error
+			factory, err = actors.FromConfig(cfg)
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(factory.Close)
+		})
+
+		It("is reported over HTTP with its status and error body", func() {
+			identity := actors.Mint(factory.MTLSApp)
+			body, err := json.Marshal(map[string]interface{}{"name": name, "type": "value", "value": "some-value"})
+			Expect(err).NotTo(HaveOccurred())
+			request, err := http.NewRequest(http.MethodPut, cfg.ApiUrl+"/api/v1/data", bytes.NewReader(body))
+			Expect(err).NotTo(HaveOccurred())
+			request.Header.Set("Content-Type", "application/json")
+
+			response, err := identity.HTTP.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(apierrors.MatchResponse(apierrors.Forbidden))
+		})
+
+		It("is returned by the Go client as a CredHub error", func() {
+			identity := actors.Mint(factory.MTLSApp)
+			_, err := identity.Client.SetValue(name, values.Value("some-value"))
+			Expect(err).To(apierrors.MatchClientError(apierrors.Forbidden))
+		})
+
+		It("makes the CLI exit 1 with the error", func() {
+			identity := actors.Mint(func() (*actors.Identity, error) { return factory.UAAClient() })
+			session := identity.CLI.Run("set", "-n", name, "-t", "value", "-v", "some-value")
+			Expect(session).To(apierrors.MatchCLIError(apierrors.Forbidden))
+		})
+	})
+})
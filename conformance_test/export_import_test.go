@@ -0,0 +1,88 @@
+package conformance_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/certificates"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/roundtrip"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/snapshot"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// corpusSize is how many random credentials a round trip carries, besides
+// its chain of certificates.
+const corpusSize = 30
+
+var _ = Describe("Export and import", func() {
+	formats := []struct {
+		name string
+		flag []string
+	}{
+		{"YAML", nil},
+		{"JSON", []string{"-j"}},
+	}
+
+	for _, format := range formats {
+		format := format
+
+		It("carries a corpus of every type through a round trip as "+format.name, func() {
+			supported, err := serverSupportsMetadata()
+			Expect(err).NotTo(HaveOccurred())
+			if !supported {
+				Skip("Server does not support metadata")
+			}
+
+			root := fmt.Sprintf("/export-import/%d/%s", time.Now().UnixNano(), GenerateUniqueCredentialName())
+			DeferCleanup(func() { RunCLI("delete", "-p", root) })
+
+			exportFile, err := ioutil.TempFile("", "export-data")
+			Expect(err).NotTo(HaveOccurred())
+			exportFile.Close()
+			DeferCleanup(func() { os.Remove(exportFile.Name()) })
+
+			By("writing a corpus")
+			corpus := roundtrip.Corpus(rand.New(rand.NewSource(GinkgoRandomSeed())), root, corpusSize)
+			written, err := roundtrip.Write(credhubClient, certificates.New(certificates.HTTP{Client: credhubClient}), corpus)
+			Expect(err).NotTo(HaveOccurred())
+			if rejected := len(corpus) - len(written); rejected > 0 {
+				AddReportEntry("names CredHub rejected", fmt.Sprintf("%d of %d", rejected, len(corpus)))
+			}
+			before, err := snapshot.Take(credhubClient, root)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(before.Credentials).To(HaveLen(len(written)))
+
+			By("exporting it and deleting it")
+			_, err = RunCLI(append([]string{"export", "-p", root, "-f", exportFile.Name()}, format.flag...)...)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = RunCLI("delete", "-p", root)
+			Expect(err).NotTo(HaveOccurred())
+			wiped, err := snapshot.Take(credhubClient, root)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(wiped.Credentials).To(BeEmpty())
+
+			By("importing it")
+			_, err = RunCLI(append([]string{"import", "-f", exportFile.Name()}, format.flag...)...)
+			Expect(err).NotTo(HaveOccurred())
+			after, err := snapshot.Take(credhubClient, root)
+			Expect(err).NotTo(HaveOccurred())
+
+			differences := roundtrip.Compare(before, after)
+			lost := roundtrip.Lost(differences)
+			AddReportEntry("lost in the round trip", roundtrip.FormatLost(lost))
+
+			unexpected := roundtrip.Unexpected(differences, roundtrip.Loses()...)
+			Expect(unexpected).To(BeEmpty(), "the round trip changed:\n%s", snapshot.Format(unexpected))
+
+			// The corpus always has history and a transitional CA, so the report
+			// must say they were lost.
+			Expect(lost).To(HaveKey(snapshot.VersionCount))
+			Expect(lost).To(HaveKey(snapshot.Transitional))
+		})
+	}
+})
@@ -0,0 +1,65 @@
+package conformance_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/conformance"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/history"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// historyVersions is how many versions each history spec writes.
+const historyVersions = 5
+
+var _ = Describe("Version history", func() {
+	sources := []struct {
+		name   string
+		source func() history.Source
+	}{
+		{"GetAllVersions", func() history.Source { return history.GoClient{Client: credhubClient} }},
+		{"get --versions", func() history.Source { return history.CLI{Run: RunCLI} }},
+	}
+
+	for _, d := range drivers {
+		d := d
+		for _, t := range conformance.Types {
+			t := t
+
+			It(fmt.Sprintf("keeps every %s version written through %s, and deletes them all", t.Name, d.name), func() {
+				driver := d.driver()
+				name := fmt.Sprintf("/history/%d/%s/%s", time.Now().UnixNano(), t.Name, GenerateUniqueCredentialName())
+				DeferCleanup(func() { driver.Delete(name) })
+
+				ids := make([]string, historyVersions)
+				for n := 1; n <= historyVersions; n++ {
+					value, err := t.Value(n)
+					Expect(err).NotTo(HaveOccurred())
+					credential, err := driver.Set(name, t, value, nil)
+					Expect(err).NotTo(HaveOccurred())
+					ids[historyVersions-n] = credential.Id
+				}
+
+				for _, s := range sources {
+					source := s.source()
+					Expect(history.Check(source, name)).To(Succeed(), "through %s", s.name)
+
+					all, err := source.All(name)
+					Expect(err).NotTo(HaveOccurred())
+					got := make([]string, len(all))
+					for i, version := range all {
+						got[i] = version.Id
+					}
+					Expect(got).To(Equal(ids), "through %s", s.name)
+				}
+
+				Expect(driver.Delete(name)).To(Succeed())
+				for _, s := range sources {
+					Expect(history.CheckDeleted(s.source(), name)).To(Succeed(), "through %s", s.name)
+				}
+			})
+		}
+	}
+})
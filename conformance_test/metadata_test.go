@@ -0,0 +1,375 @@
+package conformance_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials/generate"
+
+	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/apierrors"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/certificates"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/certs"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/conformance"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/metadata"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const importWithMetadata = "../test_helpers/bulk_import_set_with_metadata.yml"
+
+// regenerateWithoutMetadata is what regenerating without metadata does
+// through each driver: the CLI sends the latest version's metadata itself,
+// and the Go client sends none, which CredHub takes as none.
+var regenerateWithoutMetadata = map[string]metadata.Outcome{
+	"the CLI":       metadata.Preserved,
+	"the Go client": metadata.Cleared,
+}
+
+// certificateWritesWithoutMetadata is what the certificate writes that never
+// send metadata do. CredHub gives the version each creates the metadata of
+// the request, as it does for the Go client's regenerate, so all of them
+// clear it.
+var certificateWritesWithoutMetadata = map[string]metadata.Outcome{
+	"bulk regenerate":                     metadata.Cleared,
+	"/api/v1/certificates/:id/versions":   metadata.Cleared,
+	"/api/v1/certificates/:id/regenerate": metadata.Cleared,
+}
+
+var _ = Describe("Metadata", func() {
+	var root string
+
+	BeforeEach(func() {
+		supported, err := serverSupportsMetadata()
+		Expect(err).NotTo(HaveOccurred())
+		if !supported {
+			Skip("Server does not support metadata")
+		}
+		root = fmt.Sprintf("/metadata/%d/%s", time.Now().UnixNano(), GenerateUniqueCredentialName())
+	})
+
+	// checkLatest checks the latest version of name through the Go client.
+	checkLatest := func(name string, outcome metadata.Outcome, previous, sent credentials.Metadata) credentials.Credential {
+		credential, err := credhubClient.GetLatestVersion(name)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(metadata.Check(outcome, previous, sent, credential.Metadata)).To(Succeed(), "the latest version of %s", name)
+		return credential
+	}
+
+	versions := func(name string) []credentials.Credential {
+		all, err := credhubClient.GetAllVersions(name)
+		Expect(err).NotTo(HaveOccurred())
+		return all
+	}
+
+	for _, d := range drivers {
+		d := d
+
+		Describe("through "+d.name, func() {
+			var driver conformance.Driver
+
+			BeforeEach(func() {
+				driver = d.driver()
+			})
+
+			for _, t := range conformance.Types {
+				t := t
+
+				Describe("on "+t.Name+" credentials", func() {
+					var name string
+
+					BeforeEach(func() {
+						name = root + "/" + t.Name
+						DeferCleanup(func() { credhubClient.Delete(name) })
+					})
+
+					for _, fixture := range metadata.Fixtures {
+						fixture := fixture
+
+						It("stores "+fixture.Label+" metadata when setting", func() {
+							v, err := t.Value(1)
+							Expect(err).NotTo(HaveOccurred())
+							credential, err := driver.Set(name, t, v, fixture.Metadata)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(metadata.Check(metadata.Replaced, nil, fixture.Metadata, credential.Metadata)).To(Succeed())
+
+							got, err := driver.Get(name)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(metadata.Check(metadata.Replaced, nil, fixture.Metadata, got.Metadata)).To(Succeed())
+							checkLatest(name, metadata.Replaced, nil, fixture.Metadata)
+						})
+
+						if t.Generatable {
+							It("stores "+fixture.Label+" metadata when generating", func() {
+								credential, err := driver.Generate(name, t, credhub.Overwrite, fixture.Metadata)
+								Expect(err).NotTo(HaveOccurred())
+								Expect(metadata.Check(metadata.Replaced, nil, fixture.Metadata, credential.Metadata)).To(Succeed())
+								checkLatest(name, metadata.Replaced, nil, fixture.Metadata)
+							})
+						}
+					}
+
+					It("clears metadata when a new version is set without any, and keeps it on the old version", func() {
+						v, err := t.Value(1)
+						Expect(err).NotTo(HaveOccurred())
+						_, err = driver.Set(name, t, v, metadata.Flat())
+						Expect(err).NotTo(HaveOccurred())
+						before := versions(name)
+
+						v, err = t.Value(2)
+						Expect(err).NotTo(HaveOccurred())
+						credential, err := driver.Set(name, t, v, nil)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(metadata.Check(metadata.Cleared, metadata.Flat(), nil, credential.Metadata)).To(Succeed())
+						Expect(metadata.Unchanged(before, versions(name))).To(Succeed())
+					})
+
+					if !t.Generatable {
+						return
+					}
+
+					Context("when regenerating", func() {
+						var previous credentials.Metadata
+						var before []credentials.Credential
+
+						BeforeEach(func() {
+							previous = metadata.Nested(metadata.NestedDepth)
+							_, err := driver.Generate(name, t, credhub.Overwrite, previous)
+							Expect(err).NotTo(HaveOccurred())
+							before = versions(name)
+						})
+
+						It("replaces metadata with what is sent", func() {
+							sent := metadata.Flat()
+							credential, err := driver.Regenerate(name, sent)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(metadata.Check(metadata.Replaced, previous, sent, credential.Metadata)).To(Succeed())
+							checkLatest(name, metadata.Replaced, previous, sent)
+							Expect(metadata.Unchanged(before, versions(name))).To(Succeed())
+						})
+
+						It(fmt.Sprintf("leaves metadata %s when none is sent", regenerateWithoutMetadata[d.name]), func() {
+							outcome := regenerateWithoutMetadata[d.name]
+							credential, err := driver.Regenerate(name, nil)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(metadata.Check(outcome, previous, nil, credential.Metadata)).To(Succeed())
+							checkLatest(name, outcome, previous, nil)
+							Expect(metadata.Unchanged(before, versions(name))).To(Succeed())
+						})
+					})
+				})
+			}
+		})
+	}
+
+	Describe("importing "+importWithMetadata, func() {
+		var imported map[string]credentials.Metadata
+
+		BeforeEach(func() {
+			var err error
+			imported, err = metadata.Imported(importWithMetadata)
+			Expect(err).NotTo(HaveOccurred())
+
+			// The file signs a certificate with /ca-certificate.
+			_, err = RunCLI("set", "-n", "/ca-certificate", "-t", "certificate", "-c", VALID_CERTIFICATE_CA)
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(func() {
+				credhubClient.Delete("/ca-certificate")
+				for name := range imported {
+					credhubClient.Delete(name)
+				}
+			})
+		})
+
+		It("stores each credential's metadata as the file has it", func() {
+			_, err := RunCLI("import", "-f", importWithMetadata)
+			Expect(err).NotTo(HaveOccurred())
+
+			for name, want := range imported {
+				checkLatest(name, metadata.Replaced, nil, want)
+
+				for _, d := range drivers {
+					credential, err := d.driver().Get(name)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(metadata.Check(metadata.Replaced, nil, want, credential.Metadata)).To(Succeed(), "%s through %s", name, d.name)
+				}
+			}
+		})
+	})
+
+	Describe("certificates", func() {
+		var (
+			caName, certName   string
+			caMetadata, sent   credentials.Metadata
+			certificatesClient *certificates.Client
+		)
+
+		generateCertificate := func(name string, parameters generate.Certificate, m credentials.Metadata) {
+			_, err := credhubClient.GenerateCredential(name, "certificate", parameters, credhub.Overwrite, func(options *credhub.GenerateOptions) error {
+				options.Metadata = m
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			caName = root + "/ca"
+			certName = root + "/leaf"
+			caMetadata = credentials.Metadata{"role": "ca"}
+			sent = metadata.Nested(3)
+			certificatesClient = certificates.New(certificates.HTTP{Client: credhubClient})
+
+			generateCertificate(caName, generate.Certificate{CommonName: "metadata-ca", IsCA: true}, caMetadata)
+			generateCertificate(certName, generate.Certificate{CommonName: "metadata-leaf", Ca: caName}, sent)
+			DeferCleanup(func() {
+				credhubClient.Delete(certName)
+				credhubClient.Delete(caName)
+			})
+		})
+
+		bulkRegenerators := []struct {
+			name       string
+			regenerate func(signedBy string) error
+		}{
+			{"the CLI", func(signedBy string) error {
+				_, err := RunCLI("bulk-regenerate", "--signed-by", signedBy)
+				return err
+			}},
+			{"the Go client", func(signedBy string) error {
+				_, err := credhubClient.BulkRegenerate(signedBy)
+				return err
+			}},
+		}
+
+		for _, b := range bulkRegenerators {
+			b := b
+
+			outcome := certificateWritesWithoutMetadata["bulk regenerate"]
+			It(fmt.Sprintf("leaves metadata %s when bulk regenerating through %s", outcome, b.name), func() {
+				before := versions(certName)
+
+				Expect(b.regenerate(caName)).To(Succeed())
+
+				after := versions(certName)
+				Expect(after).To(HaveLen(len(before) + 1))
+				Expect(metadata.Check(outcome, sent, nil, after[0].Metadata)).To(Succeed())
+				Expect(metadata.Unchanged(before, after)).To(Succeed())
+				checkLatest(caName, metadata.Preserved, caMetadata, nil)
+			})
+		}
+
+		createVersion := certificateWritesWithoutMetadata["/api/v1/certificates/:id/versions"]
+		It(fmt.Sprintf("leaves metadata %s when a version is created through /api/v1/certificates", createVersion), func() {
+			certificate, err := certificatesClient.Get(certName)
+			Expect(err).NotTo(HaveOccurred())
+			certificatePEM, keyPEM, err := certs.GenerateSelfSigned(certs.CertOptions{CommonName: "metadata-version"})
+			Expect(err).NotTo(HaveOccurred())
+			before := versions(certName)
+
+			_, err = certificatesClient.CreateVersion(certificate.ID, certificates.Value{
+				Certificate: string(certificatePEM),
+				PrivateKey:  string(keyPEM),
+			}, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			checkLatest(certName, createVersion, sent, nil)
+			Expect(metadata.Unchanged(before, versions(certName))).To(Succeed())
+		})
+
+		regenerate := certificateWritesWithoutMetadata["/api/v1/certificates/:id/regenerate"]
+		It(fmt.Sprintf("leaves metadata %s when regenerating through /api/v1/certificates", regenerate), func() {
+			certificate, err := certificatesClient.Get(certName)
+			Expect(err).NotTo(HaveOccurred())
+			before := versions(certName)
+
+			_, err = certificatesClient.Regenerate(certificate.ID, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			checkLatest(certName, regenerate, sent, nil)
+			Expect(metadata.Unchanged(before, versions(certName))).To(Succeed())
+		})
+	})
+
+	Describe("finding", func() {
+		var latest map[string]credentials.Metadata
+
+		BeforeEach(func() {
+			latest = map[string]credentials.Metadata{}
+			for _, fixture := range metadata.Fixtures {
+				name := root + "/" + fixture.Label
+				_, err := credhubClient.SetCredential(name, "value", "some-value", func(options *credhub.SetOptions) error {
+					options.Metadata = fixture.Metadata
+					return nil
+				})
+				Expect(err).NotTo(HaveOccurred())
+				latest[name] = fixture.Metadata
+			}
+
+			// A credential whose latest version has no metadata, though an
+			// earlier one does.
+			cleared := root + "/cleared"
+			_, err := credhubClient.SetCredential(cleared, "value", "some-value", func(options *credhub.SetOptions) error {
+				options.Metadata = metadata.Flat()
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = credhubClient.SetCredential(cleared, "value", "other-value")
+			Expect(err).NotTo(HaveOccurred())
+			latest[cleared] = nil
+
+			DeferCleanup(func() {
+				for name := range latest {
+					credhubClient.Delete(name)
+				}
+			})
+		})
+
+		checkFound := func(body []byte) {
+			var found struct {
+				Credentials []map[string]interface{} `json:"credentials"`
+			}
+			Expect(json.Unmarshal(body, &found)).To(Succeed())
+
+			names := map[string]bool{}
+			for _, entry := range found.Credentials {
+				name, _ := entry["name"].(string)
+				Expect(latest).To(HaveKey(name))
+				Expect(metadata.FoundEntry(entry, latest[name])).To(Succeed(), name)
+				names[name] = true
+			}
+			Expect(names).To(HaveLen(len(latest)))
+		}
+
+		It("lists every credential, with only the latest version's metadata, over HTTP", func() {
+			response, err := credhubClient.Request(http.MethodGet, "/api/v1/data", url.Values{"name-like": {root}}, nil, false)
+			Expect(err).NotTo(HaveOccurred())
+			read, err := apierrors.ReadResponse(response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(read.Status).To(Equal(http.StatusOK), string(read.Body))
+			checkFound(read.Body)
+		})
+
+		It("lists every credential, with only the latest version's metadata, through the CLI", func() {
+			output, err := RunCLI("find", "-n", root, "-j")
+			Expect(err).NotTo(HaveOccurred())
+			checkFound(output)
+		})
+
+		It("finds credentials by path whatever their metadata", func() {
+			results, err := credhubClient.FindByPath(root)
+			Expect(err).NotTo(HaveOccurred())
+			var names []string
+			for _, credential := range results.Credentials {
+				names = append(names, credential.Name)
+			}
+			for name := range latest {
+				Expect(names).To(ContainElement(name))
+			}
+		})
+	})
+})
@@ -0,0 +1,81 @@
+package import_scale_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"runtime"
+	"testing"
+
+	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gexec"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/auth"
+)
+
+var (
+	homeDir       string
+	cfg           Config
+	credhubClient *credhub.CredHub
+)
+
+// Version histories are not checked after each spec, as reading every
+// version of each of the thousands of imported credentials would take longer
+// than the import being measured.
+func TestImportScale(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Import Scale Suite")
+}
+
+var _ = BeforeEach(func() {
+	var err error
+	homeDir, err = ioutil.TempDir("", "cm-test")
+	Expect(err).NotTo(HaveOccurred())
+
+	if runtime.GOOS == "windows" {
+		os.Setenv("USERPROFILE", homeDir)
+	} else {
+		os.Setenv("HOME", homeDir)
+	}
+
+	os.Unsetenv("CREDHUB_DEBUG")
+
+	cfg, err = LoadConfig()
+	Expect(err).NotTo(HaveOccurred())
+
+	TargetAndLoginWithClientCredentials(cfg)
+
+	credhub_ca, err := ioutil.ReadFile(path.Join(cfg.CredentialRoot, "server_ca_cert.pem"))
+	Expect(err).NotTo(HaveOccurred())
+
+	uaa_ca, err := ioutil.ReadFile(path.Join(cfg.UAACa))
+	Expect(err).NotTo(HaveOccurred())
+
+	// As in the perf suite, the client is not instrumented: thousands of
+	// recorded exchanges would only use up memory.
+	credhubClient, err = credhub.New(cfg.ApiUrl,
+		credhub.CaCerts(string(credhub_ca), string(uaa_ca)),
+		credhub.Auth(
+			auth.UaaClientCredentials(cfg.ClientName, cfg.ClientSecret),
+		),
+	)
+	Expect(err).ToNot(HaveOccurred())
+})
+
+var _ = AfterEach(func() {
+	CleanEnv()
+	os.RemoveAll(homeDir)
+})
+
+var _ = BeforeSuite(func() {
+	var err error
+	CommandPath, err = Build("code.cloudfoundry.org/credhub-cli", "-mod=mod")
+	Expect(err).NotTo(HaveOccurred())
+})
+
+var _ = AfterSuite(func() {
+	CleanupBuildArtifacts()
+})
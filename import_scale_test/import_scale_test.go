@@ -0,0 +1,58 @@
+package import_scale_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/bulkimport"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// invalidEvery is how many entries there are for each one CredHub must
+// reject.
+const invalidEvery = 100
+
+var _ = Describe("Importing thousands of credentials", func() {
+	for _, size := range []int{1000, 10000} {
+		size := size
+
+		It(fmt.Sprintf("imports %d entries and reports the result of each", size), func() {
+			root := fmt.Sprintf("/import-scale/%d/%s", time.Now().UnixNano(), GenerateUniqueCredentialName())
+			DeferCleanup(func() { RunCLI("delete", "-p", root) })
+
+			entries, err := bulkimport.Generate(rand.New(rand.NewSource(GinkgoRandomSeed())), bulkimport.Options{
+				Root: root, Count: size, Invalid: size / invalidEvery, Order: bulkimport.ReverseOrder,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			importFile, err := ioutil.TempFile("", "import-scale")
+			Expect(err).NotTo(HaveOccurred())
+			importFile.Close()
+			DeferCleanup(func() { os.Remove(importFile.Name()) })
+			Expect(bulkimport.WriteFile(importFile.Name(), entries)).To(Succeed())
+
+			start := time.Now()
+			session := RunCommand("import", "-f", importFile.Name())
+			took := time.Since(start)
+			AddReportEntry(fmt.Sprintf("import of %d entries", size), fmt.Sprintf("%s, %.1f entries a second",
+				took.Round(time.Millisecond), float64(size)/took.Seconds()))
+			Expect(session.ExitCode()).To(Equal(1))
+
+			summary, err := bulkimport.ParseSummary(session.Out.Contents())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bulkimport.Check(entries, summary)).To(Succeed())
+
+			found, err := credhubClient.FindByPath(root)
+			Expect(err).NotTo(HaveOccurred())
+			names := make([]string, len(found.Credentials))
+			for i, credential := range found.Credentials {
+				names[i] = credential.Name
+			}
+			Expect(bulkimport.Stored(entries, names)).To(Succeed())
+		})
+	}
+})
@@ -10,8 +10,11 @@ import (
 	"os/exec"
 	"regexp"
 	"strings"
+	"time"
 
 	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/certificates"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/expiry"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gbytes"
@@ -19,58 +22,45 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-type getCertificatesResponse struct {
-	Certificates []struct {
-		Id       string               `json:"id"`
-		Name     string               `json:"name"`
-		Versions []certificateVersion `json:"versions"`
-		SignedBy string               `json:"signed_by"`
-		Signs    []string             `json:"signs"`
-	} `json:"certificates"`
-}
+var _ = Describe("Certificates Test", func() {
+	var certificatesClient *certificates.Client
 
-type certificateVersion struct {
-	Id           string `json:"id"`
-	ExpiryDate   string `json:"expiry_date"`
-	Transitional bool   `json:"transitional"`
-	Generated    bool   `json:"generated"`
-}
+	BeforeEach(func() {
+		certificatesClient = CertificatesClient()
+	})
 
-type getCertificateVersionsResponse []struct {
-	Id               string                  `json:"id"`
-	Name             string                  `json:"name"`
-	ExpiryDate       string                  `json:"expiry_date"`
-	Transitional     bool                    `json:"transitional"`
-	Type             string                  `json:"type"`
-	VersionCreatedAt string                  `json:"version_created_at"`
-	Value            certificateVersionValue `json:"value"`
-}
+	getCertificate := func(name string) certificates.Certificate {
+		certificate, err := certificatesClient.Get(name)
+		Expect(err).NotTo(HaveOccurred())
+		return certificate
+	}
 
-type certificateVersionValue struct {
-	Ca          string `json:"ca"`
-	Certificate string `json:"certificate"`
-	PrivateKey  string `json:"private_key"`
-}
+	getVersions := func(id string) []certificates.Version {
+		versions, err := certificatesClient.Versions(id)
+		Expect(err).NotTo(HaveOccurred())
+		return versions
+	}
+
+	regenerate := func(id string, transitional bool) {
+		_, err := certificatesClient.Regenerate(id, transitional)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	setTransitional := func(id, versionID string) {
+		_, err := certificatesClient.SetTransitional(id, versionID)
+		Expect(err).NotTo(HaveOccurred())
+	}
 
-var _ = Describe("Certificates Test", func() {
 	Describe("getting a certificate", func() {
 		It("should get a certificate with versions", func() {
 			certName := "/" + GenerateUniqueCredentialName()
 			RunCommand("generate", "-n", certName, "-t", "certificate", "-c", certName, "--is-ca", "--self-sign")
 
-			session := RunCommand("curl", "-X", "GET", "-p", "api/v1/certificates?name="+certName)
-			Eventually(session).Should(Exit(0))
-
-			var parsedJson getCertificatesResponse
-
-			err := json.Unmarshal(session.Out.Contents(), &parsedJson)
-			Expect(err).ToNot(HaveOccurred())
-
-			Expect(parsedJson.Certificates).To(HaveLen(1))
-			Expect(parsedJson.Certificates[0].Versions).To(HaveLen(1))
-			Expect(parsedJson.Certificates[0].Versions[0].Id).ToNot(BeEmpty())
-			Expect(parsedJson.Certificates[0].Versions[0].ExpiryDate).ToNot(BeEmpty())
-			Expect(parsedJson.Certificates[0].Versions[0].Transitional).To(BeFalse())
+			certificate := getCertificate(certName)
+			Expect(certificate.Versions).To(HaveLen(1))
+			Expect(certificate.Versions[0].ID).ToNot(BeEmpty())
+			Expect(certificate.Versions[0].ExpiryDate).ToNot(BeEmpty())
+			Expect(certificate.Versions[0].Transitional).To(BeFalse())
 		})
 
 		Context("when certificate is self-signed", func() {
@@ -78,16 +68,7 @@ var _ = Describe("Certificates Test", func() {
 				certName := "/" + GenerateUniqueCredentialName()
 				RunCommand("generate", "-n", certName, "-t", "certificate", "-c", certName, "--is-ca", "--self-sign")
 
-				session := RunCommand("curl", "-X", "GET", "-p", "api/v1/certificates?name="+certName)
-				Eventually(session).Should(Exit(0))
-
-				var parsedJson getCertificatesResponse
-
-				err := json.Unmarshal(session.Out.Contents(), &parsedJson)
-				Expect(err).ToNot(HaveOccurred())
-
-				Expect(parsedJson.Certificates).To(HaveLen(1))
-				Expect(parsedJson.Certificates[0].SignedBy).To(Equal(certName))
+				Expect(getCertificate(certName).SignedBy).To(Equal(certName))
 			})
 		})
 
@@ -98,16 +79,7 @@ var _ = Describe("Certificates Test", func() {
 				RunCommand("generate", "-n", caName, "-t", "certificate", "-c", caName, "--is-ca", "--self-sign")
 				RunCommand("generate", "-n", certName, "-t", "certificate", "-c", certName, "--ca", caName)
 
-				session := RunCommand("curl", "-X", "GET", "-p", "api/v1/certificates?name="+certName)
-				Eventually(session).Should(Exit(0))
-
-				var parsedJson getCertificatesResponse
-
-				err := json.Unmarshal(session.Out.Contents(), &parsedJson)
-				Expect(err).ToNot(HaveOccurred())
-
-				Expect(parsedJson.Certificates).To(HaveLen(1))
-				Expect(parsedJson.Certificates[0].SignedBy).To(Equal(caName))
+				Expect(getCertificate(certName).SignedBy).To(Equal(caName))
 			})
 		})
 
@@ -116,16 +88,7 @@ var _ = Describe("Certificates Test", func() {
 				certName := "/" + GenerateUniqueCredentialName()
 				RunCommand("set", "-n", certName, "-t", "certificate", "-c", VALID_CERTIFICATE_CA)
 
-				session := RunCommand("curl", "-X", "GET", "-p", "api/v1/certificates?name="+certName)
-				Eventually(session).Should(Exit(0))
-
-				var parsedJson getCertificatesResponse
-
-				err := json.Unmarshal(session.Out.Contents(), &parsedJson)
-				Expect(err).ToNot(HaveOccurred())
-
-				Expect(parsedJson.Certificates).To(HaveLen(1))
-				Expect(parsedJson.Certificates[0].SignedBy).To(Equal(certName))
+				Expect(getCertificate(certName).SignedBy).To(Equal(certName))
 			})
 
 			Context("when a certificate signs other certificates", func() {
@@ -141,16 +104,9 @@ var _ = Describe("Certificates Test", func() {
 					RunCommand("generate", "-n", leafName1, "-t", "certificate", "-c", leafName1, "--ca", intermediateName1)
 					RunCommand("generate", "-n", leafName2, "-t", "certificate", "-c", leafName2, "--ca", intermediateName2)
 
-					session := RunCommand("curl", "-X", "GET", "-p", "api/v1/certificates?name="+caName)
-					Eventually(session).Should(Exit(0))
-
-					var parsedJson getCertificatesResponse
-
-					err := json.Unmarshal(session.Out.Contents(), &parsedJson)
-					Expect(err).ToNot(HaveOccurred())
-
-					Expect(parsedJson.Certificates).To(HaveLen(1))
-					Expect(parsedJson.Certificates[0].Signs).To(ConsistOf(intermediateName1, intermediateName2))
+					signs, err := certificatesClient.Signs(caName)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(signs).To(ConsistOf(intermediateName1, intermediateName2))
 				})
 			})
 		})
@@ -160,16 +116,9 @@ var _ = Describe("Certificates Test", func() {
 				certName := "/" + GenerateUniqueCredentialName()
 				RunCommand("set", "-n", certName, "-t", "certificate", "-c", VALID_CERTIFICATE)
 
-				session := RunCommand("curl", "-X", "GET", "-p", "api/v1/certificates?name="+certName)
-				Eventually(session).Should(Exit(0))
-
-				var parsedJson getCertificatesResponse
-
-				err := json.Unmarshal(session.Out.Contents(), &parsedJson)
-				Expect(err).ToNot(HaveOccurred())
-
-				Expect(parsedJson.Certificates).To(HaveLen(1))
-				Expect(parsedJson.Certificates[0].SignedBy).To(BeEmpty())
+				signedBy, err := certificatesClient.SignedBy(certName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(signedBy).To(BeEmpty())
 			})
 
 			Context("when intermediate ca is set with a ca not in credhub", func() {
@@ -177,16 +126,9 @@ var _ = Describe("Certificates Test", func() {
 					certName := "/" + GenerateUniqueCredentialName()
 					RunCommand("set", "-n", certName, "-t", "certificate", "-c", VALID_INTERMEDIATE_CA, "-r", VALID_INTERMEDIATE_CA_ROOT_CA)
 
-					session := RunCommand("curl", "-X", "GET", "-p", "api/v1/certificates?name="+certName)
-					Eventually(session).Should(Exit(0))
-
-					var parsedJson getCertificatesResponse
-
-					err := json.Unmarshal(session.Out.Contents(), &parsedJson)
-					Expect(err).ToNot(HaveOccurred())
-
-					Expect(parsedJson.Certificates).To(HaveLen(1))
-					Expect(parsedJson.Certificates[0].SignedBy).To(BeEmpty())
+					signedBy, err := certificatesClient.SignedBy(certName)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(signedBy).To(BeEmpty())
 				})
 			})
 		})
@@ -202,29 +144,25 @@ var _ = Describe("Certificates Test", func() {
 			RunCommand("generate", "-n", cert2Name, "-t", "certificate", "-c", cert2Name, "--is-ca", "--self-sign")
 			RunCommand("generate", "-n", cert2Name, "-t", "certificate", "-c", cert2Name, "--is-ca", "--self-sign")
 
-			session := RunCommand("curl", "-X", "GET", "-p", "api/v1/certificates")
-			Eventually(session).Should(Exit(0))
-
-			var parsedJson getCertificatesResponse
-			err := json.Unmarshal(session.Out.Contents(), &parsedJson)
+			all, err := certificatesClient.List()
 			Expect(err).ToNot(HaveOccurred())
 
-			cert1Versions := findCert(cert1Name, parsedJson)
-			Expect(cert1Versions).NotTo(BeNil())
-
-			cert2Versions := findCert(cert2Name, parsedJson)
-			Expect(cert2Versions).NotTo(BeNil())
-
-			Expect(cert1Versions).To(HaveLen(3))
-			Expect(cert1Versions[0].Id).ToNot(BeEmpty())
-			Expect(cert1Versions[0].ExpiryDate).ToNot(BeEmpty())
-			Expect(cert1Versions[0].Transitional).To(BeFalse())
-			Expect(cert1Versions[0].Generated).To(BeTrue())
-			Expect(cert1Versions[2].Generated).To(BeFalse())
-			Expect(cert2Versions).To(HaveLen(2))
-			Expect(cert2Versions[0].Id).ToNot(BeEmpty())
-			Expect(cert2Versions[0].ExpiryDate).ToNot(BeEmpty())
-			Expect(cert2Versions[0].Transitional).To(BeFalse())
+			cert1, ok := certificates.Find(all, cert1Name)
+			Expect(ok).To(BeTrue())
+
+			cert2, ok := certificates.Find(all, cert2Name)
+			Expect(ok).To(BeTrue())
+
+			Expect(cert1.Versions).To(HaveLen(3))
+			Expect(cert1.Versions[0].ID).ToNot(BeEmpty())
+			Expect(cert1.Versions[0].ExpiryDate).ToNot(BeEmpty())
+			Expect(cert1.Versions[0].Transitional).To(BeFalse())
+			Expect(cert1.Versions[0].Generated).To(BeTrue())
+			Expect(cert1.Versions[2].Generated).To(BeFalse())
+			Expect(cert2.Versions).To(HaveLen(2))
+			Expect(cert2.Versions[0].ID).ToNot(BeEmpty())
+			Expect(cert2.Versions[0].ExpiryDate).ToNot(BeEmpty())
+			Expect(cert2.Versions[0].Transitional).To(BeFalse())
 		})
 	})
 
@@ -232,9 +170,16 @@ var _ = Describe("Certificates Test", func() {
 		var certificateName string
 
 		BeforeEach(func() {
-			certificateName = "/" + GenerateUniqueCredentialName()
-			RunCommand("generate", "-n", certificateName, "-t", "certificate", "-d", "15", "-c", certificateName, "--is-ca", "--self-sign")
-			RunCommand("generate", "-n", certificateName, "-t", "certificate", "-d", "32", "-c", certificateName, "--is-ca", "--self-sign")
+			fixtures, err := expiry.Generate("/", time.Now(), []expiry.Bucket{
+				{Name: GenerateUniqueCredentialName(), ExpiresIn: 32 * 24 * time.Hour, Older: []time.Duration{15 * 24 * time.Hour}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			certificateName = fixtures[0].Name
+			for _, version := range fixtures[0].Versions {
+				session := RunCommand("set", "-n", certificateName, "-t", "certificate", "-c", version.Certificate, "-p", version.PrivateKey)
+				Expect(session).To(Exit(0))
+			}
 		})
 
 		AfterEach(func() {
@@ -457,55 +402,32 @@ var _ = Describe("Certificates Test", func() {
 				session = RunCommand("generate", "-n", certName, "-t", "certificate", "-c", certName, "--ca", caName)
 				Expect(session).To(Exit(0))
 
-				session = RunCommand("curl", "-p", fmt.Sprintf("/api/v1/certificates?name=%s", caName))
-				Expect(session).To(Exit(0))
-
-				var response getCertificatesResponse
-				err := json.Unmarshal(session.Out.Contents(), &response)
-				Expect(err).NotTo(HaveOccurred())
-
-				session = RunCommand("curl",
-					"-p", fmt.Sprintf("/api/v1/certificates/%s/regenerate", response.Certificates[0].Id),
-					"-X", "POST",
-					"-d", `{"set_as_transitional": "true"}`,
-				)
-				Expect(session).To(Exit(0))
+				regenerate(getCertificate(caName).ID, true)
 
 				session = RunCommand("get", "-n", certName, "-k", "ca")
 				Expect(session).To(Exit(0))
 				stdOut := string(session.Out.Contents())
 
 				re := regexp.MustCompile("BEGIN CERTIFICATE")
-				certificates := re.FindAllString(stdOut, -1)
+				matches := re.FindAllString(stdOut, -1)
 				if cfg.ConcatenateCas {
-					Expect(certificates).To(HaveLen(2))
+					Expect(matches).To(HaveLen(2))
 				} else {
-					Expect(certificates).To(HaveLen(1))
+					Expect(matches).To(HaveLen(1))
 				}
 
-				session = RunCommand("curl", "-p", fmt.Sprintf("/api/v1/certificates?name=%s", certName))
-				Expect(session).To(Exit(0))
-				err = json.Unmarshal(session.Out.Contents(), &response)
-				Expect(err).NotTo(HaveOccurred())
-
-				var versionResponse getCertificateVersionsResponse
-				session = RunCommand("curl", "-p", fmt.Sprintf("/api/v1/certificates/%s/versions", response.Certificates[0].Id))
-				Expect(session).To(Exit(0))
-				err = json.Unmarshal(session.Out.Contents(), &versionResponse)
-				Expect(err).NotTo(HaveOccurred())
-
-				ca := versionResponse[0].Value.Ca
-				certificates = re.FindAllString(ca, -1)
+				cert := getCertificate(certName)
+				cas := getVersions(cert.ID)[0].Value.CAs()
 				if cfg.ConcatenateCas {
-					Expect(certificates).To(HaveLen(2))
+					Expect(cas).To(HaveLen(2))
 				} else {
-					Expect(certificates).To(HaveLen(1))
+					Expect(cas).To(HaveLen(1))
 				}
 
 				var certificate Certificate
-				session = RunCommand("get", "--id", response.Certificates[0].Versions[0].Id, "-j")
+				session = RunCommand("get", "--id", cert.Versions[0].ID, "-j")
 				Expect(session).To(Exit(0))
-				err = json.Unmarshal(session.Out.Contents(), &certificate)
+				err := json.Unmarshal(session.Out.Contents(), &certificate)
 				Expect(err).NotTo(HaveOccurred())
 
 				if cfg.ConcatenateCas {
@@ -524,62 +446,29 @@ var _ = Describe("Certificates Test", func() {
 				session = RunCommand("generate", "-n", certName, "-t", "certificate", "-c", certName, "--ca", caName)
 				Expect(session).To(Exit(0))
 
-				session = RunCommand("curl", "-p", fmt.Sprintf("/api/v1/certificates?name=%s", caName))
-				Expect(session).To(Exit(0))
-
-				var response getCertificatesResponse
-				err := json.Unmarshal(session.Out.Contents(), &response)
-				Expect(err).NotTo(HaveOccurred())
-
-				caId := response.Certificates[0].Id
-
-				session = RunCommand("curl", "-p", fmt.Sprintf("/api/v1/certificates?name=%s", certName))
-				Expect(session).To(Exit(0))
-				err = json.Unmarshal(session.Out.Contents(), &response)
-				Expect(err).NotTo(HaveOccurred())
-
-				certId := response.Certificates[0].Id
-
-				var versionResponse getCertificateVersionsResponse
-				session = RunCommand("curl", "-p", fmt.Sprintf("/api/v1/certificates/%s/versions", certId))
-				Expect(session).To(Exit(0))
-				err = json.Unmarshal(session.Out.Contents(), &versionResponse)
-				Expect(err).NotTo(HaveOccurred())
+				caId := getCertificate(caName).ID
+				certId := getCertificate(certName).ID
+				numVersions := len(getVersions(certId))
 
-				numVersions := len(versionResponse)
-
-				session = RunCommand("curl",
-					"-p", fmt.Sprintf("/api/v1/certificates/%s/regenerate", caId),
-					"-X", "POST",
-					"-d", `{"set_as_transitional": "true"}`,
-				)
-				Expect(session).To(Exit(0))
-
-				session = RunCommand("curl", "-p", fmt.Sprintf("/api/v1/certificates/%s/versions", certId))
-				Expect(session).To(Exit(0))
-				err = json.Unmarshal(session.Out.Contents(), &versionResponse)
-				Expect(err).NotTo(HaveOccurred())
+				regenerate(caId, true)
 
 				if cfg.ConcatenateCas {
-					Expect(len(versionResponse)).To(Equal(numVersions + 1))
+					Expect(getVersions(certId)).To(HaveLen(numVersions + 1))
 				} else {
-					Expect(len(versionResponse)).To(Equal(numVersions))
+					Expect(getVersions(certId)).To(HaveLen(numVersions))
 				}
 			})
 
 			Context("certificate rotation", func() {
 				var (
-					caName            string
-					certName          string
-					caResponse        getCertificatesResponse
-					certResponse      getCertificatesResponse
-					certId            string
-					caId              string
-					caVersionResponse getCertificateVersionsResponse
-					oldCaVersionId    string
-					oldCaVersion      string
-					newCaVersionId    string
-					newCaVersion      string
+					caName         string
+					certName       string
+					certId         string
+					caId           string
+					oldCaVersionId string
+					oldCaVersion   string
+					newCaVersionId string
+					newCaVersion   string
 				)
 
 				BeforeEach(func() {
@@ -592,206 +481,95 @@ var _ = Describe("Certificates Test", func() {
 					session = RunCommand("generate", "-n", certName, "-t", "certificate", "-c", certName, "--ca", caName)
 					Expect(session).To(Exit(0))
 
-					session = RunCommand("curl", "-p", fmt.Sprintf("/api/v1/certificates?name=%s", caName))
-					Expect(session).To(Exit(0))
+					caId = getCertificate(caName).ID
+					certId = getCertificate(certName).ID
 
-					err := json.Unmarshal(session.Out.Contents(), &caResponse)
-					Expect(err).NotTo(HaveOccurred())
+					caVersions := getVersions(caId)
+					oldCaVersionId = caVersions[0].ID
+					oldCaVersion = caVersions[0].Value.CAs()[0]
 
-					caId = caResponse.Certificates[0].Id
+					regenerate(caId, false)
 
-					session = RunCommand("curl", "-p", fmt.Sprintf("/api/v1/certificates?name=%s", certName))
-					Expect(session).To(Exit(0))
-					err = json.Unmarshal(session.Out.Contents(), &certResponse)
-					Expect(err).NotTo(HaveOccurred())
-
-					certId = certResponse.Certificates[0].Id
-
-					session = RunCommand("curl", "-p", fmt.Sprintf("/api/v1/certificates/%s/versions", caId))
-					Expect(session).To(Exit(0))
-					err = json.Unmarshal(session.Out.Contents(), &caVersionResponse)
-					Expect(err).NotTo(HaveOccurred())
-
-					oldCaVersionId = caVersionResponse[0].Id
-					oldCaVersion = caVersionResponse[0].Value.Ca
-
-					session = RunCommand("curl",
-						"-p", fmt.Sprintf("/api/v1/certificates/%s/regenerate", caId),
-						"-X", "POST",
-						"-d", `{"set_as_transitional": "false"}`,
-					)
-					Expect(session).To(Exit(0))
-
-					session = RunCommand("curl", "-p", fmt.Sprintf("/api/v1/certificates/%s/versions", caId))
-					Expect(session).To(Exit(0))
-					err = json.Unmarshal(session.Out.Contents(), &caVersionResponse)
-					Expect(err).NotTo(HaveOccurred())
-
-					if caVersionResponse[0].Value.Ca != oldCaVersion {
-						newCaVersion = caVersionResponse[0].Value.Ca
-						newCaVersionId = caVersionResponse[0].Id
+					caVersions = getVersions(caId)
+					if caVersions[0].Value.CAs()[0] != oldCaVersion {
+						newCaVersion = caVersions[0].Value.CAs()[0]
+						newCaVersionId = caVersions[0].ID
 					} else {
-						newCaVersion = caVersionResponse[1].Value.Ca
-						newCaVersionId = caVersionResponse[1].Id
+						newCaVersion = caVersions[1].Value.CAs()[0]
+						newCaVersionId = caVersions[1].ID
 					}
-
 				})
 
 				Context("regenerating ca without setting as transitional", func() {
 					It("should not create a new version of the child cert", func() {
-						session := RunCommand("curl",
-							"-p", fmt.Sprintf("/api/v1/certificates/%s/regenerate", caId),
-							"-X", "POST",
-							"-d", `{"set_as_transitional": "false"}`,
-						)
-						Expect(session).To(Exit(0))
-
-						var certVersionResponse getCertificateVersionsResponse
-						session = RunCommand("curl", "-p", fmt.Sprintf("/api/v1/certificates/%s/versions", certId))
-						Expect(session).To(Exit(0))
-						err := json.Unmarshal(session.Out.Contents(), &certVersionResponse)
-						Expect(err).NotTo(HaveOccurred())
+						regenerate(caId, false)
 
-						var numCertVersions = len(certVersionResponse)
-						Expect(numCertVersions).To(Equal(1))
+						Expect(getVersions(certId)).To(HaveLen(1))
 					})
 				})
 				Context("setting non-signing ca as transitional", func() {
 					It("should create new child cert version and concatenate transitional version after signing version", func() {
-						session := RunCommand("curl",
-							"-p", fmt.Sprintf("/api/v1/certificates/%s/update_transitional_version", caId),
-							"-X", "PUT",
-							"-d", fmt.Sprintf(`{"version": "%s"}`, newCaVersionId),
-						)
-						Expect(session).To(Exit(0))
-
-						var certVersionResponse getCertificateVersionsResponse
-						session = RunCommand("curl", "-p", fmt.Sprintf("/api/v1/certificates/%s/versions", certId))
-						Expect(session).To(Exit(0))
-						err := json.Unmarshal(session.Out.Contents(), &certVersionResponse)
-						Expect(err).NotTo(HaveOccurred())
-
-						cas := certVersionResponse[0].Value.Ca
+						setTransitional(caId, newCaVersionId)
 
-						caArray := strings.SplitAfter(cas, "-----END CERTIFICATE-----\n")
-						caArray = removeEmptyValues(caArray)
+						certVersions := getVersions(certId)
+						caArray := certVersions[0].Value.CAs()
 
 						if cfg.ConcatenateCas {
-							Expect(len(certVersionResponse)).To(Equal(2))
-							Expect(len(caArray)).To(Equal(2))
-							Expect(caArray[0]).To(Equal(oldCaVersion))
-							Expect(caArray[1]).To(Equal(newCaVersion))
+							Expect(certVersions).To(HaveLen(2))
+							Expect(caArray).To(Equal([]string{oldCaVersion, newCaVersion}))
 						} else {
-							Expect(len(certVersionResponse)).To(Equal(1))
-							Expect(len(caArray)).To(Equal(1))
-							Expect(caArray[0]).To(Equal(oldCaVersion))
+							Expect(certVersions).To(HaveLen(1))
+							Expect(caArray).To(Equal([]string{oldCaVersion}))
 						}
 					})
 				})
 				Context("setting signing ca as transitional", func() {
 					It("should create new child cert version and concatenate transitional signing version before non-signing version", func() {
-						var transitionalVersionResponse getCertificateVersionsResponse
-						session := RunCommand("curl",
-							"-p", fmt.Sprintf("/api/v1/certificates/%s/update_transitional_version", caId),
-							"-X", "PUT",
-							"-d", fmt.Sprintf(`{"version": "%s"}`, oldCaVersionId),
-						)
-						Expect(session).To(Exit(0))
-						err := json.Unmarshal(session.Out.Contents(), &transitionalVersionResponse)
-						Expect(err).NotTo(HaveOccurred())
-
-						var certificateVersionResponse getCertificateVersionsResponse
-						session = RunCommand("curl", "-p", fmt.Sprintf("/api/v1/certificates/%s/versions", certId))
-						Expect(session).To(Exit(0))
-						err = json.Unmarshal(session.Out.Contents(), &certificateVersionResponse)
-						Expect(err).NotTo(HaveOccurred())
-
-						cas := certificateVersionResponse[0].Value.Ca
+						setTransitional(caId, oldCaVersionId)
 
-						caArray := strings.SplitAfter(cas, "-----END CERTIFICATE-----\n")
-						caArray = removeEmptyValues(caArray)
+						certVersions := getVersions(certId)
+						caArray := certVersions[0].Value.CAs()
 
 						if cfg.ConcatenateCas {
-							Expect(len(certificateVersionResponse)).To(Equal(2))
-							Expect(len(caArray)).To(Equal(2))
-							Expect(caArray[0]).To(Equal(oldCaVersion))
-							Expect(caArray[1]).To(Equal(newCaVersion))
+							Expect(certVersions).To(HaveLen(2))
+							Expect(caArray).To(Equal([]string{oldCaVersion, newCaVersion}))
 						} else {
-							Expect(len(certificateVersionResponse)).To(Equal(1))
-							Expect(len(caArray)).To(Equal(1))
-							Expect(caArray[0]).To(Equal(oldCaVersion))
+							Expect(certVersions).To(HaveLen(1))
+							Expect(caArray).To(Equal([]string{oldCaVersion}))
 						}
 					})
 				})
 				Context("bulk regenerating when signing ca is transitional", func() {
 					It("creates a new child version with flipped cas in the ca field", func() {
-						session := RunCommand("curl",
-							"-p", fmt.Sprintf("/api/v1/certificates/%s/update_transitional_version", caId),
-							"-X", "PUT",
-							"-d", fmt.Sprintf(`{"version": "%s"}`, oldCaVersionId),
-						)
-						Expect(session).To(Exit(0))
-
-						session = RunCommand("bulk-regenerate", "--signed-by", caName)
-						Expect(session).To(Exit(0))
+						setTransitional(caId, oldCaVersionId)
 
-						var certVersionResponse getCertificateVersionsResponse
-						session = RunCommand("curl", "-p", fmt.Sprintf("/api/v1/certificates/%s/versions", certId))
+						session := RunCommand("bulk-regenerate", "--signed-by", caName)
 						Expect(session).To(Exit(0))
-						err := json.Unmarshal(session.Out.Contents(), &certVersionResponse)
-						Expect(err).NotTo(HaveOccurred())
-
-						cas := certVersionResponse[0].Value.Ca
 
-						caArray := strings.SplitAfter(cas, "-----END CERTIFICATE-----\n")
-						caArray = removeEmptyValues(caArray)
+						certVersions := getVersions(certId)
+						caArray := certVersions[0].Value.CAs()
 
 						if cfg.ConcatenateCas {
-							Expect(len(certVersionResponse)).To(Equal(3))
-							Expect(len(caArray)).To(Equal(2))
-							Expect(caArray[0]).To(Equal(newCaVersion))
-							Expect(caArray[1]).To(Equal(oldCaVersion))
+							Expect(certVersions).To(HaveLen(3))
+							Expect(caArray).To(Equal([]string{newCaVersion, oldCaVersion}))
 						} else {
-							Expect(len(certVersionResponse)).To(Equal(2))
-							Expect(len(caArray)).To(Equal(1))
-							Expect(caArray[0]).To(Equal(newCaVersion))
+							Expect(certVersions).To(HaveLen(2))
+							Expect(caArray).To(Equal([]string{newCaVersion}))
 						}
 					})
 				})
 				Context("removing transitional flag", func() {
 					It("creates new child version with only signing ca in ca field", func() {
-						session := RunCommand("curl",
-							"-p", fmt.Sprintf("/api/v1/certificates/%s/update_transitional_version", caId),
-							"-X", "PUT",
-							"-d", fmt.Sprintf(`{"version": "%s"}`, oldCaVersionId),
-						)
-						Expect(session).To(Exit(0))
-
-						session = RunCommand("curl",
-							"-p", fmt.Sprintf("/api/v1/certificates/%s/update_transitional_version", caId),
-							"-X", "PUT",
-							"-d", `{"version": null}`,
-						)
-						Expect(session).To(Exit(0))
+						setTransitional(caId, oldCaVersionId)
+						setTransitional(caId, "")
 
-						var certVersionResponse getCertificateVersionsResponse
-						session = RunCommand("curl", "-p", fmt.Sprintf("/api/v1/certificates/%s/versions", certId))
-						Expect(session).To(Exit(0))
-						err := json.Unmarshal(session.Out.Contents(), &certVersionResponse)
-						Expect(err).NotTo(HaveOccurred())
-
-						cas := certVersionResponse[0].Value.Ca
-
-						caArray := strings.SplitAfter(cas, "-----END CERTIFICATE-----\n")
-						caArray = removeEmptyValues(caArray)
-
-						Expect(len(caArray)).To(Equal(1))
-						Expect(caArray[0]).To(Equal(oldCaVersion))
+						certVersions := getVersions(certId)
+						Expect(certVersions[0].Value.CAs()).To(Equal([]string{oldCaVersion}))
 
 						if cfg.ConcatenateCas {
-							Expect(len(certVersionResponse)).To(Equal(3))
+							Expect(certVersions).To(HaveLen(3))
 						} else {
-							Expect(len(certVersionResponse)).To(Equal(1))
+							Expect(certVersions).To(HaveLen(1))
 						}
 					})
 				})
@@ -799,41 +577,18 @@ var _ = Describe("Certificates Test", func() {
 					It("should set the transitional version as the trusted Ca", func() {
 						certName2 := "/" + GenerateUniqueCredentialName()
 
-						session := RunCommand("curl",
-							"-p", fmt.Sprintf("/api/v1/certificates/%s/update_transitional_version", caId),
-							"-X", "PUT",
-							"-d", fmt.Sprintf(`{"version": "%s"}`, newCaVersionId),
-						)
-						Expect(session).To(Exit(0))
-						session = RunCommand("generate", "-n", certName2, "-t", "certificate", "--common-name", certName2, "--ca", caName)
+						setTransitional(caId, newCaVersionId)
+						session := RunCommand("generate", "-n", certName2, "-t", "certificate", "--common-name", certName2, "--ca", caName)
 						Eventually(session).Should(Exit(0))
 
-						session = RunCommand("curl", "-p", fmt.Sprintf("/api/v1/certificates?name=%s", certName2))
-						Expect(session).To(Exit(0))
-						err := json.Unmarshal(session.Out.Contents(), &certResponse)
-						Expect(err).NotTo(HaveOccurred())
-
-						certId2 := certResponse.Certificates[0].Id
-
-						var certVersionResponse getCertificateVersionsResponse
-						session = RunCommand("curl", "-p", fmt.Sprintf("/api/v1/certificates/%s/versions", certId2))
-						Expect(session).To(Exit(0))
-						err = json.Unmarshal(session.Out.Contents(), &certVersionResponse)
-						Expect(err).NotTo(HaveOccurred())
-
-						cas := certVersionResponse[0].Value.Ca
-
-						caArray := strings.SplitAfter(cas, "-----END CERTIFICATE-----\n")
-						caArray = removeEmptyValues(caArray)
-						Expect(len(certVersionResponse)).To(Equal(1))
+						certVersions := getVersions(getCertificate(certName2).ID)
+						caArray := certVersions[0].Value.CAs()
+						Expect(certVersions).To(HaveLen(1))
 
 						if cfg.ConcatenateCas {
-							Expect(len(caArray)).To(Equal(2))
-							Expect(caArray[0]).To(Equal(oldCaVersion))
-							Expect(caArray[1]).To(Equal(newCaVersion))
+							Expect(caArray).To(Equal([]string{oldCaVersion, newCaVersion}))
 						} else {
-							Expect(len(caArray)).To(Equal(1))
-							Expect(caArray[0]).To(Equal(oldCaVersion))
+							Expect(caArray).To(Equal([]string{oldCaVersion}))
 						}
 					})
 				})
@@ -1004,15 +759,6 @@ var _ = Describe("Certificates Test", func() {
 	})
 })
 
-func findCert(certName string, certs getCertificatesResponse) []certificateVersion {
-	for _, certificate := range certs.Certificates {
-		if certName == certificate.Name {
-			return certificate.Versions
-		}
-	}
-	return nil
-}
-
 // https://golang.org/pkg/crypto/x509/#Certificate
 // prefix should be "Certificate" or "Ca"
 func CertFromPem(input string, ca bool) *x509.Certificate {
@@ -1048,12 +794,3 @@ func CertFromPem(input string, ca bool) *x509.Certificate {
 	}
 	return parsed_cert
 }
-
-func removeEmptyValues(array []string) []string {
-	for i, v := range array {
-		if &v == nil || v == "" {
-			array = append(array[:i], array[i+1:]...)
-		}
-	}
-	return array
-}
@@ -2,6 +2,7 @@ package integration_test
 
 import (
 	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/apierrors"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gexec"
@@ -15,8 +16,7 @@ var _ = Describe("Delete Test", func() {
 		Eventually(session).Should(Exit(0))
 
 		session = RunCommand("get", "-n", "/a/cred1")
-		Eventually(session).Should(Exit(1))
-		Expect(string(session.Err.Contents())).To(ContainSubstring("The request could not be completed because the credential does not exist or you do not have sufficient authorization."))
+		Expect(session).To(apierrors.MatchCLIError(apierrors.NotFound))
 	})
 
 	It("should delete credentials by path", func() {
@@ -25,6 +25,10 @@ var (
 // We look for these values in the verify-logging CI task to ensure that credentials don't leak
 const credentialValue = "FAKE-CREDENTIAL-VALUE"
 
+var _ = RegisterReporting("Integration Suite")
+var _ = RegisterContractValidation()
+var _ = RegisterHistoryChecks()
+
 func TestCommands(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Integration Suite")
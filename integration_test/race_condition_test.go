@@ -1,10 +1,10 @@
 package integration_test
 
 import (
-	"strings"
 	"time"
 
 	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/apierrors"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gexec"
@@ -79,7 +79,6 @@ var _ = Describe("Race condition tests", func() {
 	Describe("when setting one secret name for two types", func() {
 		It("should return a type mismatch error", func() {
 			rsaSecretName := GenerateUniqueCredentialName()
-			type_error := "The credential type cannot be modified. Please delete the credential if you wish to create it with a different type."
 
 			waitForSession1 := make(chan *Session)
 			waitForSession2 := make(chan *Session)
@@ -99,11 +98,7 @@ var _ = Describe("Race condition tests", func() {
 
 			Eventually(session1).Should(Exit())
 			Eventually(session2).Should(Exit())
-			out1 := string(session1.Out.Contents()) + string(session1.Err.Contents())
-			out2 := string(session2.Out.Contents()) + string(session2.Err.Contents())
-
-			errors_out := strings.Contains(out1, type_error) || strings.Contains(out2, type_error)
-			Expect(errors_out).To(BeTrue())
+			Expect([]*Session{session1, session2}).To(ContainElement(apierrors.MatchCLIError(apierrors.TypeMismatch)))
 		})
 	})
 })
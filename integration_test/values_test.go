@@ -2,6 +2,7 @@ package integration_test
 
 import (
 	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/apierrors"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gexec"
@@ -13,10 +14,7 @@ var _ = It("should set, get, and delete a new value secret", func() {
 
 	By("trying to access a secret that doesn't exist", func() {
 		session := RunCommand("get", "-n", credentialName)
-		stdErr := string(session.Err.Contents())
-
-		Expect(stdErr).To(ContainSubstring(`The request could not be completed because the credential does not exist or you do not have sufficient authorization.`))
-		Eventually(session).Should(Exit(1))
+		Expect(session).To(apierrors.MatchCLIError(apierrors.NotFound))
 	})
 
 	By("setting a new value secret", func() {
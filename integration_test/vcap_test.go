@@ -61,7 +61,7 @@ func postJSON(url string, postData string, token string) (string, int, error) {
 	tr := &http.Transport{
 		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
 	}
-	client := &http.Client{Transport: tr}
+	client := &http.Client{Transport: InstrumentTransport(tr)}
 
 	req, err := http.NewRequest("POST", url, strings.NewReader(postData))
 	req.Header.Set("Content-Type", "application/json")
@@ -0,0 +1,35 @@
+package interpolation_test
+
+import (
+	"testing"
+
+	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/actors"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var factory *actors.Factory
+
+var _ = BeforeSuite(func() {
+	config, err := LoadConfig()
+	Expect(err).NotTo(HaveOccurred())
+
+	factory, err = actors.FromConfig(config)
+	Expect(err).NotTo(HaveOccurred())
+})
+
+var _ = AfterSuite(func() {
+	if factory != nil {
+		Expect(factory.Close()).To(Succeed())
+	}
+})
+
+var _ = RegisterReporting("Interpolation Test Suite")
+var _ = RegisterContractValidation()
+var _ = RegisterHistoryChecks()
+
+func TestInterpolation(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Interpolation Test Suite")
+}
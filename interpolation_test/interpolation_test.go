@@ -0,0 +1,83 @@
+package interpolation_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/vcap"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// checkInterpolation creates the case's credentials, lets an actor that can
+// read only those under the readable path interpolate the document, and
+// compares the response with the reference interpolator.
+func checkInterpolation(build func(root string) vcap.Case) {
+	root := "/vcap/" + uuid.NewString()
+	c := build(root)
+	admin := factory.Admin()
+
+	for _, credential := range c.Credentials {
+		_, err := admin.SetCredential(credential.Name, credential.Type, credential.Value)
+		Expect(err).NotTo(HaveOccurred())
+		name := credential.Name
+		DeferCleanup(func() { admin.Delete(name) })
+	}
+
+	actor, err := factory.MTLSApp()
+	Expect(err).NotTo(HaveOccurred())
+	DeferCleanup(actor.Cleanup)
+	Expect(factory.Grant(actor, root+"/"+vcap.Readable+"/*", "read")).To(Succeed())
+
+	body, err := json.Marshal(c.Document)
+	Expect(err).NotTo(HaveOccurred())
+	expected, refErr := vcap.Interpolate(c.Document, c.Store())
+
+	response, err := actor.Client.Request(http.MethodPost, "/api/v1/interpolate", nil, json.RawMessage(body), false)
+	Expect(err).NotTo(HaveOccurred())
+	defer response.Body.Close()
+	actual, err := ioutil.ReadAll(response.Body)
+	Expect(err).NotTo(HaveOccurred())
+
+	if refErr != nil {
+		Expect(response.StatusCode).To(Equal(refErr.(*vcap.Error).Status()), "%s: %s", refErr, actual)
+		Expect(actual).NotTo(ContainSubstring("secret-"), "the error leaks a credential value")
+		return
+	}
+
+	Expect(response.StatusCode).To(Equal(http.StatusOK), string(actual))
+	want, err := json.Marshal(expected)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(actual).To(MatchJSON(want))
+}
+
+var _ = Describe("Interpolating VCAP_SERVICES", func() {
+	args := []interface{}{checkInterpolation}
+	for _, c := range vcap.Scenarios("/") {
+		name := c.Name
+		args = append(args, Entry(name, func(root string) vcap.Case {
+			for _, c := range vcap.Scenarios(root) {
+				if c.Name == name {
+					return c
+				}
+			}
+			panic("vcap: no scenario " + name)
+		}))
+	}
+	DescribeTable("matches the reference interpolator for", args...)
+
+	It("matches the reference interpolator for random documents", func() {
+		r := rand.New(rand.NewSource(GinkgoRandomSeed()))
+		for i := 0; i < 10; i++ {
+			broken := i%3 == 2
+			By(fmt.Sprintf("document %d, broken: %t", i, broken))
+			checkInterpolation(func(root string) vcap.Case {
+				return vcap.Random(r, root, 6, 6, broken)
+			})
+		}
+	})
+})
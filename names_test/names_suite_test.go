@@ -0,0 +1,109 @@
+package names_test
+
+import (
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"os"
+	"path"
+	"runtime"
+	"testing"
+
+	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/names"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gexec"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/auth"
+)
+
+var (
+	homeDir       string
+	credhubClient *credhub.CredHub
+	rawHTTP       names.HTTP
+)
+
+var _ = RegisterReporting("Names Suite")
+var _ = RegisterContractValidation()
+var _ = RegisterHistoryChecks()
+
+func TestNames(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Names Suite")
+}
+
+var _ = BeforeEach(func() {
+	var err error
+	homeDir, err = ioutil.TempDir("", "cm-test")
+	Expect(err).NotTo(HaveOccurred())
+
+	if runtime.GOOS == "windows" {
+		os.Setenv("USERPROFILE", homeDir)
+	} else {
+		os.Setenv("HOME", homeDir)
+	}
+
+	os.Unsetenv("CREDHUB_DEBUG")
+
+	config, err := LoadConfig()
+	Expect(err).NotTo(HaveOccurred())
+
+	TargetAndLoginWithClientCredentials(config)
+
+	credhub_ca, err := ioutil.ReadFile(path.Join(config.CredentialRoot, "server_ca_cert.pem"))
+	Expect(err).NotTo(HaveOccurred())
+
+	uaa_ca, err := ioutil.ReadFile(path.Join(config.UAACa))
+	Expect(err).NotTo(HaveOccurred())
+
+	credhubClient, err = credhub.New(config.ApiUrl,
+		credhub.CaCerts(string(credhub_ca), string(uaa_ca)),
+		credhub.Auth(
+			auth.UaaClientCredentials(config.ClientName, config.ClientSecret),
+		),
+	)
+	Expect(err).ToNot(HaveOccurred())
+	transport := credhubClient.Client().Transport
+	InstrumentClient(credhubClient)
+
+	oauth := credhubClient.Auth.(*auth.OAuthStrategy)
+	Expect(oauth.Login()).To(Succeed())
+	rawHTTP = names.HTTP{
+		Client: &http.Client{Transport: InstrumentTransport(transport)},
+		URL:    config.ApiUrl,
+		Token:  oauth.AccessToken(),
+	}
+})
+
+var _ = AfterEach(func() {
+	CleanEnv()
+	os.RemoveAll(homeDir)
+})
+
+var _ = SynchronizedBeforeSuite(func() []byte {
+	path, err := Build("code.cloudfoundry.org/credhub-cli", "-mod=mod")
+	Expect(err).NotTo(HaveOccurred())
+
+	return []byte(path)
+}, func(data []byte) {
+	CommandPath = string(data)
+
+	rand.Seed(GinkgoRandomSeed() + int64(GinkgoParallelNode()))
+})
+
+var _ = SynchronizedAfterSuite(func() {}, func() {
+	CleanupBuildArtifacts()
+})
+
+// clients are the ways of talking to CredHub every name is tried through.
+// They are functions as the Go client is only made in BeforeEach.
+var clients = []struct {
+	name   string
+	client func() names.Client
+}{
+	{"the CLI", func() names.Client { return names.CLI{Run: RunCLI} }},
+	{"the Go client", func() names.Client { return names.GoClient{Client: credhubClient} }},
+	{"raw HTTP", func() names.Client { return rawHTTP }},
+}
@@ -0,0 +1,76 @@
+package names_test
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/names"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// actor is granted permissions on each name. It never authenticates.
+const actor = "mtls-app:names-test-actor"
+
+var _ = Describe("Credential names", func() {
+	// check tries a name through every client, each under a root of its own
+	// of the same length, and requires them to agree.
+	check := func(build func(root string) names.Case) {
+		base := fmt.Sprintf("/names/%d", time.Now().UnixNano())
+		results := map[string]names.Result{}
+		var c names.Case
+		for i, client := range clients {
+			c = build(fmt.Sprintf("%s/%d", base, i))
+			By(fmt.Sprintf("trying %q through %s", c.Name, client.name))
+			result, err := names.RoundTrip(client.client(), c.Name, "value-"+GenerateUniqueCredentialName(), actor)
+			Expect(err).NotTo(HaveOccurred(), "%s through %s", c.Label, client.name)
+			results[client.name] = result
+		}
+		Expect(names.Consistent(c, results)).To(Succeed(), c.Label)
+	}
+
+	for _, c := range names.Cases("/") {
+		label := c.Label
+		It(fmt.Sprintf("handles %s consistently (must %s)", label, c.Outcome), func() {
+			check(func(root string) names.Case {
+				for _, c := range names.Cases(root) {
+					if c.Label == label {
+						return c
+					}
+				}
+				panic("names: no case " + label)
+			})
+		})
+	}
+
+	for _, c := range names.Cases("/") {
+		label := c.Label
+		It(fmt.Sprintf("decodes %s in query strings once", label), func() {
+			root := fmt.Sprintf("/names/%d", time.Now().UnixNano())
+			for _, c := range names.Cases(root) {
+				if c.Label != label {
+					continue
+				}
+				results, err := names.ProbeQuery(rawHTTP, c.Name, "value-"+GenerateUniqueCredentialName(), actor)
+				Expect(err).NotTo(HaveOccurred(), c.Label)
+				for _, escaping := range names.Escapings {
+					if result, ok := results[escaping]; ok {
+						AddReportEntry(escaping.String(), result.String())
+					}
+				}
+			}
+		})
+	}
+
+	It("handles random names consistently", func() {
+		seed := GinkgoRandomSeed()
+		for i := 0; i < 20; i++ {
+			// Every client gets the same name, under its own root.
+			check(func(root string) names.Case {
+				return names.Random(rand.New(rand.NewSource(seed+int64(i))), root)
+			})
+		}
+	})
+})
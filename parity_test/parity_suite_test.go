@@ -0,0 +1,57 @@
+package parity_test
+
+import (
+	"io/ioutil"
+	"path"
+	"testing"
+
+	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/auth"
+)
+
+var (
+	builtInClient *credhub.CredHub
+	remoteClient  *credhub.CredHub
+	parityConfig  ParityConfig
+)
+
+var _ = BeforeSuite(func() {
+	config, err := LoadConfig()
+	Expect(err).NotTo(HaveOccurred())
+
+	if config.Parity == nil || config.Parity.RemoteApiUrl == "" {
+		Skip("parity.remote_api_url is not set")
+	}
+	parityConfig = *config.Parity
+
+	credhub_ca, err := ioutil.ReadFile(path.Join(config.CredentialRoot, "server_ca_cert.pem"))
+	Expect(err).NotTo(HaveOccurred())
+
+	uaa_ca, err := ioutil.ReadFile(path.Join(config.UAACa))
+	Expect(err).NotTo(HaveOccurred())
+
+	// Both servers are expected to share a UAA and a CA. The clients are not
+	// instrumented, so the parity run does not count towards endpoint
+	// coverage twice.
+	newClient := func(apiUrl string) *credhub.CredHub {
+		client, err := credhub.New(apiUrl,
+			credhub.CaCerts(string(credhub_ca), string(uaa_ca)),
+			credhub.Auth(
+				auth.UaaClientCredentials(config.ClientName, config.ClientSecret),
+			),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		return client
+	}
+	builtInClient = newClient(config.ApiUrl)
+	remoteClient = newClient(parityConfig.RemoteApiUrl)
+})
+
+func TestParity(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Parity Suite")
+}
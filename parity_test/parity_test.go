@@ -0,0 +1,47 @@
+package parity_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/parity"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Remote backend parity", func() {
+	It("reports every endpoint the remote backend does not implement or implements differently", func() {
+		baseline := parity.Execute("built-in", builtInClient, parity.DefaultScenario)
+		candidate := parity.Execute("remote", remoteClient, parity.DefaultScenario)
+		report := parity.Compare(parity.DefaultScenario, baseline, candidate)
+
+		var text bytes.Buffer
+		Expect(report.WriteText(&text)).To(Succeed())
+		fmt.Fprint(GinkgoWriter, text.String())
+		AddReportEntry("parity", text.String())
+		if parityConfig.ReportPath != "" {
+			Expect(report.WriteFile(parityConfig.ReportPath)).To(Succeed())
+		}
+
+		// A step the built-in backend could not run means the scenario itself
+		// is broken, so it fails the run even when differences are only being
+		// listed. Steps the remote backend could not run, because an earlier
+		// step was unimplemented, are reported as not compared.
+		var broken, differences []string
+		for i, finding := range report.Findings {
+			line := fmt.Sprintf("%s (%s): %s", finding.Step, finding.Endpoint, finding.Detail)
+			switch {
+			case baseline.Results[i].Err != "":
+				broken = append(broken, line)
+			case finding.Kind != parity.Same:
+				differences = append(differences, fmt.Sprintf("%s, %s", finding.Kind, line))
+			}
+		}
+		Expect(broken).To(BeEmpty(), strings.Join(broken, "\n"))
+		Expect(report.NotProbed).To(BeEmpty(), "the scenario no longer reaches every catalogued endpoint")
+		if parityConfig.FailOnDifference {
+			Expect(differences).To(BeEmpty(), strings.Join(differences, "\n"))
+		}
+	})
+})
@@ -0,0 +1,109 @@
+package perf_test
+
+import (
+	"fmt"
+	"strings"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials/generate"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials/values"
+	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/perf"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	seedCount     = 10
+	namesPerActor = 10
+	readerActor   = "uaa-client:perf-reader"
+)
+
+var _ = Describe("Credential operations under load", func() {
+	var root string
+
+	BeforeEach(func() {
+		root = "/perf/" + GenerateUniqueCredentialName()
+
+		for i := 0; i < seedCount; i++ {
+			_, err := credhubClient.SetJSON(seedName(root, i), values.JSON{"key": fmt.Sprintf("value-%d", i)})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		permission, err := credhubClient.AddPermission(root+"/*", readerActor, []string{"read"})
+		Expect(err).NotTo(HaveOccurred())
+
+		DeferCleanup(func() {
+			_, err := credhubClient.DeletePermission(permission.UUID)
+			Expect(err).NotTo(HaveOccurred())
+
+			found, err := credhubClient.FindByPath(root)
+			Expect(err).NotTo(HaveOccurred())
+			for _, credential := range found.Credentials {
+				Expect(credhubClient.Delete(credential.Name)).To(Succeed())
+			}
+		})
+	})
+
+	It("sustains the configured load", func() {
+		operations := map[string]perf.Operation{
+			"set": func(worker, iteration int) error {
+				_, err := credhubClient.SetPassword(actorName(root, "set", worker, iteration), values.Password(GenerateUniqueCredentialName()))
+				return err
+			},
+			"get": func(worker, iteration int) error {
+				_, err := credhubClient.GetLatestVersion(seedName(root, iteration))
+				return err
+			},
+			"generate": func(worker, iteration int) error {
+				_, err := credhubClient.GeneratePassword(actorName(root, "generate", worker, iteration), generate.Password{Length: 30}, credhub.Overwrite)
+				return err
+			},
+			"find": func(worker, iteration int) error {
+				_, err := credhubClient.FindByPath(root)
+				return err
+			},
+			"interpolate": func(worker, iteration int) error {
+				interpolated, err := credhubClient.InterpolateString(`{"service":[{"credentials":{"credhub-ref":"((` + seedName(root, iteration) + `))"}}]}`)
+				if err == nil && strings.Contains(interpolated, "credhub-ref") {
+					err = fmt.Errorf("reference was not interpolated: %s", interpolated)
+				}
+				return err
+			},
+			"permission": func(worker, iteration int) error {
+				_, err := credhubClient.GetPermissionByPathActor(root+"/*", readerActor)
+				return err
+			},
+		}
+
+		options := loadOptions()
+		fmt.Fprintf(GinkgoWriter, "running %v for %s with %d workers\n", options.Mix, options.Duration, options.Concurrency)
+
+		report, err := perf.Run(options, operations)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(report.WriteText(GinkgoWriter)).To(Succeed())
+		AddReportEntry("perf", report)
+		if perfConfig.ReportPath != "" {
+			Expect(report.WriteFile(perfConfig.ReportPath)).To(Succeed())
+		}
+
+		if perfConfig.BaselinePath == "" {
+			return
+		}
+		baseline, err := perf.LoadReport(perfConfig.BaselinePath)
+		Expect(err).NotTo(HaveOccurred())
+
+		regressions, err := perf.Compare(baseline, report, tolerance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(regressions).To(BeEmpty(), "regressed against the baseline in %s", perfConfig.BaselinePath)
+	})
+})
+
+func seedName(root string, i int) string {
+	return fmt.Sprintf("%s/seed/%d", root, i%seedCount)
+}
+
+func actorName(root, operation string, worker, iteration int) string {
+	return fmt.Sprintf("%s/%s/%d-%d", root, operation, worker, iteration%namesPerActor)
+}
@@ -0,0 +1,94 @@
+package perf_test
+
+import (
+	"io/ioutil"
+	"path"
+	"testing"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/perf"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/auth"
+)
+
+var (
+	credhubClient *credhub.CredHub
+	perfConfig    PerfConfig
+	// tolerance is how far the run may regress against the baseline: the
+	// configured tolerances, or the defaults for those not set.
+	tolerance perf.Tolerance
+)
+
+var defaultMix = map[string]int{
+	"set":         2,
+	"get":         5,
+	"generate":    1,
+	"find":        1,
+	"interpolate": 1,
+	"permission":  1,
+}
+
+var _ = BeforeSuite(func() {
+	config, err := LoadConfig()
+	Expect(err).NotTo(HaveOccurred())
+
+	if config.Perf != nil {
+		perfConfig = *config.Perf
+	}
+	if perfConfig.Concurrency == 0 {
+		perfConfig.Concurrency = 10
+	}
+	if perfConfig.Duration == "" {
+		perfConfig.Duration = "1m"
+	}
+	if perfConfig.Mix == nil {
+		perfConfig.Mix = defaultMix
+	}
+	tolerance = perf.Tolerance{Latency: 0.2, Throughput: 0.2, ErrorRate: 0.01}
+	if perfConfig.LatencyTolerance != nil {
+		tolerance.Latency = *perfConfig.LatencyTolerance
+	}
+	if perfConfig.ThroughputTolerance != nil {
+		tolerance.Throughput = *perfConfig.ThroughputTolerance
+	}
+	if perfConfig.ErrorRateTolerance != nil {
+		tolerance.ErrorRate = *perfConfig.ErrorRateTolerance
+	}
+
+	credhub_ca, err := ioutil.ReadFile(path.Join(config.CredentialRoot, "server_ca_cert.pem"))
+	Expect(err).NotTo(HaveOccurred())
+
+	uaa_ca, err := ioutil.ReadFile(path.Join(config.UAACa))
+	Expect(err).NotTo(HaveOccurred())
+
+	// The client is deliberately not instrumented, so that recording and
+	// validating responses does not add to the measured latencies.
+	credhubClient, err = credhub.New(config.ApiUrl,
+		credhub.CaCerts(string(credhub_ca), string(uaa_ca)),
+		credhub.Auth(
+			auth.UaaClientCredentials(config.ClientName, config.ClientSecret),
+		),
+	)
+	Expect(err).ToNot(HaveOccurred())
+})
+
+func TestPerf(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Perf Suite")
+}
+
+func loadOptions() perf.Options {
+	duration, err := time.ParseDuration(perfConfig.Duration)
+	Expect(err).NotTo(HaveOccurred())
+
+	return perf.Options{
+		Concurrency: perfConfig.Concurrency,
+		Duration:    duration,
+		Mix:         perfConfig.Mix,
+		Seed:        GinkgoRandomSeed(),
+	}
+}
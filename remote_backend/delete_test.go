@@ -2,6 +2,7 @@ package remote_backend_test
 
 import (
 	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/remotebackend"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gexec"
@@ -21,6 +22,7 @@ var _ = Describe("Delete", func() {
 
 			stdOut := string(session.Out.Contents())
 			Expect(stdOut).To(ContainSubstring("Credential successfully deleted"))
+			expectBackendCalls(remotebackend.HaveCallsTo(1, "Delete", HaveField("Name", "/"+name)))
 		})
 	})
 })
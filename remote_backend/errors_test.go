@@ -0,0 +1,69 @@
+package remote_backend_test
+
+import (
+	"bytes"
+	"net/http"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/auth"
+
+	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/apierrors"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/parity"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Unimplemented endpoints", func() {
+	var client *credhub.CredHub
+
+	BeforeEach(func() {
+		var err error
+		client, err = credhub.New(cfg.ApiUrl,
+			credhub.SkipTLSValidation(true),
+			credhub.Auth(auth.UaaClientCredentials(cfg.ClientName, cfg.ClientSecret)),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		InstrumentClient(client)
+	})
+
+	It("are reported over HTTP with their status and error body", func() {
+		response, err := client.Request(http.MethodPost, "/api/v1/regenerate", nil, map[string]string{"name": "some-cert"}, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response).To(apierrors.MatchResponse(apierrors.NotImplemented))
+	})
+
+	It("are returned by the Go client as a CredHub error", func() {
+		_, err := client.Regenerate("some-cert")
+		Expect(err).To(apierrors.MatchClientError(apierrors.NotImplemented))
+	})
+
+	It("make the CLI exit 1 with the error", func() {
+		Expect(RunCommand("regenerate", "-n", "some-cert")).To(apierrors.MatchCLIError(apierrors.NotImplemented))
+	})
+
+	// Which endpoints are unimplemented is not listed here: it is what the
+	// parity scenario finds, which must be what the parity suite reported when
+	// its report is configured. Without one, some endpoints must still be
+	// unimplemented, as CredHub serves several only from its own database.
+	It("are those the parity scenario finds, each answered with the same error", func() {
+		run := parity.Execute("remote", client, parity.DefaultScenario)
+		unimplemented := run.Unimplemented()
+		AddReportEntry("unimplemented endpoints", unimplemented)
+
+		if cfg.Parity != nil && cfg.Parity.ReportPath != "" {
+			report, err := parity.ReadFile(cfg.Parity.ReportPath)
+			Expect(err).NotTo(HaveOccurred(), "reading the parity report")
+			Expect(unimplemented).To(Equal(report.Unimplemented()), "the parity report at %s lists other endpoints", cfg.Parity.ReportPath)
+		} else {
+			Expect(unimplemented).NotTo(BeEmpty(), "every endpoint is implemented; set parity.report_path to compare with a parity report")
+		}
+
+		for _, result := range run.Results {
+			if result.Status == http.StatusNotImplemented || bytes.Contains(result.Body, []byte(parity.NotImplementedMessage)) {
+				response := apierrors.Response{Status: result.Status, Body: result.Body}
+				Expect(response).To(apierrors.MatchResponse(apierrors.NotImplemented), "%s (%s)", result.Step, result.Endpoint)
+			}
+		}
+	})
+})
@@ -1,7 +1,8 @@
-package remote_backend
+package remote_backend_test
 
 import (
 	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/remotebackend"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gexec"
@@ -30,6 +31,7 @@ var _ = Describe("Find", func() {
 				Expect(stdOut).ToNot(ContainSubstring("- name: /some-credential"))
 				Expect(stdOut).To(ContainSubstring("- name: /some-other-credential"))
 				Expect(stdOut).To(ContainSubstring("- name: /another-credential"))
+				expectBackendCalls(remotebackend.HaveCallsTo(1, "FindContainingName", HaveField("Name", "other")))
 
 			})
 		})
@@ -64,6 +66,7 @@ var _ = Describe("Find", func() {
 				Expect(stdOut).To(ContainSubstring("- name: /some/credential"))
 				Expect(stdOut).To(ContainSubstring("- name: /some/other-credential"))
 				Expect(stdOut).ToNot(ContainSubstring("- name: /another/credential"))
+				expectBackendCalls(remotebackend.HaveCallsTo(1, "FindStartingWithPath", HaveField("Path", "/some")))
 			})
 		})
 		Context("when no credentials exist starting with path", func() {
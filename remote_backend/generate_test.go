@@ -8,9 +8,11 @@ import (
 	"strings"
 
 	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/remotebackend"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gexec"
+	"github.com/onsi/gomega/types"
 	"gopkg.in/yaml.v3"
 )
 
@@ -27,6 +29,7 @@ var _ = Describe("Generate", func() {
 					stdOut := string(session.Out.Contents())
 					Expect(stdOut).To(ContainSubstring(name))
 					Expect(stdOut).To(ContainSubstring("value: <redacted>"))
+					expectBackendCalls(generatedSets(name, 1, HaveField("Type", "password")))
 
 					session = RunCommand("get", "-n", name, "-q")
 					stdOut = string(session.Out.Contents())
@@ -43,6 +46,7 @@ var _ = Describe("Generate", func() {
 					stdOut := string(session.Out.Contents())
 					Expect(stdOut).To(ContainSubstring(name))
 					Expect(stdOut).To(ContainSubstring("value: <redacted>"))
+					expectBackendCalls(generatedSets(name, 1, HaveField("Type", "certificate")))
 
 					session = RunCommand("get", "-n", name)
 					stdOut = string(session.Out.Contents())
@@ -61,6 +65,7 @@ var _ = Describe("Generate", func() {
 					stdOut := string(session.Out.Contents())
 					Expect(stdOut).To(ContainSubstring(name))
 					Expect(stdOut).To(ContainSubstring("value: <redacted>"))
+					expectBackendCalls(generatedSets(name, 1, HaveField("Type", "rsa")))
 
 					session = RunCommand("get", "-n", name)
 					stdOut = string(session.Out.Contents())
@@ -77,6 +82,7 @@ var _ = Describe("Generate", func() {
 					stdOut := string(session.Out.Contents())
 					Expect(stdOut).To(ContainSubstring(name))
 					Expect(stdOut).To(ContainSubstring("value: <redacted>"))
+					expectBackendCalls(generatedSets(name, 1, HaveField("Type", "ssh")))
 
 					session = RunCommand("get", "-n", name)
 					stdOut = string(session.Out.Contents())
@@ -93,6 +99,7 @@ var _ = Describe("Generate", func() {
 					stdOut := string(session.Out.Contents())
 					Expect(stdOut).To(ContainSubstring(name))
 					Expect(stdOut).To(ContainSubstring("value: <redacted>"))
+					expectBackendCalls(generatedSets(name, 1, HaveField("Type", "user")))
 
 					session = RunCommand("get", "-n", name, "-k", "password", "-q")
 					stdOut = string(session.Out.Contents())
@@ -116,6 +123,10 @@ var _ = Describe("Generate", func() {
 				session = RunCommand("curl", "-p", "api/v1/data", "-X", "POST", "-d", generationParameters)
 				Expect(session).Should(Exit(0))
 				Expect(string(session.Out.Contents())).To(ContainSubstring(oldStdOut))
+				expectBackendCalls(
+					generatedSets(name, 1),
+					ContainElement(remotebackend.CallTo("GetByName", HaveField("Name", "/"+name))),
+				)
 			})
 		})
 
@@ -134,6 +145,10 @@ var _ = Describe("Generate", func() {
 					session = RunCommand("curl", "-p", "api/v1/data", "-X", "POST", "-d", generationParameters)
 					Expect(session).Should(Exit(0))
 					Expect(string(session.Out.Contents())).To(ContainSubstring(oldStdOut))
+					expectBackendCalls(
+						generatedSets(name, 1),
+						ContainElement(remotebackend.CallTo("GetByName", HaveField("Name", "/"+name))),
+					)
 				})
 			})
 			Context("and generation parameters are not the same", func() {
@@ -150,12 +165,20 @@ var _ = Describe("Generate", func() {
 					session = RunCommand("curl", "-p", "api/v1/data", "-X", "POST", "-d", generationParameters)
 					Expect(session).Should(Exit(0))
 					Expect(string(session.Out.Contents())).ToNot(ContainSubstring(oldStdOut))
+					expectBackendCalls(generatedSets(name, 2))
 				})
 			})
 		})
 	})
 })
 
+// generatedSets matches the calls that stored a generated version of the
+// credential, with the parameters it was generated with.
+func generatedSets(name string, count int, request ...types.GomegaMatcher) types.GomegaMatcher {
+	request = append(request, HaveField("Name", "/"+name), HaveField("GenerationParameters", Not(BeEmpty())))
+	return remotebackend.HaveCallsTo(count, "Set", request...)
+}
+
 func CertFromPem(input string, ca bool) *x509.Certificate {
 	type certificateValue struct {
 		Ca          string `yaml:"ca,omitempty"`
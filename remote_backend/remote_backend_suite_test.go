@@ -1,6 +1,7 @@
 package remote_backend_test
 
 import (
+	"crypto/tls"
 	"math/rand"
 	"testing"
 
@@ -9,14 +10,19 @@ import (
 	"runtime"
 
 	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/remotebackend"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gexec"
+	"github.com/onsi/gomega/types"
 )
 
 var (
 	homeDir string
 	cfg     Config
+	// backend is the stand-in CredHub is wired to, when the suite started
+	// one. It only exists on the first parallel node.
+	backend *remotebackend.Backend
 )
 
 var _ = BeforeEach(func() {
@@ -34,14 +40,22 @@ var _ = BeforeEach(func() {
 	Expect(err).NotTo(HaveOccurred())
 
 	TargetAndLoginSkipTls(cfg)
+
+	if backend != nil {
+		backend.ResetCalls()
+	}
 })
 
+var _ = RegisterReporting("RemoteBackend Suite")
+var _ = RegisterHistoryChecksSkipTls()
+
 func TestRemoteBackendTest(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "RemoteBackend Suite")
 }
 
 var _ = SynchronizedBeforeSuite(func() []byte {
+	startBackend()
 	return []byte("credhub")
 }, func(cli_path []byte) {
 	CommandPath = string(cli_path)
@@ -50,5 +64,47 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 })
 
 var _ = SynchronizedAfterSuite(func() {}, func() {
+	if backend != nil {
+		backend.Stop()
+	}
 	CleanupBuildArtifacts()
 })
+
+// expectBackendCalls asserts on the calls CredHub made to the stand-in
+// backend during the spec. It skips the rest of the spec when the suite is
+// run against another backend, and fails on parallel nodes other than the
+// first, which cannot see the stand-in's call log.
+func expectBackendCalls(matchers ...types.GomegaMatcher) {
+	if backend == nil && cfg.RemoteBackend != nil && cfg.RemoteBackend.SocketPath != "" {
+		Fail("the stand-in backend's calls are only visible on the first parallel node; run the suite serially", 1)
+	}
+	if backend == nil {
+		Skip("CredHub is not using the stand-in backend, so its calls cannot be checked")
+	}
+	calls := backend.Calls()
+	for _, matcher := range matchers {
+		ExpectWithOffset(1, calls).To(matcher)
+	}
+}
+
+// startBackend starts the stand-in remote backend on the configured socket,
+// for a CredHub started with that socket as its remote backend.
+func startBackend() {
+	config, err := LoadConfig()
+	Expect(err).NotTo(HaveOccurred())
+	if config.RemoteBackend == nil || config.RemoteBackend.SocketPath == "" {
+		return
+	}
+
+	options := remotebackend.Options{
+		SocketPath: config.RemoteBackend.SocketPath,
+		Seed:       remotebackend.DefaultSeed,
+	}
+	if config.RemoteBackend.CertPath != "" {
+		certificate, err := tls.LoadX509KeyPair(config.RemoteBackend.CertPath, config.RemoteBackend.KeyPath)
+		Expect(err).NotTo(HaveOccurred())
+		options.Certificate = &certificate
+	}
+	backend, err = remotebackend.Start(options)
+	Expect(err).NotTo(HaveOccurred())
+}
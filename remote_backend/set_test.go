@@ -2,6 +2,7 @@ package remote_backend_test
 
 import (
 	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/remotebackend"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gexec"
@@ -18,6 +19,7 @@ var _ = Describe("Set", func() {
 
 				session := RunCommand("set", "-t", "json", "-n", name, "-v", json)
 				Expect(session).Should(Exit(0))
+				expectBackendCalls(remotebackend.HaveCallsTo(1, "Set", HaveField("Name", "/"+name), HaveField("Type", "json"), HaveField("Data", MatchJSON(json))))
 
 				session = RunCommand("get", "-n", name)
 				Expect(session).Should(Exit(0))
@@ -25,6 +27,7 @@ var _ = Describe("Set", func() {
 				stdOut := string(session.Out.Contents())
 				Expect(stdOut).To(ContainSubstring(asYaml))
 				Expect(stdOut).To(ContainSubstring(name))
+				expectBackendCalls(ContainElement(remotebackend.CallTo("GetByName", HaveField("Name", "/"+name))))
 			})
 		})
 		Describe("value type", func() {
@@ -34,6 +37,7 @@ var _ = Describe("Set", func() {
 
 				session := RunCommand("set", "-t", "value", "-n", name, "-v", value)
 				Expect(session).Should(Exit(0))
+				expectBackendCalls(remotebackend.HaveCallsTo(1, "Set", HaveField("Name", "/"+name), HaveField("Type", "value")))
 
 				session = RunCommand("get", "-n", name)
 				Expect(session).Should(Exit(0))
@@ -50,6 +54,7 @@ var _ = Describe("Set", func() {
 
 				session := RunCommand("set", "-t", "password", "-n", name, "-w", password)
 				Expect(session).Should(Exit(0))
+				expectBackendCalls(remotebackend.HaveCallsTo(1, "Set", HaveField("Name", "/"+name), HaveField("Type", "password")))
 
 				session = RunCommand("get", "-n", name)
 				Expect(session).Should(Exit(0))
@@ -72,6 +77,7 @@ var _ = Describe("Set", func() {
 					"-c", cert,
 					"-p", privateKey)
 				Expect(session).Should(Exit(0))
+				expectBackendCalls(remotebackend.HaveCallsTo(1, "Set", HaveField("Name", "/"+name), HaveField("Type", "certificate")))
 
 				session = RunCommand("get", "-n", name)
 				Expect(session).Should(Exit(0))
@@ -101,6 +107,7 @@ var _ = Describe("Set", func() {
 
 				session := RunCommand("set", "-t", "user", "-n", name, "-z", username, "-w", password)
 				Expect(session).Should(Exit(0))
+				expectBackendCalls(remotebackend.HaveCallsTo(1, "Set", HaveField("Name", "/"+name), HaveField("Type", "user")))
 
 				session = RunCommand("get", "-n", name)
 				Expect(session).Should(Exit(0))
@@ -119,6 +126,7 @@ var _ = Describe("Set", func() {
 
 				session := RunCommand("set", "-t", "rsa", "-n", name, "-u", publicKey, "-p", privateKey)
 				Expect(session).Should(Exit(0))
+				expectBackendCalls(remotebackend.HaveCallsTo(1, "Set", HaveField("Name", "/"+name), HaveField("Type", "rsa")))
 
 				session = RunCommand("get", "-n", name)
 				Expect(session).Should(Exit(0))
@@ -146,6 +154,7 @@ var _ = Describe("Set", func() {
 
 				session := RunCommand("set", "-t", "ssh", "-n", name, "-u", publicKey, "-p", privateKey)
 				Expect(session).Should(Exit(0))
+				expectBackendCalls(remotebackend.HaveCallsTo(1, "Set", HaveField("Name", "/"+name), HaveField("Type", "ssh")))
 
 				session = RunCommand("get", "-n", name)
 				Expect(session).Should(Exit(0))
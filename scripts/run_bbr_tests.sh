@@ -2,25 +2,59 @@
 
 set -eu
 
+API_URL=${API_URL:-https://localhost:9000}
+API_IP=${API_IP:-}
+USERNAME=${USERNAME:-credhub}
+PASSWORD=${PASSWORD:-password}
+SERVER_CA_CERT_PATH=${SERVER_CA_CERT_PATH:-}
 CLIENT_NAME=${CLIENT_NAME:-credhub_client}
 CLIENT_SECRET=${CLIENT_SECRET:-secret}
+DEPLOYMENT_NAME=${DEPLOYMENT_NAME:-credhub}
+REPORT_DIR=${REPORT_DIR:-$(pwd)/reports}
+BOSH_ENVIRONMENT=${BOSH_ENVIRONMENT:-}
+BACKUP_SCRIPT=${BACKUP_SCRIPT:-}
+RESTORE_SCRIPT=${RESTORE_SCRIPT:-}
+DATABASE_URL=${DATABASE_URL:-}
+BBR_SKIP=${BBR_SKIP:-false}
 
-cat <<EOF > test_config.json
-{
-  "director_host":"${API_IP}",
-  "api_url": "${API_URL}",
-  "api_username":"${USERNAME}",
-  "api_password":"${PASSWORD}",
+# Without BOSH_ENVIRONMENT the suite backs up through a local stand-in for
+# the director, running BACKUP_SCRIPT and RESTORE_SCRIPT, or pg_dump and psql
+# against DATABASE_URL, or the installed bbr-credhubdb scripts when neither is
+# set. It fails without any of them, unless BBR_SKIP=true.
+if [ -n "$BOSH_ENVIRONMENT" ]; then
+  BBR_TARGET=$(cat <<EOF
   "bosh": {
     "bosh_environment":"$BOSH_ENVIRONMENT",
     "bosh_client":"$BOSH_CLIENT",
     "bosh_client_secret":"$BOSH_CLIENT_SECRET",
     "bosh_ca_cert_path":"$BOSH_CA_CERT_PATH"
   },
+EOF
+)
+else
+  BBR_TARGET=$(cat <<EOF
+  "bbr": {
+    "backup_script":"$BACKUP_SCRIPT",
+    "restore_script":"$RESTORE_SCRIPT",
+    "database_url":"$DATABASE_URL",
+    "skip":$BBR_SKIP
+  },
+EOF
+)
+fi
+
+cat <<EOF > test_config.json
+{
+  "director_host":"${API_IP}",
+  "api_url": "${API_URL}",
+  "api_username":"${USERNAME}",
+  "api_password":"${PASSWORD}",
+${BBR_TARGET}
   "uaa_ca":"${SERVER_CA_CERT_PATH}",
   "client_name":"${CLIENT_NAME}",
   "client_secret":"${CLIENT_SECRET}",
-  "deployment_name":"$DEPLOYMENT_NAME"
+  "deployment_name":"$DEPLOYMENT_NAME",
+  "report_dir":"${REPORT_DIR}"
 }
 EOF
 
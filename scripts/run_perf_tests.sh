@@ -0,0 +1,41 @@
+#!/bin/bash
+
+set -eu
+
+BASEDIR="$( cd "$( dirname "${BASH_SOURCE[0]}" )"/.. && pwd )"
+
+API_URL=${API_URL:-https://localhost:9000}
+USERNAME=${USERNAME:-credhub}
+PASSWORD=${PASSWORD:-password}
+CREDENTIAL_ROOT=${CREDENTIAL_ROOT:-~/workspace/credhub-release/src/credhub/applications/credhub-api/src/test/resources}
+UAA_CA=${UAA_CA:-~/workspace/credhub-deployments/ca/uaa_ca.pem}
+CLIENT_NAME=${CLIENT_NAME:-credhub_client}
+CLIENT_SECRET=${CLIENT_SECRET:-secret}
+CONCURRENCY=${CONCURRENCY:-10}
+DURATION=${DURATION:-1m}
+MIX=${MIX:-'{"set": 2, "get": 5, "generate": 1, "find": 1, "interpolate": 1, "permission": 1}'}
+REPORT_PATH=${REPORT_PATH:-${BASEDIR}/perf-report.json}
+BASELINE_PATH=${BASELINE_PATH:-}
+
+cat <<EOF > test_config.json
+{
+  "api_url": "${API_URL}",
+  "api_username":"${USERNAME}",
+  "api_password":"${PASSWORD}",
+  "credential_root":"${CREDENTIAL_ROOT}",
+  "uaa_ca":"${UAA_CA}",
+  "client_name":"${CLIENT_NAME}",
+  "client_secret":"${CLIENT_SECRET}",
+  "perf": {
+    "concurrency":${CONCURRENCY},
+    "duration":"${DURATION}",
+    "mix":${MIX},
+    "report_path":"${REPORT_PATH}",
+    "baseline_path":"${BASELINE_PATH}"
+  }
+}
+EOF
+
+pushd "$BASEDIR" >/dev/null
+  ginkgo -v -timeout 2h perf_test "$@"
+popd >/dev/null
@@ -12,6 +12,25 @@ UAA_CA=${UAA_CA:-~/workspace/credhub-deployments/ca/uaa_ca.pem}
 CLIENT_NAME=${CLIENT_NAME:-credhub_client}
 CLIENT_SECRET=${CLIENT_SECRET:-secret}
 CONCATENATE_CAS=${CONCATENATE_CAS:-false}
+REPORT_DIR=${REPORT_DIR:-${BASEDIR}/reports}
+REMOTE_BACKEND_SOCKET=${REMOTE_BACKEND_SOCKET:-}
+REMOTE_BACKEND_CERT=${REMOTE_BACKEND_CERT:-}
+REMOTE_BACKEND_KEY=${REMOTE_BACKEND_KEY:-}
+PARITY_REPORT_PATH=${PARITY_REPORT_PATH:-${BASEDIR}/parity-report.json}
+
+# The unimplemented endpoints are compared with the report run_parity_tests.sh
+# writes, when there is one.
+PARITY=
+if [ -f "${PARITY_REPORT_PATH}" ]; then
+  PARITY=$(cat <<EOF
+  "parity": {
+    "report_path":"${PARITY_REPORT_PATH}"
+  },
+EOF
+)
+else
+  echo "No parity report at ${PARITY_REPORT_PATH}; run ./scripts/run_parity_tests.sh first to compare with one." >&2
+fi
 
 cat <<EOF > test_config.json
 {
@@ -22,10 +41,24 @@ cat <<EOF > test_config.json
   "uaa_ca":"${UAA_CA}",
   "client_name":"${CLIENT_NAME}",
   "client_secret":"${CLIENT_SECRET}",
-  "concatenate_cas":${CONCATENATE_CAS}
+  "concatenate_cas":${CONCATENATE_CAS},
+  "report_dir":"${REPORT_DIR}",
+${PARITY}
+  "remote_backend": {
+    "socket_path":"${REMOTE_BACKEND_SOCKET}",
+    "cert_path":"${REMOTE_BACKEND_CERT}",
+    "key_path":"${REMOTE_BACKEND_KEY}"
+  }
 }
 EOF
 
+# The stand-in backend's call log is only visible to the first parallel node,
+# so specs asserting on it run serially.
+PARALLEL=-p
+if [ -n "${REMOTE_BACKEND_SOCKET}" ]; then
+  PARALLEL=
+fi
+
 pushd "$BASEDIR" >/dev/null
-  ginkgo -v -r -p remote_backend -randomizeAllSpecs -randomizeSuites
+  ginkgo -v -r ${PARALLEL} remote_backend -randomizeAllSpecs -randomizeSuites
 popd >/dev/null
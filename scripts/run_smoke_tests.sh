@@ -7,6 +7,7 @@ USERNAME=${USERNAME:-credhub}
 PASSWORD=${PASSWORD:-password}
 CLIENT_NAME=${CLIENT_NAME:-credhub_client}
 CLIENT_SECRET=${CLIENT_SECRET:-secret}
+REPORT_DIR=${REPORT_DIR:-$(pwd)/reports}
 
 cat <<EOF > test_config.json
 {
@@ -14,7 +15,8 @@ cat <<EOF > test_config.json
   "api_username":"${USERNAME}",
   "api_password":"${PASSWORD}",
   "client_name":"${CLIENT_NAME}",
-  "client_secret":"${CLIENT_SECRET}"
+  "client_secret":"${CLIENT_SECRET}",
+  "report_dir":"${REPORT_DIR}"
 }
 EOF
 
@@ -12,6 +12,9 @@ UAA_CA=${UAA_CA:-~/workspace/credhub-deployments/ca/uaa_ca.pem}
 CLIENT_NAME=${CLIENT_NAME:-credhub_client}
 CLIENT_SECRET=${CLIENT_SECRET:-secret}
 CONCATENATE_CAS=${CONCATENATE_CAS:-true}
+REPORT_DIR=${REPORT_DIR:-${BASEDIR}/reports}
+UAA_ADMIN_CLIENT=${UAA_ADMIN_CLIENT:-}
+UAA_ADMIN_SECRET=${UAA_ADMIN_SECRET:-}
 
 cat <<EOF > test_config.json
 {
@@ -22,10 +25,15 @@ cat <<EOF > test_config.json
   "uaa_ca":"${UAA_CA}",
   "client_name":"${CLIENT_NAME}",
   "client_secret":"${CLIENT_SECRET}",
-  "concatenate_cas":${CONCATENATE_CAS}
+  "concatenate_cas":${CONCATENATE_CAS},
+  "report_dir":"${REPORT_DIR}",
+  "uaa_admin": {
+    "client":"${UAA_ADMIN_CLIENT}",
+    "secret":"${UAA_ADMIN_SECRET}"
+  }
 }
 EOF
 
 pushd "$BASEDIR" >/dev/null
-  ginkgo -r -p -skipPackage bbr_integration_test,remote_backend -randomizeAllSpecs -randomizeSuites "$@"
+  ginkgo -r -p -skipPackage bbr_integration_test,remote_backend,perf_test,soak_test,parity_test,import_scale_test -randomizeAllSpecs -randomizeSuites "$@"
 popd >/dev/null
@@ -2,6 +2,7 @@ package smoke_test
 
 import (
 	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/apierrors"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gexec"
@@ -38,11 +39,7 @@ var _ = Describe("Smoke Test", func() {
 				Eventually(session).Should(Exit(0))
 
 				session = RunCommand("get", "-n", certificate)
-				stdErr := string(session.Err.Contents())
-
-				Eventually(session).Should(Exit(1))
-
-				Expect(stdErr).To(ContainSubstring(`request`))
+				Expect(session).To(apierrors.MatchCLIError(apierrors.NotFound))
 			})
 		})
 	})
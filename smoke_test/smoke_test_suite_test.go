@@ -35,6 +35,9 @@ var _ = BeforeEach(func() {
 	TargetAndLoginSkipTls(cfg)
 })
 
+var _ = RegisterReporting("SmokeTest Suite")
+var _ = RegisterHistoryChecksSkipTls()
+
 func TestSmokeTest(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "SmokeTest Suite")
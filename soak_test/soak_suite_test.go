@@ -0,0 +1,77 @@
+package soak_test
+
+import (
+	"io/ioutil"
+	"path"
+	"testing"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/soak"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/auth"
+)
+
+var (
+	credhubClient *credhub.CredHub
+	soakConfig    SoakConfig
+)
+
+var _ = BeforeSuite(func() {
+	config, err := LoadConfig()
+	Expect(err).NotTo(HaveOccurred())
+
+	if config.Soak != nil {
+		soakConfig = *config.Soak
+	}
+	if soakConfig.Duration == "" {
+		soakConfig.Duration = "1h"
+	}
+	if soakConfig.CheckInterval == "" {
+		soakConfig.CheckInterval = "1m"
+	}
+	if soakConfig.HealthInterval == "" {
+		soakConfig.HealthInterval = "30s"
+	}
+
+	credhub_ca, err := ioutil.ReadFile(path.Join(config.CredentialRoot, "server_ca_cert.pem"))
+	Expect(err).NotTo(HaveOccurred())
+
+	uaa_ca, err := ioutil.ReadFile(path.Join(config.UAACa))
+	Expect(err).NotTo(HaveOccurred())
+
+	// As in the perf suite, the client is not instrumented: hours of
+	// recorded exchanges would only use up memory.
+	credhubClient, err = credhub.New(config.ApiUrl,
+		credhub.CaCerts(string(credhub_ca), string(uaa_ca)),
+		credhub.Auth(
+			auth.UaaClientCredentials(config.ClientName, config.ClientSecret),
+		),
+	)
+	Expect(err).ToNot(HaveOccurred())
+})
+
+func TestSoak(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Soak Suite")
+}
+
+func loadOptions() soak.Options {
+	parse := func(duration string) time.Duration {
+		parsed, err := time.ParseDuration(duration)
+		Expect(err).NotTo(HaveOccurred())
+		return parsed
+	}
+
+	return soak.Options{
+		Duration:       parse(soakConfig.Duration),
+		CheckInterval:  parse(soakConfig.CheckInterval),
+		HealthInterval: parse(soakConfig.HealthInterval),
+		HealthPath:     soakConfig.HealthPath,
+		MemoryPath:     soakConfig.MemoryMetricPath,
+		Log:            GinkgoWriter,
+	}
+}
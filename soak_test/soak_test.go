@@ -0,0 +1,40 @@
+package soak_test
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/soak"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Long running workload", func() {
+	It("keeps every credential, version and permission it wrote", func() {
+		workload := soak.NewWorkload(credhubClient, "/soak/"+GenerateUniqueCredentialName())
+		DeferCleanup(workload.Cleanup)
+		Expect(workload.Setup()).To(Succeed())
+
+		options := loadOptions()
+		fmt.Fprintf(GinkgoWriter, "soaking for %s, checking every %s\n", options.Duration, options.CheckInterval)
+
+		report := soak.Run(workload, credhubClient, options)
+		AddReportEntry("soak", report)
+		if soakConfig.ReportPath != "" {
+			Expect(report.WriteFile(soakConfig.ReportPath)).To(Succeed())
+		}
+		fmt.Fprintf(GinkgoWriter, "%d iterations, %d checks, %d errors\n", report.Iterations, report.Checks, report.ErrorCount)
+		if options.HealthPath != "" {
+			fmt.Fprintf(GinkgoWriter, "health latency %+v ms\nmemory %+v bytes\n", report.HealthLatency, report.Memory)
+		}
+
+		findings := make([]string, len(report.Findings))
+		for i, finding := range report.Findings {
+			findings[i] = fmt.Sprintf("iteration %d: %s", finding.Iteration, finding.Violation)
+		}
+		Expect(findings).To(BeEmpty(), strings.Join(findings, "\n"))
+		Expect(report.Errors).To(BeEmpty())
+		Expect(report.Unhealthy).To(BeZero(), "health was not UP in %d of %d samples", report.Unhealthy, len(report.Health))
+	})
+})
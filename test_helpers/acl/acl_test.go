@@ -0,0 +1,308 @@
+package acl_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/acl"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   map[string]interface{}
+}
+
+// fakeClient answers each request with respond, and records it.
+type fakeClient struct {
+	requests []request
+	respond  func(request) (int, string)
+}
+
+func (c *fakeClient) Request(method string, path string, query url.Values, body interface{}, checkServerErr bool) (*http.Response, error) {
+	req := request{method: method, path: path, query: query}
+	if body != nil {
+		encoded, _ := json.Marshal(body)
+		json.Unmarshal(encoded, &req.body)
+	}
+	c.requests = append(c.requests, req)
+
+	status, responseBody := c.respond(req)
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(responseBody))}, nil
+}
+
+var _ = Describe("Combinations", func() {
+	It("returns every subset of the permissions", func() {
+		combinations := acl.Combinations(acl.Permissions)
+		Expect(combinations).To(HaveLen(32))
+		Expect(combinations[0]).To(BeEmpty())
+		Expect(combinations[31]).To(Equal(acl.Permissions))
+
+		seen := map[string]bool{}
+		for _, combination := range combinations {
+			seen[acl.Describe(combination)] = true
+		}
+		Expect(seen).To(HaveLen(32))
+		Expect(seen).To(HaveKey("none"))
+		Expect(seen).To(HaveKey("read,delete,write_acl"))
+	})
+})
+
+var _ = Describe("Operation", func() {
+	It("is allowed only with every permission it requires", func() {
+		operation := acl.Operation{Requires: []acl.Permission{acl.Read, acl.ReadACL}}
+		Expect(operation.Expect([]acl.Permission{acl.Read, acl.Write, acl.ReadACL})).To(Equal(acl.Allowed))
+		Expect(operation.Expect([]acl.Permission{acl.Read, acl.Write})).To(Equal(acl.Denied))
+		Expect(operation.Expect(nil)).To(Equal(acl.Denied))
+	})
+
+	It("lists for finds instead", func() {
+		operation := acl.Operation{Requires: []acl.Permission{acl.Read}, Lists: true}
+		Expect(operation.Expect([]acl.Permission{acl.Read})).To(Equal(acl.Listed))
+		Expect(operation.Expect([]acl.Permission{acl.Write})).To(Equal(acl.Unlisted))
+	})
+
+	It("covers every permission", func() {
+		required := map[acl.Permission]bool{}
+		for _, operation := range acl.Operations {
+			for _, permission := range operation.Requires {
+				required[permission] = true
+			}
+		}
+		Expect(required).To(HaveLen(len(acl.Permissions)))
+	})
+})
+
+var _ = Describe("Run", func() {
+	fixture := &acl.Fixture{Root: "/acl/root-id", JSON: "/acl/root-id/json", Password: "/acl/root-id/password"}
+
+	isFind := func(req request) bool {
+		return req.path == "/api/v1/data" && (req.query.Get("path") != "" || req.query.Get("name-like") != "")
+	}
+
+	// A server that denies everything but finds, which list nothing.
+	denying := func(req request) (int, string) {
+		if isFind(req) {
+			return http.StatusOK, `{"credentials": []}`
+		}
+		if req.method == "PUT" || req.method == "POST" {
+			return http.StatusForbidden, `{"error": "denied"}`
+		}
+		return http.StatusNotFound, `{"error": "not found"}`
+	}
+
+	It("agrees with a server that denies an actor granted nothing", func() {
+		Expect(acl.Run(acl.Operations, &fakeClient{respond: denying}, fixture, nil)).To(BeEmpty())
+	})
+
+	It("reports every operation the server allows against the model", func() {
+		// It enforces write, but reads by name and finds by path leak.
+		leaky := &fakeClient{respond: func(req request) (int, string) {
+			if req.path == "/api/v1/data" && (req.method == "PUT" || req.method == "POST" || req.method == "GET" && req.query.Get("name") != "") {
+				return http.StatusOK, `{"data": []}`
+			}
+			if isFind(req) && req.query.Get("path") != "" {
+				return http.StatusOK, `{"credentials": [{"name": "/acl/root-id/json"}]}`
+			}
+			return denying(req)
+		}}
+
+		mismatches := acl.Run(acl.Operations, leaky, fixture, []acl.Permission{acl.Write})
+		Expect(mismatches).To(HaveLen(3))
+		Expect(mismatches[0].String()).To(Equal("get by name: expected denied, got allowed"))
+		Expect(mismatches[1].String()).To(Equal("get versions: expected denied, got allowed"))
+		Expect(mismatches[2].String()).To(Equal("find by path: expected unlisted, got listed"))
+	})
+
+	It("reports responses that are neither allows nor denies", func() {
+		failing := &fakeClient{respond: func(req request) (int, string) {
+			return http.StatusInternalServerError, `{"error": "boom"}`
+		}}
+		mismatches := acl.Run(acl.Operations[:1], failing, fixture, acl.Permissions)
+		Expect(mismatches).To(HaveLen(1))
+		Expect(mismatches[0].String()).To(Equal(`get by name: expected allowed, got status 500: {"error": "boom"}`))
+	})
+})
+
+var _ = Describe("Fixture", func() {
+	var admin *fakeClient
+
+	BeforeEach(func() {
+		admin = &fakeClient{respond: func(req request) (int, string) {
+			switch {
+			case req.method == "PUT":
+				return http.StatusOK, `{"id": "json-id"}`
+			case req.method == "POST" && req.path == "/api/v2/permissions":
+				return http.StatusCreated, `{"uuid": "permission-uuid"}`
+			case req.method == "POST" && req.path == "/api/v1/permissions":
+				return http.StatusCreated, `{}`
+			case req.method == "GET" && req.path == "/api/v2/permissions":
+				if strings.HasSuffix(req.query.Get("path"), "/*") {
+					return http.StatusOK, `{"uuid": "wildcard-uuid"}`
+				}
+				return http.StatusNotFound, `{}`
+			default:
+				return http.StatusOK, `{}`
+			}
+		}}
+	})
+
+	It("creates credentials and other actors' permissions under a new root", func() {
+		fixture, err := acl.NewFixture(admin)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fixture.Root).To(MatchRegexp(`^/acl/[0-9a-f-]{36}$`))
+		Expect(fixture.JSONID).To(Equal("json-id"))
+		Expect(fixture.OtherUUID).To(Equal("permission-uuid"))
+		Expect(fixture.Other).To(HavePrefix("uaa-client:acl-"))
+		Expect(fixture.OtherV1).NotTo(Equal(fixture.Other))
+
+		Expect(admin.requests).To(HaveLen(4))
+		Expect(admin.requests[0].body).To(HaveKeyWithValue("name", fixture.JSON))
+		Expect(admin.requests[1].body).To(HaveKeyWithValue("name", fixture.Password))
+		Expect(admin.requests[2].body).To(HaveKeyWithValue("actor", fixture.Other))
+		Expect(admin.requests[3].path).To(Equal("/api/v1/permissions"))
+	})
+
+	It("fails when the admin is not allowed to create it", func() {
+		_, err := acl.NewFixture(&fakeClient{respond: func(request) (int, string) {
+			return http.StatusForbidden, `{"error": "denied"}`
+		}})
+		Expect(err).To(MatchError(`PUT /api/v1/data: expected status 200, got 403: {"error": "denied"}`))
+	})
+
+	It("grants on each credential or once on the wildcard", func() {
+		fixture, err := acl.NewFixture(admin)
+		Expect(err).NotTo(HaveOccurred())
+		admin.requests = nil
+
+		Expect(fixture.Grant(admin, "mtls-app:guid", acl.Exact, []acl.Permission{acl.Read})).To(Succeed())
+		Expect(fixture.Grant(admin, "mtls-app:guid", acl.Wildcard, []acl.Permission{acl.Read})).To(Succeed())
+		Expect(fixture.Grant(admin, "mtls-app:guid", acl.Wildcard, nil)).To(Succeed())
+
+		paths := []interface{}{}
+		for _, req := range admin.requests {
+			paths = append(paths, req.body["path"])
+		}
+		Expect(paths).To(Equal([]interface{}{fixture.JSON, fixture.Password, fixture.Root + "/*"}))
+	})
+
+	It("deletes the credentials and tracked actors' permissions", func() {
+		fixture, err := acl.NewFixture(admin)
+		Expect(err).NotTo(HaveOccurred())
+		fixture.Track("mtls-app:guid")
+		admin.requests = nil
+
+		fixture.Cleanup(admin)
+
+		var deleted []string
+		for _, req := range admin.requests {
+			if req.method == "DELETE" {
+				deleted = append(deleted, req.path+"?"+req.query.Encode())
+			}
+		}
+		Expect(deleted).To(Equal([]string{
+			"/api/v1/data?name=" + url.QueryEscape(fixture.JSON),
+			"/api/v1/data?name=" + url.QueryEscape(fixture.Password),
+			"/api/v2/permissions/wildcard-uuid?",
+			"/api/v2/permissions/wildcard-uuid?",
+			"/api/v2/permissions/wildcard-uuid?",
+		}))
+	})
+})
+
+var _ = Describe("API", func() {
+	var admin *fakeClient
+
+	BeforeEach(func() {
+		admin = &fakeClient{respond: func(req request) (int, string) {
+			switch {
+			case req.method == "POST":
+				return http.StatusCreated, `{}`
+			case req.method == "GET":
+				return http.StatusOK, `{"uuid": "permission-uuid"}`
+			case req.path == "/api/v1/permissions":
+				return http.StatusNoContent, ``
+			default:
+				return http.StatusOK, `{}`
+			}
+		}}
+	})
+
+	It("grants through either version", func() {
+		Expect(acl.V1.Grant(admin, "mtls-app:guid", "/a/b", []acl.Permission{acl.Read})).To(Succeed())
+		Expect(acl.V2.Grant(admin, "mtls-app:guid", "/a/*", []acl.Permission{acl.Read})).To(Succeed())
+
+		Expect(admin.requests[0].path).To(Equal("/api/v1/permissions"))
+		Expect(admin.requests[0].body).To(Equal(map[string]interface{}{
+			"credential_name": "/a/b",
+			"permissions":     []interface{}{map[string]interface{}{"actor": "mtls-app:guid", "operations": []interface{}{"read"}}},
+		}))
+		Expect(admin.requests[1].path).To(Equal("/api/v2/permissions"))
+		Expect(admin.requests[1].body).To(Equal(map[string]interface{}{
+			"path": "/a/*", "actor": "mtls-app:guid", "operations": []interface{}{"read"},
+		}))
+	})
+
+	It("only grants on credential names through v1", func() {
+		Expect(acl.V1.Grants("/a/*")).To(BeFalse())
+		Expect(acl.V2.Grants("/a/*")).To(BeTrue())
+		Expect(acl.V1.Grant(admin, "mtls-app:guid", "/a/*", []acl.Permission{acl.Read})).To(MatchError("the v1 API cannot grant on /a/*"))
+		Expect(admin.requests).To(BeEmpty())
+	})
+
+	It("revokes through either version", func() {
+		Expect(acl.V1.Revoke(admin, "mtls-app:guid", "/a/b")).To(Succeed())
+		Expect(acl.V2.Revoke(admin, "mtls-app:guid", "/a/*")).To(Succeed())
+
+		var sent []string
+		for _, req := range admin.requests {
+			sent = append(sent, req.method+" "+req.path+"?"+req.query.Encode())
+		}
+		Expect(sent).To(Equal([]string{
+			"DELETE /api/v1/permissions?actor=mtls-app%3Aguid&credential_name=%2Fa%2Fb",
+			"GET /api/v2/permissions?actor=mtls-app%3Aguid&path=%2Fa%2F%2A",
+			"DELETE /api/v2/permissions/permission-uuid?",
+		}))
+	})
+})
+
+var _ = Describe("Can", func() {
+	It("probes each permission with an operation that needs only it", func() {
+		actor := &fakeClient{respond: func(req request) (int, string) {
+			if req.method == "GET" {
+				return http.StatusOK, `{}`
+			}
+			return http.StatusForbidden, `{}`
+		}}
+
+		for permission, allowed := range map[acl.Permission]bool{
+			acl.Read: true, acl.Write: false, acl.Delete: false, acl.ReadACL: true, acl.WriteACL: false,
+		} {
+			can, err := acl.Can(actor, permission, "/a/b")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(can).To(Equal(allowed), string(permission))
+		}
+
+		_, err := acl.Can(actor, "unknown", "/a/b")
+		Expect(err).To(MatchError(`unknown permission "unknown"`))
+	})
+})
+
+var _ = Describe("Find", func() {
+	It("returns the names listed", func() {
+		actor := &fakeClient{respond: func(req request) (int, string) {
+			return http.StatusOK, `{"credentials": [{"name": "/a/b"}, {"name": "/a/c"}]}`
+		}}
+		names, err := acl.Find(actor, url.Values{"path": {"/a"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names).To(Equal([]string{"/a/b", "/a/c"}))
+		Expect(actor.requests[0].query.Get("path")).To(Equal("/a"))
+	})
+})
@@ -0,0 +1,115 @@
+package acl
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// API is a version of the permissions API.
+type API string
+
+const (
+	// V1 grants on credential names, through /api/v1/permissions. The
+	// credential must exist, and the name cannot be a wildcard.
+	V1 API = "v1"
+	// V2 grants on any path, through /api/v2/permissions.
+	V2 API = "v2"
+)
+
+// APIs lists both versions.
+var APIs = []API{V1, V2}
+
+// Grants reports whether the API can grant on a path.
+func (a API) Grants(path string) bool {
+	return a == V2 || !strings.HasSuffix(path, "*")
+}
+
+// Grant grants an actor permissions on a path through the API, as the admin.
+// Through V1, granting to an actor that already has a record on the path
+// adds to its operations.
+func (a API) Grant(admin Client, actor, path string, permissions []Permission) error {
+	if !a.Grants(path) {
+		return fmt.Errorf("the %s API cannot grant on %s", a, path)
+	}
+	if a == V1 {
+		return expect(admin, http.StatusCreated, nil, "POST", "/api/v1/permissions", nil, map[string]interface{}{
+			"credential_name": path,
+			"permissions":     []map[string]interface{}{{"actor": actor, "operations": permissions}},
+		})
+	}
+	return expect(admin, http.StatusCreated, nil, "POST", "/api/v2/permissions", nil, map[string]interface{}{
+		"path": path, "actor": actor, "operations": permissions,
+	})
+}
+
+// Revoke deletes an actor's record on a path through the API, as the admin.
+func (a API) Revoke(admin Client, actor, path string) error {
+	if a == V1 {
+		return expect(admin, http.StatusNoContent, nil, "DELETE", "/api/v1/permissions", url.Values{"credential_name": {path}, "actor": {actor}}, nil)
+	}
+	var found struct {
+		UUID string `json:"uuid"`
+	}
+	if err := expect(admin, http.StatusOK, &found, "GET", "/api/v2/permissions", url.Values{"path": {path}, "actor": {actor}}, nil); err != nil {
+		return err
+	}
+	return expect(admin, http.StatusOK, nil, "DELETE", "/api/v2/permissions/"+found.UUID, nil, nil)
+}
+
+// Can reports whether an actor may exercise a permission on a credential, by
+// attempting an operation that needs only that permission. Probing delete
+// deletes the credential when it is allowed, and probing write_acl grants
+// read to a new actor.
+func Can(actor Client, permission Permission, name string) (bool, error) {
+	var o Outcome
+	var err error
+	switch permission {
+	case Read:
+		o, err = outcome(send(actor, "GET", "/api/v1/data", url.Values{"name": {name}, "current": {"true"}}, nil))
+	case Write:
+		o, err = outcome(send(actor, "PUT", "/api/v1/data", nil, map[string]interface{}{
+			"name": name, "type": "value", "value": "probe",
+		}))
+	case Delete:
+		o, err = outcome(send(actor, "DELETE", "/api/v1/data", url.Values{"name": {name}}, nil))
+	case ReadACL:
+		o, err = outcome(send(actor, "GET", "/api/v1/permissions", url.Values{"credential_name": {name}}, nil))
+	case WriteACL:
+		o, err = outcome(send(actor, "POST", "/api/v2/permissions", nil, map[string]interface{}{
+			"path": name, "actor": "uaa-client:acl-probe-" + uuid.NewString(), "operations": []Permission{Read},
+		}))
+	default:
+		return false, fmt.Errorf("unknown permission %q", permission)
+	}
+	return o == Allowed, err
+}
+
+// Find returns the names a find lists for the actor, e.g. for
+// url.Values{"path": {"/some/path"}}.
+func Find(actor Client, query url.Values) ([]string, error) {
+	status, body, err := send(actor, "GET", "/api/v1/data", query, nil)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("find %s: status %d: %s", query.Encode(), status, body)
+	}
+	var found struct {
+		Credentials []struct {
+			Name string `json:"name"`
+		} `json:"credentials"`
+	}
+	if err := json.Unmarshal(body, &found); err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, credential := range found.Credentials {
+		names = append(names, credential.Name)
+	}
+	return names, nil
+}
@@ -0,0 +1,142 @@
+package acl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+)
+
+// Fixture is what an actor's operations act on: a JSON credential, which
+// reads and interpolation use, a password, which generation uses, and
+// permission records for other actors on the JSON credential, which the
+// permission operations read and change. Everything lives under a root of
+// its own.
+type Fixture struct {
+	Root         string
+	JSON         string
+	JSONID       string
+	Password     string
+	Other        string
+	OtherUUID    string
+	OtherV1      string
+	actors       []string
+	extraRecords []string
+}
+
+// NewFixture creates a fixture as the admin, which must be allowed to do
+// anything.
+func NewFixture(admin Client) (*Fixture, error) {
+	rootID := uuid.NewString()
+	f := &Fixture{Root: "/acl/" + rootID}
+	f.JSON = f.Root + "/json"
+	f.Password = f.Root + "/password"
+	f.Other = f.NewActor()
+	f.OtherV1 = f.NewActor()
+
+	var set struct {
+		ID string `json:"id"`
+	}
+	if err := expect(admin, http.StatusOK, &set, "PUT", "/api/v1/data", nil, map[string]interface{}{
+		"name": f.JSON, "type": "json", "value": map[string]string{"key": "value"},
+	}); err != nil {
+		return f, err
+	}
+	f.JSONID = set.ID
+
+	if err := expect(admin, http.StatusOK, nil, "POST", "/api/v1/data", nil, map[string]interface{}{
+		"name": f.Password, "type": "password",
+	}); err != nil {
+		return f, err
+	}
+
+	var permission struct {
+		UUID string `json:"uuid"`
+	}
+	if err := expect(admin, http.StatusCreated, &permission, "POST", "/api/v2/permissions", nil, map[string]interface{}{
+		"path": f.JSON, "actor": f.Other, "operations": []Permission{Read},
+	}); err != nil {
+		return f, err
+	}
+	f.OtherUUID = permission.UUID
+
+	return f, V1.Grant(admin, f.OtherV1, f.JSON, []Permission{Read})
+}
+
+// NewActor returns a UAA client actor no one has granted anything to, and
+// remembers it so Cleanup removes whatever it is granted later.
+func (f *Fixture) NewActor() string {
+	actor := "uaa-client:acl-" + uuid.NewString()
+	f.Track(actor)
+	return actor
+}
+
+// Track remembers an actor created elsewhere, such as the actor under test.
+func (f *Fixture) Track(actor string) {
+	f.actors = append(f.actors, actor)
+}
+
+// Paths are the paths a grant with the scope is made on.
+func (f *Fixture) Paths(scope Scope) []string {
+	if scope == Wildcard {
+		return []string{f.Root + "/*"}
+	}
+	return []string{f.JSON, f.Password}
+}
+
+// Grant grants the permissions to the actor as the admin. Granting no
+// permissions creates no records, since CredHub rejects empty ones.
+func (f *Fixture) Grant(admin Client, actor string, scope Scope, permissions []Permission) error {
+	if len(permissions) == 0 {
+		return nil
+	}
+	for _, path := range f.Paths(scope) {
+		if err := V2.Grant(admin, actor, path, permissions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Cleanup deletes, as the admin, the credentials and every permission record
+// of a tracked actor. Anything already gone is ignored.
+func (f *Fixture) Cleanup(admin Client) {
+	for _, name := range []string{f.JSON, f.Password} {
+		send(admin, "DELETE", "/api/v1/data", url.Values{"name": {name}}, nil)
+	}
+	paths := append(f.Paths(Exact), f.Paths(Wildcard)...)
+	for _, actor := range f.actors {
+		for _, path := range paths {
+			V2.Revoke(admin, actor, path)
+		}
+	}
+}
+
+func send(client Client, method, path string, query url.Values, body interface{}) (int, []byte, error) {
+	resp, err := client.Request(method, path, query, body, false)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	responseBody, err := io.ReadAll(resp.Body)
+	return resp.StatusCode, responseBody, err
+}
+
+// expect sends a request that must succeed with the status, and decodes the
+// response into into when it is not nil.
+func expect(client Client, status int, into interface{}, method, path string, query url.Values, body interface{}) error {
+	actual, responseBody, err := send(client, method, path, query, body)
+	if err != nil {
+		return err
+	}
+	if actual != status {
+		return fmt.Errorf("%s %s: expected status %d, got %d: %s", method, path, status, actual, responseBody)
+	}
+	if into == nil {
+		return nil
+	}
+	return json.Unmarshal(responseBody, into)
+}
@@ -0,0 +1,121 @@
+package acl
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Operations attempts every credential and permission operation on a
+// fixture. Operations that only read come first and deleting the credential
+// comes last, so each operation finds the fixture as it needs it whatever
+// the actor was allowed to do before.
+var Operations = []Operation{
+	{Name: "get by name", Requires: []Permission{Read}, Do: func(c Client, f *Fixture) (Outcome, error) {
+		return outcome(send(c, "GET", "/api/v1/data", url.Values{"name": {f.JSON}, "current": {"true"}}, nil))
+	}},
+	{Name: "get versions", Requires: []Permission{Read}, Do: func(c Client, f *Fixture) (Outcome, error) {
+		return outcome(send(c, "GET", "/api/v1/data", url.Values{"name": {f.JSON}, "versions": {"2"}}, nil))
+	}},
+	{Name: "get by id", Requires: []Permission{Read}, Do: func(c Client, f *Fixture) (Outcome, error) {
+		return outcome(send(c, "GET", "/api/v1/data/"+f.JSONID, nil, nil))
+	}},
+	{Name: "find by path", Requires: []Permission{Read}, Lists: true, Do: func(c Client, f *Fixture) (Outcome, error) {
+		return listing(c, url.Values{"path": {f.Root}}, f.JSON)
+	}},
+	{Name: "find by name", Requires: []Permission{Read}, Lists: true, Do: func(c Client, f *Fixture) (Outcome, error) {
+		return listing(c, url.Values{"name-like": {strings.TrimPrefix(f.Root, "/acl/")}}, f.JSON)
+	}},
+	{Name: "interpolate", Requires: []Permission{Read}, Do: func(c Client, f *Fixture) (Outcome, error) {
+		return outcome(send(c, "POST", "/api/v1/interpolate", nil, map[string]interface{}{
+			"service": []map[string]interface{}{{"credentials": map[string]string{"credhub-ref": "((" + f.JSON + "))"}}},
+		}))
+	}},
+	{Name: "get permissions v1", Requires: []Permission{ReadACL}, Do: func(c Client, f *Fixture) (Outcome, error) {
+		return outcome(send(c, "GET", "/api/v1/permissions", url.Values{"credential_name": {f.JSON}}, nil))
+	}},
+	{Name: "get permission", Requires: []Permission{ReadACL}, Do: func(c Client, f *Fixture) (Outcome, error) {
+		return outcome(send(c, "GET", "/api/v2/permissions/"+f.OtherUUID, nil, nil))
+	}},
+	{Name: "get permission by path and actor", Requires: []Permission{ReadACL}, Do: func(c Client, f *Fixture) (Outcome, error) {
+		return outcome(send(c, "GET", "/api/v2/permissions", url.Values{"path": {f.JSON}, "actor": {f.Other}}, nil))
+	}},
+
+	{Name: "set", Requires: []Permission{Write}, Do: func(c Client, f *Fixture) (Outcome, error) {
+		return outcome(send(c, "PUT", "/api/v1/data", nil, map[string]interface{}{
+			"name": f.JSON, "type": "json", "value": map[string]string{"key": "new-value"},
+		}))
+	}},
+	{Name: "generate", Requires: []Permission{Write}, Do: func(c Client, f *Fixture) (Outcome, error) {
+		return outcome(send(c, "POST", "/api/v1/data", nil, map[string]interface{}{
+			"name": f.Password, "type": "password", "mode": "overwrite",
+		}))
+	}},
+	{Name: "regenerate", Requires: []Permission{Write}, Do: func(c Client, f *Fixture) (Outcome, error) {
+		return outcome(send(c, "POST", "/api/v1/data", nil, map[string]interface{}{
+			"name": f.Password, "regenerate": true,
+		}))
+	}},
+
+	{Name: "add permission v1", Requires: []Permission{WriteACL}, Do: func(c Client, f *Fixture) (Outcome, error) {
+		return outcome(send(c, "POST", "/api/v1/permissions", nil, map[string]interface{}{
+			"credential_name": f.JSON,
+			"permissions":     []map[string]interface{}{{"actor": f.NewActor(), "operations": []Permission{Read}}},
+		}))
+	}},
+	{Name: "delete permission v1", Requires: []Permission{WriteACL}, Do: func(c Client, f *Fixture) (Outcome, error) {
+		return outcome(send(c, "DELETE", "/api/v1/permissions", url.Values{"credential_name": {f.JSON}, "actor": {f.OtherV1}}, nil))
+	}},
+	{Name: "add permission", Requires: []Permission{WriteACL}, Do: func(c Client, f *Fixture) (Outcome, error) {
+		return outcome(send(c, "POST", "/api/v2/permissions", nil, map[string]interface{}{
+			"path": f.JSON, "actor": f.NewActor(), "operations": []Permission{Read},
+		}))
+	}},
+	{Name: "put permission", Requires: []Permission{WriteACL}, Do: func(c Client, f *Fixture) (Outcome, error) {
+		return outcome(send(c, "PUT", "/api/v2/permissions/"+f.OtherUUID, nil, map[string]interface{}{
+			"path": f.JSON, "actor": f.Other, "operations": []Permission{Read, Write},
+		}))
+	}},
+	{Name: "patch permission", Requires: []Permission{WriteACL}, Do: func(c Client, f *Fixture) (Outcome, error) {
+		return outcome(send(c, "PATCH", "/api/v2/permissions/"+f.OtherUUID, nil, map[string]interface{}{
+			"operations": []Permission{Read},
+		}))
+	}},
+	{Name: "delete permission", Requires: []Permission{WriteACL}, Do: func(c Client, f *Fixture) (Outcome, error) {
+		return outcome(send(c, "DELETE", "/api/v2/permissions/"+f.OtherUUID, nil, nil))
+	}},
+
+	{Name: "delete", Requires: []Permission{Delete}, Do: func(c Client, f *Fixture) (Outcome, error) {
+		return outcome(send(c, "DELETE", "/api/v1/data", url.Values{"name": {f.JSON}}, nil))
+	}},
+}
+
+// outcome classifies a response: CredHub allows with a 2xx and denies with a
+// 403, or with a 404 where saying the credential exists would leak it.
+func outcome(status int, body []byte, err error) (Outcome, error) {
+	switch {
+	case err != nil:
+		return Denied, err
+	case status >= 200 && status < 300:
+		return Allowed, nil
+	case status == http.StatusForbidden || status == http.StatusNotFound:
+		return Denied, nil
+	default:
+		return Denied, fmt.Errorf("status %d: %s", status, body)
+	}
+}
+
+// listing classifies a find by whether it listed the credential.
+func listing(c Client, query url.Values, name string) (Outcome, error) {
+	names, err := Find(c, query)
+	if err != nil {
+		return Unlisted, err
+	}
+	for _, found := range names {
+		if found == name {
+			return Listed, nil
+		}
+	}
+	return Unlisted, nil
+}
@@ -0,0 +1,159 @@
+package acl
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client is the part of *credhub.CredHub the matrix uses, both as the actor
+// under test and as the admin that sets up its fixtures.
+type Client interface {
+	Request(method string, pathStr string, query url.Values, body interface{}, checkServerErr bool) (*http.Response, error)
+}
+
+// Permission is an operation a permission record grants on a path.
+type Permission string
+
+const (
+	Read     Permission = "read"
+	Write    Permission = "write"
+	Delete   Permission = "delete"
+	ReadACL  Permission = "read_acl"
+	WriteACL Permission = "write_acl"
+)
+
+// Permissions lists every permission CredHub knows.
+var Permissions = []Permission{Read, Write, Delete, ReadACL, WriteACL}
+
+// Combinations returns every subset of permissions, from none of them to all
+// of them, keeping the order they were given in.
+func Combinations(permissions []Permission) [][]Permission {
+	combinations := make([][]Permission, 0, 1<<len(permissions))
+	for mask := 0; mask < 1<<len(permissions); mask++ {
+		combination := []Permission{}
+		for i, permission := range permissions {
+			if mask&(1<<i) != 0 {
+				combination = append(combination, permission)
+			}
+		}
+		combinations = append(combinations, combination)
+	}
+	return combinations
+}
+
+// Describe names a combination, e.g. "read,write" or "none".
+func Describe(permissions []Permission) string {
+	if len(permissions) == 0 {
+		return "none"
+	}
+	names := make([]string, len(permissions))
+	for i, permission := range permissions {
+		names[i] = string(permission)
+	}
+	return strings.Join(names, ",")
+}
+
+// Scope is where a grant applies.
+type Scope int
+
+const (
+	// Exact grants on the name of each credential in the fixture.
+	Exact Scope = iota
+	// Wildcard grants once on the fixture's root followed by /*.
+	Wildcard
+)
+
+func (s Scope) String() string {
+	if s == Wildcard {
+		return "wildcard"
+	}
+	return "exact"
+}
+
+// Outcome is what an operation did, or should do, for an actor.
+type Outcome int
+
+const (
+	Allowed Outcome = iota
+	Denied
+	// Listed and Unlisted are the outcomes of finds, which succeed whatever
+	// the actor's permissions but only list credentials it can read.
+	Listed
+	Unlisted
+)
+
+func (o Outcome) String() string {
+	return [...]string{"allowed", "denied", "listed", "unlisted"}[o]
+}
+
+// Operation is a credential or permission operation an actor attempts.
+type Operation struct {
+	Name string
+	// Requires lists the permissions the actor needs, on every credential
+	// the operation touches.
+	Requires []Permission
+	// Lists is set for finds.
+	Lists bool
+	Do    func(client Client, fixture *Fixture) (Outcome, error)
+}
+
+// Expect is the outcome the policy model gives an actor granted permissions.
+func (o Operation) Expect(granted []Permission) Outcome {
+	allowed := true
+	for _, required := range o.Requires {
+		if !contains(granted, required) {
+			allowed = false
+		}
+	}
+	switch {
+	case o.Lists && allowed:
+		return Listed
+	case o.Lists:
+		return Unlisted
+	case allowed:
+		return Allowed
+	default:
+		return Denied
+	}
+}
+
+func contains(permissions []Permission, permission Permission) bool {
+	for _, p := range permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Result is an operation's outcome for an actor.
+type Result struct {
+	Operation string
+	Expected  Outcome
+	Actual    Outcome
+	// Err is set when the response was neither an allow nor a deny, such as
+	// a server error.
+	Err error
+}
+
+// Run attempts every operation as the actor, in order, and returns the
+// results that disagree with the policy model.
+func Run(operations []Operation, actor Client, fixture *Fixture, granted []Permission) []Result {
+	var mismatches []Result
+	for _, operation := range operations {
+		result := Result{Operation: operation.Name, Expected: operation.Expect(granted)}
+		result.Actual, result.Err = operation.Do(actor, fixture)
+		if result.Err != nil || result.Actual != result.Expected {
+			mismatches = append(mismatches, result)
+		}
+	}
+	return mismatches
+}
+
+func (r Result) String() string {
+	if r.Err != nil {
+		return r.Operation + ": expected " + r.Expected.String() + ", got " + r.Err.Error()
+	}
+	return r.Operation + ": expected " + r.Expected.String() + ", got " + r.Actual.String()
+}
@@ -0,0 +1,299 @@
+package actors_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/actors"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/certs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gexec"
+)
+
+// fakeServer is CredHub and UAA in one. It records each request with who
+// made it: the OU of the client certificate, or the client id behind the
+// bearer token.
+type fakeServer struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	requests []string
+	bodies   map[string]map[string]interface{}
+}
+
+func (s *fakeServer) record(r *http.Request, who string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path+" as "+who)
+
+	var body map[string]interface{}
+	if json.NewDecoder(r.Body).Decode(&body) == nil {
+		s.bodies[r.Method+" "+r.URL.Path] = body
+	}
+}
+
+func (s *fakeServer) recorded() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func newFakeServer(clientCA []byte) *fakeServer {
+	s := &fakeServer{bodies: map[string]map[string]interface{}{}}
+	s.Server = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		who := "anonymous"
+		if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+			who = strings.Join(r.TLS.PeerCertificates[0].Subject.OrganizationalUnit, ",")
+		}
+		if token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "); token != "" {
+			who = token
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/info":
+			json.NewEncoder(w).Encode(map[string]interface{}{"auth-server": map[string]string{"url": s.URL}})
+			return
+		case r.URL.Path == "/oauth/token":
+			r.ParseForm()
+			who := r.Form.Get("client_id")
+			if username := r.Form.Get("username"); username != "" {
+				who += "/" + username
+			}
+			s.record(r, "anonymous")
+			json.NewEncoder(w).Encode(map[string]string{"access_token": "token-for-" + who, "token_type": "bearer"})
+			return
+		}
+
+		s.record(r, who)
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/oauth/clients":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{}`))
+		case r.Method == "POST" && r.URL.Path == "/Users":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "user-id"}`))
+		case r.Method == "GET" && r.URL.Path == "/Groups":
+			w.Write([]byte(`{"resources": [{"id": "group-id"}]}`))
+		case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/members"):
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{}`))
+		case r.Method == "POST" && r.URL.Path == "/api/v2/permissions":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"uuid": "permission-uuid"}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(clientCA)
+	s.Server.TLS = &tls.Config{ClientAuth: tls.VerifyClientCertIfGiven, ClientCAs: pool}
+	s.Server.Config.ErrorLog = log.New(GinkgoWriter, "", 0)
+	s.StartTLS()
+	return s
+}
+
+func (s *fakeServer) caPEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw})
+}
+
+var _ = Describe("Factory", func() {
+	var (
+		clientCACert, clientCAKey []byte
+		server                    *fakeServer
+		options                   actors.Options
+		factory                   *actors.Factory
+	)
+
+	BeforeEach(func() {
+		var err error
+		clientCACert, clientCAKey, err = certs.GenerateSelfSigned(certs.CertOptions{CommonName: "client-ca", IsCA: true})
+		Expect(err).NotTo(HaveOccurred())
+
+		server = newFakeServer(clientCACert)
+		DeferCleanup(server.Close)
+
+		options = actors.Options{
+			ApiUrl:       server.URL,
+			CredhubCA:    server.caPEM(),
+			UAACA:        server.caPEM(),
+			ClientCACert: clientCACert,
+			ClientCAKey:  clientCAKey,
+			AdminClient:  "admin",
+			AdminSecret:  "admin-secret",
+		}
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		factory, err = actors.New(options)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(factory.Close)
+	})
+
+	get := func(identity *actors.Identity) {
+		resp, err := identity.Client.Request("GET", "/api/v1/data", nil, nil, true)
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+
+		resp, err = identity.HTTP.Get(server.URL + "/api/v1/data")
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+	}
+
+	Describe("certificate identities", func() {
+		It("mints mTLS apps signed by the client CA", func() {
+			identity, err := factory.MTLSApp()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(identity.Actor).To(MatchRegexp(`^mtls-app:[0-9a-f-]{36}$`))
+			Expect(identity.CLI).To(BeNil())
+
+			certPEM, err := ioutil.ReadFile(identity.CertPath)
+			Expect(err).NotTo(HaveOccurred())
+			block, _ := pem.Decode(certPEM)
+			cert, err := x509.ParseCertificate(block.Bytes)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cert).To(certs.BeValidCertSignedBy(clientCACert))
+
+			get(identity)
+			app := "app:" + strings.TrimPrefix(identity.Actor, "mtls-app:")
+			Expect(server.recorded()).To(Equal([]string{
+				"GET /api/v1/data as " + app,
+				"GET /api/v1/data as " + app,
+			}))
+
+			Expect(identity.Cleanup()).To(Succeed())
+			Expect(filepath.Dir(identity.CertPath)).NotTo(BeADirectory())
+		})
+
+		It("mints certificates the server rejects", func() {
+			expired, err := factory.ExpiredCertificate()
+			Expect(err).NotTo(HaveOccurred())
+			_, err = expired.HTTP.Get(server.URL + "/api/v1/data")
+			Expect(err).To(MatchError(ContainSubstring("expired certificate")))
+
+			// Go only presents a certificate signed by a CA the server asks
+			// for, so the untrusted one arrives unauthenticated instead.
+			untrusted, err := factory.UntrustedCertificate()
+			Expect(err).NotTo(HaveOccurred())
+			resp, err := untrusted.HTTP.Get(server.URL + "/api/v1/data")
+			Expect(err).NotTo(HaveOccurred())
+			resp.Body.Close()
+			Expect(server.recorded()).To(Equal([]string{"GET /api/v1/data as anonymous"}))
+
+			selfSigned, err := factory.SelfSignedCertificate()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(selfSigned.Actor).To(BeEmpty())
+		})
+	})
+
+	Describe("UAA identities", func() {
+		It("needs a UAA admin client", func() {
+			_, err := factory.UAAClient()
+			Expect(err).To(Equal(actors.ErrNoUAAAdmin))
+			_, err = factory.UAAUser()
+			Expect(err).To(Equal(actors.ErrNoUAAAdmin))
+		})
+
+		Context("with a UAA admin client", func() {
+			BeforeEach(func() {
+				options.UaaAdminClient = "uaa-admin"
+				options.UaaAdminSecret = "uaa-admin-secret"
+			})
+
+			It("mints clients with the scopes given", func() {
+				identity, err := factory.UAAClient("credhub.read")
+				Expect(err).NotTo(HaveOccurred())
+				clientID := strings.TrimPrefix(identity.Actor, "uaa-client:")
+				Expect(clientID).To(HavePrefix("credhub-acceptance-"))
+				Expect(server.bodies["POST /oauth/clients"]).To(HaveKeyWithValue("authorities", []interface{}{"credhub.read"}))
+				Expect(identity.CLI.Env).To(ContainElements("CREDHUB_CLIENT="+clientID, "CREDHUB_SERVER="+server.URL))
+
+				get(identity)
+				Expect(identity.Cleanup()).To(Succeed())
+				Expect(server.recorded()).To(Equal([]string{
+					"POST /oauth/token as anonymous",
+					"POST /oauth/clients as token-for-uaa-admin",
+					"POST /oauth/token as anonymous",
+					"GET /api/v1/data as token-for-" + clientID,
+					"GET /api/v1/data as token-for-" + clientID,
+					"DELETE /oauth/clients/" + clientID + " as token-for-uaa-admin",
+				}))
+			})
+
+			It("mints users in the default groups", func() {
+				identity, err := factory.UAAUser()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(identity.Actor).To(Equal("uaa-user:user-id"))
+
+				get(identity)
+				Expect(identity.Cleanup()).To(Succeed())
+				requests := server.recorded()
+				Expect(requests).To(ContainElements(
+					"GET /Groups as token-for-uaa-admin",
+					"POST /Groups/group-id/members as token-for-uaa-admin",
+					"DELETE /Users/user-id as token-for-uaa-admin",
+				))
+				Expect(requests).To(ContainElement(MatchRegexp(`^GET /api/v1/data as token-for-credhub_cli/credhub-acceptance-`)))
+				Expect(server.bodies["POST /Groups/group-id/members"]).To(HaveKeyWithValue("value", "user-id"))
+			})
+		})
+	})
+
+	It("grants permissions until the identity is cleaned up", func() {
+		identity, err := factory.MTLSApp()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(factory.Grant(identity, "/*", "read", "write")).To(Succeed())
+		Expect(server.bodies["POST /api/v2/permissions"]).To(Equal(map[string]interface{}{
+			"actor": identity.Actor, "path": "/*", "operations": []interface{}{"read", "write"},
+		}))
+
+		Expect(identity.Cleanup()).To(Succeed())
+		Expect(server.recorded()).To(ContainElement("DELETE /api/v2/permissions/permission-uuid as token-for-admin"))
+	})
+})
+
+var _ = Describe("CLI", func() {
+	var commandPath string
+
+	BeforeEach(func() {
+		// A CLI that prints its arguments and environment.
+		dir, err := ioutil.TempDir("", "actors-cli")
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(os.RemoveAll, dir)
+		script := filepath.Join(dir, "credhub")
+		Expect(ioutil.WriteFile(script, []byte("#!/bin/sh\necho \"args: $*\"\nenv\n"), 0755)).To(Succeed())
+
+		commandPath = test_helpers.CommandPath
+		test_helpers.CommandPath = script
+		DeferCleanup(func() { test_helpers.CommandPath = commandPath })
+
+		os.Setenv("CREDHUB_SECRET", "leaked")
+		DeferCleanup(os.Unsetenv, "CREDHUB_SECRET")
+	})
+
+	It("runs in an environment of its own", func() {
+		cli := &actors.CLI{Home: "/cli-home", Env: []string{"HOME=/cli-home", "CREDHUB_CLIENT=client"}}
+		session := cli.Run("get", "-n", "/some-cred")
+		Expect(session).To(Exit(0))
+
+		output := string(session.Out.Contents())
+		Expect(output).To(ContainSubstring("args: get -n /some-cred\n"))
+		Expect(output).To(ContainSubstring("HOME=/cli-home\n"))
+		Expect(output).To(ContainSubstring("CREDHUB_CLIENT=client\n"))
+		Expect(output).NotTo(ContainSubstring("CREDHUB_SECRET"))
+	})
+})
@@ -0,0 +1,123 @@
+package actors
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/certs"
+	"github.com/google/uuid"
+)
+
+// CommonName is the common name of every certificate the factory signs.
+const CommonName = "credhub_test_client"
+
+// MTLSApp mints an app identity, "mtls-app:<guid>", whose certificate the
+// client CA signed.
+func (f *Factory) MTLSApp() (*Identity, error) {
+	guid := uuid.NewString()
+	cert, key, err := certs.GenerateSigned(certs.CertOptions{
+		CommonName:         CommonName,
+		OrganizationalUnit: "app:" + guid,
+	}, f.options.ClientCACert, f.options.ClientCAKey)
+	if err != nil {
+		return nil, err
+	}
+	return f.certificateIdentity("mtls-app:"+guid, cert, key)
+}
+
+// ExpiredCertificate mints an app identity whose certificate the client CA
+// signed, but which expired five days ago.
+func (f *Factory) ExpiredCertificate() (*Identity, error) {
+	guid := uuid.NewString()
+	cert, key, err := certs.GenerateSigned(certs.CertOptions{
+		CommonName:         CommonName,
+		OrganizationalUnit: "app:" + guid,
+		NotBefore:          time.Now().Add(time.Hour * 24 * -10),
+		NotAfter:           time.Now().Add(time.Hour * 24 * -5),
+	}, f.options.ClientCACert, f.options.ClientCAKey)
+	if err != nil {
+		return nil, err
+	}
+	return f.certificateIdentity("mtls-app:"+guid, cert, key)
+}
+
+// UntrustedCertificate mints an app identity whose certificate a CA CredHub
+// does not trust signed.
+func (f *Factory) UntrustedCertificate() (*Identity, error) {
+	caCert, caKey, err := certs.GenerateSelfSigned(certs.CertOptions{IsCA: true})
+	if err != nil {
+		return nil, err
+	}
+	guid := uuid.NewString()
+	cert, key, err := certs.GenerateSigned(certs.CertOptions{
+		CommonName:         CommonName,
+		OrganizationalUnit: "app:" + guid,
+	}, caCert, caKey)
+	if err != nil {
+		return nil, err
+	}
+	return f.certificateIdentity("mtls-app:"+guid, cert, key)
+}
+
+// SelfSignedCertificate mints an identity with a self-signed certificate.
+// It has no actor name, since the certificate names no app.
+func (f *Factory) SelfSignedCertificate() (*Identity, error) {
+	cert, key, err := certs.GenerateSelfSigned(certs.CertOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return f.certificateIdentity("", cert, key)
+}
+
+func (f *Factory) certificateIdentity(actor string, cert, key []byte) (*Identity, error) {
+	identity := &Identity{Actor: actor}
+
+	dir, err := f.tempDir("certificate")
+	if err != nil {
+		return nil, err
+	}
+	identity.onCleanup(func() error { return os.RemoveAll(dir) })
+
+	identity.CertPath = filepath.Join(dir, "cert.pem")
+	identity.KeyPath = filepath.Join(dir, "key.pem")
+	if err := ioutil.WriteFile(identity.CertPath, cert, 0644); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(identity.KeyPath, key, 0600); err != nil {
+		return nil, err
+	}
+
+	client, err := credhub.New(f.options.ApiUrl,
+		credhub.CaCerts(string(f.options.CredhubCA), string(f.options.UAACA)),
+		credhub.ClientCert(identity.CertPath, identity.KeyPath),
+	)
+	if err != nil {
+		return nil, err
+	}
+	identity.Client = test_helpers.InstrumentClient(client)
+
+	keyPair, err := tls.X509KeyPair(cert, key)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := f.tlsConfig()
+	tlsConfig.Certificates = []tls.Certificate{keyPair}
+	transport := &http.Transport{TLSClientConfig: tlsConfig}
+	identity.HTTP = &http.Client{Transport: test_helpers.InstrumentTransport(transport)}
+	return identity, nil
+}
+
+// tlsConfig trusts CredHub's and UAA's CAs.
+func (f *Factory) tlsConfig() *tls.Config {
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(f.options.CredhubCA)
+	pool.AppendCertsFromPEM(f.options.UAACA)
+	return &tls.Config{RootCAs: pool}
+}
@@ -0,0 +1,84 @@
+package actors
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/reporting"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+)
+
+// CLI runs the CLI built at test_helpers.CommandPath as one identity. It has
+// a home directory and environment of its own, so contexts neither share a
+// login nor change the environment of the process.
+type CLI struct {
+	Home string
+	// Env is added to the process environment, without any CREDHUB_
+	// variables of its own.
+	Env []string
+
+	login    []string
+	loggedIn bool
+}
+
+func (f *Factory) newCLI(env map[string]string, login []string) (*CLI, error) {
+	home, err := f.tempDir("cli")
+	if err != nil {
+		return nil, err
+	}
+	cli := &CLI{
+		Home: home,
+		Env: []string{
+			"HOME=" + home,
+			"USERPROFILE=" + home,
+			"CREDHUB_CA_CERT=" + string(f.options.UAACA) + string(f.options.CredhubCA),
+		},
+	}
+	if login != nil {
+		cli.login = append(login, "-s", f.options.ApiUrl)
+	} else {
+		cli.Env = append(cli.Env, "CREDHUB_SERVER="+f.options.ApiUrl)
+	}
+	for name, value := range env {
+		cli.Env = append(cli.Env, name+"="+value)
+	}
+	return cli, nil
+}
+
+// Run runs a CLI command, logging in first if the identity is a user that
+// has not yet.
+func (c *CLI) Run(args ...string) *gexec.Session {
+	if c.login != nil && !c.loggedIn {
+		Expect(c.run(c.login...)).To(gexec.Exit(0))
+		c.loggedIn = true
+	}
+	return c.run(args...)
+}
+
+func (c *CLI) run(args ...string) *gexec.Session {
+	cmd := exec.Command(test_helpers.CommandPath, args...)
+	cmd.Env = c.environ()
+
+	session, err := gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
+	Expect(err).NotTo(HaveOccurred())
+	<-session.Exited
+
+	reporting.RecordCommand(args, session.Out.Contents())
+	return session
+}
+
+func (c *CLI) environ() []string {
+	var env []string
+	for _, variable := range os.Environ() {
+		name := strings.SplitN(variable, "=", 2)[0]
+		if strings.HasPrefix(name, "CREDHUB_") || name == "HOME" || name == "USERPROFILE" {
+			continue
+		}
+		env = append(env, variable)
+	}
+	return append(env, c.Env...)
+}
@@ -0,0 +1,118 @@
+// Package actors mints identities for authorization specs: UAA clients and
+// users, mTLS app certificates, and certificates CredHub should reject. Each
+// identity comes as a Go client, an http.Client and, where the CLI can
+// authenticate as it, an isolated CLI context.
+package actors
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/auth"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+)
+
+// Options are what a Factory needs to know about the CredHub it mints
+// identities for.
+type Options struct {
+	ApiUrl    string
+	CredhubCA []byte
+	UAACA     []byte
+	// ClientCACert and ClientCAKey sign mTLS app certificates.
+	ClientCACert []byte
+	ClientCAKey  []byte
+	// AdminClient and AdminSecret are a UAA client allowed to grant
+	// permissions on anything.
+	AdminClient string
+	AdminSecret string
+	// UaaAdminClient and UaaAdminSecret are a UAA client with clients.write,
+	// scim.write and scim.read, which UAA clients and users are minted with.
+	UaaAdminClient string
+	UaaAdminSecret string
+}
+
+// Factory mints identities. Every client it returns is instrumented.
+type Factory struct {
+	options Options
+	admin   *credhub.CredHub
+	dir     string
+}
+
+// New creates a factory, and a temporary directory for the certificates and
+// CLI homes of its identities which Close removes.
+func New(options Options) (*Factory, error) {
+	admin, err := credhub.New(options.ApiUrl,
+		credhub.CaCerts(string(options.CredhubCA), string(options.UAACA)),
+		credhub.Auth(auth.UaaClientCredentials(options.AdminClient, options.AdminSecret)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := ioutil.TempDir("", "credhub-acceptance-actors")
+	if err != nil {
+		return nil, err
+	}
+	return &Factory{options: options, admin: test_helpers.InstrumentClient(admin), dir: dir}, nil
+}
+
+// FromConfig creates a factory for the CredHub the test config targets, with
+// the client CA in its credential root.
+func FromConfig(cfg test_helpers.Config) (*Factory, error) {
+	options := Options{
+		ApiUrl:      cfg.ApiUrl,
+		AdminClient: cfg.ClientName,
+		AdminSecret: cfg.ClientSecret,
+	}
+	if cfg.UAAAdmin != nil {
+		options.UaaAdminClient = cfg.UAAAdmin.Client
+		options.UaaAdminSecret = cfg.UAAAdmin.Secret
+	}
+
+	for path, contents := range map[string]*[]byte{
+		filepath.Join(cfg.CredentialRoot, "server_ca_cert.pem"): &options.CredhubCA,
+		cfg.UAACa: &options.UAACA,
+		filepath.Join(cfg.CredentialRoot, "client_ca_cert.pem"):    &options.ClientCACert,
+		filepath.Join(cfg.CredentialRoot, "client_ca_private.pem"): &options.ClientCAKey,
+	} {
+		var err error
+		if *contents, err = ioutil.ReadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	return New(options)
+}
+
+// Admin is a client authenticated as the admin client.
+func (f *Factory) Admin() *credhub.CredHub {
+	return f.admin
+}
+
+// Close removes the factory's temporary directory.
+func (f *Factory) Close() error {
+	return os.RemoveAll(f.dir)
+}
+
+// Grant grants an identity operations on a path as the admin, and deletes
+// the grant when the identity is cleaned up.
+func (f *Factory) Grant(identity *Identity, path string, operations ...string) error {
+	var created struct {
+		UUID string `json:"uuid"`
+	}
+	if err := request(f.admin, 201, &created, "POST", "/api/v2/permissions", map[string]interface{}{
+		"actor": identity.Actor, "path": path, "operations": operations,
+	}); err != nil {
+		return fmt.Errorf("granting %v on %s to %s: %s", operations, path, identity.Actor, err)
+	}
+	identity.onCleanup(func() error {
+		return request(f.admin, 200, nil, "DELETE", "/api/v2/permissions/"+created.UUID, nil)
+	})
+	return nil
+}
+
+func (f *Factory) tempDir(prefix string) (string, error) {
+	return ioutil.TempDir(f.dir, prefix)
+}
@@ -0,0 +1,140 @@
+package actors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/auth"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// Identity is one actor, ready to use.
+type Identity struct {
+	// Actor is the identity's name in permission records, e.g.
+	// "mtls-app:<guid>" or "uaa-client:<client id>".
+	Actor  string
+	Client *credhub.CredHub
+	// HTTP authenticates raw requests as the identity, and records them as
+	// such rather than as the Go client's.
+	HTTP *http.Client
+	// CLI is nil for certificate identities, which the CLI cannot present.
+	CLI *CLI
+	// CertPath and KeyPath are set for certificate identities.
+	CertPath string
+	KeyPath  string
+
+	cleanups []func() error
+}
+
+// Mint creates an identity for the current spec with one of the factory's
+// methods, e.g. Mint(factory.MTLSApp), and cleans it up when the spec ends.
+// It skips the spec when newIdentity needs uaa_admin and it is not set.
+func Mint(newIdentity func() (*Identity, error)) *Identity {
+	identity, err := newIdentity()
+	if err == ErrNoUAAAdmin {
+		Skip("uaa_admin is not set")
+	}
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	DeferCleanup(identity.Cleanup)
+	return identity
+}
+
+// Cleanup deletes the identity's grants and the identity itself, newest
+// first, and returns the first error.
+func (i *Identity) Cleanup() error {
+	var first error
+	for j := len(i.cleanups) - 1; j >= 0; j-- {
+		if err := i.cleanups[j](); err != nil && first == nil {
+			first = err
+		}
+	}
+	i.cleanups = nil
+	return first
+}
+
+func (i *Identity) onCleanup(cleanup func() error) {
+	i.cleanups = append(i.cleanups, cleanup)
+}
+
+// bearer authenticates requests with the access token of an OAuth strategy,
+// logging in on the first request.
+type bearer struct {
+	strategy *auth.OAuthStrategy
+	inner    http.RoundTripper
+	login    sync.Once
+	err      error
+}
+
+func (b *bearer) RoundTrip(req *http.Request) (*http.Response, error) {
+	b.login.Do(func() { b.err = b.strategy.Login() })
+	if b.err != nil {
+		return nil, b.err
+	}
+	authenticated := req.Clone(req.Context())
+	authenticated.Header.Set("Authorization", "Bearer "+b.strategy.AccessToken())
+	return b.inner.RoundTrip(authenticated)
+}
+
+// request sends an authenticated request that must succeed with the status,
+// and decodes the response into into when it is not nil.
+func request(client *credhub.CredHub, status int, into interface{}, method, path string, body interface{}) error {
+	resp, err := client.Request(method, path, nil, body, false)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != status {
+		return fmt.Errorf("%s %s: expected status %d, got %d: %s", method, path, status, resp.StatusCode, responseBody)
+	}
+	if into == nil {
+		return nil
+	}
+	return json.Unmarshal(responseBody, into)
+}
+
+// doJSON sends a request with a JSON body and a bearer token, for the UAA
+// API, which the Go client does not cover.
+func doJSON(client *http.Client, token string, status int, into interface{}, method, url string, body interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != status {
+		return fmt.Errorf("%s %s: expected status %d, got %d: %s", method, url, status, resp.StatusCode, responseBody)
+	}
+	if into == nil {
+		return nil
+	}
+	return json.Unmarshal(responseBody, into)
+}
@@ -0,0 +1,181 @@
+package actors
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/auth"
+	"code.cloudfoundry.org/credhub-cli/credhub/auth/uaa"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+	"github.com/google/uuid"
+)
+
+// ErrNoUAAAdmin is returned when minting a UAA identity without a UAA admin
+// client configured. Specs usually skip on it.
+var ErrNoUAAAdmin = errors.New("no UAA admin client is configured")
+
+// DefaultScopes are what UAA identities are given when minted without any.
+var DefaultScopes = []string{"credhub.read", "credhub.write"}
+
+// cliClient is the UAA client the CLI logs users in with.
+const cliClient = "credhub_cli"
+
+// UAAClient mints a client credentials identity, "uaa-client:<client id>",
+// with the authorities given, or DefaultScopes.
+func (f *Factory) UAAClient(scopes ...string) (*Identity, error) {
+	if len(scopes) == 0 {
+		scopes = DefaultScopes
+	}
+	admin, err := f.uaaAdmin()
+	if err != nil {
+		return nil, err
+	}
+
+	clientID := "credhub-acceptance-" + uuid.NewString()
+	clientSecret := uuid.NewString()
+	if err := admin.do(http.StatusCreated, nil, "POST", "/oauth/clients", map[string]interface{}{
+		"client_id":              clientID,
+		"client_secret":          clientSecret,
+		"authorized_grant_types": []string{"client_credentials"},
+		"authorities":            scopes,
+		"scope":                  []string{"uaa.none"},
+	}); err != nil {
+		return nil, err
+	}
+	identity := &Identity{Actor: "uaa-client:" + clientID}
+	identity.onCleanup(func() error {
+		return admin.do(http.StatusOK, nil, "DELETE", "/oauth/clients/"+url.PathEscape(clientID), nil)
+	})
+
+	if err := f.uaaIdentity(identity, auth.UaaClientCredentials(clientID, clientSecret)); err != nil {
+		identity.Cleanup()
+		return nil, err
+	}
+	identity.CLI, err = f.newCLI(map[string]string{
+		"CREDHUB_CLIENT": clientID,
+		"CREDHUB_SECRET": clientSecret,
+	}, nil)
+	if err != nil {
+		identity.Cleanup()
+		return nil, err
+	}
+	return identity, nil
+}
+
+// UAAUser mints a password grant identity, "uaa-user:<user id>", that is a
+// member of the groups given, or of DefaultScopes. It logs in through the
+// credhub_cli client, like a person using the CLI.
+func (f *Factory) UAAUser(groups ...string) (*Identity, error) {
+	if len(groups) == 0 {
+		groups = DefaultScopes
+	}
+	admin, err := f.uaaAdmin()
+	if err != nil {
+		return nil, err
+	}
+
+	username := "credhub-acceptance-" + uuid.NewString()
+	password := uuid.NewString()
+	var user struct {
+		ID string `json:"id"`
+	}
+	if err := admin.do(http.StatusCreated, &user, "POST", "/Users", map[string]interface{}{
+		"userName": username,
+		"password": password,
+		"emails":   []map[string]string{{"value": username + "@example.com"}},
+	}); err != nil {
+		return nil, err
+	}
+	identity := &Identity{Actor: "uaa-user:" + user.ID}
+	identity.onCleanup(func() error {
+		return admin.do(http.StatusOK, nil, "DELETE", "/Users/"+user.ID, nil)
+	})
+
+	for _, group := range groups {
+		if err := admin.addMember(group, user.ID); err != nil {
+			identity.Cleanup()
+			return nil, err
+		}
+	}
+
+	if err := f.uaaIdentity(identity, auth.UaaPassword(cliClient, "", username, password)); err != nil {
+		identity.Cleanup()
+		return nil, err
+	}
+	identity.CLI, err = f.newCLI(nil, []string{"login", "-u", username, "-p", password})
+	if err != nil {
+		identity.Cleanup()
+		return nil, err
+	}
+	return identity, nil
+}
+
+// uaaIdentity gives an identity a client and an http.Client that
+// authenticate with the builder.
+func (f *Factory) uaaIdentity(identity *Identity, builder auth.Builder) error {
+	client, err := credhub.New(f.options.ApiUrl,
+		credhub.CaCerts(string(f.options.CredhubCA), string(f.options.UAACA)),
+		credhub.Auth(builder),
+	)
+	if err != nil {
+		return err
+	}
+	identity.Client = test_helpers.InstrumentClient(client)
+
+	strategy, ok := client.Auth.(*auth.OAuthStrategy)
+	if !ok {
+		return errors.New("UAA identities need an OAuth strategy")
+	}
+	transport := &http.Transport{TLSClientConfig: f.tlsConfig()}
+	identity.HTTP = &http.Client{Transport: &bearer{strategy: strategy, inner: test_helpers.InstrumentTransport(transport)}}
+	return nil
+}
+
+// uaaAdmin is the UAA API, as the UAA admin client.
+type uaaAdmin struct {
+	url    string
+	client *http.Client
+	token  string
+}
+
+func (f *Factory) uaaAdmin() (*uaaAdmin, error) {
+	if f.options.UaaAdminClient == "" {
+		return nil, ErrNoUAAAdmin
+	}
+	authURL, err := f.admin.AuthURL()
+	if err != nil {
+		return nil, err
+	}
+
+	// The UAA API is not CredHub's, so its requests are not instrumented.
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: f.tlsConfig()}}
+	token, err := (&uaa.Client{AuthURL: authURL, Client: client}).ClientCredentialGrant(f.options.UaaAdminClient, f.options.UaaAdminSecret)
+	if err != nil {
+		return nil, err
+	}
+	return &uaaAdmin{url: authURL, client: client, token: token}, nil
+}
+
+func (a *uaaAdmin) do(status int, into interface{}, method, path string, body interface{}) error {
+	return doJSON(a.client, a.token, status, into, method, a.url+path, body)
+}
+
+func (a *uaaAdmin) addMember(group, userID string) error {
+	var groups struct {
+		Resources []struct {
+			ID string `json:"id"`
+		} `json:"resources"`
+	}
+	filter := url.Values{"filter": {`displayName eq "` + group + `"`}}
+	if err := a.do(http.StatusOK, &groups, "GET", "/Groups?"+filter.Encode(), nil); err != nil {
+		return err
+	}
+	if len(groups.Resources) == 0 {
+		return errors.New("UAA has no group " + group)
+	}
+	return a.do(http.StatusCreated, nil, "POST", "/Groups/"+groups.Resources[0].ID+"/members", map[string]string{
+		"origin": "uaa", "type": "USER", "value": userID,
+	})
+}
@@ -0,0 +1,127 @@
+package apierrors_test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os/exec"
+	"strings"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/apierrors"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("Condition", func() {
+	It("matches whole messages unless partial", func() {
+		Expect(apierrors.TypeMismatch.Matches(apierrors.TypeMismatch.Message)).To(BeTrue())
+		Expect(apierrors.TypeMismatch.Matches(apierrors.TypeMismatch.Message + " More.")).To(BeFalse())
+		Expect(apierrors.MalformedKey.Matches(apierrors.MalformedKey.Message + ".")).To(BeTrue())
+	})
+
+	It("catalogues every condition once", func() {
+		names := map[string]bool{}
+		for _, c := range apierrors.Catalogue {
+			Expect(names).NotTo(HaveKey(c.Name))
+			names[c.Name] = true
+			Expect(c.Status).To(BeNumerically(">=", 400))
+		}
+		Expect(names).To(HaveLen(11))
+	})
+})
+
+var _ = Describe("MatchResponse", func() {
+	body := fmt.Sprintf(`{"error": %q}`, apierrors.NotFound.Message)
+
+	It("matches the status and an error body", func() {
+		Expect(apierrors.Response{Status: 404, Body: []byte(body)}).To(apierrors.MatchResponse(apierrors.NotFound))
+	})
+
+	It("reads an *http.Response", func() {
+		response := &http.Response{StatusCode: 404, Body: ioutil.NopCloser(strings.NewReader(body))}
+		Expect(response).To(apierrors.MatchResponse(apierrors.NotFound))
+	})
+
+	It("rejects another status, message or body", func() {
+		matcher := apierrors.MatchResponse(apierrors.NotFound)
+		Expect(apierrors.Response{Status: 403, Body: []byte(body)}).NotTo(matcher)
+		Expect(apierrors.Response{Status: 404, Body: []byte(`{"error": "other"}`)}).NotTo(matcher)
+		Expect(apierrors.Response{Status: 404, Body: []byte(`not json`)}).NotTo(matcher)
+
+		extra := fmt.Sprintf(`{"error": %q, "trace": "x"}`, apierrors.NotFound.Message)
+		ok, err := matcher.Match(apierrors.Response{Status: 404, Body: []byte(extra)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+		Expect(matcher.FailureMessage(nil)).To(ContainSubstring("the body was not only an error"))
+	})
+
+	It("explains a mismatched status", func() {
+		matcher := apierrors.MatchResponse(apierrors.Forbidden)
+		ok, _ := matcher.Match(apierrors.Response{Status: 404, Body: []byte(body)})
+		Expect(ok).To(BeFalse())
+		Expect(matcher.FailureMessage(nil)).To(ContainSubstring("to be the forbidden error, 403"))
+		Expect(matcher.FailureMessage(nil)).To(ContainSubstring("but the status was 404"))
+	})
+})
+
+var _ = Describe("MatchClientError", func() {
+	It("matches a not found error for a 404", func() {
+		Expect(&credhub.NotFoundError{Description: apierrors.NotFound.Message}).To(apierrors.MatchClientError(apierrors.NotFound))
+		Expect(&credhub.Error{Name: apierrors.NotFound.Message}).NotTo(apierrors.MatchClientError(apierrors.NotFound))
+	})
+
+	It("matches a CredHub error otherwise, wrapped or not", func() {
+		err := &credhub.Error{Name: apierrors.TypeMismatch.Message}
+		Expect(err).To(apierrors.MatchClientError(apierrors.TypeMismatch))
+		Expect(fmt.Errorf("setting: %w", err)).To(apierrors.MatchClientError(apierrors.TypeMismatch))
+		Expect(err).NotTo(apierrors.MatchClientError(apierrors.InvalidParameter))
+	})
+
+	It("rejects errors with a description, other errors and no error", func() {
+		Expect(&credhub.Error{Name: apierrors.TypeMismatch.Message, Description: "x"}).NotTo(apierrors.MatchClientError(apierrors.TypeMismatch))
+		Expect(errors.New(apierrors.TypeMismatch.Message)).NotTo(apierrors.MatchClientError(apierrors.TypeMismatch))
+		var err error
+		Expect(err).NotTo(apierrors.MatchClientError(apierrors.TypeMismatch))
+	})
+})
+
+var _ = Describe("MatchErrorMessage", func() {
+	It("matches the Go client's errors and the text of any other", func() {
+		matcher := apierrors.MatchErrorMessage(apierrors.StaticallySet)
+		message := "The password could not be regenerated because the value was statically set. Only generated passwords may be regenerated."
+		Expect(&credhub.Error{Name: message}).To(matcher)
+		Expect(errors.New(message)).To(matcher)
+		Expect(&credhub.NotFoundError{Description: apierrors.NotFound.Message}).To(apierrors.MatchErrorMessage(apierrors.NotFound))
+	})
+
+	It("rejects other messages and no error", func() {
+		matcher := apierrors.MatchErrorMessage(apierrors.NotRegeneratable)
+		Expect(errors.New("exit status 1")).NotTo(matcher)
+		Expect(errors.New(apierrors.NotRegeneratable.Message + " More.")).NotTo(matcher)
+		Expect(&credhub.Error{Name: apierrors.TypeMismatch.Message}).NotTo(matcher)
+		var err error
+		Expect(err).NotTo(matcher)
+	})
+})
+
+var _ = Describe("MatchCLIError", func() {
+	run := func(script string) *gexec.Session {
+		session, err := gexec.Start(exec.Command("sh", "-c", script), GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+		return session
+	}
+
+	It("matches an exit code of 1 with the message on stderr", func() {
+		Expect(run(fmt.Sprintf("echo %q >&2; exit 1", apierrors.TypeMismatch.Message))).To(apierrors.MatchCLIError(apierrors.TypeMismatch))
+	})
+
+	It("rejects other exit codes, messages and output", func() {
+		matcher := apierrors.MatchCLIError(apierrors.TypeMismatch)
+		Expect(run(fmt.Sprintf("echo %q >&2; exit 2", apierrors.TypeMismatch.Message))).NotTo(matcher)
+		Expect(run("echo other >&2; exit 1")).NotTo(matcher)
+		Expect(run(fmt.Sprintf("echo out; echo %q >&2; exit 1", apierrors.TypeMismatch.Message))).NotTo(matcher)
+	})
+})
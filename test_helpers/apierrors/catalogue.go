@@ -0,0 +1,122 @@
+// Package apierrors catalogues the errors CredHub reports, with matchers
+// that check how each one reaches every client: the HTTP status and JSON
+// body of a raw response, the Go client's error, and the CLI's exit code and
+// standard error.
+package apierrors
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Condition is an error CredHub reports for one kind of bad request.
+type Condition struct {
+	Name   string
+	Status int
+	// Message is the error CredHub sends, in full unless Partial is set.
+	Message string
+	Partial bool
+}
+
+// Matches reports whether an error message is the condition's.
+func (c Condition) Matches(message string) bool {
+	if c.Partial {
+		return strings.Contains(message, c.Message)
+	}
+	return message == c.Message
+}
+
+const invalidAccess = "The request could not be completed because the credential does not exist or you do not have sufficient authorization."
+
+var (
+	// NotFound is reading a credential that does not exist.
+	NotFound = Condition{
+		Name:    "not found",
+		Status:  http.StatusNotFound,
+		Message: invalidAccess,
+	}
+
+	// Forbidden is writing a credential without permission to. CredHub
+	// words it as NotFound, so it does not say whether the credential exists.
+	Forbidden = Condition{
+		Name:    "forbidden",
+		Status:  http.StatusForbidden,
+		Message: invalidAccess,
+	}
+
+	// TypeMismatch is setting a credential as a type other than its own.
+	TypeMismatch = Condition{
+		Name:    "type mismatch",
+		Status:  http.StatusBadRequest,
+		Message: "The credential type cannot be modified. Please delete the credential if you wish to create it with a different type.",
+	}
+
+	// InvalidParameter is generating a certificate with an extended key usage
+	// CredHub does not know, code_sinning.
+	InvalidParameter = Condition{
+		Name:    "invalid parameter",
+		Status:  http.StatusBadRequest,
+		Message: "The provided extended key usage 'code_sinning' is not supported. Valid values include 'client_auth', 'server_auth', 'code_signing', 'email_protection' and 'timestamping'.",
+	}
+
+	// NotRegeneratable is regenerating a credential of a type CredHub cannot
+	// generate, such as value or json.
+	NotRegeneratable = Condition{
+		Name:    "not regeneratable",
+		Status:  http.StatusBadRequest,
+		Message: "Credentials of this type cannot be regenerated.",
+	}
+
+	// StaticallySet is regenerating a password or user whose value was set
+	// rather than generated. CredHub names the credential's type in it.
+	StaticallySet = Condition{
+		Name:    "statically set",
+		Status:  http.StatusBadRequest,
+		Message: "could not be regenerated because the value was statically set.",
+		Partial: true,
+	}
+
+	// NotImplemented is calling an endpoint the remote backend does not
+	// support.
+	NotImplemented = Condition{
+		Name:    "unimplemented backend",
+		Status:  http.StatusNotImplemented,
+		Message: "This resource has not been implemented for this backend.",
+	}
+
+	// MalformedKey is setting a certificate with a private key that is not
+	// RSA.
+	MalformedKey = Condition{
+		Name:    "malformed key",
+		Status:  http.StatusBadRequest,
+		Message: "Private key is malformed. Key file does not contain an RSA private key",
+		Partial: true,
+	}
+
+	// EncryptedKey is setting a certificate with an encrypted private key.
+	EncryptedKey = Condition{
+		Name:    "encrypted key",
+		Status:  http.StatusBadRequest,
+		Message: "Private key is malformed. Key file is not in PKCS#1 or unencrypted PKCS#8 format",
+		Partial: true,
+	}
+
+	// MalformedCertificate is setting a certificate whose certificate is not
+	// an X.509 certificate.
+	MalformedCertificate = Condition{
+		Name:    "malformed certificate",
+		Status:  http.StatusBadRequest,
+		Message: "The provided certificate value is not a valid X509 certificate.",
+	}
+
+	// MismatchedKey is setting a certificate with a valid private key that is
+	// not the certificate's.
+	MismatchedKey = Condition{
+		Name:    "mismatched key",
+		Status:  http.StatusBadRequest,
+		Message: "The provided certificate does not match the private key.",
+	}
+)
+
+// Catalogue is every condition.
+var Catalogue = []Condition{NotFound, Forbidden, TypeMismatch, InvalidParameter, NotRegeneratable, StaticallySet, NotImplemented, MalformedKey, EncryptedKey, MalformedCertificate, MismatchedKey}
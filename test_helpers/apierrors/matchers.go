@@ -0,0 +1,170 @@
+package apierrors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/gexec"
+	"github.com/onsi/gomega/types"
+)
+
+// Response is a raw HTTP response, read.
+type Response struct {
+	Status int
+	Body   []byte
+}
+
+// ReadResponse reads and closes a response.
+func ReadResponse(response *http.Response) (Response, error) {
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	return Response{Status: response.StatusCode, Body: body}, err
+}
+
+// MatchResponse matches a Response, or an *http.Response, with the
+// condition's status and a JSON body of only its error.
+func MatchResponse(c Condition) types.GomegaMatcher {
+	return &matcher{condition: c, check: func(actual interface{}) (string, error) {
+		if response, ok := actual.(*http.Response); ok {
+			read, err := ReadResponse(response)
+			if err != nil {
+				return "", err
+			}
+			actual = read
+		}
+		response, ok := actual.(Response)
+		if !ok {
+			return "", fmt.Errorf("MatchResponse expects a Response or an *http.Response, not %T", actual)
+		}
+
+		if response.Status != c.Status {
+			return fmt.Sprintf("the status was %d", response.Status), nil
+		}
+		var body map[string]interface{}
+		if err := json.Unmarshal(response.Body, &body); err != nil {
+			return fmt.Sprintf("the body was not a JSON object: %s", response.Body), nil
+		}
+		message, ok := body["error"].(string)
+		if !ok || len(body) != 1 {
+			return fmt.Sprintf("the body was not only an error: %s", response.Body), nil
+		}
+		if !c.Matches(message) {
+			return fmt.Sprintf("the error was %q", message), nil
+		}
+		return "", nil
+	}}
+}
+
+// MatchClientError matches an error from the Go client: a
+// *credhub.NotFoundError for a 404, and a *credhub.Error otherwise.
+func MatchClientError(c Condition) types.GomegaMatcher {
+	return &matcher{condition: c, check: func(actual interface{}) (string, error) {
+		err, ok := actual.(error)
+		if !ok {
+			if actual == nil {
+				return "there was no error", nil
+			}
+			return "", fmt.Errorf("MatchClientError expects an error, not %T", actual)
+		}
+
+		var message string
+		var credhubErr *credhub.Error
+		var notFound *credhub.NotFoundError
+		switch {
+		case c.Status == http.StatusNotFound && errors.As(err, &notFound):
+			message = notFound.Description
+		case c.Status != http.StatusNotFound && errors.As(err, &credhubErr):
+			if credhubErr.Description != "" {
+				return fmt.Sprintf("the error had a description, %q", credhubErr.Description), nil
+			}
+			message = credhubErr.Name
+		default:
+			return fmt.Sprintf("the error was a %T: %s", err, err), nil
+		}
+		if !c.Matches(message) {
+			return fmt.Sprintf("the error was %q", message), nil
+		}
+		return "", nil
+	}}
+}
+
+// MatchErrorMessage matches an error from a client that is not known in
+// advance, such as a conformance.Driver's: the Go client's error as
+// MatchClientError reads it, or any other error's text, which is what the
+// CLI printed.
+func MatchErrorMessage(c Condition) types.GomegaMatcher {
+	return &matcher{condition: c, check: func(actual interface{}) (string, error) {
+		err, ok := actual.(error)
+		if !ok {
+			if actual == nil {
+				return "there was no error", nil
+			}
+			return "", fmt.Errorf("MatchErrorMessage expects an error, not %T", actual)
+		}
+
+		message := err.Error()
+		var credhubErr *credhub.Error
+		var notFound *credhub.NotFoundError
+		switch {
+		case errors.As(err, &notFound):
+			message = notFound.Description
+		case errors.As(err, &credhubErr):
+			message = credhubErr.Name
+		}
+		if !c.Matches(message) {
+			return fmt.Sprintf("the error was %q", message), nil
+		}
+		return "", nil
+	}}
+}
+
+// MatchCLIError matches a CLI session that exited 1, printing nothing but the
+// condition's error on standard error.
+func MatchCLIError(c Condition) types.GomegaMatcher {
+	return &matcher{condition: c, check: func(actual interface{}) (string, error) {
+		session, ok := actual.(*gexec.Session)
+		if !ok {
+			return "", fmt.Errorf("MatchCLIError expects a *gexec.Session, not %T", actual)
+		}
+		<-session.Exited
+
+		if session.ExitCode() != 1 {
+			return fmt.Sprintf("the CLI exited %d", session.ExitCode()), nil
+		}
+		if stdout := strings.TrimSpace(string(session.Out.Contents())); stdout != "" {
+			return fmt.Sprintf("the CLI printed %q", stdout), nil
+		}
+		if stderr := strings.TrimSpace(string(session.Err.Contents())); !c.Matches(stderr) {
+			return fmt.Sprintf("the CLI's error was %q", stderr), nil
+		}
+		return "", nil
+	}}
+}
+
+// matcher matches when check finds nothing wrong with the actual value.
+type matcher struct {
+	condition Condition
+	check     func(actual interface{}) (string, error)
+	// mismatch is why the last value did not match.
+	mismatch string
+}
+
+func (m *matcher) Match(actual interface{}) (bool, error) {
+	mismatch, err := m.check(actual)
+	m.mismatch = mismatch
+	return err == nil && mismatch == "", err
+}
+
+func (m *matcher) FailureMessage(actual interface{}) string {
+	return fmt.Sprintf("Expected\n%s\nto be the %s error, %d %q, but %s", format.Object(actual, 1), m.condition.Name, m.condition.Status, m.condition.Message, m.mismatch)
+}
+
+func (m *matcher) NegatedFailureMessage(actual interface{}) string {
+	return fmt.Sprintf("Expected\n%s\nnot to be the %s error", format.Object(actual, 1), m.condition.Name)
+}
@@ -0,0 +1,158 @@
+// Package bbr backs up and restores CredHub the way BOSH Backup and Restore
+// does, either by running bbr against a BOSH director or with a local
+// stand-in for the director, and inspects the artifacts it leaves behind.
+package bbr
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// Runner backs up a deployment into a new artifact under a directory, and
+// restores it from one.
+type Runner interface {
+	Backup(artifactDir string) error
+	Restore(artifactPath string) error
+}
+
+// DumpFile is the file the credhubdb job's backup writes.
+const DumpFile = "credhubdb_dump"
+
+// FindArtifact finds the single artifact bbr wrote for deployment under dir.
+// bbr names it after the deployment and a timestamp.
+func FindArtifact(dir, deployment string) (string, error) {
+	return findOne(filepath.Join(dir, deployment+"_*"), "artifact for "+deployment)
+}
+
+// FindArchive finds the single archive in an artifact whose name ends with
+// suffix, such as "credhubdb.tar".
+func FindArchive(artifactPath, suffix string) (string, error) {
+	return findOne(filepath.Join(artifactPath, "*"+suffix), suffix+" archive")
+}
+
+func findOne(pattern, what string) (string, error) {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return "", err
+	}
+	if len(matches) != 1 {
+		return "", fmt.Errorf("expected one %s matching %s, found %d", what, pattern, len(matches))
+	}
+	return matches[0], nil
+}
+
+// ArchiveContains reports whether a tar archive holds a file of the given
+// name. Names are compared without a leading "./".
+func ArchiveContains(archive, name string) (bool, error) {
+	file, err := os.Open(archive)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	reader := tar.NewReader(file)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if path.Clean(header.Name) == path.Clean(name) {
+			return true, nil
+		}
+	}
+}
+
+// writeArchive archives the files in dir, named "./<file>" as BOSH job
+// backups are.
+func writeArchive(archive, dir string) error {
+	file, err := os.Create(archive)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := tar.NewWriter(file)
+	err = filepath.Walk(dir, func(name string, info os.FileInfo, err error) error {
+		if err != nil || name == dir {
+			return err
+		}
+		relative, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = "./" + filepath.ToSlash(relative)
+		if err := writer.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		contents, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer contents.Close()
+		_, err = io.Copy(writer, contents)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// extractArchive extracts a tar archive into dir.
+func extractArchive(archive, dir string) error {
+	file, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := tar.NewReader(file)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := path.Clean("/" + header.Name)
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+				return err
+			}
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode)&0700)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(out, reader)
+			out.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package bbr_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestBBR(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "BBR Suite")
+}
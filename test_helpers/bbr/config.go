@@ -0,0 +1,93 @@
+package bbr
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers"
+)
+
+// The scripts of the credhub release's bbr-credhubdb job, where BOSH
+// installs them on the CredHub VM.
+const (
+	DefaultBackupScript  = "/var/vcap/jobs/bbr-credhubdb/bin/bbr/backup"
+	DefaultRestoreScript = "/var/vcap/jobs/bbr-credhubdb/bin/bbr/restore"
+)
+
+// ErrNotConfigured is returned by FromConfig when there is nothing that can
+// back up CredHub's database: no director, no database and no bbr-credhubdb
+// scripts.
+var ErrNotConfigured = errors.New("there is nothing to back CredHub up with: set the bosh section, " +
+	"or database_url or backup_script and restore_script in the bbr section, or run on the CredHub VM; " +
+	"set skip in the bbr section to skip the suite instead")
+
+// FromConfig runs bbr against the director in the bosh section of the
+// configuration when there is one, and otherwise the local stand-in. The
+// stand-in runs the bbr-credhubdb scripts in the bbr section, or dumps the
+// database at its database_url, or, without either, runs the scripts the
+// job installs on the CredHub VM. It returns ErrNotConfigured when there is
+// none of those.
+func FromConfig(cfg test_helpers.Config, output io.Writer) (Runner, error) {
+	if cfg.Bosh != nil && cfg.Bosh.Environment != "" {
+		return Director{
+			Target:     cfg.Bosh.Environment,
+			Username:   cfg.Bosh.Client,
+			Password:   cfg.Bosh.ClientSecret,
+			CACertPath: cfg.Bosh.CaCertPath,
+			Deployment: cfg.DeploymentName,
+			Output:     output,
+		}, nil
+	}
+
+	local := Local{Deployment: Deployment(cfg), Instance: "credhub", JobName: "credhubdb"}
+	scripts := Scripts{BackupScript: DefaultBackupScript, RestoreScript: DefaultRestoreScript, Output: output}
+	switch {
+	case cfg.BBR != nil && (cfg.BBR.BackupScript != "" || cfg.BBR.RestoreScript != ""):
+		scripts.BackupScript, scripts.RestoreScript = cfg.BBR.BackupScript, cfg.BBR.RestoreScript
+		for _, script := range []string{scripts.BackupScript, scripts.RestoreScript} {
+			if err := executable(script); err != nil {
+				return nil, fmt.Errorf("the bbr section's scripts cannot be run: %s", err)
+			}
+		}
+		local.Job = scripts
+	case cfg.BBR != nil && cfg.BBR.DatabaseURL != "":
+		for _, command := range []string{"pg_dump", "psql"} {
+			if _, err := exec.LookPath(command); err != nil {
+				return nil, fmt.Errorf("backing up database_url needs %s: %s", command, err)
+			}
+		}
+		local.Job = Postgres{URL: cfg.BBR.DatabaseURL, Output: output}
+	case executable(scripts.BackupScript) == nil && executable(scripts.RestoreScript) == nil:
+		local.Job = scripts
+	default:
+		return nil, ErrNotConfigured
+	}
+	return local, nil
+}
+
+// executable returns an error unless script is a file that can be run.
+func executable(script string) error {
+	if script == "" {
+		return errors.New("a script is not set")
+	}
+	info, err := os.Stat(script)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() || info.Mode()&0111 == 0 {
+		return fmt.Errorf("%s is not executable", script)
+	}
+	return nil
+}
+
+// Deployment is the deployment backed up, which the local stand-in calls
+// "credhub" unless deployment_name is set.
+func Deployment(cfg test_helpers.Config) string {
+	if cfg.DeploymentName == "" {
+		return "credhub"
+	}
+	return cfg.DeploymentName
+}
@@ -0,0 +1,48 @@
+package bbr
+
+import (
+	"io"
+	"os/exec"
+)
+
+// Director runs the bbr binary against a BOSH director.
+type Director struct {
+	// Binary is the bbr executable, "bbr" on the PATH by default.
+	Binary     string
+	Target     string
+	Username   string
+	Password   string
+	CACertPath string
+	Deployment string
+	Output     io.Writer
+}
+
+// Backup runs bbr deployment backup.
+func (d Director) Backup(artifactDir string) error {
+	return d.run("backup", "--artifact-path", artifactDir)
+}
+
+// Restore runs bbr deployment restore.
+func (d Director) Restore(artifactPath string) error {
+	return d.run("restore", "--artifact-path", artifactPath)
+}
+
+func (d Director) run(args ...string) error {
+	binary := d.Binary
+	if binary == "" {
+		binary = "bbr"
+	}
+	args = append([]string{
+		"deployment",
+		"--username", d.Username,
+		"--password", d.Password,
+		"--deployment", d.Deployment,
+		"--target", d.Target,
+		"--ca-cert", d.CACertPath,
+	}, args...)
+
+	cmd := exec.Command(binary, args...)
+	cmd.Stdout = d.Output
+	cmd.Stderr = d.Output
+	return cmd.Run()
+}
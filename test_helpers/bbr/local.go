@@ -0,0 +1,111 @@
+package bbr
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// Job is the backup and restore of one BOSH job. Backup writes into dir and
+// Restore reads what Backup wrote from it, as BBR_ARTIFACT_DIRECTORY is
+// used by a job's bbr scripts.
+type Job interface {
+	Backup(dir string) error
+	Restore(dir string) error
+}
+
+// Local stands in for a BOSH director with a deployment of one instance that
+// runs a single job, and lays out artifacts the way bbr does:
+//
+//	<dir>/<deployment>_<timestamp>/<instance>-0-<job>.tar
+//	<dir>/<deployment>_<timestamp>/metadata
+type Local struct {
+	Deployment string
+	Instance   string
+	JobName    string
+	Job        Job
+	// Now is when a backup is taken, time.Now by default.
+	Now func() time.Time
+}
+
+// Backup backs the job up into a new artifact under artifactDir.
+func (l Local) Backup(artifactDir string) error {
+	now := time.Now
+	if l.Now != nil {
+		now = l.Now
+	}
+	startedAt := now().UTC()
+
+	artifact := filepath.Join(artifactDir, fmt.Sprintf("%s_%s", l.Deployment, startedAt.Format("20060102T150405Z")))
+	if err := os.Mkdir(artifact, 0700); err != nil {
+		return err
+	}
+
+	scratch, err := ioutil.TempDir("", "bbr-"+l.JobName)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(scratch)
+
+	if err := l.Job.Backup(scratch); err != nil {
+		return fmt.Errorf("backing up %s: %s", l.JobName, err)
+	}
+	if err := writeArchive(filepath.Join(artifact, l.archiveName()), scratch); err != nil {
+		return err
+	}
+
+	metadata := fmt.Sprintf("instances:\n- name: %s\n  index: \"0\"\n  artifacts:\n  - name: %s\nbackup_activity:\n  start_time: %s\n  finish_time: %s\n",
+		l.Instance, l.JobName, startedAt.Format(time.RFC3339), now().UTC().Format(time.RFC3339))
+	return ioutil.WriteFile(filepath.Join(artifact, "metadata"), []byte(metadata), 0600)
+}
+
+// Restore restores the job from an artifact Backup wrote.
+func (l Local) Restore(artifactPath string) error {
+	scratch, err := ioutil.TempDir("", "bbr-"+l.JobName)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(scratch)
+
+	if err := extractArchive(filepath.Join(artifactPath, l.archiveName()), scratch); err != nil {
+		return err
+	}
+	if err := l.Job.Restore(scratch); err != nil {
+		return fmt.Errorf("restoring %s: %s", l.JobName, err)
+	}
+	return nil
+}
+
+func (l Local) archiveName() string {
+	return fmt.Sprintf("%s-0-%s.tar", l.Instance, l.JobName)
+}
+
+// Scripts is a job backed up and restored by executables following the bbr
+// script contract, such as those of the credhub release's bbr-credhubdb job.
+type Scripts struct {
+	BackupScript  string
+	RestoreScript string
+	Output        io.Writer
+}
+
+// Backup runs the backup script.
+func (s Scripts) Backup(dir string) error {
+	return s.run(s.BackupScript, dir)
+}
+
+// Restore runs the restore script.
+func (s Scripts) Restore(dir string) error {
+	return s.run(s.RestoreScript, dir)
+}
+
+func (s Scripts) run(script, dir string) error {
+	cmd := exec.Command(script)
+	cmd.Env = append(os.Environ(), "BBR_ARTIFACT_DIRECTORY="+dir+string(filepath.Separator))
+	cmd.Stdout = s.Output
+	cmd.Stderr = s.Output
+	return cmd.Run()
+}
@@ -0,0 +1,13 @@
+package bulkimport_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestBulkimport(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Bulkimport Suite")
+}
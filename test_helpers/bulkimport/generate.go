@@ -0,0 +1,248 @@
+// Package bulkimport writes credhub import files of any size, with
+// credentials of mixed types, chains of certificates linked by ca_name and
+// entries CredHub must reject, and reads the summary credhub import prints,
+// so specs can check the result of every entry.
+package bulkimport
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/certs"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/conformance"
+	"gopkg.in/yaml.v3"
+)
+
+// Order is the order of each chain of certificates in a file.
+type Order int
+
+const (
+	// DependencyOrder lists each CA before the certificates it signs.
+	DependencyOrder Order = iota
+	// ReverseOrder lists each certificate before the CA that signs it.
+	ReverseOrder
+)
+
+// Options say what to generate.
+type Options struct {
+	// Root is the path every name is under.
+	Root string
+	// Count is how many entries to generate.
+	Count int
+	// Invalid is how many of them CredHub must reject.
+	Invalid int
+	Order   Order
+}
+
+// Entry is one credential of an import file.
+type Entry struct {
+	Name  string      `yaml:"name"`
+	Type  string      `yaml:"type"`
+	Value interface{} `yaml:"value"`
+	// Invalid is why CredHub must reject the entry, or "" when import must set
+	// it.
+	Invalid string `yaml:"-"`
+}
+
+// Invalid entries, each a mistake made in real import files.
+const (
+	UnknownType = "an unknown type"
+	EmptyValue  = "an empty value"
+	MissingCA   = "a ca_name that is not in CredHub or the file"
+	NoName      = "no name"
+)
+
+// invalidKinds are used in turn.
+var invalidKinds = []string{UnknownType, EmptyValue, MissingCA, NoName}
+
+// chainEvery is how many valid entries there are for each chain of three
+// certificates: a root CA, an intermediate CA and a leaf.
+const chainEvery = 10
+
+// simpleTypes are the types of valid entries outside chains.
+var simpleTypes = []string{"value", "password", "json", "user", "ssh", "rsa"}
+
+// Generate returns options.Count entries in random order, but for the order
+// of each chain. Values that are slow to make, keys and certificates, are
+// made once and shared between entries.
+func Generate(r *rand.Rand, options Options) ([]Entry, error) {
+	if options.Invalid > options.Count {
+		return nil, fmt.Errorf("cannot make %d of %d entries invalid", options.Invalid, options.Count)
+	}
+	values, err := newPool()
+	if err != nil {
+		return nil, err
+	}
+
+	valid := options.Count - options.Invalid
+	chains := valid / chainEvery
+	if chains == 0 && valid >= 3 {
+		chains = 1
+	}
+
+	var entries []Entry
+	for c := 0; c < chains; c++ {
+		entries = append(entries, values.chain(fmt.Sprintf("%s/chain-%d", options.Root, c))...)
+	}
+	for i := len(entries); i < valid; i++ {
+		t := simpleTypes[r.Intn(len(simpleTypes))]
+		entries = append(entries, Entry{
+			Name:  fmt.Sprintf("%s/%s/%05d", options.Root, t, i),
+			Type:  t,
+			Value: values.value(t, i),
+		})
+	}
+	for i := 0; i < options.Invalid; i++ {
+		entries = append(entries, values.invalid(options.Root, i, invalidKinds[i%len(invalidKinds)]))
+	}
+
+	r.Shuffle(len(entries), func(i, j int) { entries[i], entries[j] = entries[j], entries[i] })
+	orderChains(entries, chains, options)
+	return entries, nil
+}
+
+// orderChains puts the certificates of each chain, wherever the shuffle left
+// them, in the order asked for.
+func orderChains(entries []Entry, chains int, options Options) {
+	for c := 0; c < chains; c++ {
+		prefix := fmt.Sprintf("%s/chain-%d/", options.Root, c)
+		var positions []int
+		var chain []Entry
+		for i, entry := range entries {
+			if strings.HasPrefix(entry.Name, prefix) {
+				positions = append(positions, i)
+				chain = append(chain, entry)
+			}
+		}
+		// Chain names sort from the root CA down.
+		sort.Slice(chain, func(i, j int) bool { return chain[i].Name < chain[j].Name })
+		if options.Order == ReverseOrder {
+			for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+				chain[i], chain[j] = chain[j], chain[i]
+			}
+		}
+		for i, position := range positions {
+			entries[position] = chain[i]
+		}
+	}
+}
+
+// pool is the values entries share.
+type pool struct {
+	// keys are ssh and rsa values, by type.
+	keys                       map[string][]interface{}
+	rootCA, intermediate, leaf map[string]interface{}
+}
+
+func newPool() (*pool, error) {
+	p := &pool{keys: map[string][]interface{}{}}
+	for _, t := range conformance.Types {
+		if t.Name != "ssh" && t.Name != "rsa" {
+			continue
+		}
+		for n := 1; n <= 2; n++ {
+			value, err := t.Value(n)
+			if err != nil {
+				return nil, err
+			}
+			p.keys[t.Name] = append(p.keys[t.Name], value)
+		}
+	}
+
+	rootCert, rootKey, err := certs.GenerateSelfSigned(certs.CertOptions{CommonName: "bulk-import-root-ca", IsCA: true})
+	if err != nil {
+		return nil, err
+	}
+	intermediateCert, intermediateKey, err := certs.GenerateSigned(certs.CertOptions{CommonName: "bulk-import-intermediate-ca", IsCA: true}, rootCert, rootKey)
+	if err != nil {
+		return nil, err
+	}
+	leafCert, leafKey, err := certs.GenerateSigned(certs.CertOptions{CommonName: "bulk-import-leaf"}, intermediateCert, intermediateKey)
+	if err != nil {
+		return nil, err
+	}
+	p.rootCA = map[string]interface{}{"certificate": string(rootCert), "private_key": string(rootKey)}
+	p.intermediate = map[string]interface{}{"certificate": string(intermediateCert), "private_key": string(intermediateKey)}
+	p.leaf = map[string]interface{}{"certificate": string(leafCert), "private_key": string(leafKey)}
+	return p, nil
+}
+
+// chain is a root CA, an intermediate CA it signs and a leaf the
+// intermediate signs, under path, linked by ca_name.
+func (p *pool) chain(path string) []Entry {
+	withCA := func(value map[string]interface{}, caName string) map[string]interface{} {
+		linked := map[string]interface{}{"ca_name": caName}
+		for key, v := range value {
+			linked[key] = v
+		}
+		return linked
+	}
+	rootName := path + "/1-root-ca"
+	intermediateName := path + "/2-intermediate-ca"
+	return []Entry{
+		{Name: rootName, Type: "certificate", Value: p.rootCA},
+		{Name: intermediateName, Type: "certificate", Value: withCA(p.intermediate, rootName)},
+		{Name: path + "/3-leaf", Type: "certificate", Value: withCA(p.leaf, intermediateName)},
+	}
+}
+
+func (p *pool) value(t string, i int) interface{} {
+	switch t {
+	case "password":
+		return fmt.Sprintf("password-%05d", i)
+	case "json":
+		return map[string]interface{}{"index": i, "nested": map[string]interface{}{"list": []interface{}{"a", i}}}
+	case "user":
+		return map[string]interface{}{"username": fmt.Sprintf("user-%05d", i), "password": fmt.Sprintf("user-password-%05d", i)}
+	case "ssh", "rsa":
+		keys := p.keys[t]
+		return keys[i%len(keys)]
+	}
+	return fmt.Sprintf("value-%05d", i)
+}
+
+func (p *pool) invalid(root string, i int, kind string) Entry {
+	entry := Entry{Name: fmt.Sprintf("%s/invalid/%05d", root, i), Type: "value", Value: fmt.Sprintf("invalid-%05d", i), Invalid: kind}
+	switch kind {
+	case UnknownType:
+		entry.Type = "not-a-type"
+	case EmptyValue:
+		entry.Value = ""
+	case MissingCA:
+		entry.Type = "certificate"
+		entry.Value = map[string]interface{}{
+			"ca_name":     fmt.Sprintf("%s/missing-ca/%05d", root, i),
+			"certificate": p.leaf["certificate"],
+			"private_key": p.leaf["private_key"],
+		}
+	case NoName:
+		entry.Name = ""
+	}
+	return entry
+}
+
+// Write writes entries as an import file.
+func Write(w io.Writer, entries []Entry) error {
+	encoder := yaml.NewEncoder(w)
+	if err := encoder.Encode(map[string]interface{}{"credentials": entries}); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// WriteFile writes entries as an import file at path.
+func WriteFile(path string, entries []Entry) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := Write(file, entries); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
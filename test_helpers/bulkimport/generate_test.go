@@ -0,0 +1,115 @@
+package bulkimport_test
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/bulkimport"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v3"
+)
+
+var _ = Describe("Generate", func() {
+	generate := func(order bulkimport.Order) []bulkimport.Entry {
+		entries, err := bulkimport.Generate(rand.New(rand.NewSource(1)), bulkimport.Options{
+			Root: "/bulk", Count: 64, Invalid: 8, Order: order,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		return entries
+	}
+
+	// position is where each chain entry is, by name.
+	position := func(entries []bulkimport.Entry) map[string]int {
+		positions := map[string]int{}
+		for i, entry := range entries {
+			if strings.Contains(entry.Name, "/chain-") {
+				positions[entry.Name] = i
+			}
+		}
+		return positions
+	}
+
+	It("generates as many entries as asked, with each kind of invalid entry in turn", func() {
+		entries := generate(bulkimport.DependencyOrder)
+		Expect(entries).To(HaveLen(64))
+
+		invalid := map[string]int{}
+		types := map[string]bool{}
+		names := map[string]bool{}
+		for _, entry := range entries {
+			if entry.Invalid != "" {
+				invalid[entry.Invalid]++
+				continue
+			}
+			types[entry.Type] = true
+			Expect(names).NotTo(HaveKey(entry.Name))
+			names[entry.Name] = true
+			Expect(entry.Name).To(HavePrefix("/bulk/"))
+		}
+		Expect(invalid).To(Equal(map[string]int{
+			bulkimport.UnknownType: 2, bulkimport.EmptyValue: 2, bulkimport.MissingCA: 2, bulkimport.NoName: 2,
+		}))
+		Expect(types).To(HaveKey("certificate"))
+		Expect(len(types)).To(BeNumerically(">", 3))
+	})
+
+	It("links each chain by ca_name", func() {
+		for _, entry := range generate(bulkimport.DependencyOrder) {
+			if !strings.HasPrefix(entry.Name, "/bulk/chain-0/") {
+				continue
+			}
+			value := entry.Value.(map[string]interface{})
+			switch {
+			case strings.HasSuffix(entry.Name, "/1-root-ca"):
+				Expect(value).NotTo(HaveKey("ca_name"))
+			case strings.HasSuffix(entry.Name, "/2-intermediate-ca"):
+				Expect(value).To(HaveKeyWithValue("ca_name", "/bulk/chain-0/1-root-ca"))
+			default:
+				Expect(value).To(HaveKeyWithValue("ca_name", "/bulk/chain-0/2-intermediate-ca"))
+			}
+		}
+	})
+
+	It("puts each CA before the certificates it signs in dependency order", func() {
+		positions := position(generate(bulkimport.DependencyOrder))
+		Expect(positions).To(HaveLen(15))
+		for c := 0; c < 5; c++ {
+			chain := "/bulk/chain-" + string(rune('0'+c))
+			Expect(positions[chain+"/1-root-ca"]).To(BeNumerically("<", positions[chain+"/2-intermediate-ca"]))
+			Expect(positions[chain+"/2-intermediate-ca"]).To(BeNumerically("<", positions[chain+"/3-leaf"]))
+		}
+	})
+
+	It("puts each CA after the certificates it signs in reverse order", func() {
+		positions := position(generate(bulkimport.ReverseOrder))
+		for c := 0; c < 5; c++ {
+			chain := "/bulk/chain-" + string(rune('0'+c))
+			Expect(positions[chain+"/1-root-ca"]).To(BeNumerically(">", positions[chain+"/2-intermediate-ca"]))
+			Expect(positions[chain+"/2-intermediate-ca"]).To(BeNumerically(">", positions[chain+"/3-leaf"]))
+		}
+	})
+
+	It("refuses to make more entries invalid than it generates", func() {
+		_, err := bulkimport.Generate(rand.New(rand.NewSource(1)), bulkimport.Options{Count: 2, Invalid: 3})
+		Expect(err).To(MatchError("cannot make 3 of 2 entries invalid"))
+	})
+
+	It("writes entries as an import file without saying which are invalid", func() {
+		entries := generate(bulkimport.ReverseOrder)
+		var file bytes.Buffer
+		Expect(bulkimport.Write(&file, entries)).To(Succeed())
+		Expect(file.String()).NotTo(ContainSubstring("invalid:"))
+
+		var read struct {
+			Credentials []map[string]interface{} `yaml:"credentials"`
+		}
+		Expect(yaml.Unmarshal(file.Bytes(), &read)).To(Succeed())
+		Expect(read.Credentials).To(HaveLen(len(entries)))
+		for i, credential := range read.Credentials {
+			Expect(credential).To(HaveKeyWithValue("name", entries[i].Name))
+			Expect(credential).To(HaveKeyWithValue("type", entries[i].Type))
+		}
+	})
+})
@@ -0,0 +1,209 @@
+package bulkimport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Failure is an entry credhub import reports it could not set.
+type Failure struct {
+	Name  string
+	Index int
+	// Message is CredHub's error.
+	Message string
+}
+
+// Summary is what credhub import prints: each failure as it happens, then
+// the counts of credentials set and not set, and the failures again.
+type Summary struct {
+	// Reported are the failures printed as they happened.
+	Reported   []Failure
+	Successful int
+	Failed     int
+	// Failures are the failures listed after the counts.
+	Failures []Failure
+}
+
+var (
+	failureLine    = regexp.MustCompile(`^(?: - )?Credential '(.*)' at index (\d+) could not be set: (.*)$`)
+	successfulLine = regexp.MustCompile(`^Successfully set: (\d+)$`)
+	failedLine     = regexp.MustCompile(`^Failed to set: (\d+)$`)
+)
+
+// ParseSummary reads what credhub import printed on standard output.
+func ParseSummary(output []byte) (Summary, error) {
+	var summary Summary
+	var complete, counted bool
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "Import complete.":
+			complete = true
+		case successfulLine.MatchString(line):
+			summary.Successful, _ = strconv.Atoi(successfulLine.FindStringSubmatch(line)[1])
+		case failedLine.MatchString(line):
+			summary.Failed, _ = strconv.Atoi(failedLine.FindStringSubmatch(line)[1])
+			counted = true
+		case failureLine.MatchString(line):
+			match := failureLine.FindStringSubmatch(line)
+			index, _ := strconv.Atoi(match[2])
+			failure := Failure{Name: match[1], Index: index, Message: match[3]}
+			if counted {
+				summary.Failures = append(summary.Failures, failure)
+			} else {
+				summary.Reported = append(summary.Reported, failure)
+			}
+		case strings.TrimSpace(line) == "":
+		default:
+			return summary, fmt.Errorf("unexpected line in the import output: %q", line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return summary, err
+	}
+	if !complete || !counted {
+		return summary, errors.New("the import output has no summary")
+	}
+	return summary, nil
+}
+
+// Check checks a summary against the entries imported: that it counts every
+// valid entry as set and every invalid one as not, and that it names each
+// invalid entry by its name and index, as it happens and again after the
+// counts.
+func Check(entries []Entry, summary Summary) error {
+	var want []Failure
+	for i, entry := range entries {
+		if entry.Invalid != "" {
+			want = append(want, Failure{Name: entry.Name, Index: i})
+		}
+	}
+	valid := len(entries) - len(want)
+
+	var problems []string
+	if summary.Successful != valid {
+		problems = append(problems, fmt.Sprintf("it counted %d set, not %d", summary.Successful, valid))
+	}
+	if summary.Failed != len(want) {
+		problems = append(problems, fmt.Sprintf("it counted %d not set, not %d", summary.Failed, len(want)))
+	}
+	for _, list := range []struct {
+		name     string
+		failures []Failure
+	}{{"as they happened", summary.Reported}, {"after the counts", summary.Failures}} {
+		if problem := compareFailures(want, list.failures); problem != "" {
+			problems = append(problems, fmt.Sprintf("the failures %s %s", list.name, problem))
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// compareFailures compares failures by name and index, ignoring messages and
+// order.
+func compareFailures(want, got []Failure) string {
+	key := func(f Failure) string { return fmt.Sprintf("%q at index %d", f.Name, f.Index) }
+	wanted := map[string]bool{}
+	for _, f := range want {
+		wanted[key(f)] = true
+	}
+	seen := map[string]bool{}
+	var unexpected, missing []string
+	for _, f := range got {
+		if !wanted[key(f)] || seen[key(f)] {
+			unexpected = append(unexpected, key(f))
+		}
+		seen[key(f)] = true
+	}
+	for _, f := range want {
+		if !seen[key(f)] {
+			missing = append(missing, key(f))
+		}
+	}
+	sort.Strings(unexpected)
+	sort.Strings(missing)
+
+	var problems []string
+	if len(missing) > 0 {
+		problems = append(problems, "missed "+strings.Join(missing, ", "))
+	}
+	if len(unexpected) > 0 {
+		problems = append(problems, "wrongly listed "+strings.Join(unexpected, ", "))
+	}
+	return strings.Join(problems, " and ")
+}
+
+// Stored checks which entries CredHub holds, given the names found under the
+// root: every valid entry, and no invalid one.
+func Stored(entries []Entry, found []string) error {
+	names := map[string]bool{}
+	for _, name := range found {
+		names[name] = true
+	}
+	var problems []string
+	for i, entry := range entries {
+		switch {
+		case entry.Invalid == "" && !names[entry.Name]:
+			problems = append(problems, fmt.Sprintf("%s at index %d was not stored", entry.Name, i))
+		case entry.Invalid != "" && entry.Name != "" && names[entry.Name]:
+			problems = append(problems, fmt.Sprintf("%s at index %d was stored despite %s", entry.Name, i, entry.Invalid))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Matches reports whether a stored value is the entry's: whether each field
+// of the entry's value is stored as it is, once read back as JSON. A
+// certificate's ca_name is not stored, so it is not compared.
+func Matches(entry Entry, stored interface{}) (bool, error) {
+	want, err := normalise(entry.Value)
+	if err != nil {
+		return false, err
+	}
+	got, err := normalise(stored)
+	if err != nil {
+		return false, err
+	}
+
+	wantFields, structured := want.(map[string]interface{})
+	if !structured || entry.Type == "json" {
+		return reflect.DeepEqual(want, got), nil
+	}
+	gotFields, ok := got.(map[string]interface{})
+	if !ok {
+		return false, nil
+	}
+	for key, value := range wantFields {
+		if key != "ca_name" && !reflect.DeepEqual(value, gotFields[key]) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func normalise(value interface{}) (interface{}, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var normalised interface{}
+	err = json.Unmarshal(encoded, &normalised)
+	return normalised, err
+}
@@ -0,0 +1,83 @@
+package bulkimport_test
+
+import (
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/bulkimport"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const output = `Credential '/bulk/invalid/00000' at index 1 could not be set: The request does not include a valid type.
+
+Credential '' at index 3 could not be set: A credential name must be provided. Please validate your input and retry your request.
+
+Import complete.
+Successfully set: 2
+Failed to set: 2
+ - Credential '/bulk/invalid/00000' at index 1 could not be set: The request does not include a valid type.
+ - Credential '' at index 3 could not be set: A credential name must be provided. Please validate your input and retry your request.
+`
+
+var _ = Describe("Summary", func() {
+	var entries []bulkimport.Entry
+
+	BeforeEach(func() {
+		entries = []bulkimport.Entry{
+			{Name: "/bulk/value/00000", Type: "value", Value: "v"},
+			{Name: "/bulk/invalid/00000", Type: "not-a-type", Value: "v", Invalid: bulkimport.UnknownType},
+			{Name: "/bulk/password/00001", Type: "password", Value: "p"},
+			{Name: "", Type: "value", Value: "v", Invalid: bulkimport.NoName},
+		}
+	})
+
+	It("parses the failures as they happened, the counts and the failures after them", func() {
+		summary, err := bulkimport.ParseSummary([]byte(output))
+		Expect(err).NotTo(HaveOccurred())
+
+		failures := []bulkimport.Failure{
+			{Name: "/bulk/invalid/00000", Index: 1, Message: "The request does not include a valid type."},
+			{Name: "", Index: 3, Message: "A credential name must be provided. Please validate your input and retry your request."},
+		}
+		Expect(summary).To(Equal(bulkimport.Summary{Reported: failures, Successful: 2, Failed: 2, Failures: failures}))
+		Expect(bulkimport.Check(entries, summary)).To(Succeed())
+	})
+
+	It("refuses output without a summary or with lines it does not know", func() {
+		_, err := bulkimport.ParseSummary([]byte("Import complete.\n"))
+		Expect(err).To(MatchError("the import output has no summary"))
+
+		_, err = bulkimport.ParseSummary([]byte(output + "Something else\n"))
+		Expect(err).To(MatchError(`unexpected line in the import output: "Something else"`))
+	})
+
+	It("reports counts and failures that do not match the entries", func() {
+		summary, err := bulkimport.ParseSummary([]byte(output))
+		Expect(err).NotTo(HaveOccurred())
+		summary.Successful = 3
+		summary.Failures = summary.Failures[:1]
+		summary.Reported[1].Index = 2
+
+		Expect(bulkimport.Check(entries, summary)).To(MatchError(`it counted 3 set, not 2; ` +
+			`the failures as they happened missed "" at index 3 and wrongly listed "" at index 2; ` +
+			`the failures after the counts missed "" at index 3`))
+	})
+
+	It("checks every valid entry is stored and no invalid one", func() {
+		Expect(bulkimport.Stored(entries, []string{"/bulk/value/00000", "/bulk/password/00001"})).To(Succeed())
+		Expect(bulkimport.Stored(entries, []string{"/bulk/value/00000", "/bulk/invalid/00000"})).To(MatchError(
+			"/bulk/invalid/00000 at index 1 was stored despite an unknown type; /bulk/password/00001 at index 2 was not stored"))
+	})
+
+	It("matches stored values field by field, but for ca_name", func() {
+		certificate := bulkimport.Entry{Type: "certificate", Value: map[string]interface{}{
+			"ca_name": "/bulk/ca", "certificate": "cert", "private_key": "key",
+		}}
+		Expect(bulkimport.Matches(certificate, map[string]interface{}{"ca": "ca", "certificate": "cert", "private_key": "key"})).To(BeTrue())
+		Expect(bulkimport.Matches(certificate, map[string]interface{}{"certificate": "other", "private_key": "key"})).To(BeFalse())
+
+		json := bulkimport.Entry{Type: "json", Value: map[string]interface{}{"index": 1}}
+		Expect(bulkimport.Matches(json, map[string]interface{}{"index": 1.0})).To(BeTrue())
+		Expect(bulkimport.Matches(json, map[string]interface{}{"index": 1, "more": true})).To(BeFalse())
+
+		Expect(bulkimport.Matches(entries[0], "v")).To(BeTrue())
+	})
+})
@@ -0,0 +1,218 @@
+// Package certificates is a typed client for CredHub's /api/v1/certificates
+// endpoints, so certificate specs can say what they do rather than build
+// curl paths and unmarshal JSON. It sends its requests over a Transport: the
+// Go client, or the CLI's curl command for suites that drive the CLI.
+package certificates
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Certificate is the metadata CredHub keeps about a certificate credential.
+type Certificate struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// SignedBy is the name of the CA that signed the certificate, the
+	// certificate's own name when it is self-signed, or empty when its CA
+	// is not in CredHub.
+	SignedBy string `json:"signed_by"`
+	// Signs are the names of the certificates the CA signs directly.
+	Signs []string `json:"signs"`
+	// Versions are newest first.
+	Versions []Summary `json:"versions"`
+}
+
+// Summary describes one version of a certificate in its metadata.
+type Summary struct {
+	ID                   string `json:"id"`
+	ExpiryDate           string `json:"expiry_date"`
+	Transitional         bool   `json:"transitional"`
+	CertificateAuthority bool   `json:"certificate_authority"`
+	SelfSigned           bool   `json:"self_signed"`
+	Generated            bool   `json:"generated"`
+}
+
+// Transitional returns the ids of the transitional versions.
+func (c Certificate) Transitional() []string {
+	var ids []string
+	for _, version := range c.Versions {
+		if version.Transitional {
+			ids = append(ids, version.ID)
+		}
+	}
+	return ids
+}
+
+// Version is one version of a certificate with its value.
+type Version struct {
+	ID               string `json:"id"`
+	Name             string `json:"name"`
+	Type             string `json:"type"`
+	ExpiryDate       string `json:"expiry_date"`
+	Transitional     bool   `json:"transitional"`
+	VersionCreatedAt string `json:"version_created_at"`
+	Value            Value  `json:"value"`
+}
+
+// Value is the value of a certificate version.
+type Value struct {
+	CA          string `json:"ca,omitempty"`
+	Certificate string `json:"certificate,omitempty"`
+	PrivateKey  string `json:"private_key,omitempty"`
+}
+
+// CAs splits the ca field into its certificates, in order, each as its own
+// PEM block.
+func (v Value) CAs() []string {
+	var cas []string
+	rest := []byte(v.CA)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return cas
+		}
+		cas = append(cas, string(pem.EncodeToMemory(block)))
+	}
+}
+
+// StatusError is a request CredHub refused.
+type StatusError struct {
+	Method string
+	Path   string
+	Status int
+	Body   string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s %s returned %d: %s", e.Method, e.Path, e.Status, e.Body)
+}
+
+// Client calls the certificates endpoints.
+type Client struct {
+	transport Transport
+}
+
+func New(transport Transport) *Client {
+	return &Client{transport: transport}
+}
+
+// List returns every certificate the caller can read.
+func (c *Client) List() ([]Certificate, error) {
+	var response struct {
+		Certificates []Certificate `json:"certificates"`
+	}
+	err := c.Call(http.MethodGet, "/api/v1/certificates", nil, nil, &response)
+	return response.Certificates, err
+}
+
+// Get returns the certificate called name.
+func (c *Client) Get(name string) (Certificate, error) {
+	var response struct {
+		Certificates []Certificate `json:"certificates"`
+	}
+	if err := c.Call(http.MethodGet, "/api/v1/certificates", url.Values{"name": {name}}, nil, &response); err != nil {
+		return Certificate{}, err
+	}
+	if len(response.Certificates) != 1 {
+		return Certificate{}, fmt.Errorf("found %d certificates named %s", len(response.Certificates), name)
+	}
+	return response.Certificates[0], nil
+}
+
+// Find returns the certificate called name from a list.
+func Find(certificates []Certificate, name string) (Certificate, bool) {
+	for _, certificate := range certificates {
+		if certificate.Name == name {
+			return certificate, true
+		}
+	}
+	return Certificate{}, false
+}
+
+// Versions returns the versions of a certificate, newest first.
+func (c *Client) Versions(id string) ([]Version, error) {
+	var versions []Version
+	err := c.Call(http.MethodGet, "/api/v1/certificates/"+id+"/versions", nil, nil, &versions)
+	return versions, err
+}
+
+// Current returns the versions of a certificate in use: the latest, and the
+// transitional one if there is one.
+func (c *Client) Current(id string) ([]Version, error) {
+	var versions []Version
+	err := c.Call(http.MethodGet, "/api/v1/certificates/"+id+"/versions", url.Values{"current": {"true"}}, nil, &versions)
+	return versions, err
+}
+
+// CreateVersion sets a new version of a certificate.
+func (c *Client) CreateVersion(id string, value Value, transitional bool) (Version, error) {
+	var version Version
+	body := map[string]interface{}{"value": value, "transitional": transitional}
+	err := c.Call(http.MethodPost, "/api/v1/certificates/"+id+"/versions", nil, body, &version)
+	return version, err
+}
+
+// Regenerate generates a new version of a certificate, as transitional or
+// not.
+func (c *Client) Regenerate(id string, transitional bool) (Version, error) {
+	var version Version
+	body := map[string]interface{}{"set_as_transitional": transitional}
+	err := c.Call(http.MethodPost, "/api/v1/certificates/"+id+"/regenerate", nil, body, &version)
+	return version, err
+}
+
+// SetTransitional marks a version of a CA as transitional, or, given "",
+// marks none. It returns the versions in use afterwards.
+func (c *Client) SetTransitional(id, versionID string) ([]Version, error) {
+	var version interface{}
+	if versionID != "" {
+		version = versionID
+	}
+	var versions []Version
+	body := map[string]interface{}{"version": version}
+	err := c.Call(http.MethodPut, "/api/v1/certificates/"+id+"/update_transitional_version", nil, body, &versions)
+	return versions, err
+}
+
+// DeleteVersion deletes one version of a certificate and returns it.
+func (c *Client) DeleteVersion(id, versionID string) (Version, error) {
+	var version Version
+	err := c.Call(http.MethodDelete, "/api/v1/certificates/"+id+"/versions/"+versionID, nil, nil, &version)
+	return version, err
+}
+
+// SignedBy returns the name of the CA that signed the certificate called
+// name.
+func (c *Client) SignedBy(name string) (string, error) {
+	certificate, err := c.Get(name)
+	return certificate.SignedBy, err
+}
+
+// Signs returns the names of the certificates the CA called name signs
+// directly.
+func (c *Client) Signs(name string) ([]string, error) {
+	certificate, err := c.Get(name)
+	return certificate.Signs, err
+}
+
+// Call sends a request and unmarshals the response into into, unless it is
+// nil. It is for the endpoints around certificates the client has no method
+// for. A status outside 2xx is returned as a *StatusError.
+func (c *Client) Call(method, path string, query url.Values, body, into interface{}) error {
+	status, contents, err := c.transport.Do(method, path, query, body)
+	if err != nil {
+		return err
+	}
+	if status < 200 || status > 299 {
+		return &StatusError{Method: method, Path: path, Status: status, Body: string(contents)}
+	}
+	if into == nil {
+		return nil
+	}
+	return json.Unmarshal(contents, into)
+}
@@ -0,0 +1,193 @@
+package certificates_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/certificates"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/certs"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   string
+}
+
+// fakeTransport records requests and answers each with the next response.
+type fakeTransport struct {
+	requests  []request
+	status    int
+	responses []string
+}
+
+func (f *fakeTransport) Do(method, path string, query url.Values, body interface{}) (int, []byte, error) {
+	encoded := ""
+	if body != nil {
+		contents, _ := json.Marshal(body)
+		encoded = string(contents)
+	}
+	f.requests = append(f.requests, request{method, path, query, encoded})
+	response := f.responses[0]
+	f.responses = f.responses[1:]
+	return f.status, []byte(response), nil
+}
+
+type fakeRequester struct {
+	method string
+	path   string
+	query  url.Values
+}
+
+func (f *fakeRequester) Request(method string, pathStr string, query url.Values, body interface{}, checkServerErr bool) (*http.Response, error) {
+	f.method, f.path, f.query = method, pathStr, query
+	return &http.Response{StatusCode: http.StatusNotFound, Body: ioutil.NopCloser(bytes.NewBufferString(`{"error":"missing"}`))}, nil
+}
+
+var _ = Describe("Client", func() {
+	var (
+		transport *fakeTransport
+		client    *certificates.Client
+	)
+
+	BeforeEach(func() {
+		transport = &fakeTransport{status: http.StatusOK}
+		client = certificates.New(transport)
+	})
+
+	It("gets a certificate by name with its relationships and versions", func() {
+		transport.responses = []string{`{"certificates":[{"id":"cert-id","name":"/ca","signed_by":"/ca","signs":["/leaf"],
+			"versions":[{"id":"v2","transitional":true,"certificate_authority":true,"self_signed":true},{"id":"v1"}]}]}`}
+
+		certificate, err := client.Get("/ca")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(transport.requests).To(ConsistOf(request{"GET", "/api/v1/certificates", url.Values{"name": {"/ca"}}, ""}))
+		Expect(certificate.ID).To(Equal("cert-id"))
+		Expect(certificate.SignedBy).To(Equal("/ca"))
+		Expect(certificate.Signs).To(Equal([]string{"/leaf"}))
+		Expect(certificate.Versions).To(HaveLen(2))
+		Expect(certificate.Versions[0].SelfSigned).To(BeTrue())
+		Expect(certificate.Transitional()).To(Equal([]string{"v2"}))
+	})
+
+	It("fails to get a name that matches no certificate", func() {
+		transport.responses = []string{`{"certificates":[]}`}
+		_, err := client.Get("/missing")
+		Expect(err).To(MatchError("found 0 certificates named /missing"))
+	})
+
+	It("finds certificates in a list", func() {
+		transport.responses = []string{`{"certificates":[{"name":"/a"},{"name":"/b","id":"b-id"}]}`}
+		list, err := client.List()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(transport.requests[0].query).To(BeEmpty())
+
+		found, ok := certificates.Find(list, "/b")
+		Expect(ok).To(BeTrue())
+		Expect(found.ID).To(Equal("b-id"))
+		_, ok = certificates.Find(list, "/c")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("sends every version operation to its endpoint", func() {
+		version := `{"id":"v","value":{"ca":"ca","certificate":"cert"}}`
+		transport.responses = []string{"[" + version + "]", "[" + version + "]", version, version, "[]", "[]", version}
+
+		Expect(client.Versions("id")).To(HaveLen(1))
+		Expect(client.Current("id")).To(HaveLen(1))
+		created, err := client.CreateVersion("id", certificates.Value{Certificate: "cert", PrivateKey: "key"}, true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created.Value.Certificate).To(Equal("cert"))
+		_, err = client.Regenerate("id", true)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.SetTransitional("id", "v")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.SetTransitional("id", "")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.DeleteVersion("id", "v")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(transport.requests).To(Equal([]request{
+			{"GET", "/api/v1/certificates/id/versions", nil, ""},
+			{"GET", "/api/v1/certificates/id/versions", url.Values{"current": {"true"}}, ""},
+			{"POST", "/api/v1/certificates/id/versions", nil, `{"transitional":true,"value":{"certificate":"cert","private_key":"key"}}`},
+			{"POST", "/api/v1/certificates/id/regenerate", nil, `{"set_as_transitional":true}`},
+			{"PUT", "/api/v1/certificates/id/update_transitional_version", nil, `{"version":"v"}`},
+			{"PUT", "/api/v1/certificates/id/update_transitional_version", nil, `{"version":null}`},
+			{"DELETE", "/api/v1/certificates/id/versions/v", nil, ""},
+		}))
+	})
+
+	It("returns refused requests as status errors", func() {
+		transport.status = http.StatusBadRequest
+		transport.responses = []string{`{"error":"The maximum number of transitional versions for a given CA is 1."}`}
+
+		_, err := client.Regenerate("id", true)
+		Expect(err).To(BeAssignableToTypeOf(&certificates.StatusError{}))
+		Expect(err.(*certificates.StatusError).Status).To(Equal(http.StatusBadRequest))
+		Expect(err).To(MatchError(ContainSubstring("POST /api/v1/certificates/id/regenerate returned 400")))
+	})
+})
+
+var _ = Describe("Value", func() {
+	It("splits a concatenated ca field", func() {
+		first, _, err := certs.GenerateSelfSigned(certs.CertOptions{CommonName: "first", IsCA: true})
+		Expect(err).NotTo(HaveOccurred())
+		second, _, err := certs.GenerateSelfSigned(certs.CertOptions{CommonName: "second", IsCA: true})
+		Expect(err).NotTo(HaveOccurred())
+
+		value := certificates.Value{CA: string(first) + "\n" + string(second)}
+		Expect(value.CAs()).To(Equal([]string{string(first), string(second)}))
+		Expect(certificates.Value{}.CAs()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Transports", func() {
+	It("sends requests through the Go client", func() {
+		requester := &fakeRequester{}
+		status, body, err := certificates.HTTP{Client: requester}.Do("GET", "/api/v1/certificates", url.Values{"name": {"/a"}}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(http.StatusNotFound))
+		Expect(string(body)).To(Equal(`{"error":"missing"}`))
+		Expect(requester.path).To(Equal("/api/v1/certificates"))
+	})
+
+	Describe("CLI", func() {
+		var args []string
+		run := func(output string) func(...string) []byte {
+			return func(a ...string) []byte {
+				args = a
+				return []byte(output)
+			}
+		}
+
+		It("sends requests with credhub curl and reads the status it prints", func() {
+			cli := certificates.CLI{Run: run("HTTP/1.1 200\r\nContent-Type: application/json\r\n\n{\n  \"certificates\": []\n}\n")}
+			status, body, err := cli.Do("PUT", "/api/v1/certificates/id/update_transitional_version", url.Values{"current": {"true"}}, map[string]interface{}{"version": nil})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(args).To(Equal([]string{"curl", "-i", "-X", "PUT", "-p", "/api/v1/certificates/id/update_transitional_version?current=true", "-d", `{"version":null}`}))
+			Expect(status).To(Equal(200))
+			Expect(body).To(MatchJSON(`{"certificates":[]}`))
+		})
+
+		It("reads a response without a body", func() {
+			status, body, err := certificates.CLI{Run: run("HTTP/1.1 204\r\n\n")}.Do("DELETE", "/api/v1/data", nil, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(204))
+			Expect(body).To(BeEmpty())
+			Expect(args).To(Equal([]string{"curl", "-i", "-X", "DELETE", "-p", "/api/v1/data"}))
+		})
+
+		It("fails when the CLI printed no status", func() {
+			_, _, err := certificates.CLI{Run: run("")}.Do("GET", "/api/v1/certificates", nil, nil)
+			Expect(err).To(MatchError(ContainSubstring("printed no status")))
+		})
+	})
+})
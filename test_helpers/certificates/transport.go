@@ -0,0 +1,93 @@
+package certificates
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Transport sends one request to CredHub and returns the response status and
+// body.
+type Transport interface {
+	Do(method, path string, query url.Values, body interface{}) (int, []byte, error)
+}
+
+// Requester is the part of the CredHub Go client HTTP uses.
+type Requester interface {
+	Request(method string, pathStr string, query url.Values, body interface{}, checkServerErr bool) (*http.Response, error)
+}
+
+// HTTP sends requests through the Go client.
+type HTTP struct {
+	Client Requester
+}
+
+func (h HTTP) Do(method, path string, query url.Values, body interface{}) (int, []byte, error) {
+	response, err := h.Client.Request(method, path, query, body, false)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer response.Body.Close()
+
+	contents, err := ioutil.ReadAll(response.Body)
+	return response.StatusCode, contents, err
+}
+
+// CLI sends requests with `credhub curl -i`. Run runs the CLI with the given
+// arguments and returns what it wrote to standard output, whatever its exit
+// code: the CLI exits non-zero on an empty response body after printing the
+// status.
+type CLI struct {
+	Run func(args ...string) []byte
+}
+
+func (c CLI) Do(method, path string, query url.Values, body interface{}) (int, []byte, error) {
+	target := path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	args := []string{"curl", "-i", "-X", method, "-p", target}
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, nil, err
+		}
+		args = append(args, "-d", string(data))
+	}
+	return parseCurl(c.Run(args...))
+}
+
+// parseCurl splits the output of `credhub curl -i` into the status and body.
+func parseCurl(output []byte) (int, []byte, error) {
+	reader := bufio.NewReader(bytes.NewReader(output))
+	statusLine, err := reader.ReadString('\n')
+	if err != nil {
+		return 0, nil, fmt.Errorf("credhub curl printed no status: %q", output)
+	}
+	fields := strings.Fields(statusLine)
+	if len(fields) < 2 || !strings.HasPrefix(fields[0], "HTTP/") {
+		return 0, nil, fmt.Errorf("credhub curl printed no status: %q", output)
+	}
+	status, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, nil, fmt.Errorf("credhub curl printed an invalid status: %q", statusLine)
+	}
+
+	for {
+		line, err := reader.ReadString('\n')
+		if strings.TrimRight(line, "\r\n") == "" {
+			break
+		}
+		if err != nil {
+			return status, nil, nil
+		}
+	}
+	rest, err := ioutil.ReadAll(reader)
+	return status, bytes.TrimSpace(rest), err
+}
@@ -15,16 +15,32 @@ import (
 
 const RsaKeySize = 4096
 
+// CertOptions describe a certificate to generate. NotBefore and NotAfter are
+// kept to the second, in whatever zone they are given: certificates hold
+// their validity in UTC with no fractional seconds, so anything finer is
+// truncated. NotBefore defaults to the time Clock tells, and NotAfter to 30
+// days after NotBefore.
 type CertOptions struct {
 	CommonName         string
 	OrganizationalUnit string
 	IsCA               bool
 	NotBefore          time.Time
 	NotAfter           time.Time
+	// Clock is time.Now unless set.
+	Clock func() time.Time
+	// KeySize is RsaKeySize unless set.
+	KeySize int
+}
+
+func (o CertOptions) keySize() int {
+	if o.KeySize == 0 {
+		return RsaKeySize
+	}
+	return o.KeySize
 }
 
 func GenerateSigned(certOptions CertOptions, caCert []byte, caKey []byte) ([]byte, []byte, error) {
-	key, err := rsa.GenerateKey(rand.Reader, RsaKeySize)
+	key, err := rsa.GenerateKey(rand.Reader, certOptions.keySize())
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to generate key: %s", err)
 	}
@@ -65,7 +81,7 @@ func GenerateSelfSigned(certOptions CertOptions) ([]byte, []byte, error) {
 		return nil, nil, err
 	}
 
-	key, err := rsa.GenerateKey(rand.Reader, RsaKeySize)
+	key, err := rsa.GenerateKey(rand.Reader, certOptions.keySize())
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to generate key: %s", err)
 	}
@@ -111,7 +127,11 @@ func generateCertificateTemplate(certOptions CertOptions) (*x509.Certificate, er
 }
 
 func calculateExpiryDates(certOptions CertOptions) (time.Time, time.Time, error) {
-	notBefore := time.Now()
+	now := time.Now
+	if certOptions.Clock != nil {
+		now = certOptions.Clock
+	}
+	notBefore := now()
 	if !certOptions.NotBefore.IsZero() {
 		notBefore = certOptions.NotBefore
 	}
@@ -119,6 +139,7 @@ func calculateExpiryDates(certOptions CertOptions) (time.Time, time.Time, error)
 	if !certOptions.NotAfter.IsZero() {
 		notAfter = certOptions.NotAfter
 	}
+	notBefore, notAfter = notBefore.UTC().Truncate(time.Second), notAfter.UTC().Truncate(time.Second)
 	if notBefore.After(notAfter) {
 		return time.Time{}, time.Time{}, fmt.Errorf("NotBefore (%s) must be earlier than NotAfter (%s)", notBefore, notAfter)
 	}
@@ -136,3 +157,16 @@ func encodeCertAndKey(derBytes []byte, key *rsa.PrivateKey) ([]byte, []byte, err
 
 	return certPem.Bytes(), keyPem.Bytes(), nil
 }
+
+// Validity returns when a PEM certificate becomes valid and when it expires.
+func Validity(certificate []byte) (time.Time, time.Time, error) {
+	block, _ := pem.Decode(certificate)
+	if block == nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to decode certificate PEM")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to parse certificate: %s", err)
+	}
+	return cert.NotBefore, cert.NotAfter, nil
+}
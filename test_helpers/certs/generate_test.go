@@ -1,6 +1,7 @@
 package certs_test
 
 import (
+	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
@@ -161,6 +162,40 @@ var _ = Describe("Generate", func() {
 					Expect(err).To(MatchError(MatchRegexp(`NotBefore (.*) must be earlier than NotAfter (.*)`)))
 				})
 			})
+
+			Context("given validity dates with fractional seconds in another zone", func() {
+				It("keeps them exactly, to the second", func() {
+					tokyo := time.FixedZone("UTC+9", 9*60*60)
+					notBefore := time.Date(2030, 1, 2, 23, 59, 59, 999999999, tokyo)
+					notAfter := time.Date(2030, 1, 31, 0, 0, 0, 1, tokyo)
+
+					options := CertOptions{NotBefore: notBefore, NotAfter: notAfter, KeySize: 2048}
+					certBytes, keyBytes, err := GenerateSelfSigned(options)
+					Expect(err).NotTo(HaveOccurred())
+
+					cert := parseCert(certBytes, keyBytes)
+					Expect(cert.NotBefore).To(Equal(time.Date(2030, 1, 2, 14, 59, 59, 0, time.UTC)))
+					Expect(cert.NotAfter).To(Equal(time.Date(2030, 1, 30, 15, 0, 0, 0, time.UTC)))
+
+					notBefore, notAfter, err = Validity(certBytes)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(notBefore).To(Equal(cert.NotBefore))
+					Expect(notAfter).To(Equal(cert.NotAfter))
+				})
+			})
+
+			Context("given a clock", func() {
+				It("starts the certificate at the time it tells", func() {
+					now := time.Date(2031, 6, 1, 12, 0, 0, 0, time.UTC)
+					certBytes, keyBytes, err := GenerateSelfSigned(CertOptions{Clock: func() time.Time { return now }, KeySize: 2048})
+					Expect(err).NotTo(HaveOccurred())
+
+					cert := parseCert(certBytes, keyBytes)
+					Expect(cert.NotBefore).To(Equal(now))
+					Expect(cert.NotAfter).To(Equal(now.Add(time.Hour * 24 * 30)))
+					Expect(cert.PublicKey.(*rsa.PublicKey).N.BitLen()).To(Equal(2048))
+				})
+			})
 		})
 	})
 
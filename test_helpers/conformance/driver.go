@@ -0,0 +1,352 @@
+package conformance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnsupported is returned by a driver for an operation its client does
+// not offer.
+var ErrUnsupported = errors.New("the driver's client does not support this")
+
+// Driver performs credential operations through one client.
+type Driver interface {
+	Set(name string, t Type, value interface{}, metadata credentials.Metadata) (credentials.Credential, error)
+	Generate(name string, t Type, mode credhub.Mode, metadata credentials.Metadata) (credentials.Credential, error)
+	// Regenerate regenerates the credential with metadata, or, given nil,
+	// without sending any: the CLI then sends the latest version's, and the Go
+	// client sends none.
+	Regenerate(name string, metadata credentials.Metadata) (credentials.Credential, error)
+	Get(name string) (credentials.Credential, error)
+	GetByID(id string) (credentials.Credential, error)
+	// Versions returns the latest n versions, newest first.
+	Versions(name string, n int) ([]credentials.Credential, error)
+	// Field returns one field of the latest value, or "" when the value has
+	// no such field or is not structured.
+	Field(name, key string) (string, error)
+	// Quiet returns the latest value alone.
+	Quiet(name string) (interface{}, error)
+	Delete(name string) error
+}
+
+// Client is the part of the CredHub Go client GoClient uses.
+type Client interface {
+	SetCredential(name, credType string, value interface{}, options ...credhub.SetOption) (credentials.Credential, error)
+	GenerateCredential(name, credType string, gen interface{}, overwrite credhub.Mode, options ...credhub.GenerateOption) (credentials.Credential, error)
+	Regenerate(name string, options ...credhub.RegenerateOption) (credentials.Credential, error)
+	GetLatestVersion(name string) (credentials.Credential, error)
+	GetById(id string) (credentials.Credential, error)
+	GetNVersions(name string, numberOfVersions int) ([]credentials.Credential, error)
+	Delete(name string) error
+}
+
+// GoClient drives the Go client.
+type GoClient struct {
+	Client Client
+}
+
+func (g GoClient) Set(name string, t Type, value interface{}, metadata credentials.Metadata) (credentials.Credential, error) {
+	return g.Client.SetCredential(name, t.Name, value, func(options *credhub.SetOptions) error {
+		options.Metadata = metadata
+		return nil
+	})
+}
+
+func (g GoClient) Generate(name string, t Type, mode credhub.Mode, metadata credentials.Metadata) (credentials.Credential, error) {
+	return g.Client.GenerateCredential(name, t.Name, t.Parameters(), mode, func(options *credhub.GenerateOptions) error {
+		options.Metadata = metadata
+		return nil
+	})
+}
+
+func (g GoClient) Regenerate(name string, metadata credentials.Metadata) (credentials.Credential, error) {
+	if metadata == nil {
+		return g.Client.Regenerate(name)
+	}
+	return g.Client.Regenerate(name, func(options *credhub.RegenerateOptions) error {
+		options.Metadata = metadata
+		return nil
+	})
+}
+
+func (g GoClient) Get(name string) (credentials.Credential, error) {
+	return g.Client.GetLatestVersion(name)
+}
+
+func (g GoClient) GetByID(id string) (credentials.Credential, error) {
+	return g.Client.GetById(id)
+}
+
+func (g GoClient) Versions(name string, n int) ([]credentials.Credential, error) {
+	return g.Client.GetNVersions(name, n)
+}
+
+func (g GoClient) Field(name, key string) (string, error) {
+	credential, err := g.Client.GetLatestVersion(name)
+	if err != nil {
+		return "", err
+	}
+	fields, ok := credential.Value.(map[string]interface{})
+	if !ok || fields[key] == nil {
+		return "", nil
+	}
+	if text, ok := fields[key].(string); ok {
+		return text, nil
+	}
+	encoded, err := json.Marshal(fields[key])
+	return string(encoded), err
+}
+
+func (g GoClient) Quiet(name string) (interface{}, error) {
+	credential, err := g.Client.GetLatestVersion(name)
+	return credential.Value, err
+}
+
+func (g GoClient) Delete(name string) error {
+	return g.Client.Delete(name)
+}
+
+// CLI drives the credhub CLI. Run runs it with the given arguments and
+// returns its standard output, or an error when it exits non-zero.
+type CLI struct {
+	Run func(args ...string) ([]byte, error)
+}
+
+func (c CLI) Set(name string, t Type, value interface{}, metadata credentials.Metadata) (credentials.Credential, error) {
+	args := []string{"set", "-n", name, "-t", t.Name, "-j"}
+	switch v := value.(type) {
+	case string:
+		if t.Name == "password" {
+			args = append(args, "-w", v)
+		} else {
+			args = append(args, "-v", v)
+		}
+	case map[string]interface{}:
+		flags, err := setFlags(t, v)
+		if err != nil {
+			return credentials.Credential{}, err
+		}
+		args = append(args, flags...)
+	default:
+		return credentials.Credential{}, fmt.Errorf("cannot set a %T with the CLI", value)
+	}
+	args, err := withMetadata(args, metadata)
+	if err != nil {
+		return credentials.Credential{}, err
+	}
+	return c.written(args...)
+}
+
+// setFlags are the set flags for a structured value.
+func setFlags(t Type, value map[string]interface{}) ([]string, error) {
+	text := func(key string) string {
+		s, _ := value[key].(string)
+		return s
+	}
+	switch t.Name {
+	case "json":
+		encoded, err := json.Marshal(value)
+		return []string{"-v", string(encoded)}, err
+	case "user":
+		return []string{"-z", text("username"), "-w", text("password")}, nil
+	case "ssh", "rsa":
+		return []string{"-u", text("public_key"), "-p", text("private_key")}, nil
+	case "certificate":
+		flags := []string{"-c", text("certificate"), "-p", text("private_key")}
+		if ca := text("ca"); ca != "" {
+			flags = append(flags, "-r", ca)
+		}
+		return flags, nil
+	}
+	return nil, fmt.Errorf("cannot set a structured %s with the CLI", t.Name)
+}
+
+func (c CLI) Generate(name string, t Type, mode credhub.Mode, metadata credentials.Metadata) (credentials.Credential, error) {
+	args := []string{"generate", "-n", name, "-t", t.Name, "-j"}
+	switch mode {
+	case credhub.Overwrite:
+	case credhub.NoOverwrite:
+		args = append(args, "--no-overwrite")
+	default:
+		return credentials.Credential{}, ErrUnsupported
+	}
+	flags, err := generateFlags(t.Parameters())
+	if err != nil {
+		return credentials.Credential{}, err
+	}
+	args, err = withMetadata(append(args, flags...), metadata)
+	if err != nil {
+		return credentials.Credential{}, err
+	}
+	return c.written(args...)
+}
+
+// generateParameterFlags are the generate flags for each generation
+// parameter.
+var generateParameterFlags = map[string]string{
+	"username":           "-z",
+	"length":             "-l",
+	"include_special":    "-S",
+	"exclude_number":     "-N",
+	"exclude_upper":      "-U",
+	"exclude_lower":      "-L",
+	"ssh_comment":        "-m",
+	"key_length":         "-k",
+	"duration":           "-d",
+	"common_name":        "-c",
+	"organization":       "-o",
+	"organization_unit":  "-u",
+	"locality":           "-i",
+	"state":              "-s",
+	"country":            "-y",
+	"alternative_names":  "-a",
+	"key_usage":          "-g",
+	"extended_key_usage": "-e",
+	"ca":                 "--ca",
+	"is_ca":              "--is-ca",
+	"self_sign":          "--self-sign",
+}
+
+// generateFlags are the generate flags for generation parameters, in the
+// order of their names: a string or number follows its flag, a true bool is
+// the flag alone and a list repeats the flag for each element.
+func generateFlags(parameters map[string]interface{}) ([]string, error) {
+	keys := make([]string, 0, len(parameters))
+	for key := range parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var flags []string
+	for _, key := range keys {
+		flag, ok := generateParameterFlags[key]
+		if !ok {
+			return nil, fmt.Errorf("the CLI has no flag for the %s parameter", key)
+		}
+		switch v := parameters[key].(type) {
+		case bool:
+			if v {
+				flags = append(flags, flag)
+			}
+		case string:
+			flags = append(flags, flag, v)
+		case int:
+			flags = append(flags, flag, strconv.Itoa(v))
+		case float64:
+			flags = append(flags, flag, strconv.FormatFloat(v, 'f', -1, 64))
+		case []string:
+			for _, element := range v {
+				flags = append(flags, flag, element)
+			}
+		default:
+			return nil, fmt.Errorf("cannot pass the %s parameter, a %T, to the CLI", key, v)
+		}
+	}
+	return flags, nil
+}
+
+func (c CLI) Regenerate(name string, metadata credentials.Metadata) (credentials.Credential, error) {
+	args, err := withMetadata([]string{"regenerate", "-n", name, "-j"}, metadata)
+	if err != nil {
+		return credentials.Credential{}, err
+	}
+	return c.written(args...)
+}
+
+func (c CLI) Get(name string) (credentials.Credential, error) {
+	return c.credential("get", "-n", name, "-j")
+}
+
+func (c CLI) GetByID(id string) (credentials.Credential, error) {
+	return c.credential("get", "--id", id, "-j")
+}
+
+func (c CLI) Versions(name string, n int) ([]credentials.Credential, error) {
+	output, err := c.Run("get", "-n", name, "--versions", strconv.Itoa(n), "-j")
+	if err != nil {
+		return nil, err
+	}
+	var versions struct {
+		Versions []credentials.Credential `json:"versions"`
+	}
+	err = json.Unmarshal(output, &versions)
+	return versions.Versions, err
+}
+
+func (c CLI) Field(name, key string) (string, error) {
+	output, err := c.Run("get", "-n", name, "-k", key)
+	return strings.TrimSuffix(string(output), "\n"), err
+}
+
+// Quiet parses what get -q prints: a string value as it is, and anything
+// else as YAML, read back the way JSON would be.
+func (c CLI) Quiet(name string) (interface{}, error) {
+	credential, err := c.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	output, err := c.Run("get", "-n", name, "-q")
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := credential.Value.(string); ok {
+		return strings.TrimSuffix(string(output), "\n"), nil
+	}
+
+	var value interface{}
+	if err := yaml.Unmarshal(output, &value); err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var normalised interface{}
+	err = json.Unmarshal(encoded, &normalised)
+	return normalised, err
+}
+
+func (c CLI) Delete(name string) error {
+	_, err := c.Run("delete", "-n", name)
+	return err
+}
+
+// written runs a command that writes a version and reads the version back
+// by its id, as set, generate and regenerate print its value as
+// "<redacted>".
+func (c CLI) written(args ...string) (credentials.Credential, error) {
+	credential, err := c.credential(args...)
+	if err != nil {
+		return credential, err
+	}
+	return c.GetByID(credential.Id)
+}
+
+func (c CLI) credential(args ...string) (credentials.Credential, error) {
+	output, err := c.Run(args...)
+	if err != nil {
+		return credentials.Credential{}, err
+	}
+	var credential credentials.Credential
+	err = json.Unmarshal(output, &credential)
+	return credential, err
+}
+
+func withMetadata(args []string, metadata credentials.Metadata) ([]string, error) {
+	if metadata == nil {
+		return args, nil
+	}
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+	return append(args, "--metadata", string(encoded)), nil
+}
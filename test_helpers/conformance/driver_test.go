@@ -0,0 +1,188 @@
+package conformance_test
+
+import (
+	"errors"
+	"strings"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/conformance"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CLI", func() {
+	var (
+		calls   [][]string
+		outputs map[string]string
+		driver  conformance.CLI
+	)
+
+	BeforeEach(func() {
+		calls = nil
+		outputs = map[string]string{}
+		driver = conformance.CLI{Run: func(args ...string) ([]byte, error) {
+			calls = append(calls, args)
+			output, ok := outputs[args[0]]
+			if !ok {
+				return nil, errors.New("exit status 1")
+			}
+			return []byte(output), nil
+		}}
+	})
+
+	// redacted is what set, generate and regenerate print: the version with
+	// its value redacted.
+	const redacted = `{"id":"some-id","name":"/some-name","type":"user","value":"<redacted>","metadata":{"team":"a"}}`
+
+	It("sets structured values with the type's flags and metadata as JSON, then reads the value back", func() {
+		outputs["set"] = redacted
+		outputs["get"] = `{"id":"some-id","name":"/some-name","type":"user","value":{"username":"u","password":"p"},"metadata":{"team":"a"}}`
+
+		credential, err := driver.Set("/some-name", typeNamed("user"), map[string]interface{}{"username": "u", "password": "p"}, map[string]interface{}{"team": "a"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(calls).To(Equal([][]string{
+			{"set", "-n", "/some-name", "-t", "user", "-j", "-z", "u", "-w", "p", "--metadata", `{"team":"a"}`},
+			{"get", "--id", "some-id", "-j"},
+		}))
+		Expect(credential.Id).To(Equal("some-id"))
+		Expect(credential.Value).To(Equal(map[string]interface{}{"username": "u", "password": "p"}))
+		Expect(credential.Metadata).To(Equal(credentials.Metadata{"team": "a"}))
+	})
+
+	It("sets JSON values as one argument and passwords with -w", func() {
+		outputs["set"] = redacted
+		outputs["get"] = `{}`
+
+		_, err := driver.Set("/json", typeNamed("json"), map[string]interface{}{"a": float64(1)}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = driver.Set("/password", typeNamed("password"), "secret", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(calls).To(Equal([][]string{
+			{"set", "-n", "/json", "-t", "json", "-j", "-v", `{"a":1}`},
+			{"get", "--id", "some-id", "-j"},
+			{"set", "-n", "/password", "-t", "password", "-j", "-w", "secret"},
+			{"get", "--id", "some-id", "-j"},
+		}))
+	})
+
+	It("generates with the type's parameters and --no-overwrite", func() {
+		outputs["generate"] = redacted
+		outputs["get"] = `{"id":"some-id","value":{"certificate":"c"}}`
+
+		credential, err := driver.Generate("/cert", typeNamed("certificate"), credhub.NoOverwrite, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(calls).To(Equal([][]string{
+			{
+				"generate", "-n", "/cert", "-t", "certificate", "-j", "--no-overwrite",
+				"-a", "conformance.example.com", "-a", "127.0.0.1",
+				"-c", "conformance",
+				"-d", "30",
+				"-e", "server_auth",
+				"-g", "digital_signature", "-g", "key_encipherment",
+				"-o", "Cloud Foundry",
+				"--self-sign",
+			},
+			{"get", "--id", "some-id", "-j"},
+		}))
+		Expect(credential.Value).To(Equal(map[string]interface{}{"certificate": "c"}))
+	})
+
+	It("passes every type's parameters as flags", func() {
+		outputs["generate"] = redacted
+		outputs["get"] = `{}`
+
+		for _, name := range []string{"password", "user", "ssh", "rsa"} {
+			_, err := driver.Generate("/"+name, typeNamed(name), credhub.Overwrite, nil)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(calls).To(Equal([][]string{
+			{"generate", "-n", "/password", "-t", "password", "-j", "-S", "-l", "40"},
+			{"get", "--id", "some-id", "-j"},
+			{"generate", "-n", "/user", "-t", "user", "-j", "-l", "40", "-z", "conformance-user"},
+			{"get", "--id", "some-id", "-j"},
+			{"generate", "-n", "/ssh", "-t", "ssh", "-j", "-k", "2048", "-m", "conformance"},
+			{"get", "--id", "some-id", "-j"},
+			{"generate", "-n", "/rsa", "-t", "rsa", "-j", "-k", "2048"},
+			{"get", "--id", "some-id", "-j"},
+		}))
+	})
+
+	It("does not support converging", func() {
+		_, err := driver.Generate("/password", typeNamed("password"), credhub.Converge, nil)
+		Expect(err).To(MatchError(conformance.ErrUnsupported))
+		Expect(calls).To(BeEmpty())
+	})
+
+	It("regenerates with metadata only when given some", func() {
+		outputs["regenerate"] = redacted
+		outputs["get"] = `{"id":"some-id","value":"regenerated"}`
+
+		credential, err := driver.Regenerate("/some-name", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(credential.Value).To(Equal("regenerated"))
+		_, err = driver.Regenerate("/some-name", credentials.Metadata{"team": "b"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(calls).To(Equal([][]string{
+			{"regenerate", "-n", "/some-name", "-j"},
+			{"get", "--id", "some-id", "-j"},
+			{"regenerate", "-n", "/some-name", "-j", "--metadata", `{"team":"b"}`},
+			{"get", "--id", "some-id", "-j"},
+		}))
+	})
+
+	It("reads versions newest first", func() {
+		outputs["get"] = `{"versions":[{"id":"2","value":"b"},{"id":"1","value":"a"}]}`
+
+		versions, err := driver.Versions("/some-name", 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(calls).To(Equal([][]string{{"get", "-n", "/some-name", "--versions", "2", "-j"}}))
+		Expect(versions).To(HaveLen(2))
+		Expect(versions[0].Id).To(Equal("2"))
+		Expect(versions[1].Value).To(Equal("a"))
+	})
+
+	It("trims the newline get -k prints", func() {
+		outputs["get"] = "some-user\n"
+
+		field, err := driver.Field("/some-name", "username")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(field).To(Equal("some-user"))
+		Expect(calls).To(Equal([][]string{{"get", "-n", "/some-name", "-k", "username"}}))
+	})
+
+	It("reads get -q output of structured values as YAML", func() {
+		driver.Run = func(args ...string) ([]byte, error) {
+			if strings.Join(args, " ") == "get -n /some-name -j" {
+				return []byte(`{"value":{"text":"x"}}`), nil
+			}
+			return []byte("index: 2\nnested:\n  list:\n  - a\n  - true\n  - null\ntext: x\n"), nil
+		}
+
+		value, err := driver.Quiet("/some-name")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal(map[string]interface{}{
+			"index":  float64(2),
+			"nested": map[string]interface{}{"list": []interface{}{"a", true, nil}},
+			"text":   "x",
+		}))
+	})
+
+	It("reads get -q output of string values as it is", func() {
+		driver.Run = func(args ...string) ([]byte, error) {
+			if strings.Join(args, " ") == "get -n /some-name -j" {
+				return []byte(`{"value":"true"}`), nil
+			}
+			return []byte("true\n"), nil
+		}
+
+		value, err := driver.Quiet("/some-name")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal("true"))
+	})
+
+	It("returns the error when the CLI fails", func() {
+		_, err := driver.Get("/missing")
+		Expect(err).To(MatchError("exit status 1"))
+	})
+})
@@ -0,0 +1,196 @@
+// Package conformance describes the behaviour every credential type must
+// share, whichever client drives it: what each type's values look like, and
+// a Driver interface over the CLI and the Go client that the conformance
+// suite runs the same specs through.
+package conformance
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/certs"
+)
+
+// Type is a credential type and how to make values of it.
+type Type struct {
+	Name string
+	// Generatable is whether CredHub can generate and regenerate the type.
+	Generatable bool
+	// RefusesStaticRegenerate is whether CredHub refuses to regenerate a value
+	// that was set rather than generated, as it has no parameters to
+	// generate it with.
+	RefusesStaticRegenerate bool
+	// Fields are the fields of a structured value that -k selects; nil for
+	// types whose values are plain strings or free-form JSON.
+	Fields []string
+	// value returns a distinct settable value for each n.
+	value func(n int) (interface{}, error)
+	// parameters are the generation parameters, for generatable types.
+	parameters map[string]interface{}
+}
+
+// Value returns a distinct settable value for each n.
+func (t Type) Value(n int) (interface{}, error) {
+	return t.value(n)
+}
+
+// Parameters are the generation parameters the conformance specs use.
+func (t Type) Parameters() map[string]interface{} {
+	parameters := map[string]interface{}{}
+	for key, value := range t.parameters {
+		parameters[key] = value
+	}
+	return parameters
+}
+
+// Scalar is whether values of the type are plain strings.
+func (t Type) Scalar() bool {
+	return t.Name == "value" || t.Name == "password"
+}
+
+// Types are every credential type.
+var Types = []Type{
+	{
+		Name:  "value",
+		value: func(n int) (interface{}, error) { return fmt.Sprintf("conformance value %d", n), nil },
+	},
+	{
+		Name: "json",
+		value: func(n int) (interface{}, error) {
+			return map[string]interface{}{
+				"text":   fmt.Sprintf("json value %d", n),
+				"index":  float64(n),
+				"nested": map[string]interface{}{"list": []interface{}{"a", true, nil}},
+			}, nil
+		},
+	},
+	{
+		Name:                    "password",
+		Generatable:             true,
+		RefusesStaticRegenerate: true,
+		value:                   func(n int) (interface{}, error) { return fmt.Sprintf("conformance-password-%d", n), nil },
+		parameters:              map[string]interface{}{"length": 40, "include_special": true},
+	},
+	{
+		Name:                    "user",
+		Generatable:             true,
+		RefusesStaticRegenerate: true,
+		Fields:                  []string{"username", "password"},
+		value: func(n int) (interface{}, error) {
+			return map[string]interface{}{"username": fmt.Sprintf("user-%d", n), "password": fmt.Sprintf("password-%d", n)}, nil
+		},
+		parameters: map[string]interface{}{"username": "conformance-user", "length": 40},
+	},
+	{
+		Name:        "ssh",
+		Generatable: true,
+		Fields:      []string{"public_key", "private_key"},
+		value:       sshValue,
+		parameters:  map[string]interface{}{"key_length": 2048, "ssh_comment": "conformance"},
+	},
+	{
+		Name:        "rsa",
+		Generatable: true,
+		Fields:      []string{"public_key", "private_key"},
+		value:       rsaValue,
+		parameters:  map[string]interface{}{"key_length": 2048},
+	},
+	{
+		Name:        "certificate",
+		Generatable: true,
+		Fields:      []string{"certificate", "private_key"},
+		value:       certificateValue,
+		parameters: map[string]interface{}{
+			"common_name":        "conformance",
+			"alternative_names":  []string{"conformance.example.com", "127.0.0.1"},
+			"organization":       "Cloud Foundry",
+			"key_usage":          []string{"digital_signature", "key_encipherment"},
+			"extended_key_usage": []string{"server_auth"},
+			"duration":           30,
+			"self_sign":          true,
+		},
+	},
+}
+
+// Matches reports whether a value read back from CredHub holds what was
+// set. Structured values only need to hold the fields that were set, as
+// CredHub adds computed ones such as an SSH key's fingerprint.
+func Matches(set, got interface{}) bool {
+	setFields, structured := set.(map[string]interface{})
+	gotFields, ok := got.(map[string]interface{})
+	if !structured || !ok {
+		return reflect.DeepEqual(set, got)
+	}
+	for key, value := range setFields {
+		if !reflect.DeepEqual(value, gotFields[key]) {
+			return false
+		}
+	}
+	return true
+}
+
+func rsaValue(int) (interface{}, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	public, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"public_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: public})),
+		"private_key": privateKeyPEM(key),
+	}, nil
+}
+
+func sshValue(int) (interface{}, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"public_key":  sshPublicKey(&key.PublicKey),
+		"private_key": privateKeyPEM(key),
+	}, nil
+}
+
+func certificateValue(n int) (interface{}, error) {
+	certificate, key, err := certs.GenerateSelfSigned(certs.CertOptions{CommonName: fmt.Sprintf("conformance-%d", n), KeySize: 2048})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"certificate": string(certificate), "private_key": string(key)}, nil
+}
+
+func privateKeyPEM(key *rsa.PrivateKey) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+}
+
+// sshPublicKey formats a key as an OpenSSH authorized key.
+func sshPublicKey(key *rsa.PublicKey) string {
+	var wire bytes.Buffer
+	writeString := func(b []byte) {
+		binary.Write(&wire, binary.BigEndian, uint32(len(b)))
+		wire.Write(b)
+	}
+	writeMPInt := func(i *big.Int) {
+		b := i.Bytes()
+		if len(b) > 0 && b[0]&0x80 != 0 {
+			b = append([]byte{0}, b...)
+		}
+		writeString(b)
+	}
+	writeString([]byte("ssh-rsa"))
+	writeMPInt(big.NewInt(int64(key.E)))
+	writeMPInt(key.N)
+	return "ssh-rsa " + base64.StdEncoding.EncodeToString(wire.Bytes())
+}
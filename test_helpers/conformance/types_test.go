@@ -0,0 +1,111 @@
+package conformance_test
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/conformance"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Types", func() {
+	It("covers every credential type", func() {
+		var names []string
+		for _, t := range conformance.Types {
+			names = append(names, t.Name)
+		}
+		Expect(names).To(ConsistOf("value", "json", "password", "user", "ssh", "rsa", "certificate"))
+	})
+
+	for _, t := range conformance.Types {
+		t := t
+
+		It("makes distinct "+t.Name+" values with the type's fields", func() {
+			first, err := t.Value(1)
+			Expect(err).NotTo(HaveOccurred())
+			second, err := t.Value(2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(first).NotTo(Equal(second))
+
+			if t.Scalar() {
+				Expect(first).To(BeAssignableToTypeOf(""))
+				return
+			}
+			fields, ok := first.(map[string]interface{})
+			Expect(ok).To(BeTrue())
+			for _, field := range t.Fields {
+				Expect(fields).To(HaveKeyWithValue(field, Not(BeEmpty())))
+			}
+		})
+	}
+
+	It("gives certificates a parseable certificate and key", func() {
+		value, err := typeNamed("certificate").Value(1)
+		Expect(err).NotTo(HaveOccurred())
+		fields := value.(map[string]interface{})
+
+		block, _ := pem.Decode([]byte(fields["certificate"].(string)))
+		Expect(block).NotTo(BeNil())
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(certificate.Subject.CommonName).To(Equal("conformance-1"))
+
+		block, _ = pem.Decode([]byte(fields["private_key"].(string)))
+		Expect(block).NotTo(BeNil())
+	})
+
+	It("gives SSH keys a public key in OpenSSH's authorized key format", func() {
+		value, err := typeNamed("ssh").Value(1)
+		Expect(err).NotTo(HaveOccurred())
+		parts := strings.Fields(value.(map[string]interface{})["public_key"].(string))
+		Expect(parts).To(HaveLen(2))
+		Expect(parts[0]).To(Equal("ssh-rsa"))
+
+		wire, err := base64.StdEncoding.DecodeString(parts[1])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(wire[:11]).To(Equal([]byte("\x00\x00\x00\x07ssh-rsa")))
+	})
+
+	It("copies the generation parameters", func() {
+		password := typeNamed("password")
+		parameters := password.Parameters()
+		Expect(parameters).To(Equal(map[string]interface{}{"length": 40, "include_special": true}))
+
+		parameters["length"] = 10
+		Expect(password.Parameters()["length"]).To(Equal(40))
+	})
+
+	Describe("Matches", func() {
+		It("compares scalars exactly", func() {
+			Expect(conformance.Matches("a", "a")).To(BeTrue())
+			Expect(conformance.Matches("a", "b")).To(BeFalse())
+			Expect(conformance.Matches("a", map[string]interface{}{"a": "a"})).To(BeFalse())
+		})
+
+		It("allows fields CredHub adds to structured values", func() {
+			set := map[string]interface{}{"public_key": "public", "private_key": "private"}
+			got := map[string]interface{}{"public_key": "public", "private_key": "private", "public_key_fingerprint": "print"}
+			Expect(conformance.Matches(set, got)).To(BeTrue())
+		})
+
+		It("requires every field that was set, nested values included", func() {
+			set := map[string]interface{}{"nested": map[string]interface{}{"list": []interface{}{"a", nil}}}
+			Expect(conformance.Matches(set, map[string]interface{}{})).To(BeFalse())
+			Expect(conformance.Matches(set, map[string]interface{}{"nested": map[string]interface{}{"list": []interface{}{"a"}}})).To(BeFalse())
+			Expect(conformance.Matches(set, map[string]interface{}{"nested": map[string]interface{}{"list": []interface{}{"a", nil}}})).To(BeTrue())
+		})
+	})
+})
+
+func typeNamed(name string) conformance.Type {
+	for _, t := range conformance.Types {
+		if t.Name == name {
+			return t
+		}
+	}
+	Fail("no type named " + name)
+	return conformance.Type{}
+}
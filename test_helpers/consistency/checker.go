@@ -0,0 +1,118 @@
+package consistency
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Violation is a name whose operations cannot be explained by any
+// linearizable execution.
+type Violation struct {
+	Name string
+	Ops  []Op
+	// Linearized is the longest prefix of the operations that could be
+	// ordered consistently with the model, which usually points at the
+	// operation that broke it.
+	Linearized []Op
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("no linearizable order of the %d operations on %s\nhistory:\n%s\nlongest linearizable prefix:\n%s",
+		len(v.Ops), v.Name, indent(Format(v.Ops)), indent(Format(v.Linearized)))
+}
+
+// Check looks for a linearization of the history for each name, i.e. an order
+// of its operations which respects real time (an operation that finished
+// before another started comes first) and in which every operation got the
+// result the model says it should.
+func Check(history *History) []Violation {
+	byName := history.ByName()
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var violations []Violation
+	for _, name := range names {
+		ops := byName[name]
+		c := &checker{ops: ops, failed: map[string]bool{}}
+		if !c.search(make([]bool, len(ops)), nil, state{}) {
+			violations = append(violations, Violation{Name: name, Ops: ops, Linearized: c.longest})
+		}
+	}
+	return violations
+}
+
+// checker is a Wing and Gong style search: at each point, try every pending
+// operation that could have taken effect first, and backtrack when the model
+// rejects its result. Dead ends are memoised by the set of operations done
+// and the model state, which keeps the search tractable for the short
+// per-name histories the harness produces.
+type checker struct {
+	ops     []Op
+	failed  map[string]bool
+	longest []Op
+}
+
+func (c *checker) search(done []bool, order []Op, s state) bool {
+	if len(order) > len(c.longest) {
+		c.longest = append([]Op(nil), order...)
+	}
+	if len(order) == len(c.ops) {
+		return true
+	}
+
+	key := memoKey(done, s)
+	if c.failed[key] {
+		return false
+	}
+
+	// An operation can only go next if it started before every pending
+	// operation finished; otherwise one of those must precede it.
+	var firstEnd time.Time
+	for i, op := range c.ops {
+		if !done[i] && (firstEnd.IsZero() || op.End.Before(firstEnd)) {
+			firstEnd = op.End
+		}
+	}
+
+	for i, op := range c.ops {
+		if done[i] || op.Start.After(firstEnd) {
+			continue
+		}
+		next, ok := step(s, op)
+		if !ok {
+			continue
+		}
+		done[i] = true
+		found := c.search(done, append(order, op), next)
+		done[i] = false
+		if found {
+			return true
+		}
+	}
+
+	c.failed[key] = true
+	return false
+}
+
+func memoKey(done []bool, s state) string {
+	var key strings.Builder
+	for _, d := range done {
+		if d {
+			key.WriteByte('1')
+		} else {
+			key.WriteByte('0')
+		}
+	}
+	key.WriteByte('|')
+	key.WriteString(s.key())
+	return key.String()
+}
+
+func indent(text string) string {
+	return "  " + strings.ReplaceAll(text, "\n", "\n  ")
+}
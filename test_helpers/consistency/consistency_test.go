@@ -0,0 +1,266 @@
+package consistency_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/consistency"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var (
+	errNotFound         = errors.New("The request could not be completed because the credential does not exist or you do not have sufficient authorization.")
+	errTypeMismatch     = errors.New("The credential type cannot be modified. Please delete the credential if you wish to create it with a different type.")
+	errNotRegeneratable = errors.New("The password could not be regenerated because the value was statically set. Only generated passwords may be regenerated.")
+)
+
+type credential struct {
+	credType  string
+	generated bool
+	versions  []string
+}
+
+// fakeCredHub keeps credentials in memory. Every request holds the lock for
+// its whole duration, unless racyNoOverwrite is set, in which case
+// --no-overwrite checks for an existing credential and writes a new one
+// under separate locks.
+type fakeCredHub struct {
+	mutex           sync.Mutex
+	credentials     map[string]*credential
+	nextID          int
+	racyNoOverwrite bool
+}
+
+func newFakeCredHub() *fakeCredHub {
+	return &fakeCredHub{credentials: map[string]*credential{}}
+}
+
+func (f *fakeCredHub) write(name, credType string, generated bool) (Version, error) {
+	existing := f.credentials[name]
+	if existing != nil && existing.credType != credType {
+		return Version{}, errTypeMismatch
+	}
+	if existing == nil {
+		existing = &credential{credType: credType}
+		f.credentials[name] = existing
+	}
+	f.nextID++
+	id := fmt.Sprint(f.nextID)
+	existing.generated = generated
+	existing.versions = append(existing.versions, id)
+	return Version{ID: id, Type: credType}, nil
+}
+
+func (f *fakeCredHub) Set(name, credType string) (Version, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.write(name, credType, false)
+}
+
+func (f *fakeCredHub) Generate(name, credType string, overwrite bool) (Version, error) {
+	f.mutex.Lock()
+	existing := f.credentials[name]
+	if !overwrite && existing != nil {
+		defer f.mutex.Unlock()
+		if existing.credType != credType {
+			return Version{}, errTypeMismatch
+		}
+		return Version{ID: existing.versions[len(existing.versions)-1], Type: credType}, nil
+	}
+	if !overwrite && f.racyNoOverwrite {
+		f.mutex.Unlock()
+		time.Sleep(time.Millisecond)
+		f.mutex.Lock()
+	}
+	defer f.mutex.Unlock()
+	return f.write(name, credType, true)
+}
+
+func (f *fakeCredHub) Regenerate(name string) (Version, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	existing := f.credentials[name]
+	if existing == nil {
+		return Version{}, errNotFound
+	}
+	if !existing.generated {
+		return Version{}, errNotRegeneratable
+	}
+	return f.write(name, existing.credType, true)
+}
+
+func (f *fakeCredHub) Delete(name string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.credentials[name] == nil {
+		return errNotFound
+	}
+	delete(f.credentials, name)
+	return nil
+}
+
+func (f *fakeCredHub) Get(name string) (Version, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	existing := f.credentials[name]
+	if existing == nil {
+		return Version{}, errNotFound
+	}
+	return Version{ID: existing.versions[len(existing.versions)-1], Type: existing.credType}, nil
+}
+
+func (f *fakeCredHub) CountVersions(name string) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	existing := f.credentials[name]
+	if existing == nil {
+		return 0, errNotFound
+	}
+	return len(existing.versions), nil
+}
+
+var _ = Describe("Harness", func() {
+	options := Options{
+		Actors:      6,
+		OpsPerActor: 15,
+		Names:       []string{"/one", "/two"},
+		Types:       []string{"password", "user"},
+		Weights:     DefaultWeights,
+	}
+
+	It("finds a linearization of a linearizable CredHub", func() {
+		for seed := int64(0); seed < 10; seed++ {
+			options.Seed = seed
+			history := Run(newFakeCredHub(), options)
+
+			Expect(history.Ops()).To(HaveLen(6*15 + 2))
+			Expect(Check(history)).To(BeEmpty())
+		}
+	})
+
+	It("catches two writers both winning a --no-overwrite race", func() {
+		racy := Options{
+			Actors:      8,
+			OpsPerActor: 4,
+			Names:       []string{"/one"},
+			Types:       []string{"password"},
+			Weights:     map[Kind]int{GenerateNoOverwrite: 1, Delete: 1},
+		}
+
+		found := false
+		for seed := int64(0); seed < 20 && !found; seed++ {
+			racy.Seed = seed
+			fake := newFakeCredHub()
+			fake.racyNoOverwrite = true
+			found = len(Check(Run(fake, racy))) > 0
+		}
+		Expect(found).To(BeTrue())
+	})
+})
+
+var _ = Describe("Check", func() {
+	var (
+		origin  time.Time
+		history *History
+	)
+
+	// at records an operation over [start, end] milliseconds.
+	at := func(start, end int, op Op) {
+		op.Start = origin.Add(time.Duration(start) * time.Millisecond)
+		op.End = origin.Add(time.Duration(end) * time.Millisecond)
+		if op.Outcome == "" {
+			op.Outcome = OK
+		}
+		history.Add(op)
+	}
+
+	BeforeEach(func() {
+		origin = time.Now()
+		history = &History{}
+	})
+
+	It("accepts one --no-overwrite winner that everyone else observes", func() {
+		at(0, 10, Op{Actor: 0, Kind: GenerateNoOverwrite, Name: "/a", Type: "rsa", Version: Version{"2", "rsa"}})
+		at(1, 9, Op{Actor: 1, Kind: GenerateNoOverwrite, Name: "/a", Type: "rsa", Version: Version{"2", "rsa"}})
+		at(11, 12, Op{Kind: CountVersions, Name: "/a", Count: 1})
+
+		Expect(Check(history)).To(BeEmpty())
+	})
+
+	It("rejects two --no-overwrite winners", func() {
+		at(0, 10, Op{Actor: 0, Kind: GenerateNoOverwrite, Name: "/a", Type: "rsa", Version: Version{"1", "rsa"}})
+		at(1, 9, Op{Actor: 1, Kind: GenerateNoOverwrite, Name: "/a", Type: "rsa", Version: Version{"2", "rsa"}})
+
+		violations := Check(history)
+		Expect(violations).To(HaveLen(1))
+		Expect(violations[0].Name).To(Equal("/a"))
+		Expect(violations[0].Linearized).To(HaveLen(1))
+		Expect(violations[0].String()).To(ContainSubstring("no linearizable order of the 2 operations on /a"))
+	})
+
+	It("reorders concurrent operations but not sequential ones", func() {
+		at(0, 10, Op{Actor: 0, Kind: Get, Name: "/a", Version: Version{"1", "password"}})
+		at(1, 5, Op{Actor: 1, Kind: Set, Name: "/a", Type: "password", Version: Version{"1", "password"}})
+		Expect(Check(history)).To(BeEmpty())
+
+		history = &History{}
+		at(0, 4, Op{Actor: 0, Kind: Get, Name: "/a", Version: Version{"1", "password"}})
+		at(5, 10, Op{Actor: 1, Kind: Set, Name: "/a", Type: "password", Version: Version{"1", "password"}})
+		Expect(Check(history)).To(HaveLen(1))
+	})
+
+	It("expects a type mismatch when a write changes the type", func() {
+		at(0, 1, Op{Actor: 0, Kind: Set, Name: "/a", Type: "ssh", Version: Version{"1", "ssh"}})
+		at(2, 5, Op{Actor: 1, Kind: Set, Name: "/a", Type: "rsa", Outcome: TypeMismatch})
+		Expect(Check(history)).To(BeEmpty())
+
+		history = &History{}
+		at(0, 1, Op{Actor: 0, Kind: Set, Name: "/a", Type: "ssh", Version: Version{"1", "ssh"}})
+		at(2, 5, Op{Actor: 1, Kind: Set, Name: "/a", Type: "rsa", Version: Version{"2", "rsa"}})
+		Expect(Check(history)).To(HaveLen(1))
+	})
+
+	It("allows a type change after a delete", func() {
+		at(0, 1, Op{Actor: 0, Kind: Set, Name: "/a", Type: "ssh", Version: Version{"1", "ssh"}})
+		at(2, 3, Op{Actor: 0, Kind: Delete, Name: "/a"})
+		at(4, 5, Op{Actor: 0, Kind: Generate, Name: "/a", Type: "rsa", Version: Version{"3", "rsa"}})
+		at(6, 7, Op{Actor: 0, Kind: Regenerate, Name: "/a", Version: Version{"4", "rsa"}})
+		at(8, 9, Op{Kind: CountVersions, Name: "/a", Count: 2})
+		Expect(Check(history)).To(BeEmpty())
+	})
+
+	It("checks the version count left behind", func() {
+		at(0, 1, Op{Actor: 0, Kind: Set, Name: "/a", Type: "value", Version: Version{"1", "value"}})
+		at(0, 1, Op{Actor: 1, Kind: Set, Name: "/a", Type: "value", Version: Version{"2", "value"}})
+		at(2, 3, Op{Kind: CountVersions, Name: "/a", Count: 1})
+		Expect(Check(history)).To(HaveLen(1))
+	})
+
+	It("expects statically set credentials not to be regenerated", func() {
+		at(0, 1, Op{Actor: 0, Kind: Set, Name: "/a", Type: "password", Version: Version{"1", "password"}})
+		at(2, 3, Op{Actor: 0, Kind: Regenerate, Name: "/a", Outcome: NotRegeneratable, Err: errNotRegeneratable})
+		at(4, 5, Op{Actor: 0, Kind: Regenerate, Name: "/b", Outcome: NotFound, Err: errNotFound})
+		Expect(Check(history)).To(BeEmpty())
+	})
+
+	It("never accepts unexpected errors", func() {
+		at(0, 1, Op{Actor: 0, Kind: Get, Name: "/a", Outcome: Failed, Err: errors.New("connection reset")})
+		violations := Check(history)
+		Expect(violations).To(HaveLen(1))
+		Expect(violations[0].String()).To(ContainSubstring("actor 0: get /a => failed (connection reset)"))
+	})
+})
+
+var _ = Describe("ClassifyError", func() {
+	It("recognises CredHub's expected errors", func() {
+		Expect(ClassifyError(nil)).To(Equal(OK))
+		Expect(ClassifyError(errNotFound)).To(Equal(NotFound))
+		Expect(ClassifyError(errTypeMismatch)).To(Equal(TypeMismatch))
+		Expect(ClassifyError(errNotRegeneratable)).To(Equal(NotRegeneratable))
+		Expect(ClassifyError(errors.New("boom"))).To(Equal(Failed))
+	})
+})
@@ -0,0 +1,153 @@
+package consistency
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Version is the credential version returned by a request.
+type Version struct {
+	ID   string
+	Type string
+}
+
+// Client makes the requests of each Kind against CredHub. Implementations
+// must be safe for concurrent use.
+type Client interface {
+	Set(name, credType string) (Version, error)
+	Generate(name, credType string, overwrite bool) (Version, error)
+	Regenerate(name string) (Version, error)
+	Delete(name string) error
+	Get(name string) (Version, error)
+	CountVersions(name string) (int, error)
+}
+
+// Options configures a run of the harness.
+type Options struct {
+	Actors      int
+	OpsPerActor int
+	// Names are the credential names the actors contend over. Keep them few
+	// so that operations collide often.
+	Names []string
+	// Types are the credential types set and generated. Giving more than one
+	// makes some writes change a credential's type.
+	Types []string
+	// Weights sets how often each kind of operation is picked. Kinds that are
+	// not given are never picked.
+	Weights map[Kind]int
+	Seed    int64
+	// Classify maps errors returned by the client onto outcomes. It defaults
+	// to ClassifyError.
+	Classify func(error) Outcome
+}
+
+// DefaultWeights exercises every kind of operation, favouring the writes
+// that race with each other.
+var DefaultWeights = map[Kind]int{
+	Set:                 3,
+	Generate:            2,
+	GenerateNoOverwrite: 4,
+	Regenerate:          2,
+	Delete:              1,
+	Get:                 2,
+}
+
+// ClassifyError recognises the errors CredHub returns for the expected
+// failures of each kind of operation.
+func ClassifyError(err error) Outcome {
+	if err == nil {
+		return OK
+	}
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "type cannot be modified"):
+		return TypeMismatch
+	case strings.Contains(message, "does not exist"):
+		return NotFound
+	case strings.Contains(message, "statically set"):
+		return NotRegeneratable
+	default:
+		return Failed
+	}
+}
+
+// Run starts every actor at once, each making OpsPerActor random operations,
+// then counts the versions of each name. The returned history is ready to
+// Check.
+func Run(client Client, opts Options) *History {
+	classify := opts.Classify
+	if classify == nil {
+		classify = ClassifyError
+	}
+
+	kinds := make([]Kind, 0, len(opts.Weights))
+	for kind := range opts.Weights {
+		kinds = append(kinds, kind)
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i] < kinds[j] })
+
+	var weighted []Kind
+	for _, kind := range kinds {
+		for i := 0; i < opts.Weights[kind]; i++ {
+			weighted = append(weighted, kind)
+		}
+	}
+
+	history := &History{}
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+
+	for actor := 0; actor < opts.Actors; actor++ {
+		wg.Add(1)
+		go func(actor int) {
+			defer wg.Done()
+			random := rand.New(rand.NewSource(opts.Seed + int64(actor)))
+			<-start
+			for i := 0; i < opts.OpsPerActor; i++ {
+				op := Op{
+					Actor: actor,
+					Kind:  weighted[random.Intn(len(weighted))],
+					Name:  opts.Names[random.Intn(len(opts.Names))],
+				}
+				if op.Kind == Set || op.Kind == Generate || op.Kind == GenerateNoOverwrite {
+					op.Type = opts.Types[random.Intn(len(opts.Types))]
+				}
+				history.Add(perform(client, classify, op))
+			}
+		}(actor)
+	}
+
+	close(start)
+	wg.Wait()
+
+	for _, name := range opts.Names {
+		history.Add(perform(client, classify, Op{Actor: -1, Kind: CountVersions, Name: name}))
+	}
+	return history
+}
+
+func perform(client Client, classify func(error) Outcome, op Op) Op {
+	op.Start = time.Now()
+	switch op.Kind {
+	case Set:
+		op.Version, op.Err = client.Set(op.Name, op.Type)
+	case Generate:
+		op.Version, op.Err = client.Generate(op.Name, op.Type, true)
+	case GenerateNoOverwrite:
+		op.Version, op.Err = client.Generate(op.Name, op.Type, false)
+	case Regenerate:
+		op.Version, op.Err = client.Regenerate(op.Name)
+	case Delete:
+		op.Err = client.Delete(op.Name)
+	case Get:
+		op.Version, op.Err = client.Get(op.Name)
+	case CountVersions:
+		op.Count, op.Err = client.CountVersions(op.Name)
+	}
+	op.End = time.Now()
+	op.Outcome = classify(op.Err)
+	return op
+}
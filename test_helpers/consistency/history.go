@@ -0,0 +1,124 @@
+package consistency
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kind is the kind of request an operation makes.
+type Kind string
+
+const (
+	Set                 Kind = "set"
+	Generate            Kind = "generate"
+	GenerateNoOverwrite Kind = "generate --no-overwrite"
+	Regenerate          Kind = "regenerate"
+	Delete              Kind = "delete"
+	Get                 Kind = "get"
+	// CountVersions is run once per name after every actor has finished, so
+	// that the number of versions left behind is checked too.
+	CountVersions Kind = "count versions"
+)
+
+// Outcome classifies the result of an operation.
+type Outcome string
+
+const (
+	OK               Outcome = "ok"
+	NotFound         Outcome = "not found"
+	TypeMismatch     Outcome = "type mismatch"
+	NotRegeneratable Outcome = "not regeneratable"
+	// Failed is any other error. It is never expected, so a history with a
+	// failed operation is not linearizable.
+	Failed Outcome = "failed"
+)
+
+// Op is one request made by an actor, together with its result.
+type Op struct {
+	Actor int
+	Kind  Kind
+	Name  string
+	Type  string
+
+	Start time.Time
+	End   time.Time
+
+	Outcome Outcome
+	Version Version
+	Count   int
+	Err     error
+}
+
+func (o Op) String() string {
+	actor := fmt.Sprintf("actor %d", o.Actor)
+	if o.Actor < 0 {
+		actor = "after run"
+	}
+	request := fmt.Sprintf("%s: %s %s", actor, o.Kind, o.Name)
+	if o.Type != "" {
+		request += " -t " + o.Type
+	}
+
+	var result string
+	switch {
+	case o.Outcome != OK:
+		result = fmt.Sprintf("%s (%v)", o.Outcome, o.Err)
+	case o.Kind == CountVersions:
+		result = fmt.Sprintf("%d versions", o.Count)
+	case o.Kind == Delete:
+		result = "ok"
+	default:
+		result = fmt.Sprintf("%s version %s", o.Version.Type, o.Version.ID)
+	}
+	return request + " => " + result
+}
+
+// History is the record of every operation made during a run. It is safe for
+// concurrent use.
+type History struct {
+	mutex sync.Mutex
+	ops   []Op
+}
+
+func (h *History) Add(op Op) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.ops = append(h.ops, op)
+}
+
+// Ops returns the operations ordered by the time they started.
+func (h *History) Ops() []Op {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	ops := append([]Op(nil), h.ops...)
+	sort.SliceStable(ops, func(i, j int) bool { return ops[i].Start.Before(ops[j].Start) })
+	return ops
+}
+
+// ByName partitions the operations by credential name. Operations on
+// different names never affect each other, so each partition can be checked
+// on its own.
+func (h *History) ByName() map[string][]Op {
+	byName := map[string][]Op{}
+	for _, op := range h.Ops() {
+		byName[op.Name] = append(byName[op.Name], op)
+	}
+	return byName
+}
+
+// Format renders ops with their timing relative to the first of them, one
+// per line.
+func Format(ops []Op) string {
+	if len(ops) == 0 {
+		return ""
+	}
+	origin := ops[0].Start
+	lines := make([]string, len(ops))
+	for i, op := range ops {
+		lines[i] = fmt.Sprintf("[%8s, %8s] %s", op.Start.Sub(origin).Round(time.Microsecond), op.End.Sub(origin).Round(time.Microsecond), op)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,100 @@
+package consistency
+
+import (
+	"fmt"
+	"strings"
+)
+
+// state is what the model knows about a single credential name.
+type state struct {
+	exists    bool
+	credType  string
+	generated bool
+	// versions are the ids of the versions written since the name was last
+	// deleted, oldest first.
+	versions []string
+}
+
+func (s state) current() string {
+	if len(s.versions) == 0 {
+		return ""
+	}
+	return s.versions[len(s.versions)-1]
+}
+
+func (s state) key() string {
+	return fmt.Sprintf("%t|%s|%t|%s", s.exists, s.credType, s.generated, strings.Join(s.versions, ","))
+}
+
+// step applies op to s as a linearizable CredHub would, reporting whether
+// op's recorded result is the one that CredHub should have returned.
+func step(s state, op Op) (state, bool) {
+	if op.Outcome == Failed {
+		return s, false
+	}
+
+	switch op.Kind {
+	case Set, Generate:
+		if s.exists && s.credType != op.Type {
+			return s, op.Outcome == TypeMismatch
+		}
+		return s.write(op, op.Type, op.Kind == Generate)
+
+	case GenerateNoOverwrite:
+		if s.exists && s.credType != op.Type {
+			return s, op.Outcome == TypeMismatch
+		}
+		if s.exists {
+			// Only the first writer wins; everyone else gets its version.
+			return s, op.Outcome == OK && op.Version.ID == s.current() && op.Version.Type == s.credType
+		}
+		return s.write(op, op.Type, true)
+
+	case Regenerate:
+		if !s.exists {
+			return s, op.Outcome == NotFound
+		}
+		if !s.generated {
+			return s, op.Outcome == NotRegeneratable
+		}
+		return s.write(op, s.credType, true)
+
+	case Delete:
+		if !s.exists {
+			return s, op.Outcome == NotFound
+		}
+		return state{}, op.Outcome == OK
+
+	case Get:
+		if !s.exists {
+			return s, op.Outcome == NotFound
+		}
+		return s, op.Outcome == OK && op.Version.ID == s.current() && op.Version.Type == s.credType
+
+	case CountVersions:
+		if !s.exists {
+			return s, op.Outcome == NotFound
+		}
+		return s, op.Outcome == OK && op.Count == len(s.versions)
+	}
+
+	return s, false
+}
+
+// write appends the version op created, which must be a new one of credType.
+func (s state) write(op Op, credType string, generated bool) (state, bool) {
+	if op.Outcome != OK || op.Version.ID == "" || op.Version.Type != credType {
+		return s, false
+	}
+	for _, id := range s.versions {
+		if id == op.Version.ID {
+			return s, false
+		}
+	}
+	return state{
+		exists:    true,
+		credType:  credType,
+		generated: generated,
+		versions:  append(append([]string(nil), s.versions...), op.Version.ID),
+	}, true
+}
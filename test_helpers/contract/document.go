@@ -0,0 +1,160 @@
+package contract
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+//go:embed credhub-openapi.json
+var defaultDocument []byte
+
+// Document is the subset of an OpenAPI 3 document needed to validate
+// responses: the response schemas of each operation, and the shared schemas
+// they refer to.
+type Document struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+
+	operations []operation
+}
+
+type operation struct {
+	method    string
+	segments  []string
+	responses map[string]*Schema
+}
+
+type openAPIOperation struct {
+	Responses map[string]struct {
+		Content map[string]struct {
+			Schema *Schema `json:"schema"`
+		} `json:"content"`
+	} `json:"responses"`
+}
+
+var methods = []string{"get", "put", "post", "delete", "patch"}
+
+// Load parses an OpenAPI document.
+func Load(data []byte) (*Document, error) {
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	for path, item := range doc.Paths {
+		for _, method := range methods {
+			raw, ok := item[method]
+			if !ok {
+				continue
+			}
+			var parsed openAPIOperation
+			if err := json.Unmarshal(raw, &parsed); err != nil {
+				return nil, fmt.Errorf("%s %s: %s", strings.ToUpper(method), path, err)
+			}
+
+			op := operation{method: strings.ToUpper(method), segments: split(path), responses: map[string]*Schema{}}
+			for status, response := range parsed.Responses {
+				for contentType, content := range response.Content {
+					if strings.Contains(contentType, "json") {
+						op.responses[strings.ToUpper(status)] = content.Schema
+					}
+				}
+			}
+			doc.operations = append(doc.operations, op)
+		}
+	}
+	return &doc, nil
+}
+
+// LoadFile parses the OpenAPI document at path.
+func LoadFile(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Load(data)
+}
+
+// DefaultDocument returns the CredHub API document that ships with this package.
+func DefaultDocument() *Document {
+	doc, err := Load(defaultDocument)
+	if err != nil {
+		panic(fmt.Sprintf("invalid embedded OpenAPI document: %s", err))
+	}
+	return doc
+}
+
+// Check validates a JSON response body returned for method and path with the
+// given status code. It returns nil when the response matches the document,
+// or when the document does not describe the response at all.
+func (d *Document) Check(method, path string, status int, body []byte) []string {
+	schema, ok := d.responseSchema(method, path, status)
+	if !ok {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []string{fmt.Sprintf("$: invalid JSON: %s", err)}
+	}
+	return d.validate(schema, value, "$")
+}
+
+// responseSchema finds the operation whose path template best matches path,
+// preferring literal segments over parameters, then the schema of its
+// response for status, falling back to the status class (e.g. 4XX) and then
+// the default response.
+func (d *Document) responseSchema(method, path string, status int) (*Schema, bool) {
+	segments := split(path)
+
+	var best *operation
+	bestLiterals := -1
+	for i := range d.operations {
+		op := &d.operations[i]
+		if op.method != strings.ToUpper(method) {
+			continue
+		}
+		literals, ok := match(op.segments, segments)
+		if ok && literals > bestLiterals {
+			best, bestLiterals = op, literals
+		}
+	}
+	if best == nil {
+		return nil, false
+	}
+
+	code := strconv.Itoa(status)
+	for _, key := range []string{code, code[:1] + "XX", "DEFAULT"} {
+		if schema, ok := best.responses[key]; ok {
+			return schema, true
+		}
+	}
+	return nil, false
+}
+
+func match(template, segments []string) (int, bool) {
+	if len(template) != len(segments) {
+		return 0, false
+	}
+	literals := 0
+	for i, segment := range template {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			continue
+		}
+		if segment != segments[i] {
+			return 0, false
+		}
+		literals++
+	}
+	return literals, true
+}
+
+func split(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
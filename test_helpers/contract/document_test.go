@@ -0,0 +1,150 @@
+package contract_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/contract"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const credentialFields = `"id":"6ba7b810-9dad-11d1-80b4-00c04fd430c8","name":"/foo","version_created_at":"2017-01-01T04:07:18Z"`
+
+var _ = Describe("Document", func() {
+	var doc *Document
+
+	BeforeEach(func() {
+		doc = DefaultDocument()
+	})
+
+	DescribeTable("accepts every credential type",
+		func(credentialType, value string) {
+			body := `{` + credentialFields + `,"type":"` + credentialType + `","value":` + value + `}`
+			Expect(doc.Check("GET", "/api/v1/data/6ba7b810-9dad-11d1-80b4-00c04fd430c8", 200, []byte(body))).To(BeEmpty())
+			Expect(doc.Check("GET", "/api/v1/data", 200, []byte(`{"data":[`+body+`]}`))).To(BeEmpty())
+		},
+		Entry("value", "value", `"some-value"`),
+		Entry("json", "json", `{"key":["value"]}`),
+		Entry("password", "password", `"some-password"`),
+		Entry("user", "user", `{"username":null,"password":"p","password_hash":"h"}`),
+		Entry("certificate", "certificate", `{"ca":"ca","ca_name":"/ca","certificate":"cert","private_key":null}`),
+		Entry("rsa", "rsa", `{"public_key":"pub","private_key":"priv"}`),
+		Entry("ssh", "ssh", `{"public_key":"pub","private_key":"priv","public_key_fingerprint":"fp"}`),
+	)
+
+	It("accepts the fields certificate versions carry", func() {
+		body := `{` + credentialFields + `,"type":"certificate","value":{"ca":"ca","certificate":"cert","private_key":"key"},` +
+			`"expiry_date":"2018-01-01T04:07:18Z","transitional":false,"certificate_authority":true,"self_signed":true,` +
+			`"generated":true,"duration_overridden":false,"duration_used":365}`
+		Expect(doc.Check("GET", "/api/v1/data/6ba7b810-9dad-11d1-80b4-00c04fd430c8", 200, []byte(body))).To(BeEmpty())
+		Expect(doc.Check("POST", "/api/v1/regenerate", 200, []byte(body))).To(BeEmpty())
+	})
+
+	It("reports extra and missing fields", func() {
+		body := `{` + credentialFields + `,"type":"rsa","value":{"public_key":"pub","private_key":"priv","passphrase":"p"}}`
+		Expect(doc.Check("PUT", "/api/v1/data", 200, []byte(body))).To(ConsistOf(`$.value.passphrase: unexpected property`))
+
+		body = `{"name":"/foo","type":"password","value":"p","version_created_at":"2017-01-01T04:07:18Z"}`
+		Expect(doc.Check("POST", "/api/v1/data", 200, []byte(body))).To(ConsistOf(`$: missing required property "id"`))
+	})
+
+	It("reports values of the wrong type", func() {
+		body := `{` + credentialFields + `,"type":"password","value":{"password":"p"}}`
+		Expect(doc.Check("POST", "/api/v1/data", 200, []byte(body))).To(ConsistOf(`$.value: expected string, got object`))
+
+		body = `{` + credentialFields + `,"type":"password","value":"p","duration_used":1.5}`
+		Expect(doc.Check("POST", "/api/v1/data", 200, []byte(body))).To(ConsistOf(`$.duration_used: expected integer, got number`))
+	})
+
+	It("reports unknown credential types", func() {
+		body := `{` + credentialFields + `,"type":"secret","value":"p"}`
+		Expect(doc.Check("POST", "/api/v1/data", 200, []byte(body))).To(ConsistOf(ContainSubstring(`$.type: "secret" is not one of`)))
+	})
+
+	It("distinguishes the shapes returned by the data endpoint", func() {
+		Expect(doc.Check("GET", "/api/v1/data", 200, []byte(`{"credentials":[{"name":"/foo","version_created_at":"2017-01-01T04:07:18Z"}]}`))).To(BeEmpty())
+		Expect(doc.Check("GET", "/api/v1/data", 200, []byte(`{"paths":[{"path":"/foo/"}]}`))).To(BeEmpty())
+		Expect(doc.Check("GET", "/api/v1/data", 200, []byte(`{"credentials":[{"name":"/foo"}]}`))).To(ConsistOf(`$.credentials[0]: missing required property "version_created_at"`))
+	})
+
+	It("validates permissions", func() {
+		permission := `{"actor":"mtls-app:1","operations":["read","write"],"path":"/foo","uuid":"6ba7b810-9dad-11d1-80b4-00c04fd430c8"}`
+		Expect(doc.Check("POST", "/api/v2/permissions", 201, []byte(permission))).To(BeEmpty())
+		Expect(doc.Check("DELETE", "/api/v2/permissions/6ba7b810-9dad-11d1-80b4-00c04fd430c8", 200, []byte(permission))).To(BeEmpty())
+		Expect(doc.Check("GET", "/api/v2/permissions", 200, []byte(`{"actor":"a","operations":["admin"],"path":"/foo","uuid":"u"}`))).To(ConsistOf(`$.operations[0]: "admin" is not one of ["read","write","delete","read_acl","write_acl"]`))
+	})
+
+	It("validates error bodies of every status class", func() {
+		Expect(doc.Check("GET", "/api/v1/data", 404, []byte(`{"error":"The request could not be completed."}`))).To(BeEmpty())
+		Expect(doc.Check("GET", "/api/v1/data", 401, []byte(`{"error":"invalid_token","error_description":"expired"}`))).To(BeEmpty())
+		Expect(doc.Check("GET", "/api/v1/data", 500, []byte(`{"timestamp":"2017-01-01T04:07:18.000+00:00","status":500,"error":"Internal Server Error","path":"/api/v1/data"}`))).To(BeEmpty())
+		Expect(doc.Check("POST", "/api/v2/permissions", 500, []byte(`{"detail":"oops"}`))).To(ConsistOf(
+			`$: missing required property "error"`,
+			`$.detail: unexpected property`,
+		))
+	})
+
+	It("ignores responses it does not describe", func() {
+		Expect(doc.Check("GET", "/api/v1/unknown", 200, []byte(`{}`))).To(BeEmpty())
+		Expect(doc.Check("DELETE", "/api/v1/data", 204, nil)).To(BeEmpty())
+	})
+
+	It("reports bodies that are not JSON", func() {
+		Expect(doc.Check("GET", "/info", 200, []byte(`<html>`))).To(ConsistOf(HavePrefix("$: invalid JSON")))
+	})
+
+	It("loads other documents", func() {
+		other, err := Load([]byte(`{
+			"paths": {"/things/{id}": {"get": {"responses": {"default": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Thing"}}}}}}}},
+			"components": {"schemas": {"Thing": {"type": ["string", "integer"]}}}
+		}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(other.Check("GET", "/things/1", 200, []byte(`3`))).To(BeEmpty())
+		Expect(other.Check("GET", "/things/1", 418, []byte(`true`))).To(ConsistOf(`$: expected string or integer, got boolean`))
+	})
+})
+
+var _ = Describe("Transport", func() {
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		Reset()
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/version":
+				w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+				w.Write([]byte(`{"version":2}`))
+			default:
+				w.Header().Set("Content-Type", "text/plain")
+				w.Write([]byte(`{"version":2}`))
+			}
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("records JSON responses that violate the document without consuming them", func() {
+		client := &http.Client{Transport: NewTransport(DefaultDocument(), nil)}
+
+		resp, err := client.Get(server.URL + "/version")
+		Expect(err).NotTo(HaveOccurred())
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(body)).To(Equal(`{"version":2}`))
+
+		_, err = client.Get(server.URL + "/health")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(Violations()).To(ConsistOf(Violation{
+			Method: "GET",
+			Path:   "/version",
+			Status: 200,
+			Errors: []string{"$.version: expected string, got integer"},
+		}))
+	})
+})
@@ -0,0 +1,30 @@
+package contract
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+)
+
+// Register adds hooks to a suite that fail any spec which received a
+// response not matching the API document. It must be called at the top level
+// of the suite, e.g. `var _ = contract.Register()`.
+func Register() bool {
+	BeforeEach(func() {
+		Reset()
+	})
+
+	AfterEach(func() {
+		found := Violations()
+		if len(found) == 0 {
+			return
+		}
+		messages := make([]string, len(found))
+		for i, violation := range found {
+			messages[i] = violation.String()
+		}
+		Fail("responses did not match the CredHub API document:\n" + strings.Join(messages, "\n"))
+	})
+
+	return true
+}
@@ -0,0 +1,258 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Schema is the subset of JSON Schema used by the CredHub API document:
+// type (optionally nullable), properties, required, additionalProperties,
+// items, enum, oneOf with an optional discriminator, and local $refs.
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 Types              `json:"type"`
+	Nullable             bool               `json:"nullable"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties *Additional        `json:"additionalProperties"`
+	Items                *Schema            `json:"items"`
+	Enum                 []interface{}      `json:"enum"`
+	OneOf                []*Schema          `json:"oneOf"`
+	Discriminator        *Discriminator     `json:"discriminator"`
+}
+
+// Discriminator names the property whose enum picks the alternative of a
+// oneOf that applies to an object.
+type Discriminator struct {
+	PropertyName string `json:"propertyName"`
+}
+
+// Types is the value of a schema's type keyword, which may be a single type
+// or a list of types.
+type Types []string
+
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = Types{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*t = list
+	return nil
+}
+
+// Additional is the value of additionalProperties, which is either a boolean
+// or a schema that every additional property must match.
+type Additional struct {
+	Allowed bool
+	Schema  *Schema
+}
+
+func (a *Additional) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &a.Allowed); err == nil {
+		return nil
+	}
+	a.Allowed = true
+	return json.Unmarshal(data, &a.Schema)
+}
+
+const refPrefix = "#/components/schemas/"
+
+// validate checks value against schema, returning one message per mismatch,
+// each prefixed with the location of the mismatch in value.
+func (d *Document) validate(schema *Schema, value interface{}, location string) []string {
+	if schema == nil {
+		return nil
+	}
+
+	if schema.Ref != "" {
+		resolved, ok := d.resolve(schema)
+		if !ok {
+			return []string{fmt.Sprintf("%s: unresolvable $ref %q", location, schema.Ref)}
+		}
+		return d.validate(resolved, value, location)
+	}
+
+	if value == nil && schema.Nullable {
+		return nil
+	}
+
+	if len(schema.Type) > 0 && !schema.Type.matches(value) {
+		return []string{fmt.Sprintf("%s: expected %s, got %s", location, strings.Join(schema.Type, " or "), typeOf(value))}
+	}
+
+	if len(schema.Enum) > 0 && !contains(schema.Enum, value) {
+		return []string{fmt.Sprintf("%s: %s is not one of %s", location, format(value), format(schema.Enum))}
+	}
+
+	if len(schema.OneOf) > 0 {
+		return d.validateOneOf(d.discriminate(schema, value), value, location)
+	}
+
+	var errs []string
+	switch v := value.(type) {
+	case map[string]interface{}:
+		errs = append(errs, d.validateObject(schema, v, location)...)
+	case []interface{}:
+		for i, item := range v {
+			errs = append(errs, d.validate(schema.Items, item, fmt.Sprintf("%s[%d]", location, i))...)
+		}
+	}
+	return errs
+}
+
+func (d *Document) validateObject(schema *Schema, object map[string]interface{}, location string) []string {
+	var errs []string
+	for _, name := range schema.Required {
+		if _, ok := object[name]; !ok {
+			errs = append(errs, fmt.Sprintf("%s: missing required property %q", location, name))
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		propertyLocation := location + "." + name
+		if property, ok := schema.Properties[name]; ok {
+			errs = append(errs, d.validate(property, object[name], propertyLocation)...)
+			continue
+		}
+		if schema.AdditionalProperties == nil {
+			continue
+		}
+		if !schema.AdditionalProperties.Allowed {
+			errs = append(errs, fmt.Sprintf("%s: unexpected property", propertyLocation))
+			continue
+		}
+		errs = append(errs, d.validate(schema.AdditionalProperties.Schema, object[name], propertyLocation)...)
+	}
+	return errs
+}
+
+// validateOneOf requires exactly one alternative to match. When none do, the
+// mismatches of the closest alternative are reported, since they are usually
+// the ones that explain the drift.
+func (d *Document) validateOneOf(alternatives []*Schema, value interface{}, location string) []string {
+	var closest []string
+	matches := 0
+	for i, alternative := range alternatives {
+		errs := d.validate(alternative, value, location)
+		if len(errs) == 0 {
+			matches++
+			continue
+		}
+		if i == 0 || len(errs) < len(closest) {
+			closest = errs
+		}
+	}
+
+	switch {
+	case matches == 1:
+		return nil
+	case matches > 1:
+		return []string{fmt.Sprintf("%s: matches %d alternatives of oneOf, expected exactly one", location, matches)}
+	default:
+		return closest
+	}
+}
+
+// discriminate narrows the alternatives of a oneOf to those whose
+// discriminator property accepts the value's, if the schema has a
+// discriminator and any alternative does.
+func (d *Document) discriminate(schema *Schema, value interface{}) []*Schema {
+	object, ok := value.(map[string]interface{})
+	if schema.Discriminator == nil || !ok {
+		return schema.OneOf
+	}
+
+	var narrowed []*Schema
+	for _, alternative := range schema.OneOf {
+		resolved, ok := d.resolve(alternative)
+		if !ok {
+			continue
+		}
+		property, ok := d.resolve(resolved.Properties[schema.Discriminator.PropertyName])
+		if ok && contains(property.Enum, object[schema.Discriminator.PropertyName]) {
+			narrowed = append(narrowed, alternative)
+		}
+	}
+	if len(narrowed) == 0 {
+		return schema.OneOf
+	}
+	return narrowed
+}
+
+// resolve follows schema's $ref, if it has one.
+func (d *Document) resolve(schema *Schema) (*Schema, bool) {
+	if schema == nil {
+		return nil, false
+	}
+	if schema.Ref == "" {
+		return schema, true
+	}
+	if !strings.HasPrefix(schema.Ref, refPrefix) {
+		return nil, false
+	}
+	resolved, ok := d.Components.Schemas[strings.TrimPrefix(schema.Ref, refPrefix)]
+	return resolved, ok
+}
+
+func (t Types) matches(value interface{}) bool {
+	for _, expected := range t {
+		actual := typeOf(value)
+		if actual == expected || (expected == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func typeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func contains(values []interface{}, value interface{}) bool {
+	for _, candidate := range values {
+		if format(candidate) == format(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func format(value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
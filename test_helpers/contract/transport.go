@@ -0,0 +1,87 @@
+package contract
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/reporting"
+)
+
+// Violation is a response that did not match the API document.
+type Violation struct {
+	Method string
+	Path   string
+	Status int
+	Errors []string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s %s (%d):\n  %s", v.Method, v.Path, v.Status, strings.Join(v.Errors, "\n  "))
+}
+
+var (
+	mutex      sync.Mutex
+	violations []Violation
+)
+
+// Reset forgets the violations recorded so far.
+func Reset() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	violations = nil
+}
+
+// Violations returns the violations recorded since the last Reset.
+func Violations() []Violation {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return append([]Violation(nil), violations...)
+}
+
+func record(violation Violation) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	violations = append(violations, violation)
+}
+
+type validatingTransport struct {
+	doc   *Document
+	inner http.RoundTripper
+}
+
+// NewTransport wraps inner so that every JSON response it receives is
+// checked against doc. A nil inner uses http.DefaultTransport.
+func NewTransport(doc *Document, inner http.RoundTripper) http.RoundTripper {
+	if inner == nil {
+		inner = http.DefaultTransport
+	}
+	return &validatingTransport{doc: doc, inner: inner}
+}
+
+func (t *validatingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.inner.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if !strings.HasSuffix(mediaType, "json") {
+		return resp, nil
+	}
+
+	body, err := reporting.ReadBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) == 0 {
+		return resp, nil
+	}
+
+	if errs := t.doc.Check(req.Method, req.URL.Path, resp.StatusCode, body); len(errs) > 0 {
+		record(Violation{Method: req.Method, Path: req.URL.Path, Status: resp.StatusCode, Errors: errs})
+	}
+	return resp, nil
+}
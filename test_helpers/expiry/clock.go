@@ -0,0 +1,60 @@
+// Package expiry builds certificate fixtures that expire at exact times,
+// relative to CredHub's clock, and predicts which of them a find with
+// expires-within-days returns.
+package expiry
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Clock tells the time as CredHub sees it: the local time shifted by how far
+// CredHub's clock is ahead.
+type Clock struct {
+	// Local is time.Now unless set.
+	Local func() time.Time
+	Skew  time.Duration
+}
+
+// Fixed returns a clock that always tells t.
+func Fixed(t time.Time) Clock {
+	return Clock{Local: func() time.Time { return t }}
+}
+
+// Now returns the time in UTC.
+func (c Clock) Now() time.Time {
+	local := c.Local
+	if local == nil {
+		local = time.Now
+	}
+	return local().Add(c.Skew).UTC()
+}
+
+// Requester is the part of the CredHub Go client Sync uses.
+type Requester interface {
+	Request(method string, pathStr string, query url.Values, body interface{}, checkServerErr bool) (*http.Response, error)
+}
+
+// Sync returns a clock that agrees with CredHub's to within about a second,
+// from the Date header of a request to /info.
+func Sync(client Requester) (Clock, error) {
+	before := time.Now()
+	response, err := client.Request(http.MethodGet, "/info", nil, nil, false)
+	if err != nil {
+		return Clock{}, err
+	}
+	response.Body.Close()
+	after := time.Now()
+
+	server, err := http.ParseTime(response.Header.Get("Date"))
+	if err != nil {
+		return Clock{}, fmt.Errorf("CredHub sent no usable Date header: %s", err)
+	}
+	// The header is truncated to the second, so the server's clock read
+	// somewhere in the second after it.
+	server = server.Add(500 * time.Millisecond)
+	local := before.Add(after.Sub(before) / 2)
+	return Clock{Skew: server.Sub(local)}, nil
+}
@@ -0,0 +1,149 @@
+package expiry_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+	_ "time/tzdata"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials/values"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/certs"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/expiry"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const day = 24 * time.Hour
+
+type dateRequester struct {
+	date string
+}
+
+func (d dateRequester) Request(method string, pathStr string, query url.Values, body interface{}, checkServerErr bool) (*http.Response, error) {
+	header := http.Header{}
+	header.Set("Date", d.date)
+	return &http.Response{StatusCode: http.StatusOK, Header: header, Body: ioutil.NopCloser(&bytes.Buffer{})}, nil
+}
+
+type recordingClient struct {
+	set []string
+}
+
+func (r *recordingClient) SetCertificate(name string, value values.Certificate, options ...credhub.SetOption) (credentials.Certificate, error) {
+	r.set = append(r.set, name+" "+value.Certificate[:27])
+	return credentials.Certificate{}, nil
+}
+
+var _ = Describe("Clock", func() {
+	It("tells a fixed time in UTC", func() {
+		t := time.Date(2030, 1, 1, 9, 0, 0, 0, time.FixedZone("UTC+9", 9*60*60))
+		Expect(expiry.Fixed(t).Now()).To(Equal(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)))
+	})
+
+	It("syncs with the Date CredHub sends", func() {
+		server := time.Now().Add(3 * time.Hour).UTC()
+		clock, err := expiry.Sync(dateRequester{date: server.Format(http.TimeFormat)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(clock.Now()).To(BeTemporally("~", server, 2*time.Second))
+	})
+
+	It("fails without a Date header", func() {
+		_, err := expiry.Sync(dateRequester{})
+		Expect(err).To(MatchError(ContainSubstring("no usable Date header")))
+	})
+})
+
+var _ = Describe("Fixtures", func() {
+	now := time.Date(2030, 3, 10, 22, 30, 15, 500, time.UTC)
+
+	It("generates every version of every certificate in a bucket to expire exactly when asked", func() {
+		fixtures, err := expiry.Generate("/expiry", now, []expiry.Bucket{
+			{Name: "soon", ExpiresIn: day, Older: []time.Duration{-day}, Count: 2},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fixtures).To(HaveLen(2))
+		Expect(fixtures[0].Name).To(Equal("/expiry/soon-0"))
+		Expect(fixtures[1].Name).To(Equal("/expiry/soon-1"))
+
+		versions := fixtures[0].Versions
+		Expect(versions).To(HaveLen(2))
+		Expect(versions[0].NotAfter).To(Equal(time.Date(2030, 3, 9, 22, 30, 15, 0, time.UTC)))
+		Expect(fixtures[0].Expiry()).To(Equal(time.Date(2030, 3, 11, 22, 30, 15, 0, time.UTC)))
+		for _, version := range versions {
+			_, notAfter, err := certs.Validity([]byte(version.Certificate))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(notAfter).To(Equal(version.NotAfter))
+		}
+	})
+
+	It("sets versions oldest first", func() {
+		fixtures, err := expiry.Generate("/expiry", now, []expiry.Bucket{{Name: "a", ExpiresIn: day, Older: []time.Duration{day}}, {Name: "b", ExpiresIn: day}})
+		Expect(err).NotTo(HaveOccurred())
+
+		client := &recordingClient{}
+		Expect(expiry.Set(client, fixtures)).To(Succeed())
+		Expect(client.set).To(Equal([]string{
+			"/expiry/a-0 " + fixtures[0].Versions[0].Certificate[:27],
+			"/expiry/a-0 " + fixtures[0].Versions[1].Certificate[:27],
+			"/expiry/b-0 " + fixtures[1].Versions[0].Certificate[:27],
+		}))
+	})
+
+	It("expects the fixtures whose latest version expires within the window, and is unsure near its end", func() {
+		fixtures := []expiry.Fixture{
+			{Name: "/expired", Versions: []expiry.Version{{NotAfter: now.Add(-day)}}},
+			{Name: "/inside", Versions: []expiry.Version{{NotAfter: now.Add(7*day - 2*expiry.Tolerance)}}},
+			{Name: "/edge", Versions: []expiry.Version{{NotAfter: now.Add(7*day + expiry.Tolerance/2)}}},
+			{Name: "/outside", Versions: []expiry.Version{{NotAfter: now.Add(7*day + 2*expiry.Tolerance)}}},
+			{Name: "/older-expiring", Versions: []expiry.Version{{NotAfter: now}, {NotAfter: now.Add(30 * day)}}},
+			{Name: "/latest-expiring", Versions: []expiry.Version{{NotAfter: now.Add(30 * day)}, {NotAfter: now}}},
+		}
+
+		expected, uncertain := expiry.Expect(fixtures, 7, now)
+		Expect(expected).To(Equal([]string{"/expired", "/inside", "/latest-expiring"}))
+		Expect(uncertain).To(Equal([]string{"/edge"}))
+	})
+
+	It("puts a certificate inside and outside every window, and on the day boundaries", func() {
+		buckets := expiry.DefaultBuckets(now)
+		fixtures := make([]expiry.Fixture, 0, len(buckets))
+		for _, bucket := range buckets {
+			fixtures = append(fixtures, expiry.Fixture{Name: bucket.Name, Versions: []expiry.Version{{NotAfter: now.Add(bucket.ExpiresIn)}}})
+		}
+		for _, days := range expiry.Windows {
+			expected, _ := expiry.Expect(fixtures, days, now)
+			Expect(expected).To(ContainElements("expired", "inside-"+strconv.Itoa(days)))
+			Expect(expected).NotTo(ContainElements("outside-" + strconv.Itoa(days)))
+		}
+
+		byName := map[string]expiry.Bucket{}
+		for _, bucket := range buckets {
+			byName[bucket.Name] = bucket
+		}
+		Expect(now.Add(byName["start-of-day-7"].ExpiresIn)).To(Equal(time.Date(2030, 3, 17, 0, 0, 0, 0, time.UTC)))
+		Expect(now.Add(byName["end-of-day-7"].ExpiresIn)).To(Equal(time.Date(2030, 3, 17, 23, 59, 59, 0, time.UTC)))
+	})
+
+	It("puts certificates on the day boundaries of zones away from UTC, which are not UTC's", func() {
+		kiritimati, err := time.LoadLocation("Pacific/Kiritimati")
+		Expect(err).NotTo(HaveOccurred())
+		gmtMinus12, err := time.LoadLocation("Etc/GMT+12")
+		Expect(err).NotTo(HaveOccurred())
+
+		byName := map[string]expiry.Bucket{}
+		for _, bucket := range expiry.DefaultBuckets(now, time.UTC, kiritimati, gmtMinus12) {
+			byName[bucket.Name] = bucket
+		}
+		Expect(byName).NotTo(HaveKey("start-of-day-7-utc-plus-0000"))
+		Expect(now.Add(byName["start-of-day-7-utc-plus-1400"].ExpiresIn)).To(Equal(time.Date(2030, 3, 17, 10, 0, 0, 0, time.UTC)))
+		Expect(now.Add(byName["end-of-day-7-utc-plus-1400"].ExpiresIn)).To(Equal(time.Date(2030, 3, 18, 9, 59, 59, 0, time.UTC)))
+		Expect(now.Add(byName["start-of-day-7-utc-minus-1200"].ExpiresIn)).To(Equal(time.Date(2030, 3, 17, 12, 0, 0, 0, time.UTC)))
+		Expect(now.Add(byName["end-of-day-7-utc-minus-1200"].ExpiresIn)).To(Equal(time.Date(2030, 3, 18, 11, 59, 59, 0, time.UTC)))
+		Expect(byName).To(HaveLen(len(expiry.DefaultBuckets(now)) + 2*2*len(expiry.Windows)))
+	})
+})
@@ -0,0 +1,187 @@
+package expiry
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"time"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials/values"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/certs"
+)
+
+const day = 24 * time.Hour
+
+// Tolerance is how close to the end of a window an expiry may be for Expect
+// to call it uncertain: a synced clock agrees with CredHub's only to about a
+// second, and time passes while a find runs.
+const Tolerance = time.Minute
+
+// Bucket is a group of certificates whose latest versions expire at the
+// same time.
+type Bucket struct {
+	Name string
+	// ExpiresIn is when the latest version expires, from now. Expiries are
+	// kept to the second.
+	ExpiresIn time.Duration
+	// Older are when earlier versions expire, from now, oldest first.
+	Older []time.Duration
+	// Count is how many certificates are in the bucket; 1 unless set.
+	Count int
+}
+
+// Version is one version of a fixture.
+type Version struct {
+	Certificate string
+	PrivateKey  string
+	NotAfter    time.Time
+}
+
+// Fixture is a certificate to set into CredHub.
+type Fixture struct {
+	Name   string
+	Bucket string
+	// Versions are oldest first.
+	Versions []Version
+}
+
+// Expiry is when the latest version expires.
+func (f Fixture) Expiry() time.Time {
+	return f.Versions[len(f.Versions)-1].NotAfter
+}
+
+// Generate generates the fixtures for buckets, named root/<bucket>-<n>,
+// with expiries relative to now.
+func Generate(root string, now time.Time, buckets []Bucket) ([]Fixture, error) {
+	var fixtures []Fixture
+	for _, bucket := range buckets {
+		count := bucket.Count
+		if count == 0 {
+			count = 1
+		}
+		expiries := append(append([]time.Duration(nil), bucket.Older...), bucket.ExpiresIn)
+
+		for n := 0; n < count; n++ {
+			fixture := Fixture{Name: path.Join(root, fmt.Sprintf("%s-%d", bucket.Name, n)), Bucket: bucket.Name}
+			for _, expiresIn := range expiries {
+				notAfter := now.Add(expiresIn).Truncate(time.Second).UTC()
+				notBefore := now.Add(-day)
+				if notAfter.Before(now) {
+					notBefore = notAfter.Add(-day)
+				}
+				certificate, key, err := certs.GenerateSelfSigned(certs.CertOptions{
+					CommonName: path.Base(fixture.Name),
+					NotBefore:  notBefore,
+					NotAfter:   notAfter,
+					KeySize:    2048,
+				})
+				if err != nil {
+					return nil, err
+				}
+				fixture.Versions = append(fixture.Versions, Version{
+					Certificate: string(certificate),
+					PrivateKey:  string(key),
+					NotAfter:    notAfter,
+				})
+			}
+			fixtures = append(fixtures, fixture)
+		}
+	}
+	return fixtures, nil
+}
+
+// Client is the part of the CredHub client Set uses.
+type Client interface {
+	SetCertificate(name string, value values.Certificate, options ...credhub.SetOption) (credentials.Certificate, error)
+}
+
+// Set sets every version of every fixture, oldest first.
+func Set(client Client, fixtures []Fixture) error {
+	for _, fixture := range fixtures {
+		for _, version := range fixture.Versions {
+			value := values.Certificate{Certificate: version.Certificate, PrivateKey: version.PrivateKey}
+			if _, err := client.SetCertificate(fixture.Name, value); err != nil {
+				return fmt.Errorf("setting %s: %s", fixture.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Expect returns the names of the fixtures a find with
+// expires-within-days=days at now returns: those whose latest version has
+// expired, or expires within days whole days of now. Fixtures that expire
+// within Tolerance of the end of the window are returned as uncertain
+// instead. Both are sorted.
+func Expect(fixtures []Fixture, days int, now time.Time) ([]string, []string) {
+	end := now.Add(time.Duration(days) * day)
+	var expected, uncertain []string
+	for _, fixture := range fixtures {
+		distance := fixture.Expiry().Sub(end)
+		switch {
+		case distance > -Tolerance && distance < Tolerance:
+			uncertain = append(uncertain, fixture.Name)
+		case distance < 0:
+			expected = append(expected, fixture.Name)
+		}
+	}
+	sort.Strings(expected)
+	sort.Strings(uncertain)
+	return expected, uncertain
+}
+
+// Windows are the expires-within-days values DefaultBuckets is built around.
+var Windows = []int{1, 7, 30, 365}
+
+// DefaultBuckets are certificates that have expired, that expire just inside
+// and just outside each of Windows, at the start and end of the UTC day a
+// window ends in, and whose older versions alone expire, or alone do not.
+// For each of zones that is not at UTC's offset, they also include
+// certificates at the start and end of the day the window ends in there,
+// which are not UTC day boundaries, so that a find counting days in a local
+// zone would get them wrong.
+func DefaultBuckets(now time.Time, zones ...*time.Location) []Bucket {
+	margin := 5 * Tolerance
+	buckets := []Bucket{
+		{Name: "expired", ExpiresIn: -day},
+		{Name: "expired-just-now", ExpiresIn: -margin},
+		{Name: "only-older-expiring", ExpiresIn: 2 * 365 * day, Older: []time.Duration{-day, 2 * day}},
+		{Name: "only-latest-expiring", ExpiresIn: 3 * day, Older: []time.Duration{2 * 365 * day}},
+	}
+	for _, days := range Windows {
+		window := time.Duration(days) * day
+		startOfDay := now.Add(window).UTC().Truncate(day)
+		buckets = append(buckets,
+			Bucket{Name: fmt.Sprintf("inside-%d", days), ExpiresIn: window - margin, Count: 2},
+			Bucket{Name: fmt.Sprintf("outside-%d", days), ExpiresIn: window + margin, Count: 2},
+			Bucket{Name: fmt.Sprintf("start-of-day-%d", days), ExpiresIn: startOfDay.Sub(now)},
+			Bucket{Name: fmt.Sprintf("end-of-day-%d", days), ExpiresIn: startOfDay.Add(day - time.Second).Sub(now)},
+		)
+		for _, zone := range zones {
+			end := now.Add(window).In(zone)
+			_, offset := end.Zone()
+			if offset%int(day/time.Second) == 0 {
+				continue
+			}
+			startOfLocalDay := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, zone)
+			endOfLocalDay := startOfLocalDay.AddDate(0, 0, 1).Add(-time.Second)
+			buckets = append(buckets,
+				Bucket{Name: fmt.Sprintf("start-of-day-%d-%s", days, zoneLabel(offset)), ExpiresIn: startOfLocalDay.Sub(now)},
+				Bucket{Name: fmt.Sprintf("end-of-day-%d-%s", days, zoneLabel(offset)), ExpiresIn: endOfLocalDay.Sub(now)},
+			)
+		}
+	}
+	return buckets
+}
+
+// zoneLabel names a UTC offset in seconds for credential names, e.g.
+// utc-plus-1400.
+func zoneLabel(offset int) string {
+	sign := "plus"
+	if offset < 0 {
+		sign, offset = "minus", -offset
+	}
+	return fmt.Sprintf("utc-%s-%02d%02d", sign, offset/3600, offset%3600/60)
+}
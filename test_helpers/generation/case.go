@@ -0,0 +1,159 @@
+// Package generation samples generation parameters for passwords, users,
+// SSH keys and RSA keys, checks generated values against what the parameters
+// promise, and shrinks a failing sample to a minimal one.
+package generation
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials/generate"
+)
+
+// Types are the credential types Random samples parameters for.
+var Types = []string{"password", "user", "ssh", "rsa"}
+
+// KeyLengths are the key lengths CredHub accepts for SSH and RSA keys.
+var KeyLengths = []int{2048, 3072, 4096}
+
+const (
+	// DefaultLength is the length of a password generated without one.
+	DefaultLength = 30
+	// DefaultKeyLength is the length of a key generated without one.
+	DefaultKeyLength = 2048
+
+	minLength = 4
+	maxLength = 200
+
+	usernameChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_."
+	commentChars  = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_.@"
+)
+
+// Case is one set of generation parameters. Zero values are left out of the
+// request, so CredHub uses its defaults.
+type Case struct {
+	Type string
+
+	// Password parameters, for passwords and users.
+	Length         int
+	ExcludeUpper   bool
+	ExcludeLower   bool
+	ExcludeNumber  bool
+	IncludeSpecial bool
+
+	// Username is the username of a user; CredHub generates one if empty.
+	Username string
+
+	// Key parameters, for SSH and RSA keys.
+	KeyLength  int
+	SSHComment string
+}
+
+// Parameters returns the case as the Go client's generation parameters.
+func (c Case) Parameters() interface{} {
+	switch c.Type {
+	case "password":
+		return generate.Password{
+			Length:         c.Length,
+			IncludeSpecial: c.IncludeSpecial,
+			ExcludeNumber:  c.ExcludeNumber,
+			ExcludeUpper:   c.ExcludeUpper,
+			ExcludeLower:   c.ExcludeLower,
+		}
+	case "user":
+		return generate.User{
+			Username:       c.Username,
+			Length:         c.Length,
+			IncludeSpecial: c.IncludeSpecial,
+			ExcludeNumber:  c.ExcludeNumber,
+			ExcludeUpper:   c.ExcludeUpper,
+			ExcludeLower:   c.ExcludeLower,
+		}
+	case "ssh":
+		return generate.SSH{KeyLength: c.keyLength(), Comment: c.SSHComment}
+	case "rsa":
+		return generate.RSA{KeyLength: c.keyLength()}
+	}
+	return nil
+}
+
+// Valid is whether CredHub should accept the case: a password must be
+// allowed at least one kind of character.
+func (c Case) Valid() bool {
+	if c.Type != "password" && c.Type != "user" {
+		return true
+	}
+	if c.Length != 0 && (c.Length < minLength || c.Length > maxLength) {
+		return false
+	}
+	return !c.ExcludeUpper || !c.ExcludeLower || !c.ExcludeNumber || c.IncludeSpecial
+}
+
+// String lists the parameters that are set, to reproduce a failure with.
+func (c Case) String() string {
+	parameters := []string{c.Type}
+	add := func(set bool, format string, args ...interface{}) {
+		if set {
+			parameters = append(parameters, fmt.Sprintf(format, args...))
+		}
+	}
+	add(c.Length != 0, "length=%d", c.Length)
+	add(c.ExcludeUpper, "exclude_upper")
+	add(c.ExcludeLower, "exclude_lower")
+	add(c.ExcludeNumber, "exclude_number")
+	add(c.IncludeSpecial, "include_special")
+	add(c.Username != "", "username=%q", c.Username)
+	add(c.KeyLength != 0, "key_length=%d", c.KeyLength)
+	add(c.SSHComment != "", "ssh_comment=%q", c.SSHComment)
+	return strings.Join(parameters, " ")
+}
+
+// keyLength is the key length to send. The Go client always sends it, so
+// the default is filled in.
+func (c Case) keyLength() int {
+	if c.KeyLength == 0 {
+		return DefaultKeyLength
+	}
+	return c.KeyLength
+}
+
+// Random samples a valid case for a credential type. Each parameter is left
+// unset about half the time.
+func Random(r *rand.Rand, credType string) Case {
+	c := Case{Type: credType}
+	switch credType {
+	case "password", "user":
+		for {
+			if r.Intn(2) == 0 {
+				c.Length = minLength + r.Intn(maxLength-minLength+1)
+			}
+			c.ExcludeUpper = r.Intn(3) == 0
+			c.ExcludeLower = r.Intn(3) == 0
+			c.ExcludeNumber = r.Intn(3) == 0
+			c.IncludeSpecial = r.Intn(2) == 0
+			if c.Valid() {
+				break
+			}
+		}
+		if credType == "user" && r.Intn(2) == 0 {
+			c.Username = randomString(r, usernameChars, 1+r.Intn(64))
+		}
+	case "ssh", "rsa":
+		if r.Intn(2) == 0 {
+			c.KeyLength = KeyLengths[r.Intn(len(KeyLengths))]
+		}
+		if credType == "ssh" && r.Intn(2) == 0 {
+			c.SSHComment = randomString(r, commentChars, 1+r.Intn(64))
+		}
+	}
+	return c
+}
+
+func randomString(r *rand.Rand, chars string, length int) string {
+	s := make([]byte, length)
+	for i := range s {
+		s[i] = chars[r.Intn(len(chars))]
+	}
+	return string(s)
+}
@@ -0,0 +1,118 @@
+package generation
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Failure is a case a property failed for, shrunk as far as it would go.
+type Failure struct {
+	Case Case
+	Err  error
+	// Original is the sampled case before shrinking.
+	Original Case
+	// Shrinks is how many times a smaller failing case was found.
+	Shrinks int
+}
+
+func (f Failure) Error() string {
+	return fmt.Sprintf("%s: %s (shrunk %d times from %s)", f.Case, f.Err, f.Shrinks, f.Original)
+}
+
+// MaxShrinks bounds how many smaller cases Check tries in all, as each try
+// generates a credential.
+const MaxShrinks = 100
+
+// Check runs property for runs cases of a credential type sampled from r,
+// and returns the first failure, shrunk, or nil if every case passed.
+func Check(r *rand.Rand, credType string, runs int, property func(Case) error) *Failure {
+	for i := 0; i < runs; i++ {
+		c := Random(r, credType)
+		if err := property(c); err != nil {
+			failure := shrink(Failure{Case: c, Err: err, Original: c}, property)
+			return &failure
+		}
+	}
+	return nil
+}
+
+// shrink replaces the failing case with the first smaller case that also
+// fails, until none of the smaller cases fail.
+func shrink(failure Failure, property func(Case) error) Failure {
+	tries := 0
+	for {
+		shrunk := false
+		for _, smaller := range Shrink(failure.Case) {
+			if tries == MaxShrinks {
+				return failure
+			}
+			tries++
+			if err := property(smaller); err != nil {
+				failure.Case, failure.Err = smaller, err
+				failure.Shrinks++
+				shrunk = true
+				break
+			}
+		}
+		if !shrunk {
+			return failure
+		}
+	}
+}
+
+// Shrink returns valid cases a step smaller than c, simplest first: with a
+// parameter left unset, then with a parameter closer to its smallest value.
+func Shrink(c Case) []Case {
+	var smaller []Case
+	add := func(change func(*Case)) {
+		candidate := c
+		change(&candidate)
+		if candidate != c && candidate.Valid() {
+			smaller = append(smaller, candidate)
+		}
+	}
+
+	add(func(c *Case) { c.Length = 0 })
+	add(func(c *Case) { c.ExcludeUpper = false })
+	add(func(c *Case) { c.ExcludeLower = false })
+	add(func(c *Case) { c.ExcludeNumber = false })
+	add(func(c *Case) { c.IncludeSpecial = false })
+	add(func(c *Case) { c.Username = "" })
+	add(func(c *Case) { c.KeyLength = 0 })
+	add(func(c *Case) { c.SSHComment = "" })
+
+	for _, length := range towards(c.Length, minLength) {
+		length := length
+		add(func(c *Case) { c.Length = length })
+	}
+	for _, length := range KeyLengths {
+		if length < c.KeyLength {
+			length := length
+			add(func(c *Case) { c.KeyLength = length })
+		}
+	}
+	for _, length := range towards(len(c.Username), 1) {
+		length := length
+		add(func(c *Case) { c.Username = c.Username[:length] })
+	}
+	for _, length := range towards(len(c.SSHComment), 1) {
+		length := length
+		add(func(c *Case) { c.SSHComment = c.SSHComment[:length] })
+	}
+	return smaller
+}
+
+// towards returns values between min and n, excluding n: min itself, then
+// ever closer to n, so that shrinking takes about log n steps.
+func towards(n, min int) []int {
+	if n <= min {
+		return nil
+	}
+	values := []int{min}
+	for distance := (n - min) / 2; distance > 0; distance /= 2 {
+		if value := n - distance; value != values[len(values)-1] {
+			values = append(values, value)
+		}
+	}
+	return values
+}
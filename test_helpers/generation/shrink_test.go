@@ -0,0 +1,139 @@
+package generation_test
+
+import (
+	"errors"
+	"math/rand"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/generation"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Random", func() {
+	It("samples only valid cases", func() {
+		r := rand.New(rand.NewSource(1))
+		for _, credType := range generation.Types {
+			for i := 0; i < 500; i++ {
+				c := generation.Random(r, credType)
+				Expect(c.Type).To(Equal(credType))
+				Expect(c.Valid()).To(BeTrue(), c.String())
+			}
+		}
+	})
+
+	It("samples the same cases from the same seed", func() {
+		first := generation.Random(rand.New(rand.NewSource(7)), "user")
+		second := generation.Random(rand.New(rand.NewSource(7)), "user")
+		Expect(first).To(Equal(second))
+	})
+
+	It("samples every parameter of a type", func() {
+		r := rand.New(rand.NewSource(1))
+		var sawLength, sawUsername, sawSpecial, sawExclusion bool
+		for i := 0; i < 200; i++ {
+			c := generation.Random(r, "user")
+			sawLength = sawLength || c.Length != 0
+			sawUsername = sawUsername || c.Username != ""
+			sawSpecial = sawSpecial || c.IncludeSpecial
+			sawExclusion = sawExclusion || c.ExcludeUpper || c.ExcludeLower || c.ExcludeNumber
+		}
+		Expect([]bool{sawLength, sawUsername, sawSpecial, sawExclusion}).To(HaveEach(BeTrue()))
+
+		var sawKeyLength, sawComment bool
+		for i := 0; i < 200; i++ {
+			c := generation.Random(r, "ssh")
+			sawKeyLength = sawKeyLength || c.KeyLength != 0
+			sawComment = sawComment || c.SSHComment != ""
+		}
+		Expect([]bool{sawKeyLength, sawComment}).To(HaveEach(BeTrue()))
+	})
+})
+
+var _ = Describe("Valid", func() {
+	It("refuses passwords that exclude every kind of character", func() {
+		c := generation.Case{Type: "password", ExcludeUpper: true, ExcludeLower: true, ExcludeNumber: true}
+		Expect(c.Valid()).To(BeFalse())
+		c.IncludeSpecial = true
+		Expect(c.Valid()).To(BeTrue())
+	})
+
+	It("refuses lengths CredHub does not generate", func() {
+		Expect(generation.Case{Type: "user", Length: 3}.Valid()).To(BeFalse())
+		Expect(generation.Case{Type: "user", Length: 201}.Valid()).To(BeFalse())
+		Expect(generation.Case{Type: "user", Length: 4}.Valid()).To(BeTrue())
+	})
+})
+
+var _ = Describe("Shrink", func() {
+	It("returns valid cases, each different from the case shrunk", func() {
+		r := rand.New(rand.NewSource(3))
+		for _, credType := range generation.Types {
+			for i := 0; i < 100; i++ {
+				c := generation.Random(r, credType)
+				for _, smaller := range generation.Shrink(c) {
+					Expect(smaller.Valid()).To(BeTrue())
+					Expect(smaller).NotTo(Equal(c))
+				}
+			}
+		}
+	})
+
+	It("has nothing smaller than a case with no parameters", func() {
+		Expect(generation.Shrink(generation.Case{Type: "ssh"})).To(BeEmpty())
+	})
+
+	It("keeps a password valid when clearing include_special", func() {
+		c := generation.Case{Type: "password", ExcludeUpper: true, ExcludeLower: true, ExcludeNumber: true, IncludeSpecial: true}
+		for _, smaller := range generation.Shrink(c) {
+			Expect(smaller.IncludeSpecial).To(BeTrue())
+		}
+	})
+})
+
+var _ = Describe("Check", func() {
+	It("returns nil when every case passes", func() {
+		runs := 0
+		failure := generation.Check(rand.New(rand.NewSource(1)), "password", 20, func(generation.Case) error {
+			runs++
+			return nil
+		})
+		Expect(failure).To(BeNil())
+		Expect(runs).To(Equal(20))
+	})
+
+	It("shrinks a failure to the smallest failing case", func() {
+		failure := generation.Check(rand.New(rand.NewSource(1)), "user", 100, func(c generation.Case) error {
+			if c.Length > 50 && len(c.Username) >= 3 {
+				return errors.New("too long")
+			}
+			return nil
+		})
+		Expect(failure).NotTo(BeNil())
+		Expect(failure.Case).To(Equal(generation.Case{Type: "user", Length: 51, Username: failure.Original.Username[:3]}))
+		Expect(failure.Err).To(MatchError("too long"))
+		Expect(failure.Shrinks).To(BeNumerically(">", 0))
+		Expect(failure.Error()).To(ContainSubstring("user length=51 username="))
+	})
+
+	It("shrinks key lengths to the smallest that fails", func() {
+		failure := generation.Check(rand.New(rand.NewSource(1)), "rsa", 100, func(c generation.Case) error {
+			if c.KeyLength >= 3072 {
+				return errors.New("slow")
+			}
+			return nil
+		})
+		Expect(failure).NotTo(BeNil())
+		Expect(failure.Case).To(Equal(generation.Case{Type: "rsa", KeyLength: 3072}))
+	})
+
+	It("stops shrinking after MaxShrinks tries", func() {
+		tries := 0
+		failure := generation.Check(rand.New(rand.NewSource(1)), "password", 1, func(generation.Case) error {
+			tries++
+			return errors.New("always")
+		})
+		Expect(failure).NotTo(BeNil())
+		Expect(tries).To(BeNumerically("<=", 1+generation.MaxShrinks))
+		Expect(failure.Case).To(Equal(generation.Case{Type: "password"}))
+	})
+})
@@ -0,0 +1,243 @@
+package generation
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+const (
+	upper   = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	lower   = "abcdefghijklmnopqrstuvwxyz"
+	numbers = "0123456789"
+	special = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+)
+
+// generatedUsername is what CredHub generates when no username is given.
+var generatedUsername = regexp.MustCompile(`^[A-Za-z]{20}$`)
+
+// fingerprint is an unpadded base64 SHA-256 digest.
+var fingerprint = regexp.MustCompile(`^[A-Za-z0-9+/]{43}$`)
+
+// Verify checks a generated value against what the case's parameters
+// promise. value is a credential's value as the Go client decodes it.
+func Verify(c Case, value interface{}) error {
+	switch c.Type {
+	case "password":
+		password, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("password value is a %T", value)
+		}
+		return verifyPassword(c, password)
+	case "user":
+		fields, err := stringFields(value, "username", "password")
+		if err != nil {
+			return err
+		}
+		if c.Username != "" && fields["username"] != c.Username {
+			return fmt.Errorf("username is %q, not %q", fields["username"], c.Username)
+		}
+		if c.Username == "" && !generatedUsername.MatchString(fields["username"]) {
+			return fmt.Errorf("generated username %q is not 20 letters", fields["username"])
+		}
+		return verifyPassword(c, fields["password"])
+	case "rsa":
+		fields, err := stringFields(value, "public_key", "private_key")
+		if err != nil {
+			return err
+		}
+		private, err := verifyPrivateKey(c, fields["private_key"])
+		if err != nil {
+			return err
+		}
+		block, _ := pem.Decode([]byte(fields["public_key"]))
+		if block == nil || block.Type != "PUBLIC KEY" {
+			return errors.New("public key is not a PEM public key")
+		}
+		public, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("public key does not parse: %s", err)
+		}
+		if !private.PublicKey.Equal(public) {
+			return errors.New("public key does not match the private key")
+		}
+		return nil
+	case "ssh":
+		fields, err := stringFields(value, "public_key", "private_key", "public_key_fingerprint")
+		if err != nil {
+			return err
+		}
+		private, err := verifyPrivateKey(c, fields["private_key"])
+		if err != nil {
+			return err
+		}
+		return verifySSHPublicKey(c, private, fields["public_key"], fields["public_key_fingerprint"])
+	}
+	return fmt.Errorf("no contract for %s credentials", c.Type)
+}
+
+func verifyPassword(c Case, password string) error {
+	length := c.Length
+	if length == 0 {
+		length = DefaultLength
+	}
+	if len(password) != length {
+		return fmt.Errorf("password %q is %d characters, not %d", password, len(password), length)
+	}
+
+	classes := []struct {
+		name     string
+		chars    string
+		included bool
+	}{
+		{"upper case letters", upper, !c.ExcludeUpper},
+		{"lower case letters", lower, !c.ExcludeLower},
+		{"numbers", numbers, !c.ExcludeNumber},
+		{"special characters", special, c.IncludeSpecial},
+	}
+	allowed := ""
+	for _, class := range classes {
+		if class.included {
+			allowed += class.chars
+			if !strings.ContainsAny(password, class.chars) {
+				return fmt.Errorf("password %q has no %s", password, class.name)
+			}
+		}
+	}
+	for _, r := range password {
+		if !strings.ContainsRune(allowed, r) {
+			return fmt.Errorf("password %q has %q, which the parameters exclude", password, r)
+		}
+	}
+	return nil
+}
+
+func verifyPrivateKey(c Case, encoded string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(encoded))
+	if block == nil {
+		return nil, errors.New("private key is not PEM")
+	}
+	var key *rsa.PrivateKey
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("private key does not parse: %s", err)
+		}
+		key = parsed
+	case "PRIVATE KEY":
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("private key does not parse: %s", err)
+		}
+		rsaKey, ok := parsed.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("private key is a %T, not RSA", parsed)
+		}
+		key = rsaKey
+	default:
+		return nil, fmt.Errorf("private key is a %s block", block.Type)
+	}
+
+	if bits := key.N.BitLen(); bits != c.keyLength() {
+		return nil, fmt.Errorf("key is %d bits, not %d", bits, c.keyLength())
+	}
+	return key, nil
+}
+
+// verifySSHPublicKey checks an OpenSSH authorized key, "ssh-rsa <base64
+// wire format> [comment]", and its fingerprint.
+func verifySSHPublicKey(c Case, private *rsa.PrivateKey, publicKey, publicKeyFingerprint string) error {
+	parts := strings.SplitN(publicKey, " ", 3)
+	if len(parts) < 2 || parts[0] != "ssh-rsa" {
+		return fmt.Errorf("public key %q is not an ssh-rsa key", publicKey)
+	}
+	comment := ""
+	if len(parts) == 3 {
+		comment = parts[2]
+	}
+	if comment != c.SSHComment {
+		return fmt.Errorf("public key comment is %q, not %q", comment, c.SSHComment)
+	}
+
+	wire, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return fmt.Errorf("public key is not base64: %s", err)
+	}
+	public, err := parseSSHRSA(wire)
+	if err != nil {
+		return err
+	}
+	if !private.PublicKey.Equal(public) {
+		return errors.New("public key does not match the private key")
+	}
+
+	if !fingerprint.MatchString(publicKeyFingerprint) {
+		return fmt.Errorf("fingerprint %q is not an unpadded base64 SHA-256 digest", publicKeyFingerprint)
+	}
+	digest := sha256.Sum256(wire)
+	if expected := base64.RawStdEncoding.EncodeToString(digest[:]); publicKeyFingerprint != expected {
+		return fmt.Errorf("fingerprint is %s, not %s", publicKeyFingerprint, expected)
+	}
+	return nil
+}
+
+// parseSSHRSA parses the wire format of an ssh-rsa public key: the strings
+// "ssh-rsa", e and n, each prefixed with its length.
+func parseSSHRSA(wire []byte) (*rsa.PublicKey, error) {
+	reader := bytes.NewReader(wire)
+	next := func() ([]byte, error) {
+		var length uint32
+		if err := binary.Read(reader, binary.BigEndian, &length); err != nil {
+			return nil, err
+		}
+		if int(length) > reader.Len() {
+			return nil, errors.New("truncated")
+		}
+		field := make([]byte, length)
+		_, err := reader.Read(field)
+		return field, err
+	}
+
+	var fields [3][]byte
+	for i := range fields {
+		field, err := next()
+		if err != nil {
+			return nil, fmt.Errorf("public key wire format: %s", err)
+		}
+		fields[i] = field
+	}
+	if string(fields[0]) != "ssh-rsa" || reader.Len() != 0 {
+		return nil, errors.New("public key wire format is not ssh-rsa")
+	}
+	e := new(big.Int).SetBytes(fields[1])
+	if !e.IsInt64() {
+		return nil, errors.New("public key exponent is too large")
+	}
+	return &rsa.PublicKey{E: int(e.Int64()), N: new(big.Int).SetBytes(fields[2])}, nil
+}
+
+func stringFields(value interface{}, keys ...string) (map[string]string, error) {
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("value is a %T, not an object", value)
+	}
+	values := map[string]string{}
+	for _, key := range keys {
+		s, ok := fields[key].(string)
+		if !ok || s == "" {
+			return nil, fmt.Errorf("value has no %s", key)
+		}
+		values[key] = s
+	}
+	return values, nil
+}
@@ -0,0 +1,154 @@
+package generation_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"math/big"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/generation"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Verify", func() {
+	Describe("passwords", func() {
+		It("accepts a password of the default length with every default kind of character", func() {
+			Expect(generation.Verify(generation.Case{Type: "password"}, "aB3defghijklmnopqrstuvwxyzABCD")).To(Succeed())
+		})
+
+		It("checks the length", func() {
+			c := generation.Case{Type: "password", Length: 5}
+			Expect(generation.Verify(c, "aB3de")).To(Succeed())
+			Expect(generation.Verify(c, "aB3def")).To(MatchError(ContainSubstring("is 6 characters, not 5")))
+		})
+
+		It("rejects excluded characters", func() {
+			c := generation.Case{Type: "password", Length: 4, ExcludeNumber: true}
+			Expect(generation.Verify(c, "aBcD")).To(Succeed())
+			Expect(generation.Verify(c, "aB3D")).To(MatchError(ContainSubstring("which the parameters exclude")))
+			Expect(generation.Verify(c, "aB!D")).To(MatchError(ContainSubstring("which the parameters exclude")))
+		})
+
+		It("requires every included kind of character", func() {
+			c := generation.Case{Type: "password", Length: 4, ExcludeUpper: true, IncludeSpecial: true}
+			Expect(generation.Verify(c, "a1!b")).To(Succeed())
+			Expect(generation.Verify(c, "a1bc")).To(MatchError(ContainSubstring("has no special characters")))
+			Expect(generation.Verify(c, "a!bc")).To(MatchError(ContainSubstring("has no numbers")))
+		})
+	})
+
+	Describe("users", func() {
+		It("checks a given username", func() {
+			c := generation.Case{Type: "user", Username: "some-user", Length: 4}
+			Expect(generation.Verify(c, map[string]interface{}{"username": "some-user", "password": "aB3d"})).To(Succeed())
+			Expect(generation.Verify(c, map[string]interface{}{"username": "other", "password": "aB3d"})).To(MatchError(ContainSubstring(`username is "other"`)))
+		})
+
+		It("checks the form of a generated username", func() {
+			c := generation.Case{Type: "user", Length: 4}
+			Expect(generation.Verify(c, map[string]interface{}{"username": "abcdefghijABCDEFGHIJ", "password": "aB3d"})).To(Succeed())
+			Expect(generation.Verify(c, map[string]interface{}{"username": "abc", "password": "aB3d"})).To(HaveOccurred())
+		})
+
+		It("checks the password against the password parameters", func() {
+			c := generation.Case{Type: "user", Username: "u", Length: 4, ExcludeLower: true}
+			Expect(generation.Verify(c, map[string]interface{}{"username": "u", "password": "aB3D"})).To(HaveOccurred())
+		})
+	})
+
+	Describe("keys", func() {
+		var key, other *rsa.PrivateKey
+
+		BeforeEach(func() {
+			var err error
+			key, err = rsa.GenerateKey(rand.Reader, 2048)
+			Expect(err).NotTo(HaveOccurred())
+			other, err = rsa.GenerateKey(rand.Reader, 2048)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("accepts an RSA key pair and rejects a mismatched one", func() {
+			c := generation.Case{Type: "rsa"}
+			Expect(generation.Verify(c, rsaValue(key, &key.PublicKey))).To(Succeed())
+			Expect(generation.Verify(c, rsaValue(key, &other.PublicKey))).To(MatchError(ContainSubstring("does not match")))
+		})
+
+		It("checks the key length", func() {
+			c := generation.Case{Type: "rsa", KeyLength: 4096}
+			Expect(generation.Verify(c, rsaValue(key, &key.PublicKey))).To(MatchError(ContainSubstring("is 2048 bits, not 4096")))
+		})
+
+		It("accepts an SSH key with its comment and fingerprint", func() {
+			c := generation.Case{Type: "ssh", SSHComment: "someone@somewhere"}
+			Expect(generation.Verify(c, sshValue(key, &key.PublicKey, "someone@somewhere"))).To(Succeed())
+		})
+
+		It("rejects an SSH key with the wrong comment", func() {
+			c := generation.Case{Type: "ssh"}
+			Expect(generation.Verify(c, sshValue(key, &key.PublicKey, "unexpected"))).To(MatchError(ContainSubstring("comment")))
+		})
+
+		It("rejects an SSH public key that does not match the private key", func() {
+			c := generation.Case{Type: "ssh"}
+			Expect(generation.Verify(c, sshValue(key, &other.PublicKey, ""))).To(MatchError(ContainSubstring("does not match")))
+		})
+
+		It("rejects an SSH fingerprint of the wrong form or key", func() {
+			c := generation.Case{Type: "ssh"}
+			value := sshValue(key, &key.PublicKey, "")
+			value["public_key_fingerprint"] = "SHA256:" + value["public_key_fingerprint"].(string)
+			Expect(generation.Verify(c, value)).To(MatchError(ContainSubstring("not an unpadded base64 SHA-256 digest")))
+
+			value["public_key_fingerprint"] = sshValue(other, &other.PublicKey, "")["public_key_fingerprint"]
+			Expect(generation.Verify(c, value)).To(MatchError(ContainSubstring("fingerprint is")))
+		})
+	})
+})
+
+func privateKeyPEM(key *rsa.PrivateKey) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+}
+
+func rsaValue(key *rsa.PrivateKey, public *rsa.PublicKey) map[string]interface{} {
+	encoded, err := x509.MarshalPKIXPublicKey(public)
+	Expect(err).NotTo(HaveOccurred())
+	return map[string]interface{}{
+		"public_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: encoded})),
+		"private_key": privateKeyPEM(key),
+	}
+}
+
+func sshValue(key *rsa.PrivateKey, public *rsa.PublicKey, comment string) map[string]interface{} {
+	var wire bytes.Buffer
+	writeString := func(b []byte) {
+		binary.Write(&wire, binary.BigEndian, uint32(len(b)))
+		wire.Write(b)
+	}
+	writeMPInt := func(i *big.Int) {
+		b := i.Bytes()
+		if len(b) > 0 && b[0]&0x80 != 0 {
+			b = append([]byte{0}, b...)
+		}
+		writeString(b)
+	}
+	writeString([]byte("ssh-rsa"))
+	writeMPInt(big.NewInt(int64(public.E)))
+	writeMPInt(public.N)
+
+	publicKey := "ssh-rsa " + base64.StdEncoding.EncodeToString(wire.Bytes())
+	if comment != "" {
+		publicKey += " " + comment
+	}
+	digest := sha256.Sum256(wire.Bytes())
+	return map[string]interface{}{
+		"public_key":             publicKey,
+		"private_key":            privateKeyPEM(key),
+		"public_key_fingerprint": base64.RawStdEncoding.EncodeToString(digest[:]),
+	}
+}
@@ -0,0 +1,245 @@
+// Package history checks the version history of credentials: that every
+// version has its own id, that versions are listed newest first, that the
+// latest version is what a plain get returns, that asking for the latest n
+// versions returns the first n of them all, and that deleting a credential
+// deletes every version. It records the credentials each spec writes and
+// deletes, through the Go client, raw HTTP and the CLI, so that suites can
+// check them all after every spec.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/apierrors"
+)
+
+// Source fetches the versions of a credential.
+type Source interface {
+	// All returns every version, newest first.
+	All(name string) ([]credentials.Credential, error)
+	Latest(name string) (credentials.Credential, error)
+	// Versions returns the latest n versions, newest first.
+	Versions(name string, n int) ([]credentials.Credential, error)
+}
+
+// Client is the part of the CredHub Go client GoClient uses.
+type Client interface {
+	GetAllVersions(name string) ([]credentials.Credential, error)
+	GetLatestVersion(name string) (credentials.Credential, error)
+	GetNVersions(name string, numberOfVersions int) ([]credentials.Credential, error)
+}
+
+// GoClient fetches versions with the Go client.
+type GoClient struct {
+	Client Client
+}
+
+func (g GoClient) All(name string) ([]credentials.Credential, error) {
+	return g.Client.GetAllVersions(name)
+}
+
+func (g GoClient) Latest(name string) (credentials.Credential, error) {
+	return g.Client.GetLatestVersion(name)
+}
+
+func (g GoClient) Versions(name string, n int) ([]credentials.Credential, error) {
+	return g.Client.GetNVersions(name, n)
+}
+
+// CLI fetches versions with get --versions. Run runs the CLI with the given
+// arguments and returns its standard output, or an error when it exits
+// non-zero.
+type CLI struct {
+	Run func(args ...string) ([]byte, error)
+}
+
+// All asks for twice as many versions until fewer come back, as the CLI has
+// no way to ask for every version.
+func (c CLI) All(name string) ([]credentials.Credential, error) {
+	for n := 16; ; n *= 2 {
+		versions, err := c.Versions(name, n)
+		if err != nil || len(versions) < n {
+			return versions, err
+		}
+	}
+}
+
+func (c CLI) Latest(name string) (credentials.Credential, error) {
+	var credential credentials.Credential
+	output, err := c.Run("get", "-n", name, "-j")
+	if err != nil {
+		return credential, err
+	}
+	err = json.Unmarshal(output, &credential)
+	return credential, err
+}
+
+func (c CLI) Versions(name string, n int) ([]credentials.Credential, error) {
+	output, err := c.Run("get", "-n", name, "--versions", strconv.Itoa(n), "-j")
+	if err != nil {
+		return nil, err
+	}
+	var versions struct {
+		Versions []credentials.Credential `json:"versions"`
+	}
+	err = json.Unmarshal(output, &versions)
+	return versions.Versions, err
+}
+
+// ErrUnreadable is returned by Check for a credential it may not read or
+// that does not exist, neither of which is a fault in its history.
+var ErrUnreadable = errors.New("the credential does not exist or cannot be read")
+
+// Check fetches every version of the credential and checks its history.
+func Check(source Source, name string) error {
+	all, err := source.All(name)
+	if err != nil {
+		if isNotFound(err) {
+			return ErrUnreadable
+		}
+		return fmt.Errorf("fetching every version: %s", err)
+	}
+	if len(all) == 0 {
+		return errors.New("it has no versions")
+	}
+
+	var problems []string
+	ids := map[string]int{}
+	for i, version := range all {
+		if first, ok := ids[version.Id]; ok {
+			problems = append(problems, fmt.Sprintf("versions %d and %d share the id %s", first, i, version.Id))
+		}
+		ids[version.Id] = i
+	}
+	problems = append(problems, checkOrder(all)...)
+
+	latest, err := source.Latest(name)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("fetching the latest version: %s", err))
+	} else if problem := compare(all[0], latest); problem != "" {
+		problems = append(problems, "the latest version is not the first of every version: "+problem)
+	}
+
+	for _, n := range counts(len(all)) {
+		versions, err := source.Versions(name, n)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("fetching the latest %d versions: %s", n, err))
+			continue
+		}
+		if len(versions) != n {
+			problems = append(problems, fmt.Sprintf("asking for the latest %d versions returned %d", n, len(versions)))
+			continue
+		}
+		for i := range versions {
+			if problem := compare(all[i], versions[i]); problem != "" {
+				problems = append(problems, fmt.Sprintf("version %d of the latest %d is not version %d of every version: %s", i, n, i, problem))
+				break
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// CheckDeleted checks that no version of a deleted credential is left.
+func CheckDeleted(source Source, name string) error {
+	var problems []string
+	if all, err := source.All(name); !gone(len(all), err) {
+		problems = append(problems, fmt.Sprintf("fetching every version returned %d versions and %v", len(all), err))
+	}
+	if latest, err := source.Latest(name); !isNotFound(err) {
+		problems = append(problems, fmt.Sprintf("fetching the latest version returned %s and %v", latest.Id, err))
+	}
+	if versions, err := source.Versions(name, 1); !gone(len(versions), err) {
+		problems = append(problems, fmt.Sprintf("fetching the latest version by count returned %d versions and %v", len(versions), err))
+	}
+	if len(problems) > 0 {
+		return errors.New("it was deleted, but " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// gone reports whether fetching versions found none.
+func gone(versions int, err error) bool {
+	return isNotFound(err) || (err == nil && versions == 0)
+}
+
+// checkOrder checks that no version was created after the one listed before
+// it. Versions written within the same instant may share a time.
+func checkOrder(all []credentials.Credential) []string {
+	var problems []string
+	var previous time.Time
+	for i, version := range all {
+		created, err := time.Parse(time.RFC3339Nano, version.VersionCreatedAt)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("version %d was created at %q, which is not a time", i, version.VersionCreatedAt))
+			continue
+		}
+		if !previous.IsZero() && created.After(previous) {
+			problems = append(problems, fmt.Sprintf("version %d, created at %s, is listed after version %d, which was created earlier at %s",
+				i, version.VersionCreatedAt, i-1, all[i-1].VersionCreatedAt))
+		}
+		previous = created
+	}
+	return problems
+}
+
+// counts are the numbers of versions to ask for of a credential with total
+// versions: one, two and all of them.
+func counts(total int) []int {
+	counts := []int{1}
+	if total > 2 {
+		counts = append(counts, 2)
+	}
+	if total > 1 {
+		counts = append(counts, total)
+	}
+	return counts
+}
+
+// compare describes how got differs from want, or returns "" when they are
+// the same version with the same contents.
+func compare(want, got credentials.Credential) string {
+	if want.Id != got.Id {
+		return fmt.Sprintf("its id is %s, not %s", got.Id, want.Id)
+	}
+	wantJSON, err := json.Marshal(want)
+	if err != nil {
+		return err.Error()
+	}
+	gotJSON, err := json.Marshal(got)
+	if err != nil {
+		return err.Error()
+	}
+	var wantFields, gotFields interface{}
+	json.Unmarshal(wantJSON, &wantFields)
+	json.Unmarshal(gotJSON, &gotFields)
+	if !reflect.DeepEqual(wantFields, gotFields) {
+		return fmt.Sprintf("version %s is %s, not %s", want.Id, gotJSON, wantJSON)
+	}
+	return ""
+}
+
+// isNotFound reports whether err is CredHub saying the credential does not
+// exist or may not be read, from the Go client or the CLI.
+func isNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	var notFound *credhub.NotFoundError
+	if errors.As(err, &notFound) {
+		return true
+	}
+	return apierrors.NotFound.Matches(strings.TrimSpace(err.Error()))
+}
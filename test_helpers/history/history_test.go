@@ -0,0 +1,166 @@
+package history_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/history"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const notFound = "The request could not be completed because the credential does not exist or you do not have sufficient authorization."
+
+// fakeSource serves versions newest first, and can be made to serve a
+// different latest version or different versions by count.
+type fakeSource struct {
+	versions map[string][]credentials.Credential
+	latest   *credentials.Credential
+	byCount  []credentials.Credential
+}
+
+func (f *fakeSource) All(name string) ([]credentials.Credential, error) {
+	versions, ok := f.versions[name]
+	if !ok {
+		return nil, &credhub.NotFoundError{}
+	}
+	return versions, nil
+}
+
+func (f *fakeSource) Latest(name string) (credentials.Credential, error) {
+	if f.latest != nil {
+		return *f.latest, nil
+	}
+	versions, ok := f.versions[name]
+	if !ok {
+		return credentials.Credential{}, errors.New(notFound)
+	}
+	return versions[0], nil
+}
+
+func (f *fakeSource) Versions(name string, n int) ([]credentials.Credential, error) {
+	if f.byCount != nil {
+		return f.byCount[:n], nil
+	}
+	versions, ok := f.versions[name]
+	if !ok {
+		return nil, errors.New(notFound)
+	}
+	return versions[:n], nil
+}
+
+func version(id, createdAt string, value interface{}) credentials.Credential {
+	credential := credentials.Credential{Value: value}
+	credential.Id = id
+	credential.Name = "/c"
+	credential.Type = "value"
+	credential.VersionCreatedAt = createdAt
+	return credential
+}
+
+var _ = Describe("Check", func() {
+	var source *fakeSource
+
+	BeforeEach(func() {
+		source = &fakeSource{versions: map[string][]credentials.Credential{
+			"/c": {
+				version("3", "2026-10-19T00:00:02Z", "c"),
+				version("2", "2026-10-19T00:00:01Z", "b"),
+				version("1", "2026-10-19T00:00:01Z", "a"),
+			},
+		}}
+	})
+
+	It("passes a sound history", func() {
+		Expect(history.Check(source, "/c")).To(Succeed())
+	})
+
+	It("reports credentials it cannot read apart from broken histories", func() {
+		Expect(history.Check(source, "/missing")).To(Equal(history.ErrUnreadable))
+	})
+
+	It("reports shared ids and versions listed out of order", func() {
+		versions := source.versions["/c"]
+		versions[2].Id = "3"
+		versions[1].VersionCreatedAt = "2026-10-19T00:00:03Z"
+
+		Expect(history.Check(source, "/c")).To(MatchError(
+			"versions 0 and 2 share the id 3; " +
+				"version 1, created at 2026-10-19T00:00:03Z, is listed after version 0, which was created earlier at 2026-10-19T00:00:02Z"))
+	})
+
+	It("reports a latest version other than the first of every version", func() {
+		latest := version("3", "2026-10-19T00:00:02Z", "changed")
+		source.latest = &latest
+
+		Expect(history.Check(source, "/c")).To(MatchError(
+			`the latest version is not the first of every version: version 3 is ` +
+				`{"id":"3","name":"/c","type":"value","value":"changed","metadata":null,"version_created_at":"2026-10-19T00:00:02Z"}, not ` +
+				`{"id":"3","name":"/c","type":"value","value":"c","metadata":null,"version_created_at":"2026-10-19T00:00:02Z"}`))
+	})
+
+	It("reports versions by count that are not a prefix of every version", func() {
+		source.byCount = []credentials.Credential{source.versions["/c"][0], source.versions["/c"][2], source.versions["/c"][1]}
+
+		Expect(history.Check(source, "/c")).To(MatchError(
+			"version 1 of the latest 2 is not version 1 of every version: its id is 1, not 2; " +
+				"version 1 of the latest 3 is not version 1 of every version: its id is 1, not 2"))
+	})
+
+	It("checks a deleted credential has no versions left", func() {
+		Expect(history.CheckDeleted(source, "/missing")).To(Succeed())
+		Expect(history.CheckDeleted(source, "/c")).To(MatchError(
+			"it was deleted, but fetching every version returned 3 versions and <nil>; " +
+				"fetching the latest version returned 3 and <nil>; " +
+				"fetching the latest version by count returned 1 versions and <nil>"))
+	})
+})
+
+var _ = Describe("CLI", func() {
+	// run serves get --versions from total versions, as the CLI prints them.
+	run := func(total int, calls *[]string) func(args ...string) ([]byte, error) {
+		return func(args ...string) ([]byte, error) {
+			*calls = append(*calls, fmt.Sprint(args))
+			if len(args) == 4 {
+				return json.Marshal(version(strconv.Itoa(total), "2026-10-19T00:00:00Z", "v"))
+			}
+			n, err := strconv.Atoi(args[4])
+			Expect(err).NotTo(HaveOccurred())
+			var versions []credentials.Credential
+			for i := total; i > 0 && len(versions) < n; i-- {
+				versions = append(versions, version(strconv.Itoa(i), "2026-10-19T00:00:00Z", "v"))
+			}
+			return json.Marshal(map[string]interface{}{"versions": versions})
+		}
+	}
+
+	It("asks for more versions until fewer come back", func() {
+		var calls []string
+		all, err := history.CLI{Run: run(20, &calls)}.All("/c")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(all).To(HaveLen(20))
+		Expect(all[0].Id).To(Equal("20"))
+		Expect(calls).To(Equal([]string{
+			"[get -n /c --versions 16 -j]",
+			"[get -n /c --versions 32 -j]",
+		}))
+	})
+
+	It("gets the latest version and the latest n versions", func() {
+		var calls []string
+		source := history.CLI{Run: run(3, &calls)}
+
+		latest, err := source.Latest("/c")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(latest.Id).To(Equal("3"))
+
+		versions, err := source.Versions("/c", 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(versions).To(HaveLen(2))
+		Expect(calls).To(Equal([]string{"[get -n /c -j]", "[get -n /c --versions 2 -j]"}))
+	})
+})
@@ -0,0 +1,233 @@
+package history
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/reporting"
+	"gopkg.in/yaml.v3"
+)
+
+type recorder struct {
+	mutex sync.Mutex
+	// deleted is whether each name written or deleted since the last Reset
+	// was deleted last.
+	deleted map[string]bool
+}
+
+var current = &recorder{deleted: map[string]bool{}}
+
+// Reset forgets the names recorded so far. It is called before each spec.
+func Reset() {
+	current.mutex.Lock()
+	defer current.mutex.Unlock()
+	current.deleted = map[string]bool{}
+}
+
+// Written returns the names written since the last Reset that are still
+// there, and those deleted since, each sorted.
+func Written() (present, deleted []string) {
+	current.mutex.Lock()
+	defer current.mutex.Unlock()
+	for name, gone := range current.deleted {
+		if gone {
+			deleted = append(deleted, name)
+		} else {
+			present = append(present, name)
+		}
+	}
+	sort.Strings(present)
+	sort.Strings(deleted)
+	return present, deleted
+}
+
+// RecordWrite records that a credential was given a new version.
+func RecordWrite(name string) {
+	if name == "" {
+		return
+	}
+	current.mutex.Lock()
+	defer current.mutex.Unlock()
+	current.deleted[canonical(name)] = false
+}
+
+// RecordDelete records that a credential was deleted.
+func RecordDelete(name string) {
+	if name == "" {
+		return
+	}
+	current.mutex.Lock()
+	defer current.mutex.Unlock()
+	current.deleted[canonical(name)] = true
+}
+
+// RecordPathDelete records that the credentials under a path were deleted:
+// those recorded so far, as no others are checked.
+func RecordPathDelete(path string) {
+	prefix := strings.TrimSuffix(canonical(path), "/") + "/"
+	current.mutex.Lock()
+	defer current.mutex.Unlock()
+	for name := range current.deleted {
+		if strings.HasPrefix(name, prefix) {
+			current.deleted[name] = true
+		}
+	}
+}
+
+// canonical is a name as CredHub stores it, which always starts with a
+// slash.
+func canonical(name string) string {
+	if strings.HasPrefix(name, "/") {
+		return name
+	}
+	return "/" + name
+}
+
+var certificateWrite = regexp.MustCompile(`^/api/v1/certificates/[^/]+/(regenerate|versions)$`)
+
+// RecordRequest records the credentials a successful request wrote or
+// deleted, from the request and its response.
+func RecordRequest(method string, u *url.URL, responseBody []byte) {
+	switch {
+	case method == http.MethodDelete && u.Path == "/api/v1/data":
+		RecordDelete(u.Query().Get("name"))
+	case method == http.MethodPut && u.Path == "/api/v1/data",
+		method == http.MethodPost && (u.Path == "/api/v1/data" || u.Path == "/api/v1/regenerate" || certificateWrite.MatchString(u.Path)):
+		var written struct {
+			Name string `json:"name"`
+		}
+		if json.Unmarshal(responseBody, &written) == nil {
+			RecordWrite(written.Name)
+		}
+	case method == http.MethodPost && u.Path == "/api/v1/bulk-regenerate":
+		for _, name := range regenerated(responseBody) {
+			RecordWrite(name)
+		}
+	}
+}
+
+// regenerated reads the names bulk regeneration lists, from its JSON
+// response or the YAML the CLI prints.
+func regenerated(output []byte) []string {
+	var result struct {
+		Names []string `yaml:"regenerated_credentials"`
+	}
+	yaml.Unmarshal(output, &result)
+	return result.Names
+}
+
+var importFailure = regexp.MustCompile(`(?m)^Credential '(.*)' at index \d+ could not be set: `)
+
+// RecordCommand records the credentials a CLI invocation wrote or deleted,
+// from its arguments, exit code and standard output. Import records every
+// entry of its file but those it reports it could not set, as it exits
+// non-zero when any entry fails.
+func RecordCommand(args []string, exitCode int, stdout []byte) {
+	if len(args) == 0 || (exitCode != 0 && args[0] != "import") {
+		return
+	}
+	switch args[0] {
+	case "set", "generate", "regenerate":
+		RecordWrite(flagValue(args, "-n", "--name"))
+	case "delete":
+		if path := flagValue(args, "-p", "--path"); path != "" {
+			RecordPathDelete(path)
+		} else {
+			RecordDelete(flagValue(args, "-n", "--name"))
+		}
+	case "bulk-regenerate":
+		for _, name := range regenerated(stdout) {
+			RecordWrite(name)
+		}
+	case "import":
+		recordImport(flagValue(args, "-f", "--file"), stdout)
+	case "curl":
+		// curl exits zero whatever CredHub responds.
+		var failure struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(stdout, &failure) == nil && failure.Error != "" {
+			return
+		}
+		method := flagValue(args, "-X", "--method")
+		if method == "" {
+			method = http.MethodGet
+		}
+		if u, err := url.Parse(flagValue(args, "-p", "--path")); err == nil {
+			RecordRequest(method, u, stdout)
+		}
+	}
+}
+
+func recordImport(file string, stdout []byte) {
+	contents, err := ioutil.ReadFile(file)
+	if err != nil {
+		return
+	}
+	// JSON import files are YAML too.
+	var imported struct {
+		Credentials []struct {
+			Name string `yaml:"name"`
+		} `yaml:"credentials"`
+	}
+	if yaml.Unmarshal(contents, &imported) != nil {
+		return
+	}
+	failed := map[string]bool{}
+	for _, match := range importFailure.FindAllSubmatch(stdout, -1) {
+		failed[string(match[1])] = true
+	}
+	for _, credential := range imported.Credentials {
+		if !failed[credential.Name] {
+			RecordWrite(credential.Name)
+		}
+	}
+}
+
+// flagValue returns the value given for any of the flags, or "".
+func flagValue(args []string, flags ...string) string {
+	for i, arg := range args {
+		for _, flag := range flags {
+			if arg == flag && i+1 < len(args) {
+				return args[i+1]
+			}
+			if strings.HasPrefix(arg, flag+"=") {
+				return strings.TrimPrefix(arg, flag+"=")
+			}
+		}
+	}
+	return ""
+}
+
+type recordingTransport struct {
+	inner http.RoundTripper
+}
+
+// NewTransport wraps inner so that the credentials written and deleted by
+// every successful request it sends are recorded. A nil inner uses
+// http.DefaultTransport.
+func NewTransport(inner http.RoundTripper) http.RoundTripper {
+	if inner == nil {
+		inner = http.DefaultTransport
+	}
+	return &recordingTransport{inner: inner}
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.inner.RoundTrip(req)
+	if err != nil || resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp, err
+	}
+	body, err := reporting.ReadBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	RecordRequest(req.Method, req.URL, body)
+	return resp, nil
+}
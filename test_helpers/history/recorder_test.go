@@ -0,0 +1,113 @@
+package history_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/history"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Recording", func() {
+	BeforeEach(func() {
+		history.Reset()
+	})
+
+	written := func() []string {
+		present, _ := history.Written()
+		return present
+	}
+	deleted := func() []string {
+		_, gone := history.Written()
+		return gone
+	}
+
+	It("records what successful requests write and delete", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/api/v1/bulk-regenerate":
+				w.Write([]byte(`{"regenerated_credentials":["/leaf-1","/leaf-2"]}`))
+			case r.Method == http.MethodDelete:
+				w.WriteHeader(http.StatusNoContent)
+			case r.URL.Path == "/api/v1/data" && strings.Contains(r.URL.RawQuery, "fail"):
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":"bad"}`))
+			default:
+				w.Write([]byte(`{"name":"/` + strings.TrimPrefix(r.URL.Path, "/api/v1/") + `"}`))
+			}
+		}))
+		defer server.Close()
+		client := &http.Client{Transport: history.NewTransport(nil)}
+
+		request := func(method, path string) {
+			req, err := http.NewRequest(method, server.URL+path, nil)
+			Expect(err).NotTo(HaveOccurred())
+			resp, err := client.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = ioutil.ReadAll(resp.Body)
+			Expect(err).NotTo(HaveOccurred())
+			resp.Body.Close()
+		}
+		request(http.MethodPut, "/api/v1/data")
+		request(http.MethodPost, "/api/v1/regenerate")
+		request(http.MethodPost, "/api/v1/bulk-regenerate")
+		request(http.MethodPost, "/api/v1/certificates/id/versions")
+		request(http.MethodPut, "/api/v1/data?fail")
+		request(http.MethodGet, "/api/v1/other")
+		request(http.MethodDelete, "/api/v1/data?name=/gone")
+
+		Expect(written()).To(Equal([]string{"/certificates/id/versions", "/data", "/leaf-1", "/leaf-2", "/regenerate"}))
+		Expect(deleted()).To(Equal([]string{"/gone"}))
+	})
+
+	It("records what successful CLI commands write and delete", func() {
+		history.RecordCommand([]string{"set", "-n", "set", "-t", "value", "-v", "v"}, 0, nil)
+		history.RecordCommand([]string{"generate", "--name=/path/generated", "-t", "password"}, 0, nil)
+		history.RecordCommand([]string{"regenerate", "-n", "/path/failed"}, 1, nil)
+		history.RecordCommand([]string{"bulk-regenerate", "--signed-by", "/ca"}, 0, []byte("regenerated_credentials:\n- /leaf\n"))
+		history.RecordCommand([]string{"curl", "-X", "PUT", "-p", "/api/v1/data", "-d", "{}"}, 0, []byte(`{"name":"/curled"}`))
+		history.RecordCommand([]string{"curl", "-X", "DELETE", "-p", "/api/v1/data?name=/set"}, 0, []byte(`{"error":"no"}`))
+		Expect(written()).To(Equal([]string{"/curled", "/leaf", "/path/generated", "/set"}))
+
+		history.RecordCommand([]string{"delete", "-n", "set"}, 0, nil)
+		history.RecordCommand([]string{"delete", "-p", "path"}, 0, nil)
+		Expect(written()).To(Equal([]string{"/curled", "/leaf"}))
+		Expect(deleted()).To(Equal([]string{"/path/generated", "/set"}))
+
+		history.RecordCommand([]string{"set", "-n", "/set", "-t", "value", "-v", "again"}, 0, nil)
+		Expect(written()).To(Equal([]string{"/curled", "/leaf", "/set"}))
+	})
+
+	It("records the entries of an import file but those it could not set", func() {
+		file, err := ioutil.TempFile("", "history-import")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.Remove(file.Name())
+		file.WriteString(`credentials:
+- name: /imported
+  type: value
+  value: v
+- name: /rejected
+  type: not-a-type
+  value: v
+`)
+		file.Close()
+
+		history.RecordCommand([]string{"import", "-f", file.Name()}, 1, []byte(
+			"Credential '/rejected' at index 1 could not be set: The request does not include a valid type.\n\n"+
+				"Import complete.\nSuccessfully set: 1\nFailed to set: 1\n"+
+				" - Credential '/rejected' at index 1 could not be set: The request does not include a valid type.\n"))
+		Expect(written()).To(Equal([]string{"/imported"}))
+	})
+
+	It("records requests made without the transport", func() {
+		u, err := url.Parse("/api/v1/data?name=%2Fescaped%20name")
+		Expect(err).NotTo(HaveOccurred())
+		history.RecordRequest(http.MethodDelete, u, nil)
+		Expect(deleted()).To(Equal([]string{"/escaped name"}))
+	})
+})
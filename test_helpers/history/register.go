@@ -0,0 +1,46 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+)
+
+// Register adds hooks to a suite that check the history of every credential
+// a spec wrote, and that those it deleted are gone, once the spec has passed.
+// Credentials source may not read are not checked. It must be called at the
+// top level of the suite, e.g. `var _ = history.Register(source)`.
+func Register(source func() Source) bool {
+	BeforeEach(func() {
+		Reset()
+	})
+
+	AfterEach(func() {
+		if CurrentSpecReport().Failed() {
+			return
+		}
+		present, deleted := Written()
+		if len(present) == 0 && len(deleted) == 0 {
+			return
+		}
+
+		s := source()
+		var messages []string
+		for _, name := range present {
+			if err := Check(s, name); err != nil && err != ErrUnreadable {
+				messages = append(messages, fmt.Sprintf("%s: %s", name, err))
+			}
+		}
+		for _, name := range deleted {
+			if err := CheckDeleted(s, name); err != nil {
+				messages = append(messages, fmt.Sprintf("%s: %s", name, err))
+			}
+		}
+		if len(messages) > 0 {
+			Fail("version histories broke their invariants:\n" + strings.Join(messages, "\n"))
+		}
+	})
+
+	return true
+}
@@ -0,0 +1,134 @@
+package test_helpers
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"sync"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/auth"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/contract"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/history"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/reporting"
+	. "github.com/onsi/gomega"
+)
+
+var (
+	contractOnce     sync.Once
+	contractDocument *contract.Document
+	contractErr      error
+
+	historyOnce   sync.Once
+	historyClient *credhub.CredHub
+	historyErr    error
+)
+
+// RegisterReporting adds the reporting hooks to a suite, writing reports to
+// the report_dir given in test_config.json.
+func RegisterReporting(suiteName string) bool {
+	return reporting.Register(suiteName, func() string {
+		cfg, err := LoadConfig()
+		if err != nil {
+			return ""
+		}
+		return cfg.ReportDir
+	})
+}
+
+// RegisterContractValidation fails specs that receive a response from an
+// instrumented client which does not match the CredHub API document.
+func RegisterContractValidation() bool {
+	return contract.Register()
+}
+
+// RegisterHistoryChecks fails specs that leave a credential they wrote with
+// a broken version history, or a credential they deleted with versions
+// left. Histories are read with a Go client of its own, logged in as the
+// client in test_config.json and not instrumented, so that the checks are
+// not reported as the spec's calls. The client trusts the CAs under
+// credential_root and uaa_ca.
+func RegisterHistoryChecks() bool {
+	return registerHistoryChecks(false)
+}
+
+// RegisterHistoryChecksSkipTls does the same as RegisterHistoryChecks for
+// suites that log in with TargetAndLoginSkipTls, whose configuration need
+// not name the CAs, so the client skips TLS validation as theirs does. A
+// suite registers one or the other.
+func RegisterHistoryChecksSkipTls() bool {
+	return registerHistoryChecks(true)
+}
+
+func registerHistoryChecks(skipTLS bool) bool {
+	return history.Register(func() history.Source {
+		historyOnce.Do(func() {
+			historyClient, historyErr = newHistoryClient(skipTLS)
+		})
+		Expect(historyErr).NotTo(HaveOccurred())
+		return history.GoClient{Client: historyClient}
+	})
+}
+
+func newHistoryClient(skipTLS bool) (*credhub.CredHub, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	login := credhub.Auth(auth.UaaClientCredentials(cfg.ClientName, cfg.ClientSecret))
+	if skipTLS {
+		return credhub.New(cfg.ApiUrl, credhub.SkipTLSValidation(true), login)
+	}
+	credhubCA, err := ioutil.ReadFile(path.Join(cfg.CredentialRoot, "server_ca_cert.pem"))
+	if err != nil {
+		return nil, err
+	}
+	uaaCA, err := ioutil.ReadFile(cfg.UAACa)
+	if err != nil {
+		return nil, err
+	}
+	return credhub.New(cfg.ApiUrl, credhub.CaCerts(string(credhubCA), string(uaaCA)), login)
+}
+
+// ContractDocument returns the CredHub API document responses are validated
+// against: the contract_spec given in test_config.json, or the document
+// shipped with the contract package when there is no test_config.json or it
+// sets none. It fails the spec when test_config.json cannot be read.
+func ContractDocument() *contract.Document {
+	contractOnce.Do(func() {
+		var cfg Config
+		cfg, contractErr = LoadConfig()
+		switch {
+		case os.IsNotExist(contractErr):
+			contractDocument, contractErr = contract.DefaultDocument(), nil
+		case contractErr != nil:
+		case cfg.ContractSpec == "":
+			contractDocument = contract.DefaultDocument()
+		default:
+			contractDocument, contractErr = contract.LoadFile(cfg.ContractSpec)
+		}
+	})
+	Expect(contractErr).NotTo(HaveOccurred(), "loading the contract document")
+	return contractDocument
+}
+
+// InstrumentClient records every request the client sends to CredHub, and
+// the credentials it writes and deletes, and validates every response. It
+// wraps the transport of the client's shared http.Client, so it must be
+// called once per client, after credhub.New.
+func InstrumentClient(ch *credhub.CredHub) *credhub.CredHub {
+	client := ch.Client()
+	client.Transport = instrument(reporting.GoClient, client.Transport)
+	return ch
+}
+
+// InstrumentTransport does the same as InstrumentClient for raw HTTP
+// requests made without the Go client.
+func InstrumentTransport(inner http.RoundTripper) http.RoundTripper {
+	return instrument(reporting.HTTP, inner)
+}
+
+func instrument(driver reporting.Driver, inner http.RoundTripper) http.RoundTripper {
+	return reporting.NewTransport(driver, contract.NewTransport(ContractDocument(), history.NewTransport(inner)))
+}
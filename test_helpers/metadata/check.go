@@ -0,0 +1,123 @@
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+)
+
+// Outcome is what a write does to metadata on the version it creates.
+type Outcome int
+
+const (
+	// Replaced means the new version has the metadata sent with the write.
+	Replaced Outcome = iota
+	// Preserved means the new version has the latest version's metadata.
+	Preserved
+	// Cleared means the new version has no metadata.
+	Cleared
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case Replaced:
+		return "replaced"
+	case Preserved:
+		return "preserved"
+	}
+	return "cleared"
+}
+
+// Check checks the metadata a write left on the version it created, given
+// the latest version's metadata before the write and the metadata the write
+// sent.
+func Check(outcome Outcome, previous, sent, got credentials.Metadata) error {
+	var allowed []credentials.Metadata
+	switch outcome {
+	case Replaced:
+		allowed = []credentials.Metadata{sent}
+	case Preserved:
+		allowed = []credentials.Metadata{previous}
+	case Cleared:
+		allowed = []credentials.Metadata{nil}
+	}
+	for _, want := range allowed {
+		equal, err := Equal(want, got)
+		if err != nil {
+			return err
+		}
+		if equal {
+			return nil
+		}
+	}
+	return fmt.Errorf("metadata should be %s, but was %s", outcome, describe(got))
+}
+
+// Unchanged checks that every version in before is still in after, by id,
+// with the same metadata: writing a version never changes the metadata of
+// the versions before it.
+func Unchanged(before, after []credentials.Credential) error {
+	byID := map[string]credentials.Credential{}
+	for _, version := range after {
+		byID[version.Id] = version
+	}
+	for _, version := range before {
+		got, ok := byID[version.Id]
+		if !ok {
+			return fmt.Errorf("version %s is missing", version.Id)
+		}
+		equal, err := Equal(version.Metadata, got.Metadata)
+		if err != nil {
+			return err
+		}
+		if !equal {
+			return fmt.Errorf("version %s had metadata %s, but now has %s", version.Id, describe(version.Metadata), describe(got.Metadata))
+		}
+	}
+	return nil
+}
+
+// FoundEntry checks one credential of a raw find response: that it has only
+// the fields find returns, and that if it has metadata, it is the latest
+// version's.
+func FoundEntry(entry map[string]interface{}, latest credentials.Metadata) error {
+	for key := range entry {
+		switch key {
+		case "name", "version_created_at", "metadata":
+		default:
+			return fmt.Errorf("find returned the field %q", key)
+		}
+	}
+	raw, ok := entry["metadata"]
+	if !ok || raw == nil {
+		return nil
+	}
+	found, ok := raw.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("find returned metadata that is not an object: %#v", raw)
+	}
+	equal, err := Equal(latest, found)
+	if err != nil {
+		return err
+	}
+	if !equal {
+		return fmt.Errorf("find returned metadata %s, not the latest version's, %s", describe(found), describe(latest))
+	}
+	return nil
+}
+
+// describe is metadata as JSON, cut short when it is long.
+func describe(metadata credentials.Metadata) string {
+	if len(metadata) == 0 {
+		return "none"
+	}
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Sprintf("%#v", metadata)
+	}
+	if len(encoded) > 200 {
+		return fmt.Sprintf("%s... (%d bytes)", encoded[:200], len(encoded))
+	}
+	return string(encoded)
+}
@@ -0,0 +1,133 @@
+// Package metadata builds credential metadata for specs, from a few flat
+// keys to deeply nested and large documents, and checks what metadata each
+// way of writing a credential version leaves on it and on the versions
+// before it.
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
+
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"gopkg.in/yaml.v3"
+)
+
+// Fixture is metadata a spec writes, with a label for its description.
+type Fixture struct {
+	Label    string
+	Metadata credentials.Metadata
+}
+
+// LargeSize is the least size of Large metadata as JSON.
+const LargeSize = 16 * 1024
+
+// NestedDepth is how deep Nested metadata goes.
+const NestedDepth = 10
+
+// Fixtures are flat, nested and large metadata.
+var Fixtures = []Fixture{
+	{"flat", Flat()},
+	{"nested", Nested(NestedDepth)},
+	{"large", Large(LargeSize)},
+}
+
+// Flat is a few string keys, as inventory tooling sets.
+func Flat() credentials.Metadata {
+	return credentials.Metadata{"owner": "inventory", "team": "credhub", "environment": "acceptance"}
+}
+
+// Nested is depth levels of objects, each with every JSON type alongside the
+// next level: a string with unicode and quotes, a number, a boolean, a null
+// and an array of mixed values.
+func Nested(depth int) credentials.Metadata {
+	var level interface{} = "leaf"
+	for i := depth; i > 0; i-- {
+		level = map[string]interface{}{
+			"level":  float64(i),
+			"name":   fmt.Sprintf("lévêl-%d \"quoted\" 🔑", i),
+			"active": i%2 == 0,
+			"none":   nil,
+			"list":   []interface{}{float64(i), "item", false, nil, map[string]interface{}{"in": "array"}},
+			"next":   level,
+		}
+	}
+	return credentials.Metadata{"nested": level}
+}
+
+// Large is at least size bytes of metadata as JSON, as many keys of
+// distinct values.
+func Large(size int) credentials.Metadata {
+	metadata := credentials.Metadata{}
+	for i := 0; length(metadata) < size; i++ {
+		metadata[fmt.Sprintf("key-%04d", i)] = fmt.Sprintf("value-%04d-%s", i, strings.Repeat("x", 48))
+	}
+	return metadata
+}
+
+func length(metadata credentials.Metadata) int {
+	encoded, _ := json.Marshal(metadata)
+	return len(encoded)
+}
+
+// Normalise returns metadata as JSON would read it back, so metadata read
+// from YAML or built in Go compares equal to metadata read from CredHub.
+// Empty metadata normalises to nil, as CredHub does not tell them apart.
+func Normalise(metadata credentials.Metadata) (credentials.Metadata, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+	var normalised credentials.Metadata
+	err = json.Unmarshal(encoded, &normalised)
+	return normalised, err
+}
+
+// Equal reports whether two sets of metadata are the same once normalised.
+func Equal(a, b credentials.Metadata) (bool, error) {
+	normalisedA, err := Normalise(a)
+	if err != nil {
+		return false, err
+	}
+	normalisedB, err := Normalise(b)
+	if err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(normalisedA, normalisedB), nil
+}
+
+// Imported reads a credhub import file and returns the metadata of each
+// credential in it by name, normalised. Credentials without metadata are
+// included with nil.
+func Imported(file string) (map[string]credentials.Metadata, error) {
+	contents, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var bulk struct {
+		Credentials []struct {
+			Name     string                 `yaml:"name"`
+			Metadata map[string]interface{} `yaml:"metadata"`
+		} `yaml:"credentials"`
+	}
+	if err := yaml.Unmarshal(contents, &bulk); err != nil {
+		return nil, err
+	}
+
+	imported := map[string]credentials.Metadata{}
+	for _, credential := range bulk.Credentials {
+		if credential.Name == "" {
+			return nil, fmt.Errorf("%s has a credential without a name", file)
+		}
+		imported[credential.Name], err = Normalise(credential.Metadata)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", credential.Name, err)
+		}
+	}
+	return imported, nil
+}
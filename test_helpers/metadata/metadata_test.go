@@ -0,0 +1,146 @@
+package metadata_test
+
+import (
+	"encoding/json"
+
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/metadata"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fixtures", func() {
+	It("builds large metadata of at least the size asked for", func() {
+		encoded, err := json.Marshal(metadata.Large(metadata.LargeSize))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(encoded)).To(BeNumerically(">=", metadata.LargeSize))
+	})
+
+	It("nests metadata to the depth asked for", func() {
+		var level interface{} = metadata.Nested(3)["nested"]
+		depth := 0
+		for {
+			object, ok := level.(map[string]interface{})
+			if !ok {
+				break
+			}
+			depth++
+			level = object["next"]
+		}
+		Expect(depth).To(Equal(3))
+		Expect(level).To(Equal("leaf"))
+	})
+
+	It("builds fixtures that survive JSON unchanged", func() {
+		for _, fixture := range metadata.Fixtures {
+			normalised, err := metadata.Normalise(fixture.Metadata)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(metadata.Equal(fixture.Metadata, normalised)).To(BeTrue(), fixture.Label)
+		}
+	})
+})
+
+var _ = Describe("Equal", func() {
+	It("compares numbers as JSON reads them", func() {
+		Expect(metadata.Equal(credentials.Metadata{"n": 1}, credentials.Metadata{"n": float64(1)})).To(BeTrue())
+	})
+
+	It("treats empty metadata as none", func() {
+		Expect(metadata.Equal(credentials.Metadata{}, nil)).To(BeTrue())
+	})
+
+	It("tells different metadata apart", func() {
+		Expect(metadata.Equal(credentials.Metadata{"a": []interface{}{"b"}}, credentials.Metadata{"a": []interface{}{"c"}})).To(BeFalse())
+	})
+})
+
+var _ = Describe("Imported", func() {
+	It("reads the metadata of every credential in an import file", func() {
+		imported, err := metadata.Imported("../bulk_import_set_with_metadata.yml")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(imported).To(HaveLen(10))
+		Expect(imported).To(HaveKeyWithValue("/director/deployment/blobstore-director1-with-metadata",
+			credentials.Metadata{"some": []interface{}{"different", "metadata"}}))
+		Expect(imported).To(HaveKeyWithValue("/director/deployment/bosh-ca1-with-metadata",
+			credentials.Metadata{"some": map[string]interface{}{"object": map[string]interface{}{"with": "data"}}}))
+	})
+
+	It("reads credentials without metadata as nil", func() {
+		imported, err := metadata.Imported("../bulk_import_set.yml")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(imported).NotTo(BeEmpty())
+		for name, m := range imported {
+			Expect(m).To(BeNil(), name)
+		}
+	})
+})
+
+var _ = Describe("Check", func() {
+	previous := credentials.Metadata{"version": "previous"}
+	sent := credentials.Metadata{"version": "sent"}
+
+	DescribeTable("checks the new version's metadata against the outcome",
+		func(outcome metadata.Outcome, got credentials.Metadata, ok bool) {
+			err := metadata.Check(outcome, previous, sent, got)
+			if ok {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(ContainSubstring("should be " + outcome.String())))
+			}
+		},
+		Entry("replaced with what was sent", metadata.Replaced, sent, true),
+		Entry("replaced, but kept", metadata.Replaced, previous, false),
+		Entry("preserved", metadata.Preserved, previous, true),
+		Entry("preserved, but cleared", metadata.Preserved, nil, false),
+		Entry("cleared", metadata.Cleared, credentials.Metadata{}, true),
+		Entry("cleared, but kept", metadata.Cleared, previous, false),
+	)
+})
+
+var _ = Describe("Unchanged", func() {
+	version := func(id string, m credentials.Metadata) credentials.Credential {
+		var credential credentials.Credential
+		credential.Id = id
+		credential.Metadata = m
+		return credential
+	}
+
+	It("passes when earlier versions keep their metadata", func() {
+		before := []credentials.Credential{version("2", credentials.Metadata{"a": "2"}), version("1", nil)}
+		after := append([]credentials.Credential{version("3", credentials.Metadata{"a": "3"})}, before...)
+		Expect(metadata.Unchanged(before, after)).To(Succeed())
+	})
+
+	It("fails when an earlier version's metadata changed", func() {
+		before := []credentials.Credential{version("1", credentials.Metadata{"a": "1"})}
+		after := []credentials.Credential{version("2", nil), version("1", credentials.Metadata{"a": "2"})}
+		Expect(metadata.Unchanged(before, after)).To(MatchError(ContainSubstring(`version 1 had metadata {"a":"1"}`)))
+	})
+
+	It("fails when an earlier version is missing", func() {
+		before := []credentials.Credential{version("1", nil)}
+		Expect(metadata.Unchanged(before, nil)).To(MatchError("version 1 is missing"))
+	})
+})
+
+var _ = Describe("FoundEntry", func() {
+	latest := credentials.Metadata{"team": "a"}
+
+	It("passes entries without metadata", func() {
+		Expect(metadata.FoundEntry(map[string]interface{}{"name": "/a", "version_created_at": "now"}, latest)).To(Succeed())
+	})
+
+	It("passes entries with the latest version's metadata", func() {
+		Expect(metadata.FoundEntry(map[string]interface{}{"name": "/a", "metadata": map[string]interface{}{"team": "a"}}, latest)).To(Succeed())
+	})
+
+	It("fails entries with other metadata", func() {
+		err := metadata.FoundEntry(map[string]interface{}{"name": "/a", "metadata": map[string]interface{}{"team": "b"}}, latest)
+		Expect(err).To(MatchError(ContainSubstring("not the latest version's")))
+	})
+
+	It("fails entries with fields find does not return", func() {
+		err := metadata.FoundEntry(map[string]interface{}{"name": "/a", "value": "secret"}, latest)
+		Expect(err).To(MatchError(`find returned the field "value"`))
+	})
+})
@@ -0,0 +1,173 @@
+// Package names builds credential names that are easy to mishandle, such as
+// names with percent-encoding, dot segments, unicode or reserved characters,
+// and checks that a client either stores each one exactly as given or
+// rejects it, without the name reaching a different credential.
+package names
+
+import (
+	"math/rand"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Outcome is what CredHub must do with a name.
+type Outcome int
+
+const (
+	// Either means CredHub may accept or reject the name, as long as every
+	// client sees the same.
+	Either Outcome = iota
+	Accept
+	Reject
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case Accept:
+		return "accept"
+	case Reject:
+		return "reject"
+	}
+	return "either"
+}
+
+// Case is a credential name and what CredHub must do with it.
+type Case struct {
+	Label   string
+	Name    string
+	Outcome Outcome
+}
+
+// MaxLength is the longest credential name CredHub stores.
+const MaxLength = 1024
+
+// Cases are the named cases under root, which must start with a slash and
+// must not end with one.
+func Cases(root string) []Case {
+	long := func(length int) string {
+		name := root + "/"
+		return name + strings.Repeat("x", length-len(name))
+	}
+	return []Case{
+		{"a plain name", root + "/plain", Accept},
+		{"a name without a leading slash", strings.TrimPrefix(root, "/") + "/no-leading-slash", Accept},
+		{"a nested path", root + "/a/b/c", Accept},
+		{"dashes, underscores and dots", root + "/some-name_with.dots", Accept},
+
+		{"latin unicode", root + "/ünïcödé", Either},
+		{"CJK unicode", root + "/日本語の名前", Either},
+		{"an emoji", root + "/🔑", Either},
+		{"a combining character", root + "/café", Either},
+		{"a right-to-left override", root + "/‮gnp.exe", Either},
+		// The CLI cannot pass a NUL byte to exec, so it reports the name as
+		// unsupported.
+		{"a NUL byte", root + "/a\x00b", Either},
+
+		{"an encoded slash", root + "/a%2Fb", Either},
+		{"an encoded space", root + "/a%20b", Either},
+		{"encoded dot segments", root + "/%2e%2e/escaped", Either},
+		{"an encoded percent sign", root + "/a%25", Either},
+		{"an incomplete escape", root + "/a%", Either},
+		{"a double encoding", root + "/a%252Fb", Either},
+
+		{"a double slash", root + "//double", Reject},
+		{"a double slash at the start", "/" + root + "/double-start", Reject},
+		{"a trailing slash", root + "/trailing/", Reject},
+		{"a backslash", root + "/a\\b", Either},
+
+		{"a dot dot segment", root + "/a/../b", Either},
+		{"a trailing dot dot segment", root + "/a/..", Either},
+		{"a dot segment", root + "/a/./b", Either},
+		{"dots that are not a segment", root + "/a/..b", Either},
+
+		{"a name of the longest length", long(MaxLength), Either},
+		{"a name over twice the longest length", long(2*MaxLength + 1), Reject},
+
+		{"an inner space", root + "/a b", Either},
+		{"a trailing space", root + "/trailing ", Either},
+		{"a leading space", root + "/ leading", Either},
+		{"a tab", root + "/a\tb", Either},
+		{"a newline", root + "/a\nb", Either},
+
+		{"a question mark", root + "/a?b=c", Either},
+		{"a hash", root + "/a#b", Either},
+		{"an ampersand and equals", root + "/a&name=b", Either},
+		{"a plus", root + "/a+b", Either},
+		{"a semicolon", root + "/a;b", Either},
+		{"an asterisk", root + "/a*", Either},
+		{"SQL wildcards", root + "/a_%", Either},
+		{"quotes", root + "/a'b\"c", Either},
+		{"brackets", root + "/a[0](1){2}<3>", Either},
+		{"other punctuation", root + "/a|b^c`d~e!f$g@h,i:j", Either},
+	}
+}
+
+// runes are what Random builds names from, favouring the characters most
+// likely to be mishandled.
+var runes = []string{
+	"a", "Z", "0", "-", "_", ".", "..", "%", "%2F", "%2e", "%25", " ", "\t",
+	"?", "#", "&", "=", "+", ";", "*", "\\", "'", "\"", "[", "]", ":", ",", "~",
+	"ü", "日", "🔑", "́", "‮",
+}
+
+// Random returns a name of one to four segments under root built from
+// characters that are easy to mishandle. Segments may be empty or dot
+// segments.
+func Random(r *rand.Rand, root string) Case {
+	count := 1 + r.Intn(4)
+	segments := []string{root}
+	for i := 0; i < count; i++ {
+		var segment strings.Builder
+		for j := r.Intn(8); j > 0; j-- {
+			segment.WriteString(runes[r.Intn(len(runes))])
+		}
+		segments = append(segments, segment.String())
+	}
+	name := strings.Join(segments, "/")
+	return Case{Label: "random name " + url.PathEscape(strings.TrimPrefix(name, root)), Name: name, Outcome: Either}
+}
+
+// Canonical is the name CredHub stores a name as: with a leading slash.
+func Canonical(name string) string {
+	if strings.HasPrefix(name, "/") {
+		return name
+	}
+	return "/" + name
+}
+
+// Aliases are other names a server that normalises or decodes names could
+// confuse a name with. None of them may reach the credential stored under
+// the name.
+func Aliases(name string) []string {
+	name = Canonical(name)
+	candidates := []string{
+		path.Clean(name),
+		strings.ReplaceAll(name, "//", "/"),
+		strings.TrimRight(name, "/"),
+		strings.TrimSpace(name),
+		strings.ReplaceAll(name, "\\", "/"),
+	}
+	if decoded, err := url.PathUnescape(name); err == nil {
+		candidates = append(candidates, decoded, path.Clean(decoded))
+	}
+	if decoded, err := url.QueryUnescape(name); err == nil {
+		candidates = append(candidates, decoded)
+	}
+	for _, cut := range []string{"?", "#", "\x00", ";"} {
+		if i := strings.Index(name, cut); i > 0 {
+			candidates = append(candidates, name[:i])
+		}
+	}
+
+	var aliases []string
+	seen := map[string]bool{name: true, "": true, "/": true}
+	for _, candidate := range candidates {
+		candidate = Canonical(candidate)
+		if !seen[candidate] {
+			seen[candidate] = true
+			aliases = append(aliases, candidate)
+		}
+	}
+	return aliases
+}
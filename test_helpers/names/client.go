@@ -0,0 +1,374 @@
+package names
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"code.cloudfoundry.org/credhub-cli/credhub/permissions"
+)
+
+// Rejected is CredHub refusing a request. Status is 0 when the client does
+// not say what the status was.
+type Rejected struct {
+	Status  int
+	Message string
+}
+
+func (r *Rejected) Error() string {
+	return r.Message
+}
+
+// ErrUnsupported is returned by a client that cannot send a name at all, so
+// CredHub never sees it.
+var ErrUnsupported = errors.New("the client cannot send the name")
+
+// Permission is a permission as CredHub stored it.
+type Permission struct {
+	UUID string
+	Path string
+}
+
+// Client is the credential and permission operations RoundTrip needs, by
+// name, through one way of talking to CredHub. Errors CredHub sends back are
+// returned as *Rejected.
+type Client interface {
+	// Set sets a value credential and returns the name it was stored as.
+	Set(name, value string) (string, error)
+	// Get returns the name and value of the latest version.
+	Get(name string) (string, string, error)
+	// Find returns the names of credentials with names like nameLike.
+	Find(nameLike string) ([]string, error)
+	Delete(name string) error
+	// AddPermission grants actor read on path.
+	AddPermission(path, actor string) (Permission, error)
+	GetPermission(path, actor string) (Permission, error)
+	DeletePermission(uuid string) error
+}
+
+// GoClient talks to CredHub through the Go client.
+type GoClient struct {
+	Client *credhub.CredHub
+}
+
+func (g GoClient) Set(name, value string) (string, error) {
+	credential, err := g.Client.SetCredential(name, "value", value)
+	return credential.Name, goClientError(err)
+}
+
+func (g GoClient) Get(name string) (string, string, error) {
+	credential, err := g.Client.GetLatestVersion(name)
+	if err != nil {
+		return "", "", goClientError(err)
+	}
+	value, _ := credential.Value.(string)
+	return credential.Name, value, nil
+}
+
+func (g GoClient) Find(nameLike string) ([]string, error) {
+	results, err := g.Client.FindByPartialName(nameLike)
+	return foundNames(results), goClientError(err)
+}
+
+func (g GoClient) Delete(name string) error {
+	return goClientError(g.Client.Delete(name))
+}
+
+func (g GoClient) AddPermission(path, actor string) (Permission, error) {
+	permission, err := g.Client.AddPermission(path, actor, []string{"read"})
+	if err != nil {
+		return Permission{}, goClientError(err)
+	}
+	return Permission{UUID: permission.UUID, Path: permission.Path}, nil
+}
+
+func (g GoClient) GetPermission(path, actor string) (Permission, error) {
+	permission, err := g.Client.GetPermissionByPathActor(path, actor)
+	if err != nil {
+		return Permission{}, goClientError(err)
+	}
+	return Permission{UUID: permission.UUID, Path: permission.Path}, nil
+}
+
+func (g GoClient) DeletePermission(uuid string) error {
+	_, err := g.Client.DeletePermission(uuid)
+	return goClientError(err)
+}
+
+// goClientError turns the Go client's errors for CredHub's responses into
+// *Rejected.
+func goClientError(err error) error {
+	var credhubErr *credhub.Error
+	var notFound *credhub.NotFoundError
+	switch {
+	case errors.As(err, &credhubErr):
+		return &Rejected{Message: credhubErr.Error()}
+	case errors.As(err, &notFound):
+		return &Rejected{Status: http.StatusNotFound, Message: notFound.Error()}
+	}
+	return err
+}
+
+// CLI talks to CredHub through the CLI. Run runs it with the given arguments
+// and returns its standard output, or an error with its standard error when
+// it exits non-zero, which CLI returns as *Rejected. Arguments with a NUL
+// byte cannot be passed to a process, so CLI returns ErrUnsupported for them.
+type CLI struct {
+	Run func(args ...string) ([]byte, error)
+}
+
+func (c CLI) Set(name, value string) (string, error) {
+	var credential credentials.Credential
+	err := c.json(&credential, "set", "-n", name, "-t", "value", "-v", value, "-j")
+	return credential.Name, err
+}
+
+func (c CLI) Get(name string) (string, string, error) {
+	var credential credentials.Credential
+	if err := c.json(&credential, "get", "-n", name, "-j"); err != nil {
+		return "", "", err
+	}
+	value, _ := credential.Value.(string)
+	return credential.Name, value, nil
+}
+
+func (c CLI) Find(nameLike string) ([]string, error) {
+	var results credentials.FindResults
+	err := c.json(&results, "find", "-n", nameLike, "-j")
+	return foundNames(results), err
+}
+
+func (c CLI) Delete(name string) error {
+	_, err := c.run("delete", "-n", name)
+	return err
+}
+
+func (c CLI) AddPermission(path, actor string) (Permission, error) {
+	var permission permissions.Permission
+	err := c.json(&permission, "set-permission", "-a", actor, "-p", path, "-o", "read", "-j")
+	return Permission{UUID: permission.UUID, Path: permission.Path}, err
+}
+
+func (c CLI) GetPermission(path, actor string) (Permission, error) {
+	var permission permissions.Permission
+	err := c.json(&permission, "get-permission", "-a", actor, "-p", path, "-j")
+	return Permission{UUID: permission.UUID, Path: permission.Path}, err
+}
+
+// DeletePermission is not offered by the CLI by UUID, so it is sent with
+// credhub curl.
+func (c CLI) DeletePermission(uuid string) error {
+	_, err := c.run("curl", "-X", "DELETE", "-p", "/api/v2/permissions/"+url.PathEscape(uuid))
+	return err
+}
+
+func (c CLI) run(args ...string) ([]byte, error) {
+	for _, arg := range args {
+		if strings.ContainsRune(arg, 0) {
+			return nil, ErrUnsupported
+		}
+	}
+	output, err := c.Run(args...)
+	if err != nil {
+		return nil, &Rejected{Message: strings.TrimSpace(err.Error())}
+	}
+	return output, nil
+}
+
+func (c CLI) json(into interface{}, args ...string) error {
+	output, err := c.run(args...)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(output, into)
+}
+
+// Escaping is how HTTP writes a name into a query string.
+type Escaping int
+
+const (
+	// Escaped escapes the name once, as url.Values does and as the CLI and
+	// the Go client send it.
+	Escaped Escaping = iota
+	// Unescaped writes the name's bytes as they are, so that a %2F, a + or an
+	// & in it reaches the server's query decoding.
+	Unescaped
+	// DoubleEscaped escapes the name twice, so that a server that decodes
+	// more than once reads the name itself.
+	DoubleEscaped
+)
+
+// Escapings are every Escaping.
+var Escapings = []Escaping{Escaped, Unescaped, DoubleEscaped}
+
+func (e Escaping) String() string {
+	switch e {
+	case Unescaped:
+		return "unescaped"
+	case DoubleEscaped:
+		return "double-escaped"
+	}
+	return "escaped"
+}
+
+// Query writes key=value into a query string.
+func (e Escaping) Query(key, value string) string {
+	switch e {
+	case Unescaped:
+		return key + "=" + value
+	case DoubleEscaped:
+		return key + "=" + url.QueryEscape(url.QueryEscape(value))
+	}
+	return key + "=" + url.QueryEscape(value)
+}
+
+// Decoded is the name a server that decodes a query string once reads from
+// what e writes for name. It is false when what is written is not a valid
+// query string, which a server may reject or read as it likes: a name with
+// a # or a character a URI cannot hold, written unescaped.
+func (e Escaping) Decoded(name string) (string, bool) {
+	switch e {
+	case Unescaped:
+		for _, b := range []byte(name) {
+			if b <= ' ' || b >= 0x7f || strings.IndexByte("#\"<>\\^`{|}", b) >= 0 {
+				return "", false
+			}
+		}
+		if i := strings.IndexByte(name, '&'); i >= 0 {
+			name = name[:i]
+		}
+		decoded, err := url.QueryUnescape(name)
+		return decoded, err == nil
+	case DoubleEscaped:
+		return url.QueryEscape(name), true
+	}
+	return name, true
+}
+
+// HTTP talks to CredHub with raw requests sent with a plain http.Client,
+// which must authenticate them, e.g. with an instrumented transport. Names
+// in request bodies are sent as they are, and names in query strings are
+// written by hand as Escaping says, so the server's own decoding decides
+// what they mean.
+type HTTP struct {
+	Client *http.Client
+	// URL is CredHub's, e.g. https://credhub.example.com:8844.
+	URL string
+	// Token is sent as a bearer token.
+	Token    string
+	Escaping Escaping
+}
+
+func (h HTTP) Set(name, value string) (string, error) {
+	var credential credentials.Credential
+	err := h.call(http.MethodPut, "/api/v1/data", "", map[string]interface{}{"name": name, "type": "value", "value": value}, &credential)
+	return credential.Name, err
+}
+
+func (h HTTP) Get(name string) (string, string, error) {
+	var response struct {
+		Data []credentials.Credential `json:"data"`
+	}
+	if err := h.call(http.MethodGet, "/api/v1/data", h.Escaping.Query("name", name)+"&current=true", nil, &response); err != nil {
+		return "", "", err
+	}
+	if len(response.Data) == 0 {
+		return "", "", fmt.Errorf("CredHub returned no versions of %q", name)
+	}
+	value, _ := response.Data[0].Value.(string)
+	return response.Data[0].Name, value, nil
+}
+
+func (h HTTP) Find(nameLike string) ([]string, error) {
+	var results credentials.FindResults
+	err := h.call(http.MethodGet, "/api/v1/data", h.Escaping.Query("name-like", nameLike), nil, &results)
+	return foundNames(results), err
+}
+
+func (h HTTP) Delete(name string) error {
+	return h.call(http.MethodDelete, "/api/v1/data", h.Escaping.Query("name", name), nil, nil)
+}
+
+func (h HTTP) AddPermission(path, actor string) (Permission, error) {
+	var permission permissions.Permission
+	body := map[string]interface{}{"path": path, "actor": actor, "operations": []string{"read"}}
+	err := h.call(http.MethodPost, "/api/v2/permissions", "", body, &permission)
+	return Permission{UUID: permission.UUID, Path: permission.Path}, err
+}
+
+func (h HTTP) GetPermission(path, actor string) (Permission, error) {
+	var permission permissions.Permission
+	query := h.Escaping.Query("path", path) + "&" + Escaped.Query("actor", actor)
+	err := h.call(http.MethodGet, "/api/v2/permissions", query, nil, &permission)
+	return Permission{UUID: permission.UUID, Path: permission.Path}, err
+}
+
+func (h HTTP) DeletePermission(uuid string) error {
+	return h.call(http.MethodDelete, "/api/v2/permissions/"+url.PathEscape(uuid), "", nil, nil)
+}
+
+// call sends the request with rawQuery as the query string, byte for byte.
+// Go refuses to send control characters in a URL, so a query with one is
+// ErrUnsupported.
+func (h HTTP) call(method, path, rawQuery string, body interface{}, into interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	request, err := http.NewRequest(method, strings.TrimSuffix(h.URL, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	for _, b := range []byte(rawQuery) {
+		if b < ' ' || b == 0x7f {
+			return ErrUnsupported
+		}
+	}
+	request.URL.RawQuery = rawQuery
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "Bearer "+h.Token)
+
+	response, err := h.Client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	contents, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode >= 300 {
+		var credhubErr credhub.Error
+		json.Unmarshal(contents, &credhubErr)
+		message := credhubErr.Error()
+		if message == "" {
+			message = strings.TrimSpace(string(contents))
+		}
+		return &Rejected{Status: response.StatusCode, Message: message}
+	}
+	if into == nil || len(contents) == 0 {
+		return nil
+	}
+	return json.Unmarshal(contents, into)
+}
+
+func foundNames(results credentials.FindResults) []string {
+	var names []string
+	for _, credential := range results.Credentials {
+		names = append(names, credential.Name)
+	}
+	return names
+}
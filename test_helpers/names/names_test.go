@@ -0,0 +1,260 @@
+package names_test
+
+import (
+	"fmt"
+	"math/rand"
+	"path"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/names"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeClient stores credentials and permissions under normalise(name),
+// rejecting names with a double slash as CredHub does.
+type fakeClient struct {
+	normalise   func(string) string
+	credentials map[string]string
+	permissions map[string]names.Permission
+	nextUUID    int
+}
+
+func newFakeClient(normalise func(string) string) *fakeClient {
+	return &fakeClient{normalise: normalise, credentials: map[string]string{}, permissions: map[string]names.Permission{}}
+}
+
+func (f *fakeClient) key(name string) (string, error) {
+	if strings.Contains(name, "//") {
+		return "", &names.Rejected{Status: 400, Message: "The name " + name + " is invalid."}
+	}
+	return f.normalise(names.Canonical(name)), nil
+}
+
+func (f *fakeClient) Set(name, value string) (string, error) {
+	key, err := f.key(name)
+	if err != nil {
+		return "", err
+	}
+	f.credentials[key] = value
+	return key, nil
+}
+
+func (f *fakeClient) Get(name string) (string, string, error) {
+	key, err := f.key(name)
+	if err != nil {
+		return "", "", err
+	}
+	value, ok := f.credentials[key]
+	if !ok {
+		return "", "", &names.Rejected{Status: 404, Message: "not found"}
+	}
+	return key, value, nil
+}
+
+func (f *fakeClient) Find(nameLike string) ([]string, error) {
+	var found []string
+	for name := range f.credentials {
+		if strings.Contains(strings.ToLower(name), strings.ToLower(nameLike)) {
+			found = append(found, name)
+		}
+	}
+	return found, nil
+}
+
+func (f *fakeClient) Delete(name string) error {
+	key, err := f.key(name)
+	if err != nil {
+		return err
+	}
+	if _, ok := f.credentials[key]; !ok {
+		return &names.Rejected{Status: 404, Message: "not found"}
+	}
+	delete(f.credentials, key)
+	return nil
+}
+
+func (f *fakeClient) AddPermission(path, actor string) (names.Permission, error) {
+	key, err := f.key(path)
+	if err != nil {
+		return names.Permission{}, err
+	}
+	f.nextUUID++
+	permission := names.Permission{UUID: fmt.Sprint(f.nextUUID), Path: key}
+	f.permissions[key+" "+actor] = permission
+	return permission, nil
+}
+
+func (f *fakeClient) GetPermission(path, actor string) (names.Permission, error) {
+	key, err := f.key(path)
+	if err != nil {
+		return names.Permission{}, err
+	}
+	permission, ok := f.permissions[key+" "+actor]
+	if !ok {
+		return names.Permission{}, &names.Rejected{Status: 404, Message: "not found"}
+	}
+	return permission, nil
+}
+
+func (f *fakeClient) DeletePermission(uuid string) error {
+	for key, permission := range f.permissions {
+		if permission.UUID == uuid {
+			delete(f.permissions, key)
+			return nil
+		}
+	}
+	return &names.Rejected{Status: 404, Message: "not found"}
+}
+
+func identity(name string) string { return name }
+
+var _ = Describe("Cases", func() {
+	It("puts every name under the root, with a leading slash or without", func() {
+		for _, c := range names.Cases("/root/1") {
+			Expect(names.Canonical(c.Name)).To(Or(HavePrefix("/root/1/"), HavePrefix("//root/1/")), c.Label)
+		}
+	})
+
+	It("builds long names of the exact lengths", func() {
+		var lengths []int
+		for _, c := range names.Cases("/root/1") {
+			if strings.Contains(c.Label, "longest length") {
+				lengths = append(lengths, len(c.Name))
+			}
+		}
+		Expect(lengths).To(Equal([]int{names.MaxLength, 2*names.MaxLength + 1}))
+	})
+
+	It("gives every case a distinct label and name", func() {
+		labels, named := map[string]bool{}, map[string]bool{}
+		for _, c := range names.Cases("/root") {
+			Expect(labels).NotTo(HaveKey(c.Label))
+			Expect(named).NotTo(HaveKey(c.Name))
+			labels[c.Label], named[c.Name] = true, true
+		}
+	})
+})
+
+var _ = Describe("Random", func() {
+	It("builds the same names from the same seed, under the root", func() {
+		first := names.Random(rand.New(rand.NewSource(5)), "/root")
+		second := names.Random(rand.New(rand.NewSource(5)), "/root")
+		Expect(first).To(Equal(second))
+		Expect(first.Name).To(HavePrefix("/root/"))
+		Expect(first.Outcome).To(Equal(names.Either))
+	})
+
+	It("builds one to four segments", func() {
+		segments := map[int]bool{}
+		for seed := int64(0); seed < 200; seed++ {
+			name := names.Random(rand.New(rand.NewSource(seed)), "/root").Name
+			segments[strings.Count(strings.TrimPrefix(name, "/root"), "/")] = true
+		}
+		Expect(segments).To(Equal(map[int]bool{1: true, 2: true, 3: true, 4: true}))
+	})
+})
+
+var _ = Describe("Aliases", func() {
+	It("includes the names a normalising server could confuse a name with", func() {
+		Expect(names.Aliases("/root/a/../b")).To(ContainElement("/root/b"))
+		Expect(names.Aliases("/root/a%2Fb")).To(ContainElement("/root/a/b"))
+		Expect(names.Aliases("/root/%2e%2e/x")).To(ContainElement("/x"))
+		Expect(names.Aliases("/root/a+b")).To(ContainElement("/root/a b"))
+		Expect(names.Aliases("/root/a?b")).To(ContainElement("/root/a"))
+		Expect(names.Aliases("/root/a ")).To(ContainElement("/root/a"))
+		Expect(names.Aliases("/root/a\\b")).To(ContainElement("/root/a/b"))
+	})
+
+	It("leaves out the name itself", func() {
+		Expect(names.Aliases("/root/plain")).To(BeEmpty())
+		Expect(names.Aliases("root/plain")).To(BeEmpty())
+	})
+})
+
+var _ = Describe("RoundTrip", func() {
+	It("accepts names a server stores as they are", func() {
+		client := newFakeClient(identity)
+		for _, c := range names.Cases("/root") {
+			if strings.Contains(c.Name, "//") {
+				continue
+			}
+			result, err := names.RoundTrip(client, c.Name, "value-"+c.Label, "mtls-app:actor")
+			Expect(err).NotTo(HaveOccurred(), c.Label)
+			Expect(result.Accepted).To(BeTrue())
+		}
+		Expect(client.credentials).To(BeEmpty())
+		Expect(client.permissions).To(BeEmpty())
+	})
+
+	It("reports a rejection without the name", func() {
+		result, err := names.RoundTrip(newFakeClient(identity), "/root//a", "value", "mtls-app:actor")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(names.Result{Rejection: "The name <name> is invalid."}))
+	})
+
+	It("catches a server that normalises dot segments", func() {
+		_, err := names.RoundTrip(newFakeClient(path.Clean), "/root/a/../b", "value", "mtls-app:actor")
+		Expect(err).To(MatchError(`stored as "/root/b", not "/root/a/../b"`))
+	})
+
+	It("catches a server that reads a name through its alias", func() {
+		plusAsSpace := newFakeClient(identity)
+		plusAsSpace.normalise = func(name string) string { return strings.ReplaceAll(name, "+", " ") }
+		_, err := names.RoundTrip(plusAsSpace, "/root/a b", "value", "mtls-app:actor")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = names.RoundTrip(plusAsSpace, "/root/a+b", "value", "mtls-app:actor")
+		Expect(err).To(MatchError(`stored as "/root/a b", not "/root/a+b"`))
+	})
+
+	It("reports a name the client cannot send as unsupported", func() {
+		cli := names.CLI{Run: func(args ...string) ([]byte, error) {
+			Fail(fmt.Sprintf("ran the CLI with %q", args))
+			return nil, nil
+		}}
+		result, err := names.RoundTrip(cli, "/root/a\x00b", "value", "mtls-app:actor")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(names.Result{Unsupported: true}))
+	})
+
+	It("catches a credential that survives a delete", func() {
+		client := &undeletable{newFakeClient(identity)}
+		_, err := names.RoundTrip(client, "/root/a", "value", "mtls-app:actor")
+		Expect(err).To(MatchError(`get returned "value" after deleting`))
+	})
+})
+
+var _ = Describe("Consistent", func() {
+	accepted := names.Result{Accepted: true}
+	rejected := names.Result{Rejection: "invalid"}
+
+	It("requires every client to see the same", func() {
+		Expect(names.Consistent(names.Case{}, map[string]names.Result{"cli": accepted, "go": accepted})).To(Succeed())
+		Expect(names.Consistent(names.Case{}, map[string]names.Result{"cli": rejected, "go": rejected})).To(Succeed())
+		Expect(names.Consistent(names.Case{}, map[string]names.Result{"cli": accepted, "go": rejected})).
+			To(MatchError(`cli accepted, but go rejected with "invalid"`))
+		Expect(names.Consistent(names.Case{}, map[string]names.Result{"cli": rejected, "go": {Rejection: "other"}})).To(HaveOccurred())
+	})
+
+	It("requires the case's outcome", func() {
+		Expect(names.Consistent(names.Case{Outcome: names.Accept}, map[string]names.Result{"go": rejected})).
+			To(MatchError(`rejected with "invalid", but it must be accepted`))
+		Expect(names.Consistent(names.Case{Outcome: names.Reject}, map[string]names.Result{"go": accepted})).
+			To(MatchError("accepted, but it must be rejected"))
+	})
+
+	It("leaves out clients that could not send the name", func() {
+		unsupported := names.Result{Unsupported: true}
+		Expect(names.Consistent(names.Case{}, map[string]names.Result{"cli": unsupported, "go": accepted, "http": accepted})).To(Succeed())
+		Expect(names.Consistent(names.Case{Outcome: names.Reject}, map[string]names.Result{"cli": unsupported, "go": accepted})).
+			To(MatchError("accepted, but it must be rejected"))
+		Expect(names.Consistent(names.Case{}, map[string]names.Result{"cli": unsupported})).To(MatchError("no client could send the name"))
+	})
+})
+
+type undeletable struct {
+	*fakeClient
+}
+
+func (u *undeletable) Delete(string) error { return nil }
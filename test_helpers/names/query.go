@@ -0,0 +1,77 @@
+package names
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ProbeQuery checks how CredHub decodes names in query strings. It sets
+// value under name in a request body, where nothing is decoded, and grants
+// actor read on it. Then, through each Escaping, it gets the credential and
+// the permission by name, and deletes by name where that must not reach the
+// credential. When Escaping.Decoded says what a server that decodes once
+// reads, the credential must be reached exactly when that is its name.
+// Otherwise CredHub may reject the request or read it as it likes. It
+// returns what each Escaping saw of the get, or nil when CredHub does not
+// store the name at all.
+func ProbeQuery(client HTTP, name, value, actor string) (map[Escaping]Result, error) {
+	canonical := Canonical(name)
+	client.Escaping = Escaped
+
+	var rejected *Rejected
+	if _, err := client.Set(name, value); errors.As(err, &rejected) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("setting: %s", err)
+	}
+	defer client.Delete(name)
+	permission, err := client.AddPermission(name, actor)
+	if err != nil {
+		return nil, fmt.Errorf("adding a permission: %s", err)
+	}
+	defer client.DeletePermission(permission.UUID)
+
+	results := map[Escaping]Result{}
+	for _, escaping := range Escapings {
+		probe := client
+		probe.Escaping = escaping
+		decoded, valid := escaping.Decoded(name)
+		reaches := valid && Canonical(decoded) == canonical
+
+		gotName, got, err := probe.Get(name)
+		switch {
+		case err == nil:
+			results[escaping] = Result{Accepted: true}
+		case errors.Is(err, ErrUnsupported):
+			results[escaping] = Result{Unsupported: true}
+		case errors.As(err, &rejected):
+			results[escaping] = Result{Rejection: rejected.Message}
+		default:
+			return nil, fmt.Errorf("getting %s: %s", escaping, err)
+		}
+		switch {
+		case err == nil && reaches && (gotName != canonical || got != value):
+			return nil, fmt.Errorf("getting %s returned %q = %q, not %q = %q", escaping, gotName, got, canonical, value)
+		case err == nil && valid && !reaches && got == value:
+			return nil, fmt.Errorf("getting %s read the credential, but the query decodes to %q", escaping, decoded)
+		case err != nil && escaping == Escaped:
+			return nil, fmt.Errorf("getting %s: %s", escaping, err)
+		}
+
+		granted, err := probe.GetPermission(name, actor)
+		switch {
+		case err == nil && reaches && granted.UUID != permission.UUID:
+			return nil, fmt.Errorf("getting the permission %s returned %s, not %s", escaping, granted.UUID, permission.UUID)
+		case err == nil && valid && !reaches && granted.UUID == permission.UUID:
+			return nil, fmt.Errorf("getting the permission %s found it, but the query decodes to %q", escaping, decoded)
+		}
+
+		if valid && !reaches {
+			probe.Delete(name)
+			if _, got, err := client.Get(name); err != nil || got != value {
+				return nil, fmt.Errorf("deleting %s removed the credential, but the query decodes to %q", escaping, decoded)
+			}
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,205 @@
+package names_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/names"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeServer serves the credential and permission endpoints HTTP uses,
+// reading names from query strings with decode.
+type fakeServer struct {
+	decode      func(string) (string, error)
+	credentials map[string]string
+	permissions map[string]string
+	queries     []string
+	tokens      []string
+}
+
+func newFakeServer(decode func(string) (string, error)) *fakeServer {
+	return &fakeServer{decode: decode, credentials: map[string]string{}, permissions: map[string]string{}}
+}
+
+func (f *fakeServer) query(r *http.Request, key string) string {
+	for _, pair := range strings.Split(r.URL.RawQuery, "&") {
+		if strings.HasPrefix(pair, key+"=") {
+			value, err := f.decode(strings.TrimPrefix(pair, key+"="))
+			if err == nil {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.queries = append(f.queries, r.URL.RawQuery)
+	f.tokens = append(f.tokens, r.Header.Get("Authorization"))
+	var body map[string]interface{}
+	json.NewDecoder(r.Body).Decode(&body)
+	respond := func(status int, response interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(response)
+	}
+	notFound := map[string]string{"error": "not found"}
+
+	switch {
+	case r.Method == http.MethodPut && r.URL.Path == "/api/v1/data":
+		name := names.Canonical(body["name"].(string))
+		f.credentials[name] = body["value"].(string)
+		respond(http.StatusOK, map[string]interface{}{"name": name, "type": "value", "value": body["value"]})
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v1/data":
+		name := names.Canonical(f.query(r, "name"))
+		if value, ok := f.credentials[name]; ok {
+			respond(http.StatusOK, map[string]interface{}{"data": []interface{}{map[string]interface{}{"name": name, "type": "value", "value": value}}})
+		} else {
+			respond(http.StatusNotFound, notFound)
+		}
+	case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/data":
+		name := names.Canonical(f.query(r, "name"))
+		if _, ok := f.credentials[name]; ok {
+			delete(f.credentials, name)
+			w.WriteHeader(http.StatusNoContent)
+		} else {
+			respond(http.StatusNotFound, notFound)
+		}
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v2/permissions":
+		path := names.Canonical(body["path"].(string))
+		f.permissions[path] = fmt.Sprintf("uuid-%d", len(f.queries))
+		respond(http.StatusCreated, map[string]interface{}{"path": path, "actor": body["actor"], "uuid": f.permissions[path]})
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v2/permissions":
+		path := names.Canonical(f.query(r, "path"))
+		if uuid, ok := f.permissions[path]; ok {
+			respond(http.StatusOK, map[string]interface{}{"path": path, "actor": f.query(r, "actor"), "uuid": uuid})
+		} else {
+			respond(http.StatusNotFound, notFound)
+		}
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/api/v2/permissions/"):
+		for path, uuid := range f.permissions {
+			if uuid == strings.TrimPrefix(r.URL.Path, "/api/v2/permissions/") {
+				delete(f.permissions, path)
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		respond(http.StatusNotFound, notFound)
+	}
+}
+
+var _ = Describe("Escaping", func() {
+	DescribeTable("writes and reads names",
+		func(escaping names.Escaping, name, query, decoded string, valid bool) {
+			Expect(escaping.Query("name", name)).To(Equal("name=" + query))
+			got, ok := escaping.Decoded(name)
+			Expect(ok).To(Equal(valid))
+			if valid {
+				Expect(got).To(Equal(decoded))
+			}
+		},
+		Entry("escaped", names.Escaped, "/a%2Fb c", "%2Fa%252Fb+c", "/a%2Fb c", true),
+		Entry("unescaped percent-encoding", names.Unescaped, "/a%2Fb", "/a%2Fb", "/a/b", true),
+		Entry("unescaped plus", names.Unescaped, "/a+b", "/a+b", "/a b", true),
+		Entry("unescaped ampersand", names.Unescaped, "/a&b=c", "/a&b=c", "/a", true),
+		Entry("unescaped semicolon and dot segments", names.Unescaped, "/a/../b;c", "/a/../b;c", "/a/../b;c", true),
+		Entry("unescaped fragment", names.Unescaped, "/a#b", "/a#b", "", false),
+		Entry("unescaped space", names.Unescaped, "/a b", "/a b", "", false),
+		Entry("unescaped unicode", names.Unescaped, "/ü", "/ü", "", false),
+		Entry("unescaped bad escape", names.Unescaped, "/a%zz", "/a%zz", "", false),
+		Entry("double-escaped", names.DoubleEscaped, "/a%2Fb", "%252Fa%25252Fb", "%2Fa%252Fb", true),
+	)
+})
+
+var _ = Describe("HTTP", func() {
+	It("sends the query string byte for byte with the token", func() {
+		fake := newFakeServer(url.QueryUnescape)
+		server := httptest.NewServer(fake)
+		defer server.Close()
+
+		client := names.HTTP{Client: server.Client(), URL: server.URL + "/", Token: "token", Escaping: names.Unescaped}
+		client.Get("/a%2Fb;c")
+		client.Escaping = names.DoubleEscaped
+		client.Get("/a%2Fb;c")
+		Expect(fake.queries).To(Equal([]string{"name=/a%2Fb;c&current=true", "name=%252Fa%25252Fb%253Bc&current=true"}))
+		Expect(fake.tokens).To(ConsistOf("Bearer token", "Bearer token"))
+	})
+
+	It("does not send control characters", func() {
+		client := names.HTTP{Client: http.DefaultClient, URL: "http://127.0.0.1:1", Escaping: names.Unescaped}
+		_, _, err := client.Get("/a\nb")
+		Expect(err).To(Equal(names.ErrUnsupported))
+	})
+})
+
+var _ = Describe("ProbeQuery", func() {
+	probe := func(decode func(string) (string, error), name string) (map[names.Escaping]names.Result, *fakeServer, error) {
+		fake := newFakeServer(decode)
+		server := httptest.NewServer(fake)
+		DeferCleanup(server.Close)
+		results, err := names.ProbeQuery(names.HTTP{Client: server.Client(), URL: server.URL}, name, "value", "mtls-app:actor")
+		return results, fake, err
+	}
+
+	It("passes a server that decodes query strings once", func() {
+		for _, name := range []string{"/root/plain", "/root/a%2Fb", "/root/a+b", "/root/a;b", "/root/a/../b", "/root/a&b", "/root/a#b", "/root/ü"} {
+			results, fake, err := probe(url.QueryUnescape, name)
+			Expect(err).NotTo(HaveOccurred(), name)
+			Expect(results).To(HaveLen(3))
+			Expect(results[names.Escaped]).To(Equal(names.Result{Accepted: true}))
+			Expect(fake.credentials).To(BeEmpty())
+			Expect(fake.permissions).To(BeEmpty())
+		}
+	})
+
+	It("reports what each escaping saw", func() {
+		results, _, err := probe(url.QueryUnescape, "/root/a%2Fb")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(Equal(map[names.Escaping]names.Result{
+			names.Escaped:       {Accepted: true},
+			names.Unescaped:     {Rejection: "not found"},
+			names.DoubleEscaped: {Rejection: "not found"},
+		}))
+	})
+
+	It("catches a server that decodes twice", func() {
+		twice := func(query string) (string, error) {
+			once, err := url.QueryUnescape(query)
+			if err != nil {
+				return "", err
+			}
+			return url.QueryUnescape(once)
+		}
+		_, _, err := probe(twice, "/root/a%2Fb")
+		Expect(err).To(MatchError(ContainSubstring("getting escaped")))
+
+		_, _, err = probe(twice, "/root/a")
+		Expect(err).To(MatchError(ContainSubstring(`getting double-escaped read the credential, but the query decodes to "%2Froot%2Fa"`)))
+	})
+
+	It("catches a server that does not decode", func() {
+		_, _, err := probe(func(query string) (string, error) { return query, nil }, "/root/a%2Fb")
+		Expect(err).To(MatchError("getting escaped: not found"))
+	})
+
+	It("does not probe a name CredHub will not store", func() {
+		fake := newFakeServer(url.QueryUnescape)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPut {
+				http.Error(w, `{"error": "The name is invalid."}`, http.StatusBadRequest)
+				return
+			}
+			fake.ServeHTTP(w, r)
+		}))
+		defer server.Close()
+		results, err := names.ProbeQuery(names.HTTP{Client: server.Client(), URL: server.URL}, "/root//a", "value", "mtls-app:actor")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(BeNil())
+	})
+})
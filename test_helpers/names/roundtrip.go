@@ -0,0 +1,164 @@
+package names
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Result is what a client saw CredHub do with a name.
+type Result struct {
+	Accepted bool
+	// Rejection is CredHub's message when it rejected the name.
+	Rejection string
+	// Unsupported is whether the client could not send the name.
+	Unsupported bool
+}
+
+func (r Result) String() string {
+	if r.Accepted {
+		return "accepted"
+	}
+	if r.Unsupported {
+		return "could not send it"
+	}
+	return fmt.Sprintf("rejected with %q", r.Rejection)
+}
+
+// RoundTrip sets value under name and checks that CredHub either stores it
+// under the canonical name, or rejects it and stores nothing. When it is
+// stored, it checks that the credential can be read, found, given a
+// permission and deleted by that name, and that none of the name's aliases
+// reach it. value must be unique to the call. A name the client cannot send
+// is reported as Unsupported.
+func RoundTrip(client Client, name, value, actor string) (Result, error) {
+	canonical := Canonical(name)
+
+	stored, err := client.Set(name, value)
+	if errors.Is(err, ErrUnsupported) {
+		return Result{Unsupported: true}, nil
+	}
+	var rejected *Rejected
+	if errors.As(err, &rejected) {
+		if _, got, err := client.Get(name); err == nil && got == value {
+			return Result{}, fmt.Errorf("set was rejected with %q, but the value was stored", rejected.Message)
+		}
+		// Messages that quote the name are compared without it, as each client
+		// tries the name under a root of its own.
+		message := strings.ReplaceAll(rejected.Message, canonical, "<name>")
+		return Result{Rejection: strings.ReplaceAll(message, name, "<name>")}, nil
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("setting: %s", err)
+	}
+	defer client.Delete(name)
+
+	if stored != canonical {
+		return Result{}, fmt.Errorf("stored as %q, not %q", stored, canonical)
+	}
+
+	gotName, got, err := client.Get(name)
+	if err != nil {
+		return Result{}, fmt.Errorf("getting: %s", err)
+	}
+	if gotName != canonical || got != value {
+		return Result{}, fmt.Errorf("get returned %q = %q, not %q = %q", gotName, got, canonical, value)
+	}
+
+	for _, alias := range Aliases(name) {
+		if _, got, err := client.Get(alias); err == nil && got == value {
+			return Result{}, fmt.Errorf("the credential can be read as %q", alias)
+		}
+	}
+
+	found, err := client.Find(canonical)
+	if err != nil {
+		return Result{}, fmt.Errorf("finding: %s", err)
+	}
+	if !contains(found, canonical) {
+		return Result{}, fmt.Errorf("find returned %s, without the credential", quoteAll(found))
+	}
+
+	if err := roundTripPermission(client, name, canonical, actor); err != nil {
+		return Result{}, err
+	}
+
+	if err := client.Delete(name); err != nil {
+		return Result{}, fmt.Errorf("deleting: %s", err)
+	}
+	if _, got, err := client.Get(name); err == nil {
+		return Result{}, fmt.Errorf("get returned %q after deleting", got)
+	}
+	return Result{Accepted: true}, nil
+}
+
+func roundTripPermission(client Client, name, canonical, actor string) error {
+	permission, err := client.AddPermission(name, actor)
+	if err != nil {
+		return fmt.Errorf("adding a permission: %s", err)
+	}
+	defer client.DeletePermission(permission.UUID)
+	if permission.Path != canonical {
+		return fmt.Errorf("permission stored for %q, not %q", permission.Path, canonical)
+	}
+
+	got, err := client.GetPermission(name, actor)
+	if err != nil {
+		return fmt.Errorf("getting the permission: %s", err)
+	}
+	if got.UUID != permission.UUID || got.Path != canonical {
+		return fmt.Errorf("got permission %s for %q, not %s for %q", got.UUID, got.Path, permission.UUID, canonical)
+	}
+
+	for _, alias := range Aliases(name) {
+		if got, err := client.GetPermission(alias, actor); err == nil && got.UUID == permission.UUID {
+			return fmt.Errorf("the permission can be read as %q", alias)
+		}
+	}
+	return nil
+}
+
+// Consistent checks that every client that could send the name saw the same
+// result, and that it is the outcome the case requires. results are by
+// client. It fails when no client could send the name.
+func Consistent(c Case, results map[string]Result) error {
+	var first string
+	for client, result := range results {
+		if !result.Unsupported && (first == "" || client < first) {
+			first = client
+		}
+	}
+	if first == "" {
+		return errors.New("no client could send the name")
+	}
+	for client, result := range results {
+		if !result.Unsupported && result != results[first] {
+			return fmt.Errorf("%s %s, but %s %s", first, results[first], client, result)
+		}
+	}
+
+	switch {
+	case c.Outcome == Accept && !results[first].Accepted:
+		return fmt.Errorf("%s, but it must be accepted", results[first])
+	case c.Outcome == Reject && results[first].Accepted:
+		return errors.New("accepted, but it must be rejected")
+	}
+	return nil
+}
+
+func contains(names []string, name string) bool {
+	for _, candidate := range names {
+		if candidate == name {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteAll(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = fmt.Sprintf("%q", name)
+	}
+	return "[" + strings.Join(quoted, " ") + "]"
+}
@@ -0,0 +1,253 @@
+package parity
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/reporting"
+)
+
+// Kind is how a candidate backend's response compares with the built-in
+// backend's. Kinds are ordered: an endpoint is reported with the most
+// severe kind of any of its steps.
+type Kind int
+
+const (
+	Same Kind = iota
+	NotCompared
+	DifferentError
+	Different
+	Unimplemented
+)
+
+var kindNames = map[Kind]string{
+	Same:           "same",
+	NotCompared:    "not compared",
+	DifferentError: "different error",
+	Different:      "different",
+	Unimplemented:  "unimplemented",
+}
+
+func (k Kind) String() string {
+	return kindNames[k]
+}
+
+func (k Kind) MarshalJSON() ([]byte, error) {
+	return json.Marshal(k.String())
+}
+
+func (k *Kind) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	for kind, kindName := range kindNames {
+		if kindName == name {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown kind %q", name)
+}
+
+// NotImplementedMessage is the error CredHub returns for endpoints a backend
+// does not support.
+const NotImplementedMessage = "This resource has not been implemented for this backend."
+
+// Finding is how one step compared.
+type Finding struct {
+	Step     string             `json:"step"`
+	Endpoint reporting.Endpoint `json:"endpoint"`
+	Kind     Kind               `json:"kind"`
+	Detail   string             `json:"detail,omitempty"`
+}
+
+// EndpointParity is how an endpoint compared over every step that reached
+// it.
+type EndpointParity struct {
+	Endpoint reporting.Endpoint `json:"endpoint"`
+	Kind     Kind               `json:"kind"`
+	Steps    []string           `json:"steps"`
+}
+
+// Report compares a candidate backend with the built-in one.
+type Report struct {
+	Baseline  string           `json:"baseline"`
+	Candidate string           `json:"candidate"`
+	Findings  []Finding        `json:"findings"`
+	Endpoints []EndpointParity `json:"endpoints"`
+	// NotProbed lists catalogued endpoints no step reached.
+	NotProbed []reporting.Endpoint `json:"not_probed,omitempty"`
+}
+
+// Compare compares two runs of a scenario, step by step.
+func Compare(scenario []Step, baseline, candidate Run) Report {
+	report := Report{Baseline: baseline.Target, Candidate: candidate.Target}
+	byEndpoint := map[reporting.Endpoint]*EndpointParity{}
+	var order []reporting.Endpoint
+
+	for i, step := range scenario {
+		finding := compareStep(step, baseline, candidate, i)
+		report.Findings = append(report.Findings, finding)
+
+		parity, ok := byEndpoint[finding.Endpoint]
+		if !ok {
+			parity = &EndpointParity{Endpoint: finding.Endpoint}
+			byEndpoint[finding.Endpoint] = parity
+			order = append(order, finding.Endpoint)
+		}
+		parity.Steps = append(parity.Steps, step.Name)
+		if finding.Kind > parity.Kind {
+			parity.Kind = finding.Kind
+		}
+	}
+
+	for _, endpoint := range order {
+		report.Endpoints = append(report.Endpoints, *byEndpoint[endpoint])
+	}
+	for _, endpoint := range reporting.Catalogue() {
+		if _, ok := byEndpoint[endpoint]; !ok {
+			report.NotProbed = append(report.NotProbed, endpoint)
+		}
+	}
+	return report
+}
+
+func compareStep(step Step, baseline, candidate Run, i int) Finding {
+	b, c := baseline.Results[i], candidate.Results[i]
+	finding := Finding{Step: step.Name, Endpoint: b.Endpoint}
+
+	switch {
+	case b.Err != "":
+		finding.Kind, finding.Detail = NotCompared, baseline.Target+": "+b.Err
+		return finding
+	case notImplemented(c) && !notImplemented(b):
+		finding.Kind = Unimplemented
+		return finding
+	case c.Err != "":
+		finding.Kind, finding.Detail = NotCompared, candidate.Target+": "+c.Err
+		return finding
+	}
+
+	bBody := Normalize(b.Body, baseline.Root, baseline.RootID, step.Mask)
+	cBody := Normalize(c.Body, candidate.Root, candidate.RootID, step.Mask)
+	bFailed, cFailed := b.Status >= 400, c.Status >= 400
+
+	switch {
+	case bFailed && cFailed:
+		if b.Status != c.Status {
+			finding.Kind, finding.Detail = DifferentError, fmt.Sprintf("status %d, got %d", b.Status, c.Status)
+		} else if d := Diff(bBody, cBody); d != "" {
+			finding.Kind, finding.Detail = DifferentError, d
+		}
+	case b.Status != c.Status:
+		finding.Kind, finding.Detail = Different, fmt.Sprintf("status %d, got %d", b.Status, c.Status)
+	default:
+		if d := Diff(bBody, cBody); d != "" {
+			finding.Kind, finding.Detail = Different, d
+		}
+	}
+	return finding
+}
+
+func notImplemented(result Result) bool {
+	return result.Status == http.StatusNotImplemented || strings.Contains(string(result.Body), NotImplementedMessage)
+}
+
+// Unimplemented lists the endpoints the candidate does not implement.
+func (r Report) Unimplemented() []reporting.Endpoint {
+	return r.endpoints(Unimplemented)
+}
+
+// Unimplemented lists the endpoints the server answered as not implemented
+// for any step, found without a baseline to compare with.
+func (r Run) Unimplemented() []reporting.Endpoint {
+	seen := map[reporting.Endpoint]bool{}
+	var endpoints []reporting.Endpoint
+	for _, result := range r.Results {
+		if result.Err == "" && notImplemented(result) && !seen[result.Endpoint] {
+			seen[result.Endpoint] = true
+			endpoints = append(endpoints, result.Endpoint)
+		}
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].String() < endpoints[j].String()
+	})
+	return endpoints
+}
+
+func (r Report) endpoints(kind Kind) []reporting.Endpoint {
+	var endpoints []reporting.Endpoint
+	for _, parity := range r.Endpoints {
+		if parity.Kind == kind {
+			endpoints = append(endpoints, parity.Endpoint)
+		}
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].String() < endpoints[j].String()
+	})
+	return endpoints
+}
+
+// WriteText writes the endpoints that are unimplemented, behave differently
+// or fail differently, with the step findings that explain them.
+func (r Report) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s compared with %s\n", r.Candidate, r.Baseline)
+
+	for _, kind := range []Kind{Unimplemented, Different, DifferentError, NotCompared} {
+		endpoints := r.endpoints(kind)
+		if len(endpoints) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s:\n", kind)
+		for _, endpoint := range endpoints {
+			fmt.Fprintf(&b, "  %s\n", endpoint)
+			if kind == Unimplemented {
+				continue
+			}
+			for _, finding := range r.Findings {
+				if finding.Endpoint == endpoint && finding.Kind == kind {
+					fmt.Fprintf(&b, "    %s: %s\n", finding.Step, finding.Detail)
+				}
+			}
+		}
+	}
+	if same := r.endpoints(Same); len(same) > 0 {
+		fmt.Fprintf(&b, "\nsame: %d endpoints\n", len(same))
+	}
+	if len(r.NotProbed) > 0 {
+		fmt.Fprintf(&b, "\nnot probed:\n")
+		for _, endpoint := range r.NotProbed {
+			fmt.Fprintf(&b, "  %s\n", endpoint)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ReadFile reads a report WriteFile wrote.
+func ReadFile(path string) (Report, error) {
+	var report Report
+	encoded, err := os.ReadFile(path)
+	if err != nil {
+		return report, err
+	}
+	err = json.Unmarshal(encoded, &report)
+	return report, err
+}
+
+// WriteFile writes the report to path as JSON.
+func (r Report) WriteFile(path string) error {
+	encoded, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, encoded, 0644)
+}
@@ -0,0 +1,174 @@
+package parity
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var uuidPattern = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+
+// Normalize parses a response body and replaces everything that differs
+// between runs, or between servers, with placeholders: the run's root and
+// root id, uuids, timestamps and certificates, and every string beneath a
+// masked key. Bodies that are not JSON are compared as text.
+func Normalize(body []byte, root, rootID string, mask []string) interface{} {
+	n := normalizer{root: root, rootID: rootID, mask: map[string]bool{}}
+	for _, key := range mask {
+		n.mask[key] = true
+	}
+
+	var parsed interface{}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return n.string(strings.TrimSpace(string(body)))
+	}
+	return n.value(parsed, false)
+}
+
+type normalizer struct {
+	root, rootID string
+	mask         map[string]bool
+}
+
+func (n normalizer) value(v interface{}, masked bool) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			normalized[key] = n.value(value, masked || n.mask[key])
+		}
+		return normalized
+	case []interface{}:
+		normalized := make([]interface{}, len(v))
+		for i, value := range v {
+			normalized[i] = n.value(value, masked)
+		}
+		return normalized
+	case string:
+		if masked {
+			return "<masked>"
+		}
+		return n.string(v)
+	default:
+		return v
+	}
+}
+
+func (n normalizer) string(s string) string {
+	if strings.Contains(s, "-----BEGIN ") {
+		return "<pem>"
+	}
+	if _, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return "<time>"
+	}
+	if n.root != "" {
+		s = strings.ReplaceAll(s, n.root, "<root>")
+	}
+	if n.rootID != "" {
+		s = strings.ReplaceAll(s, n.rootID, "<root-id>")
+	}
+	return uuidPattern.ReplaceAllString(s, "<uuid>")
+}
+
+// Diff describes the first difference between two normalized bodies, or
+// returns "" when they are equal.
+func Diff(baseline, candidate interface{}) string {
+	return diff("$", baseline, candidate)
+}
+
+func diff(path string, a, b interface{}) string {
+	switch a := a.(type) {
+	case map[string]interface{}:
+		bMap, ok := b.(map[string]interface{})
+		if !ok {
+			return fmt.Sprintf("%s: %s, got %s", path, describe(a), describe(b))
+		}
+		keys := map[string]bool{}
+		for key := range a {
+			keys[key] = true
+		}
+		for key := range bMap {
+			keys[key] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for key := range keys {
+			sorted = append(sorted, key)
+		}
+		sort.Strings(sorted)
+		for _, key := range sorted {
+			aValue, inA := a[key]
+			bValue, inB := bMap[key]
+			switch {
+			case !inB:
+				return fmt.Sprintf("%s.%s: missing", path, key)
+			case !inA:
+				return fmt.Sprintf("%s.%s: unexpected %s", path, key, describe(bValue))
+			}
+			if d := diff(path+"."+key, aValue, bValue); d != "" {
+				return d
+			}
+		}
+		return ""
+	case []interface{}:
+		bSlice, ok := b.([]interface{})
+		if !ok {
+			return fmt.Sprintf("%s: %s, got %s", path, describe(a), describe(b))
+		}
+		if len(a) != len(bSlice) {
+			return fmt.Sprintf("%s: %d elements, got %d", path, len(a), len(bSlice))
+		}
+		for i := range a {
+			if d := diff(path+"["+strconv.Itoa(i)+"]", a[i], bSlice[i]); d != "" {
+				return d
+			}
+		}
+		return ""
+	default:
+		if !reflect.DeepEqual(a, b) {
+			return fmt.Sprintf("%s: %s, got %s", path, describe(a), describe(b))
+		}
+		return ""
+	}
+}
+
+func describe(v interface{}) string {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return "an object"
+	case []interface{}:
+		return "an array"
+	case nil:
+		return "null"
+	default:
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	}
+}
+
+// lookup finds the value at a dotted JSON path, e.g. "certificates.0.id".
+func lookup(v interface{}, path string) (interface{}, bool) {
+	for _, segment := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			value, ok := node[segment]
+			if !ok {
+				return nil, false
+			}
+			v = value
+		case []interface{}:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			v = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
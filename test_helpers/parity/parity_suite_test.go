@@ -0,0 +1,13 @@
+package parity_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestParity(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Parity Suite")
+}
@@ -0,0 +1,246 @@
+package parity_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/parity"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/reporting"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   string
+}
+
+// fakeClient answers each request with respond, and records it.
+type fakeClient struct {
+	requests []request
+	respond  func(request) (int, string)
+}
+
+func (c *fakeClient) Request(method string, path string, query url.Values, body interface{}, checkServerErr bool) (*http.Response, error) {
+	req := request{method: method, path: path, query: query}
+	if body != nil {
+		encoded, _ := json.Marshal(body)
+		req.body = string(encoded)
+	}
+	c.requests = append(c.requests, req)
+
+	status, responseBody := http.StatusOK, "{}"
+	if c.respond != nil {
+		status, responseBody = c.respond(req)
+	}
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(responseBody))}, nil
+}
+
+var _ = Describe("Normalize", func() {
+	It("replaces what differs between runs", func() {
+		body := `{
+			"id": "6f2f4d4e-1f3b-4c1e-9a63-7a5a7b0c1d2e",
+			"name": "/parity/root-id/value",
+			"version_created_at": "2019-01-01T00:00:00Z",
+			"value": {"certificate": "-----BEGIN CERTIFICATE-----\nabc\n-----END CERTIFICATE-----", "generated": 3},
+			"names": ["root-id"],
+			"type": "value"
+		}`
+		Expect(parity.Normalize([]byte(body), "/parity/root-id", "root-id", nil)).To(Equal(map[string]interface{}{
+			"id":                 "<uuid>",
+			"name":               "<root>/value",
+			"version_created_at": "<time>",
+			"value":              map[string]interface{}{"certificate": "<pem>", "generated": 3.0},
+			"names":              []interface{}{"<root-id>"},
+			"type":               "value",
+		}))
+	})
+
+	It("masks every string beneath a masked key", func() {
+		body := `{"value": {"username": "a", "password": "b", "length": 3}, "type": "user"}`
+		Expect(parity.Normalize([]byte(body), "", "", []string{"value"})).To(Equal(map[string]interface{}{
+			"value": map[string]interface{}{"username": "<masked>", "password": "<masked>", "length": 3.0},
+			"type":  "user",
+		}))
+	})
+
+	It("compares other bodies as text", func() {
+		Expect(parity.Normalize([]byte(" not json\n"), "", "", nil)).To(Equal("not json"))
+	})
+})
+
+var _ = Describe("Diff", func() {
+	diff := func(a, b string) string {
+		return parity.Diff(parity.Normalize([]byte(a), "", "", nil), parity.Normalize([]byte(b), "", "", nil))
+	}
+
+	It("describes the first difference", func() {
+		Expect(diff(`{"a": [1, {"b": "x"}]}`, `{"a": [1, {"b": "x"}]}`)).To(BeEmpty())
+		Expect(diff(`{"a": [1, {"b": "x"}]}`, `{"a": [1, {"b": "y"}]}`)).To(Equal(`$.a[1].b: "x", got "y"`))
+		Expect(diff(`{"a": 1}`, `{}`)).To(Equal(`$.a: missing`))
+		Expect(diff(`{}`, `{"error": "e"}`)).To(Equal(`$.error: unexpected "e"`))
+		Expect(diff(`{"a": [1]}`, `{"a": [1, 2]}`)).To(Equal(`$.a: 1 elements, got 2`))
+		Expect(diff(`{"a": {}}`, `{"a": null}`)).To(Equal(`$.a: an object, got null`))
+	})
+})
+
+var _ = Describe("Execute", func() {
+	It("substitutes the root and captured variables", func() {
+		client := &fakeClient{respond: func(req request) (int, string) {
+			if req.method == "PUT" {
+				return http.StatusOK, `{"id": "6f2f4d4e-1f3b-4c1e-9a63-7a5a7b0c1d2e", "value": {"key": "v"}}`
+			}
+			return http.StatusOK, `{}`
+		}}
+		scenario := []parity.Step{
+			{Name: "set", Method: "PUT", Path: "/api/v1/data", Body: `{"name": "${root}/json"}`,
+				Capture: map[string]string{"id": "id", "value": "value"}},
+			{Name: "get", Method: "GET", Path: "/api/v1/data/${id}?name=${root}/json"},
+			{Name: "set again", Method: "PUT", Path: "/api/v1/data", Body: `{"value": ${value}}`},
+			{Name: "missing", Method: "GET", Path: "/api/v1/certificates/${certificate-id}/versions"},
+		}
+
+		run := parity.Execute("fake", client, scenario)
+		Expect(run.Root).To(Equal("/parity/" + run.RootID))
+		Expect(run.Results).To(HaveLen(4))
+
+		Expect(client.requests[0].body).To(Equal(`{"name":"` + run.Root + `/json"}`))
+		Expect(client.requests[1].path).To(Equal("/api/v1/data/6f2f4d4e-1f3b-4c1e-9a63-7a5a7b0c1d2e"))
+		Expect(client.requests[1].query.Get("name")).To(Equal(run.Root + "/json"))
+		Expect(client.requests[2].body).To(Equal(`{"value":{"key":"v"}}`))
+
+		Expect(run.Results[1].Endpoint).To(Equal(reporting.Endpoint{Method: "GET", Path: "/api/v1/data/{id}", Operation: "get-by-id"}))
+		Expect(run.Results[3].Err).To(Equal("no certificate-id was captured by an earlier step"))
+		Expect(run.Results[3].Endpoint).To(Equal(reporting.Endpoint{Method: "GET", Path: "/api/v1/certificates/{id}/versions"}))
+
+		// The step with a missing variable is not sent, and the run ends by
+		// finding what is left under its root.
+		Expect(client.requests).To(HaveLen(4))
+		Expect(client.requests[3].query.Get("path")).To(Equal(run.Root))
+	})
+
+	It("reaches every catalogued endpoint with the default scenario", func() {
+		run := parity.Execute("fake", &fakeClient{}, parity.DefaultScenario)
+		report := parity.Compare(parity.DefaultScenario, run, run)
+		Expect(report.NotProbed).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Compare", func() {
+	scenario := []parity.Step{
+		{Name: "get", Method: "GET", Path: "/api/v1/data?name=${root}/value"},
+		{Name: "get missing", Method: "GET", Path: "/api/v1/data?name=${root}/missing"},
+		{Name: "interpolate", Method: "POST", Path: "/api/v1/interpolate", Body: `{}`},
+		{Name: "key usage", Method: "GET", Path: "/api/v1/key-usage"},
+		{Name: "set", Method: "PUT", Path: "/api/v1/data", Body: `{}`},
+		{Name: "info", Method: "GET", Path: "/info"},
+	}
+	baselineResponses := map[string]string{
+		"/api/v1/data GET":      `{"name": "${root}/value", "value": "v"}`,
+		"/api/v1/interpolate":   `{"service": []}`,
+		"/api/v1/key-usage":     `{"matching_key_usage": 1}`,
+		"/api/v1/data PUT":      `{"name": "${root}/value"}`,
+		"/info":                 `{"app": {"name": "CredHub"}}`,
+		"/api/v1/data? missing": `{"error": "The request could not be completed because the credential does not exist or you do not have sufficient authorization."}`,
+	}
+
+	respond := func(overrides map[string]string, statuses map[string]int) func(req request) (int, string) {
+		return func(req request) (int, string) {
+			key := req.path
+			switch {
+			case strings.HasSuffix(req.query.Get("name"), "/missing"):
+				key = "/api/v1/data? missing"
+			case req.path == "/api/v1/data":
+				key = req.path + " " + req.method
+			case req.query.Get("path") != "":
+				return http.StatusOK, `{"credentials": []}`
+			}
+			body := baselineResponses[key]
+			if override, ok := overrides[key]; ok {
+				body = override
+			}
+			root := "/parity/" + strings.Split(req.query.Get("name")+"//", "/")[2]
+			body = strings.ReplaceAll(body, "${root}", root)
+			status := http.StatusOK
+			if s, ok := statuses[key]; ok {
+				status = s
+			}
+			return status, body
+		}
+	}
+
+	It("classifies every step and endpoint", func() {
+		baseline := parity.Execute("built-in", &fakeClient{respond: respond(nil, map[string]int{"/api/v1/data? missing": 404})}, scenario)
+		candidate := parity.Execute("remote", &fakeClient{respond: respond(map[string]string{
+			"/api/v1/interpolate":   `{"error": "` + parity.NotImplementedMessage + `"}`,
+			"/api/v1/key-usage":     `{"error": "` + parity.NotImplementedMessage + `"}`,
+			"/api/v1/data PUT":      `{"name": "${root}/value", "extra": true}`,
+			"/api/v1/data? missing": `{"message": "not found"}`,
+		}, map[string]int{"/api/v1/data? missing": 404})}, scenario)
+
+		report := parity.Compare(scenario, baseline, candidate)
+		kinds := map[string]parity.Kind{}
+		for _, finding := range report.Findings {
+			kinds[finding.Step] = finding.Kind
+		}
+		Expect(kinds).To(Equal(map[string]parity.Kind{
+			"get":         parity.Same,
+			"get missing": parity.DifferentError,
+			"interpolate": parity.Unimplemented,
+			"key usage":   parity.Unimplemented,
+			"set":         parity.Different,
+			"info":        parity.Same,
+		}))
+		Expect(report.Findings[4].Detail).To(Equal("$.extra: unexpected true"))
+
+		Expect(report.Unimplemented()).To(Equal([]reporting.Endpoint{
+			{Method: "GET", Path: "/api/v1/key-usage"},
+			{Method: "POST", Path: "/api/v1/interpolate"},
+		}))
+		Expect(candidate.Unimplemented()).To(Equal(report.Unimplemented()))
+		Expect(baseline.Unimplemented()).To(BeEmpty())
+		Expect(report.Endpoints[0]).To(Equal(parity.EndpointParity{
+			Endpoint: reporting.Endpoint{Method: "GET", Path: "/api/v1/data", Operation: "get"},
+			Kind:     parity.DifferentError,
+			Steps:    []string{"get", "get missing"},
+		}))
+
+		var text bytes.Buffer
+		Expect(report.WriteText(&text)).To(Succeed())
+		Expect(text.String()).To(ContainSubstring("unimplemented:\n  GET /api/v1/key-usage\n  POST /api/v1/interpolate\n"))
+		Expect(text.String()).To(ContainSubstring("different:\n  PUT /api/v1/data [set]\n    set: $.extra: unexpected true\n"))
+		Expect(text.String()).To(ContainSubstring("different error:\n  GET /api/v1/data [get]\n    get missing: $.error: missing\n"))
+	})
+
+	It("reads the reports it writes", func() {
+		report := parity.Report{Baseline: "built-in", Candidate: "remote", Endpoints: []parity.EndpointParity{
+			{Endpoint: reporting.Endpoint{Method: "GET", Path: "/api/v1/key-usage"}, Kind: parity.Unimplemented, Steps: []string{"key usage"}},
+		}}
+		dir, err := ioutil.TempDir("", "parity")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "parity.json")
+		Expect(report.WriteFile(path)).To(Succeed())
+		Expect(parity.ReadFile(path)).To(Equal(report))
+	})
+
+	It("round trips kinds through JSON", func() {
+		encoded, err := json.Marshal(parity.Unimplemented)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(encoded)).To(Equal(`"unimplemented"`))
+
+		var kind parity.Kind
+		Expect(json.Unmarshal([]byte(`"different error"`), &kind)).To(Succeed())
+		Expect(kind).To(Equal(parity.DifferentError))
+	})
+})
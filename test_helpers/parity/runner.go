@@ -0,0 +1,169 @@
+package parity
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/reporting"
+	"github.com/google/uuid"
+)
+
+// Client is the part of *credhub.CredHub a run uses.
+type Client interface {
+	Request(method string, pathStr string, query url.Values, body interface{}, checkServerErr bool) (*http.Response, error)
+}
+
+// Result is the response to a step against one server.
+type Result struct {
+	Step     string             `json:"step"`
+	Endpoint reporting.Endpoint `json:"endpoint"`
+	Status   int                `json:"status,omitempty"`
+	Body     json.RawMessage    `json:"body,omitempty"`
+	// Err is set when the request could not be made, because of a transport
+	// error or a variable an earlier step did not capture.
+	Err string `json:"error,omitempty"`
+}
+
+// Run is a scenario's results against one server, with the root its
+// credentials were created under.
+type Run struct {
+	Target  string   `json:"target"`
+	Root    string   `json:"root"`
+	RootID  string   `json:"root_id"`
+	Results []Result `json:"results"`
+}
+
+var variable = regexp.MustCompile(`\$\{([a-z-]+)\}`)
+
+const placeholderID = "00000000-0000-0000-0000-000000000000"
+
+// Execute runs every step of the scenario in order against a server, under a
+// new root, then deletes whatever is left under the root.
+func Execute(target string, client Client, scenario []Step) Run {
+	run := Run{Target: target, RootID: uuid.NewString()}
+	run.Root = "/parity/" + run.RootID
+	vars := map[string]string{"root": run.Root, "root-id": run.RootID}
+
+	for _, step := range scenario {
+		run.Results = append(run.Results, execute(client, step, vars))
+	}
+	cleanup(client, run.Root)
+	return run
+}
+
+func execute(client Client, step Step, vars map[string]string) Result {
+	result := Result{Step: step.Name}
+
+	path, missing := substitute(step.Path, vars)
+	body, missingFromBody := substitute(step.Body, vars)
+	missing = append(missing, missingFromBody...)
+
+	if len(missing) > 0 {
+		// Classify the step as if the variables were ids, which they
+		// usually are.
+		u, _ := url.Parse(variable.ReplaceAllString(path, placeholderID))
+		result.Endpoint, _ = reporting.Classify(step.Method, u, nil)
+		result.Err = fmt.Sprintf("no %s was captured by an earlier step", strings.Join(missing, ", "))
+		return result
+	}
+	u, err := url.Parse(path)
+	if err != nil {
+		result.Err = err.Error()
+		return result
+	}
+	result.Endpoint, _ = reporting.Classify(step.Method, u, []byte(body))
+
+	var requestBody interface{}
+	if body != "" {
+		requestBody = json.RawMessage(body)
+	}
+	resp, err := client.Request(step.Method, u.Path, u.Query(), requestBody, false)
+	if err != nil {
+		result.Err = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		result.Err = err.Error()
+		return result
+	}
+	result.Status = resp.StatusCode
+	if json.Valid(responseBody) {
+		result.Body = responseBody
+	} else {
+		result.Body, _ = json.Marshal(string(responseBody))
+	}
+
+	if resp.StatusCode < 300 {
+		capture(responseBody, step.Capture, vars)
+	}
+	return result
+}
+
+// substitute replaces the variables in s, and returns the names of any
+// variables that are not set.
+func substitute(s string, vars map[string]string) (string, []string) {
+	var missing []string
+	substituted := variable.ReplaceAllStringFunc(s, func(match string) string {
+		name := variable.FindStringSubmatch(match)[1]
+		value, ok := vars[name]
+		if !ok {
+			missing = append(missing, name)
+			return match
+		}
+		return value
+	})
+	return substituted, missing
+}
+
+func capture(body []byte, captures map[string]string, vars map[string]string) {
+	if len(captures) == 0 {
+		return
+	}
+	var parsed interface{}
+	if json.Unmarshal(body, &parsed) != nil {
+		return
+	}
+	for name, path := range captures {
+		value, ok := lookup(parsed, path)
+		if !ok {
+			continue
+		}
+		if s, isString := value.(string); isString {
+			vars[name] = s
+		} else {
+			encoded, _ := json.Marshal(value)
+			vars[name] = string(encoded)
+		}
+	}
+}
+
+// cleanup deletes the credentials left under root. Servers that cannot find
+// by path keep them.
+func cleanup(client Client, root string) {
+	resp, err := client.Request("GET", "/api/v1/data", url.Values{"path": {root}}, nil, false)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	var found struct {
+		Credentials []struct {
+			Name string `json:"name"`
+		} `json:"credentials"`
+	}
+	if json.NewDecoder(resp.Body).Decode(&found) != nil {
+		return
+	}
+	for _, credential := range found.Credentials {
+		if deleted, err := client.Request("DELETE", "/api/v1/data", url.Values{"name": {credential.Name}}, nil, false); err == nil {
+			deleted.Body.Close()
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package parity
+
+// Step is one request of a scenario. ${var} in the path and body is replaced
+// by a variable: root and root-id, which every run starts with, or one
+// captured from an earlier step's response.
+type Step struct {
+	Name   string
+	Method string
+	// Path includes the query, e.g. "/api/v1/data?name=${root}/value".
+	Path string
+	Body string
+	// Capture names variables to set from the response, by JSON path, e.g.
+	// {"certificate-id": "certificates.0.id"}. Strings are captured as they
+	// are, anything else as JSON.
+	Capture map[string]string
+	// Mask lists response keys whose values differ on every run, such as
+	// generated values. Every string beneath them is ignored.
+	Mask []string
+}
+
+const (
+	parityActor   = "uaa-client:parity"
+	parityActorV2 = "uaa-client:parity-v2"
+)
+
+var generatedValue = []string{"value"}
+
+// DefaultScenario exercises every catalogued CredHub endpoint, successfully
+// where the backend supports it, and a few that fail, to compare error
+// responses.
+var DefaultScenario = []Step{
+	{Name: "set value", Method: "PUT", Path: "/api/v1/data",
+		Body:    `{"name": "${root}/value", "type": "value", "value": "some-value"}`,
+		Capture: map[string]string{"value-id": "id"}},
+	{Name: "set json", Method: "PUT", Path: "/api/v1/data",
+		Body: `{"name": "${root}/json", "type": "json", "value": {"some-key": "some-value"}}`},
+	{Name: "set password", Method: "PUT", Path: "/api/v1/data",
+		Body: `{"name": "${root}/password", "type": "password", "value": "some-password"}`},
+	{Name: "set user", Method: "PUT", Path: "/api/v1/data",
+		Body: `{"name": "${root}/user", "type": "user", "value": {"username": "some-user", "password": "some-password"}}`},
+	{Name: "set with unknown type", Method: "PUT", Path: "/api/v1/data",
+		Body: `{"name": "${root}/unknown", "type": "unknown", "value": "some-value"}`},
+
+	{Name: "generate password", Method: "POST", Path: "/api/v1/data",
+		Body: `{"name": "${root}/generated-password", "type": "password", "parameters": {"length": 20}}`,
+		Mask: generatedValue},
+	{Name: "generate user", Method: "POST", Path: "/api/v1/data",
+		Body: `{"name": "${root}/generated-user", "type": "user"}`,
+		Mask: generatedValue},
+	{Name: "generate ca", Method: "POST", Path: "/api/v1/data",
+		Body: `{"name": "${root}/ca", "type": "certificate", "parameters": {"is_ca": true, "common_name": "parity-ca"}}`,
+		Mask: generatedValue},
+	{Name: "generate certificate", Method: "POST", Path: "/api/v1/data",
+		Body:    `{"name": "${root}/certificate", "type": "certificate", "parameters": {"ca": "${root}/ca", "common_name": "parity-leaf"}}`,
+		Capture: map[string]string{"certificate-value": "value"},
+		Mask:    generatedValue},
+	{Name: "generate rsa", Method: "POST", Path: "/api/v1/data",
+		Body:    `{"name": "${root}/rsa", "type": "rsa"}`,
+		Capture: map[string]string{"rsa-value": "value"},
+		Mask:    generatedValue},
+	{Name: "generate ssh", Method: "POST", Path: "/api/v1/data",
+		Body:    `{"name": "${root}/ssh", "type": "ssh"}`,
+		Capture: map[string]string{"ssh-value": "value"},
+		Mask:    generatedValue},
+	{Name: "generate without overwriting", Method: "POST", Path: "/api/v1/data",
+		Body: `{"name": "${root}/generated-password", "type": "password", "mode": "no-overwrite", "parameters": {"length": 20}}`,
+		Mask: generatedValue},
+	{Name: "generate with unknown parameter", Method: "POST", Path: "/api/v1/data",
+		Body: `{"name": "${root}/invalid", "type": "password", "parameters": {"unknown": true}}`},
+
+	{Name: "set certificate", Method: "PUT", Path: "/api/v1/data",
+		Body: `{"name": "${root}/set-certificate", "type": "certificate", "value": ${certificate-value}}`,
+		Mask: generatedValue},
+	{Name: "set rsa", Method: "PUT", Path: "/api/v1/data",
+		Body: `{"name": "${root}/set-rsa", "type": "rsa", "value": ${rsa-value}}`,
+		Mask: generatedValue},
+	{Name: "set ssh", Method: "PUT", Path: "/api/v1/data",
+		Body: `{"name": "${root}/set-ssh", "type": "ssh", "value": ${ssh-value}}`,
+		Mask: generatedValue},
+
+	{Name: "get by name", Method: "GET", Path: "/api/v1/data?name=${root}/value"},
+	{Name: "get by id", Method: "GET", Path: "/api/v1/data/${value-id}"},
+	{Name: "get current", Method: "GET", Path: "/api/v1/data?name=${root}/json&current=true"},
+	{Name: "get missing", Method: "GET", Path: "/api/v1/data?name=${root}/missing"},
+	{Name: "get missing id", Method: "GET", Path: "/api/v1/data/00000000-0000-0000-0000-000000000000"},
+	{Name: "find by name", Method: "GET", Path: "/api/v1/data?name-like=${root-id}"},
+	{Name: "find by path", Method: "GET", Path: "/api/v1/data?path=${root}"},
+	{Name: "find by missing path", Method: "GET", Path: "/api/v1/data?path=${root}/missing"},
+
+	{Name: "regenerate", Method: "POST", Path: "/api/v1/data",
+		Body: `{"name": "${root}/generated-password", "regenerate": true}`,
+		Mask: generatedValue},
+	{Name: "get versions", Method: "GET", Path: "/api/v1/data?name=${root}/generated-password&versions=2",
+		Mask: generatedValue},
+	{Name: "regenerate missing", Method: "POST", Path: "/api/v1/data",
+		Body: `{"name": "${root}/missing", "regenerate": true}`},
+	{Name: "bulk regenerate", Method: "POST", Path: "/api/v1/bulk-regenerate",
+		Body: `{"signed_by": "${root}/ca"}`},
+	{Name: "interpolate", Method: "POST", Path: "/api/v1/interpolate",
+		Body: `{"service": [{"credentials": {"credhub-ref": "((${root}/json))"}}]}`},
+
+	{Name: "list certificates", Method: "GET", Path: "/api/v1/certificates?name=${root}/certificate",
+		Capture: map[string]string{"certificate-id": "certificates.0.id"}},
+	{Name: "get certificate versions", Method: "GET", Path: "/api/v1/certificates/${certificate-id}/versions",
+		Mask: generatedValue},
+	// Only a CA can have a transitional version.
+	{Name: "list ca certificates", Method: "GET", Path: "/api/v1/certificates?name=${root}/ca",
+		Capture: map[string]string{"ca-id": "certificates.0.id"}},
+	{Name: "regenerate certificate", Method: "POST", Path: "/api/v1/certificates/${ca-id}/regenerate",
+		Body: `{"set_as_transitional": true}`,
+		Mask: generatedValue},
+	{Name: "update transitional version", Method: "PUT", Path: "/api/v1/certificates/${ca-id}/update_transitional_version",
+		Body: `{"version": null}`,
+		Mask: generatedValue},
+	{Name: "add certificate version", Method: "POST", Path: "/api/v1/certificates/${certificate-id}/versions",
+		Body:    `{"value": ${certificate-value}, "transitional": false}`,
+		Capture: map[string]string{"added-version-id": "id"},
+		Mask:    generatedValue},
+	{Name: "delete certificate version", Method: "DELETE", Path: "/api/v1/certificates/${certificate-id}/versions/${added-version-id}",
+		Mask: generatedValue},
+
+	{Name: "add permission v1", Method: "POST", Path: "/api/v1/permissions",
+		Body: `{"credential_name": "${root}/value", "permissions": [{"actor": "` + parityActor + `", "operations": ["read"]}]}`},
+	{Name: "get permissions v1", Method: "GET", Path: "/api/v1/permissions?credential_name=${root}/value"},
+	{Name: "delete permission v1", Method: "DELETE", Path: "/api/v1/permissions?credential_name=${root}/value&actor=" + parityActor},
+	{Name: "add permission", Method: "POST", Path: "/api/v2/permissions",
+		Body:    `{"path": "${root}/*", "actor": "` + parityActorV2 + `", "operations": ["read"]}`,
+		Capture: map[string]string{"permission-id": "uuid"}},
+	{Name: "add duplicate permission", Method: "POST", Path: "/api/v2/permissions",
+		Body: `{"path": "${root}/*", "actor": "` + parityActorV2 + `", "operations": ["read"]}`},
+	{Name: "get permission by path and actor", Method: "GET", Path: "/api/v2/permissions?path=${root}/*&actor=" + parityActorV2},
+	{Name: "get permission", Method: "GET", Path: "/api/v2/permissions/${permission-id}"},
+	{Name: "put permission", Method: "PUT", Path: "/api/v2/permissions/${permission-id}",
+		Body: `{"path": "${root}/*", "actor": "` + parityActorV2 + `", "operations": ["read", "write"]}`},
+	{Name: "patch permission", Method: "PATCH", Path: "/api/v2/permissions/${permission-id}",
+		Body: `{"operations": ["read"]}`},
+	{Name: "delete permission", Method: "DELETE", Path: "/api/v2/permissions/${permission-id}"},
+	{Name: "get missing permission", Method: "GET", Path: "/api/v2/permissions/00000000-0000-0000-0000-000000000000"},
+
+	{Name: "key usage", Method: "GET", Path: "/api/v1/key-usage"},
+	{Name: "info", Method: "GET", Path: "/info", Mask: []string{"url"}},
+	{Name: "version", Method: "GET", Path: "/version"},
+	{Name: "health", Method: "GET", Path: "/health"},
+
+	{Name: "delete", Method: "DELETE", Path: "/api/v1/data?name=${root}/value"},
+	{Name: "get deleted", Method: "GET", Path: "/api/v1/data?name=${root}/value"},
+	{Name: "delete missing", Method: "DELETE", Path: "/api/v1/data?name=${root}/missing"},
+}
@@ -0,0 +1,89 @@
+package perf
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Tolerance is how much worse than the baseline a run may be before it counts
+// as a regression. Latency and Throughput are fractions of the baseline
+// value, so 0.2 allows 20% slower; ErrorRate is an absolute increase.
+type Tolerance struct {
+	Latency    float64
+	Throughput float64
+	ErrorRate  float64
+}
+
+// Regression is a metric of a run that is worse than the baseline by more
+// than the tolerance.
+type Regression struct {
+	Operation string
+	Metric    string
+	Baseline  float64
+	Current   float64
+}
+
+func (r Regression) String() string {
+	return fmt.Sprintf("%s %s regressed from %.3f to %.3f", r.Operation, r.Metric, r.Baseline, r.Current)
+}
+
+// Compare lists the regressions of current against baseline. It returns an
+// error instead when the baseline was recorded with another concurrency or
+// operation mix, as its numbers are then not comparable. An operation the
+// baseline has that the run does not is a throughput regression to zero;
+// one only the run has is not compared.
+func Compare(baseline, current Report, tolerance Tolerance) ([]Regression, error) {
+	if baseline.Concurrency != current.Concurrency {
+		return nil, fmt.Errorf("the baseline was recorded with a concurrency of %d, not %d", baseline.Concurrency, current.Concurrency)
+	}
+	if !reflect.DeepEqual(baseline.Mix, current.Mix) {
+		return nil, fmt.Errorf("the baseline was recorded with the mix %v, not %v", baseline.Mix, current.Mix)
+	}
+
+	var regressions []Regression
+
+	check := func(operation, metric string, baselineValue, currentValue float64, worse bool) {
+		if worse {
+			regressions = append(regressions, Regression{operation, metric, baselineValue, currentValue})
+		}
+	}
+	compareThroughput := func(operation string, baselineValue, currentValue float64) {
+		check(operation, "throughput", baselineValue, currentValue, currentValue < baselineValue*(1-tolerance.Throughput))
+	}
+	compareErrorRate := func(operation string, baselineValue, currentValue float64) {
+		check(operation, "error rate", baselineValue, currentValue, currentValue > baselineValue+tolerance.ErrorRate)
+	}
+
+	compareThroughput("total", baseline.Throughput, current.Throughput)
+	compareErrorRate("total", baseline.ErrorRate, current.ErrorRate)
+
+	names := make([]string, 0, len(baseline.Operations))
+	for name := range baseline.Operations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		before := baseline.Operations[name]
+		after, ok := current.Operations[name]
+		if !ok {
+			check(name, "throughput", before.Throughput, 0, true)
+			continue
+		}
+		compareThroughput(name, before.Throughput, after.Throughput)
+		compareErrorRate(name, before.ErrorRate, after.ErrorRate)
+		for _, percentile := range []struct {
+			metric        string
+			before, after float64
+		}{
+			{"p50 latency", before.Latency.P50, after.Latency.P50},
+			{"p95 latency", before.Latency.P95, after.Latency.P95},
+			{"p99 latency", before.Latency.P99, after.Latency.P99},
+		} {
+			check(name, percentile.metric, percentile.before, percentile.after, percentile.after > percentile.before*(1+tolerance.Latency))
+		}
+	}
+
+	return regressions, nil
+}
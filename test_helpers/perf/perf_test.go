@@ -0,0 +1,185 @@
+package perf_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/perf"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Run", func() {
+	It("runs the operations in proportion to the mix from every worker", func() {
+		var gets, sets int64
+		workers := make([]int64, 4)
+		operations := map[string]perf.Operation{
+			"get": func(worker, iteration int) error {
+				atomic.AddInt64(&gets, 1)
+				atomic.AddInt64(&workers[worker], 1)
+				time.Sleep(time.Millisecond)
+				return nil
+			},
+			"set": func(worker, iteration int) error {
+				atomic.AddInt64(&sets, 1)
+				atomic.AddInt64(&workers[worker], 1)
+				time.Sleep(time.Millisecond)
+				return errors.New("set failed")
+			},
+			"find": func(worker, iteration int) error {
+				Fail("find is not in the mix")
+				return nil
+			},
+		}
+
+		report, err := perf.Run(perf.Options{Concurrency: 4, Duration: 200 * time.Millisecond, Mix: map[string]int{"get": 3, "set": 1}}, operations)
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, count := range workers {
+			Expect(count).To(BeNumerically(">", 0))
+		}
+		Expect(float64(gets) / float64(sets)).To(BeNumerically("~", 3, 1.5))
+
+		Expect(report.Concurrency).To(Equal(4))
+		Expect(report.Mix).To(Equal(map[string]int{"get": 3, "set": 1}))
+		Expect(report.DurationSeconds).To(BeNumerically(">=", 0.2))
+		Expect(report.Requests).To(BeEquivalentTo(gets + sets))
+		Expect(report.Operations).To(HaveLen(2))
+		Expect(report.Operations["get"].Requests).To(BeEquivalentTo(gets))
+		Expect(report.Operations["get"].ErrorRate).To(BeZero())
+		Expect(report.Operations["get"].Latency.P50).To(BeNumerically(">=", 1))
+		Expect(report.Operations["set"].ErrorRate).To(Equal(1.0))
+		Expect(report.Operations["set"].SampleErrors).To(Equal([]string{"set failed"}))
+		Expect(report.ErrorRate).To(BeNumerically("~", float64(sets)/float64(gets+sets), 0.001))
+		Expect(report.Throughput).To(BeNumerically("~", float64(report.Requests)/report.DurationSeconds, 0.001))
+	})
+
+	It("rejects invalid options", func() {
+		operations := map[string]perf.Operation{"get": func(int, int) error { return nil }}
+
+		_, err := perf.Run(perf.Options{Concurrency: 0, Duration: time.Second, Mix: map[string]int{"get": 1}}, operations)
+		Expect(err).To(MatchError("concurrency must be at least 1"))
+
+		_, err = perf.Run(perf.Options{Concurrency: 1, Duration: time.Second, Mix: map[string]int{"set": 1}}, operations)
+		Expect(err).To(MatchError(`unknown operation "set" in mix`))
+
+		_, err = perf.Run(perf.Options{Concurrency: 1, Duration: time.Second, Mix: map[string]int{"get": 0}}, operations)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Percentile", func() {
+	It("uses the nearest rank", func() {
+		var latencies []time.Duration
+		for i := 1; i <= 20; i++ {
+			latencies = append(latencies, time.Duration(i)*time.Millisecond)
+		}
+		Expect(perf.Percentile(latencies, 50)).To(Equal(10 * time.Millisecond))
+		Expect(perf.Percentile(latencies, 95)).To(Equal(19 * time.Millisecond))
+		Expect(perf.Percentile(latencies, 99)).To(Equal(20 * time.Millisecond))
+		Expect(perf.Percentile(latencies, 0)).To(Equal(1 * time.Millisecond))
+	})
+})
+
+var _ = Describe("Compare", func() {
+	mix := map[string]int{"get": 4, "set": 1}
+	baseline := perf.Report{
+		Concurrency: 10,
+		Mix:         mix,
+		Throughput:  100,
+		ErrorRate:   0.01,
+		Operations: map[string]perf.OperationReport{
+			"get": {Throughput: 80, Latency: perf.Latency{P50: 10, P95: 20, P99: 40}},
+			"set": {Throughput: 20, Latency: perf.Latency{P50: 10, P95: 20, P99: 40}},
+		},
+	}
+	tolerance := perf.Tolerance{Latency: 0.2, Throughput: 0.1, ErrorRate: 0.01}
+
+	It("accepts runs within the tolerance", func() {
+		current := perf.Report{
+			Concurrency: 10,
+			Mix:         map[string]int{"set": 1, "get": 4},
+			Throughput:  91,
+			ErrorRate:   0.02,
+			Operations: map[string]perf.OperationReport{
+				"get":         {Throughput: 73, Latency: perf.Latency{P50: 12, P95: 24, P99: 48}},
+				"set":         {Throughput: 18, Latency: perf.Latency{P50: 12, P95: 24, P99: 48}},
+				"interpolate": {Throughput: 1, Latency: perf.Latency{P50: 1000}},
+			},
+		}
+		regressions, err := perf.Compare(baseline, current, tolerance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(regressions).To(BeEmpty())
+	})
+
+	It("reports an operation the run is missing", func() {
+		current := perf.Report{
+			Concurrency: 10,
+			Mix:         mix,
+			Throughput:  100,
+			ErrorRate:   0.01,
+			Operations: map[string]perf.OperationReport{
+				"get": {Throughput: 80, Latency: perf.Latency{P50: 10, P95: 20, P99: 40}},
+			},
+		}
+		regressions, err := perf.Compare(baseline, current, tolerance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(regressions).To(ConsistOf(perf.Regression{Operation: "set", Metric: "throughput", Baseline: 20, Current: 0}))
+	})
+
+	It("rejects a baseline recorded with another concurrency or mix", func() {
+		_, err := perf.Compare(baseline, perf.Report{Concurrency: 20, Mix: mix}, tolerance)
+		Expect(err).To(MatchError("the baseline was recorded with a concurrency of 10, not 20"))
+
+		_, err = perf.Compare(baseline, perf.Report{Concurrency: 10, Mix: map[string]int{"get": 1, "set": 1}}, tolerance)
+		Expect(err).To(MatchError("the baseline was recorded with the mix map[get:4 set:1], not map[get:1 set:1]"))
+
+		_, err = perf.Compare(perf.Report{Concurrency: 10}, perf.Report{Concurrency: 10, Mix: mix}, tolerance)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("reports each metric that regressed", func() {
+		current := perf.Report{
+			Concurrency: 10,
+			Mix:         mix,
+			Throughput:  89,
+			ErrorRate:   0.03,
+			Operations: map[string]perf.OperationReport{
+				"get": {Throughput: 80, ErrorRate: 0.5, Latency: perf.Latency{P50: 10, P95: 25, P99: 40}},
+				"set": {Throughput: 10, Latency: perf.Latency{P50: 10, P95: 20, P99: 40}},
+			},
+		}
+		regressions, err := perf.Compare(baseline, current, tolerance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(regressions).To(ConsistOf(
+			perf.Regression{Operation: "total", Metric: "throughput", Baseline: 100, Current: 89},
+			perf.Regression{Operation: "total", Metric: "error rate", Baseline: 0.01, Current: 0.03},
+			perf.Regression{Operation: "get", Metric: "error rate", Baseline: 0, Current: 0.5},
+			perf.Regression{Operation: "get", Metric: "p95 latency", Baseline: 20, Current: 25},
+			perf.Regression{Operation: "set", Metric: "throughput", Baseline: 20, Current: 10},
+		))
+		Expect(regressions[0].String()).To(Equal("total throughput regressed from 100.000 to 89.000"))
+	})
+})
+
+var _ = Describe("Report files", func() {
+	It("round-trips through JSON", func() {
+		dir, err := os.MkdirTemp("", "perf")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		report, err := perf.Run(perf.Options{Concurrency: 1, Duration: 10 * time.Millisecond, Mix: map[string]int{"get": 1}},
+			map[string]perf.Operation{"get": func(int, int) error { return nil }})
+		Expect(err).NotTo(HaveOccurred())
+
+		path := filepath.Join(dir, "report.json")
+		Expect(report.WriteFile(path)).To(Succeed())
+		loaded, err := perf.LoadReport(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded).To(Equal(report))
+	})
+})
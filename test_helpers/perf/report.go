@@ -0,0 +1,171 @@
+package perf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+const maxSampleErrors = 3
+
+// Report summarises a load run. Latencies are in milliseconds and
+// throughputs in requests per second.
+type Report struct {
+	Concurrency     int                        `json:"concurrency"`
+	Mix             map[string]int             `json:"mix"`
+	DurationSeconds float64                    `json:"duration_seconds"`
+	Requests        int                        `json:"requests"`
+	Errors          int                        `json:"errors"`
+	ErrorRate       float64                    `json:"error_rate"`
+	Throughput      float64                    `json:"throughput"`
+	Operations      map[string]OperationReport `json:"operations"`
+}
+
+type OperationReport struct {
+	Requests     int      `json:"requests"`
+	Errors       int      `json:"errors"`
+	ErrorRate    float64  `json:"error_rate"`
+	Throughput   float64  `json:"throughput"`
+	Latency      Latency  `json:"latency_ms"`
+	SampleErrors []string `json:"sample_errors,omitempty"`
+}
+
+type Latency struct {
+	Min  float64 `json:"min"`
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P95  float64 `json:"p95"`
+	P99  float64 `json:"p99"`
+	Max  float64 `json:"max"`
+}
+
+func newReport(opts Options, elapsed time.Duration, samples []sample) Report {
+	report := Report{
+		Concurrency:     opts.Concurrency,
+		Mix:             opts.Mix,
+		DurationSeconds: elapsed.Seconds(),
+		Requests:        len(samples),
+		Operations:      map[string]OperationReport{},
+	}
+
+	byOperation := map[string][]sample{}
+	for _, s := range samples {
+		byOperation[s.operation] = append(byOperation[s.operation], s)
+	}
+
+	for name, operationSamples := range byOperation {
+		operation := OperationReport{Requests: len(operationSamples)}
+		latencies := make([]time.Duration, len(operationSamples))
+		seen := map[string]bool{}
+		for i, s := range operationSamples {
+			latencies[i] = s.latency
+			if s.err == nil {
+				continue
+			}
+			operation.Errors++
+			if message := s.err.Error(); !seen[message] && len(operation.SampleErrors) < maxSampleErrors {
+				seen[message] = true
+				operation.SampleErrors = append(operation.SampleErrors, message)
+			}
+		}
+		operation.ErrorRate = rate(operation.Errors, operation.Requests)
+		operation.Throughput = float64(operation.Requests) / elapsed.Seconds()
+		operation.Latency = summarise(latencies)
+
+		report.Errors += operation.Errors
+		report.Operations[name] = operation
+	}
+
+	report.ErrorRate = rate(report.Errors, report.Requests)
+	report.Throughput = float64(report.Requests) / elapsed.Seconds()
+	return report
+}
+
+func summarise(latencies []time.Duration) Latency {
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	var total time.Duration
+	for _, latency := range latencies {
+		total += latency
+	}
+
+	return Latency{
+		Min:  milliseconds(latencies[0]),
+		Mean: milliseconds(total / time.Duration(len(latencies))),
+		P50:  milliseconds(Percentile(latencies, 50)),
+		P90:  milliseconds(Percentile(latencies, 90)),
+		P95:  milliseconds(Percentile(latencies, 95)),
+		P99:  milliseconds(Percentile(latencies, 99)),
+		Max:  milliseconds(latencies[len(latencies)-1]),
+	}
+}
+
+// Percentile returns the nearest-rank percentile p of sorted, which must be
+// in ascending order and non-empty.
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func rate(count, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(count) / float64(total)
+}
+
+// WriteText renders the report as a table with one row per operation.
+func (r Report) WriteText(w io.Writer) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(table, "OPERATION\tREQUESTS\tREQ/S\tERRORS\tP50 MS\tP95 MS\tP99 MS\tMAX MS\t")
+
+	names := make([]string, 0, len(r.Operations))
+	for name := range r.Operations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		op := r.Operations[name]
+		fmt.Fprintf(table, "%s\t%d\t%.1f\t%d\t%.1f\t%.1f\t%.1f\t%.1f\t\n",
+			name, op.Requests, op.Throughput, op.Errors, op.Latency.P50, op.Latency.P95, op.Latency.P99, op.Latency.Max)
+	}
+	fmt.Fprintf(table, "total\t%d\t%.1f\t%d\t\t\t\t\t\n", r.Requests, r.Throughput, r.Errors)
+	return table.Flush()
+}
+
+// WriteFile writes the report to path as JSON.
+func (r Report) WriteFile(path string) error {
+	encoded, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, encoded, 0644)
+}
+
+// LoadReport reads a report written by WriteFile, e.g. a stored baseline.
+func LoadReport(path string) (Report, error) {
+	var report Report
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return report, err
+	}
+	err = json.Unmarshal(contents, &report)
+	return report, err
+}
@@ -0,0 +1,87 @@
+package perf
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Operation performs one request against CredHub. worker identifies the
+// goroutine running it and iteration counts that worker's requests, so that
+// operations can spread their load over several names without coordinating.
+type Operation func(worker, iteration int) error
+
+// Options configures a load run.
+type Options struct {
+	Concurrency int
+	Duration    time.Duration
+	// Mix weights the operations, e.g. {"get": 5, "set": 1} makes gets five
+	// times as frequent as sets. Operations not in the mix are not run.
+	Mix  map[string]int
+	Seed int64
+}
+
+type sample struct {
+	operation string
+	latency   time.Duration
+	err       error
+}
+
+// Run drives the operations from Concurrency goroutines for Duration, each
+// picking its next operation at random according to Mix.
+func Run(opts Options, operations map[string]Operation) (Report, error) {
+	if opts.Concurrency < 1 {
+		return Report{}, errors.New("concurrency must be at least 1")
+	}
+	if opts.Duration <= 0 {
+		return Report{}, errors.New("duration must be positive")
+	}
+
+	names := make([]string, 0, len(opts.Mix))
+	for name := range opts.Mix {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var weighted []string
+	for _, name := range names {
+		if _, ok := operations[name]; !ok {
+			return Report{}, fmt.Errorf("unknown operation %q in mix", name)
+		}
+		for i := 0; i < opts.Mix[name]; i++ {
+			weighted = append(weighted, name)
+		}
+	}
+	if len(weighted) == 0 {
+		return Report{}, errors.New("mix must give at least one operation a positive weight")
+	}
+
+	samples := make([][]sample, opts.Concurrency)
+	var wg sync.WaitGroup
+	start := time.Now()
+	deadline := start.Add(opts.Duration)
+
+	for worker := 0; worker < opts.Concurrency; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			random := rand.New(rand.NewSource(opts.Seed + int64(worker)))
+			for iteration := 0; time.Now().Before(deadline); iteration++ {
+				name := weighted[random.Intn(len(weighted))]
+				began := time.Now()
+				err := operations[name](worker, iteration)
+				samples[worker] = append(samples[worker], sample{operation: name, latency: time.Since(began), err: err})
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	var all []sample
+	for _, workerSamples := range samples {
+		all = append(all, workerSamples...)
+	}
+	return newReport(opts, time.Since(start), all), nil
+}
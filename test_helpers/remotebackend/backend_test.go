@@ -0,0 +1,248 @@
+package remotebackend_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/remotebackend"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gcustom"
+	"github.com/onsi/gomega/types"
+)
+
+var _ = Describe("Backend", func() {
+	var (
+		backend *remotebackend.Backend
+		conn    *grpc.ClientConn
+	)
+
+	invoke := func(method string, req, resp proto.Message) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return conn.Invoke(ctx, remotebackend.FullMethod(method), req, resp)
+	}
+
+	set := func(name, value string) *remotebackend.SetResponse {
+		resp := &remotebackend.SetResponse{}
+		Expect(invoke("Set", &remotebackend.SetRequest{Name: name, Type: "value", Data: []byte(value), Requester: "uaa-client:test"}, resp)).To(Succeed())
+		return resp
+	}
+
+	BeforeEach(func() {
+		dir, err := os.MkdirTemp("", "backend")
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(os.RemoveAll, dir)
+
+		socket := filepath.Join(dir, "backend.sock")
+		backend, err = remotebackend.Start(remotebackend.Options{SocketPath: socket, Seed: remotebackend.DefaultSeed})
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(backend.Stop)
+
+		conn, err = remotebackend.Dial(socket, nil)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(conn.Close)
+	})
+
+	It("serves the seed", func() {
+		resp := &remotebackend.GetResponse{}
+		Expect(invoke("GetById", &remotebackend.GetByIdRequest{Id: "00000000-0000-0000-0000-000000000001"}, resp)).To(Succeed())
+		Expect(resp.Name).To(Equal("/my-value"))
+		Expect(string(resp.Data)).To(Equal(`"test-value"`))
+	})
+
+	It("keeps every version of a credential", func() {
+		first := set("/some/cred", "one")
+		second := set("/Some/Cred", "two")
+		Expect(second.Id).NotTo(Equal(first.Id))
+		Expect(second.VersionCreatedAt).NotTo(BeEmpty())
+
+		latest := &remotebackend.GetResponse{}
+		Expect(invoke("GetByName", &remotebackend.GetByNameRequest{Name: "/some/cred"}, latest)).To(Succeed())
+		Expect(latest.Id).To(Equal(second.Id))
+
+		versions := &remotebackend.GetNVersionsResponse{}
+		Expect(invoke("GetNVersions", &remotebackend.GetNVersionsRequest{Name: "/some/cred"}, versions)).To(Succeed())
+		Expect(versions.Versions).To(HaveLen(2))
+		Expect(string(versions.Versions[0].Data)).To(Equal("two"))
+		Expect(string(versions.Versions[1].Data)).To(Equal("one"))
+
+		Expect(invoke("GetNVersions", &remotebackend.GetNVersionsRequest{Name: "/some/cred", NumberOfVersions: 1}, versions)).To(Succeed())
+		Expect(versions.Versions).To(HaveLen(1))
+
+		Expect(invoke("Delete", &remotebackend.DeleteByNameRequest{Name: "/some/cred"}, &remotebackend.DeleteByNameResponse{})).To(Succeed())
+		err := invoke("GetById", &remotebackend.GetByIdRequest{Id: first.Id}, latest)
+		Expect(status.Code(err)).To(Equal(codes.NotFound))
+	})
+
+	It("finds credentials by name and path, newest first", func() {
+		set("/some/credential", "a")
+		set("/some/other-credential", "b")
+		set("/someother/credential", "c")
+
+		found := &remotebackend.FindResponse{}
+		Expect(invoke("FindContainingName", &remotebackend.FindContainingNameRequest{Name: "OTHER"}, found)).To(Succeed())
+		Expect(found.Results).To(HaveLen(2))
+		Expect(found.Results[0].Name).To(Equal("/someother/credential"))
+		Expect(found.Results[1].Name).To(Equal("/some/other-credential"))
+
+		Expect(invoke("FindStartingWithPath", &remotebackend.FindStartingWithPathRequest{Path: "/some"}, found)).To(Succeed())
+		Expect(found.Results).To(HaveLen(2))
+		Expect(found.Results[0].Name).To(Equal("/some/other-credential"))
+	})
+
+	It("stores permissions", func() {
+		added, found := &remotebackend.PermissionsResponse{}, &remotebackend.PermissionsResponse{}
+		Expect(invoke("AddPermission", &remotebackend.PermissionsRequest{Path: "/some/*", Actor: "some-actor", Operations: []string{"read"}}, added)).To(Succeed())
+		Expect(added.Uuid).NotTo(BeEmpty())
+
+		err := invoke("AddPermission", &remotebackend.PermissionsRequest{Path: "/some/*", Actor: "some-actor", Operations: []string{"write"}}, found)
+		Expect(status.Code(err)).To(Equal(codes.AlreadyExists))
+
+		Expect(invoke("PatchPermissions", &remotebackend.PatchPermissionsRequest{Uuid: added.Uuid, Operations: []string{"read", "write"}}, found)).To(Succeed())
+		Expect(invoke("FindPermissionByPathAndActor", &remotebackend.FindPermissionByPathAndActorRequest{Path: "/some/*", Actor: "some-actor"}, found)).To(Succeed())
+		Expect(found).To(EqualProto(&remotebackend.PermissionsResponse{Uuid: added.Uuid, Path: "/some/*", Actor: "some-actor", Operations: []string{"read", "write"}}))
+
+		Expect(invoke("DeletePermission", &remotebackend.PermissionByUUIDRequest{Uuid: added.Uuid}, found)).To(Succeed())
+		err = invoke("GetPermissionByUUID", &remotebackend.PermissionByUUIDRequest{Uuid: added.Uuid}, found)
+		Expect(status.Code(err)).To(Equal(codes.NotFound))
+	})
+
+	It("logs every call", func() {
+		set("/logged", "value")
+		invoke("GetByName", &remotebackend.GetByNameRequest{Name: "/missing", Requester: "uaa-client:test"}, &remotebackend.GetResponse{})
+
+		calls := backend.Calls()
+		Expect(calls).To(HaveLen(2))
+		Expect(calls[0].Method).To(Equal("Set"))
+		Expect(calls[0].Request).To(EqualProto(&remotebackend.SetRequest{Name: "/logged", Type: "value", Data: []byte("value"), Requester: "uaa-client:test"}))
+		Expect(calls[0].Response.(*remotebackend.SetResponse).Name).To(Equal("/logged"))
+		Expect(calls[0].Code).To(Equal(codes.OK))
+		Expect(calls[1].Method).To(Equal("GetByName"))
+		Expect(calls[1].Response).To(BeNil())
+		Expect(calls[1].Code).To(Equal(codes.NotFound))
+
+		backend.ResetCalls()
+		Expect(backend.Calls()).To(BeEmpty())
+	})
+
+	It("injects faults", func() {
+		backend.InjectFault("Set", remotebackend.Fault{Code: codes.Unavailable, Message: "backend down", Times: 1})
+
+		err := invoke("Set", &remotebackend.SetRequest{Name: "/faulty", Type: "value"}, &remotebackend.SetResponse{})
+		Expect(status.Code(err)).To(Equal(codes.Unavailable))
+		Expect(status.Convert(err).Message()).To(Equal("backend down"))
+		set("/faulty", "value")
+
+		backend.InjectFault("", remotebackend.Fault{Code: codes.Internal})
+		err = invoke("GetByName", &remotebackend.GetByNameRequest{Name: "/faulty"}, &remotebackend.GetResponse{})
+		Expect(status.Code(err)).To(Equal(codes.Internal))
+
+		backend.ClearFaults()
+		Expect(invoke("GetByName", &remotebackend.GetByNameRequest{Name: "/faulty"}, &remotebackend.GetResponse{})).To(Succeed())
+	})
+
+	It("injects latency", func() {
+		backend.SetLatency("GetByName", 100*time.Millisecond)
+
+		start := time.Now()
+		invoke("GetByName", &remotebackend.GetByNameRequest{Name: "/my-value"}, &remotebackend.GetResponse{})
+		Expect(time.Since(start)).To(BeNumerically(">=", 100*time.Millisecond))
+
+		start = time.Now()
+		invoke("GetById", &remotebackend.GetByIdRequest{Id: "some-id"}, &remotebackend.GetResponse{})
+		Expect(time.Since(start)).To(BeNumerically("<", 100*time.Millisecond))
+	})
+
+	It("resets to the seed", func() {
+		set("/transient", "value")
+		backend.Reset()
+
+		err := invoke("GetByName", &remotebackend.GetByNameRequest{Name: "/transient"}, &remotebackend.GetResponse{})
+		Expect(status.Code(err)).To(Equal(codes.NotFound))
+		Expect(invoke("GetByName", &remotebackend.GetByNameRequest{Name: "/my-value"}, &remotebackend.GetResponse{})).To(Succeed())
+	})
+})
+
+var _ = Describe("wire format", func() {
+	It("uses the field numbers of remote_backend.proto", func() {
+		dir, err := os.MkdirTemp("", "backend")
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(os.RemoveAll, dir)
+
+		socket := filepath.Join(dir, "backend.sock")
+		backend, err := remotebackend.Start(remotebackend.Options{SocketPath: socket})
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(backend.Stop)
+
+		// A PermissionsRequest encoded by hand, as another client would
+		// send it.
+		var raw []byte
+		raw = protowire.AppendTag(raw, 1, protowire.BytesType)
+		raw = protowire.AppendString(raw, "/path")
+		raw = protowire.AppendTag(raw, 2, protowire.BytesType)
+		raw = protowire.AppendString(raw, "some-actor")
+		for _, operation := range []string{"read", "write"} {
+			raw = protowire.AppendTag(raw, 3, protowire.BytesType)
+			raw = protowire.AppendString(raw, operation)
+		}
+		raw = protowire.AppendTag(raw, 4, protowire.BytesType)
+		raw = protowire.AppendString(raw, "uaa-client:raw")
+
+		conn, err := grpc.NewClient("unix://"+socket, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithDefaultCallOptions(grpc.ForceCodec(rawCodec{})))
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(conn.Close)
+
+		var resp []byte
+		Expect(conn.Invoke(context.Background(), remotebackend.FullMethod("AddPermission"), raw, &resp)).To(Succeed())
+		Expect(backend.Calls()[0].Request).To(EqualProto(&remotebackend.PermissionsRequest{
+			Path: "/path", Actor: "some-actor", Operations: []string{"read", "write"}, Requester: "uaa-client:raw",
+		}))
+
+		num, typ, n := protowire.ConsumeTag(resp)
+		Expect(n).To(BeNumerically(">", 0))
+		Expect(num).To(Equal(protowire.Number(1)))
+		Expect(typ).To(Equal(protowire.BytesType))
+	})
+})
+
+// EqualProto succeeds when a message is proto.Equal to expected.
+func EqualProto(expected proto.Message) types.GomegaMatcher {
+	return gcustom.MakeMatcher(func(actual proto.Message) (bool, error) {
+		return proto.Equal(actual, expected), nil
+	}).WithTemplate("Expected\n{{.FormattedActual}}\n{{.To}} equal\n{{format .Data 1}}", expected)
+}
+
+// rawCodec sends and receives messages already in the wire format.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	b, ok := v.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("cannot marshal %T", v)
+	}
+	return b, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("cannot unmarshal into %T", v)
+	}
+	*b = append([]byte(nil), data...)
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
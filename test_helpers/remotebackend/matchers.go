@@ -0,0 +1,157 @@
+package remotebackend
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// CallTo matches a Call to method whose request satisfies every request
+// matcher, e.g.
+//
+//	Expect(backend.Calls()).To(ContainElement(CallTo("Delete", HaveField("Name", "/some-cred"))))
+func CallTo(method string, request ...types.GomegaMatcher) types.GomegaMatcher {
+	return &callMatcher{method: method, request: request}
+}
+
+// HaveCallsTo succeeds when exactly count calls in a []Call are calls to
+// method whose request satisfies every request matcher. A count of zero
+// asserts that no such call was made.
+func HaveCallsTo(count int, method string, request ...types.GomegaMatcher) types.GomegaMatcher {
+	return &callCountMatcher{count: count, call: callMatcher{method: method, request: request}}
+}
+
+// Methods returns the method of each call, in order.
+func Methods(calls []Call) []string {
+	methods := make([]string, len(calls))
+	for i, call := range calls {
+		methods[i] = call.Method
+	}
+	return methods
+}
+
+// Filter returns the calls that satisfy a matcher, such as CallTo.
+func Filter(calls []Call, matcher types.GomegaMatcher) []Call {
+	var matching []Call
+	for _, call := range calls {
+		if ok, err := matcher.Match(call); ok && err == nil {
+			matching = append(matching, call)
+		}
+	}
+	return matching
+}
+
+func (c Call) String() string {
+	return fmt.Sprintf("%s(%s) %s", c.Method, describe(c.Request), c.Code)
+}
+
+// describe prints the fields of a message, showing bytes as text since the
+// backend is sent JSON.
+func describe(m proto.Message) string {
+	if m == nil || !m.ProtoReflect().IsValid() {
+		return "nil"
+	}
+	var fields []string
+	m.ProtoReflect().Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		// The JSON name, capitalised, is the Go field name.
+		name := strings.ToUpper(field.JSONName()[:1]) + field.JSONName()[1:]
+		fields = append(fields, fmt.Sprintf("%s: %s", name, describeValue(field, value)))
+		return true
+	})
+	return strings.Join(fields, ", ")
+}
+
+func describeValue(field protoreflect.FieldDescriptor, value protoreflect.Value) string {
+	if field.IsList() {
+		list := value.List()
+		values := make([]string, list.Len())
+		for i := range values {
+			values[i] = describeScalar(field, list.Get(i))
+		}
+		return "[" + strings.Join(values, " ") + "]"
+	}
+	return describeScalar(field, value)
+}
+
+func describeScalar(field protoreflect.FieldDescriptor, value protoreflect.Value) string {
+	switch field.Kind() {
+	case protoreflect.BytesKind:
+		return fmt.Sprintf("%q", value.Bytes())
+	case protoreflect.MessageKind:
+		return "{" + describe(value.Message().Interface()) + "}"
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+type callMatcher struct {
+	method  string
+	request []types.GomegaMatcher
+}
+
+func (m *callMatcher) Match(actual interface{}) (bool, error) {
+	call, ok := actual.(Call)
+	if !ok {
+		return false, fmt.Errorf("CallTo expects a remotebackend.Call, got:\n%s", format.Object(actual, 1))
+	}
+	if call.Method != m.method {
+		return false, nil
+	}
+	for _, matcher := range m.request {
+		if ok, err := matcher.Match(call.Request); !ok || err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (m *callMatcher) FailureMessage(actual interface{}) string {
+	return fmt.Sprintf("Expected\n\t%v\nto be %s", actual, m.description())
+}
+
+func (m *callMatcher) NegatedFailureMessage(actual interface{}) string {
+	return fmt.Sprintf("Expected\n\t%v\nnot to be %s", actual, m.description())
+}
+
+func (m *callMatcher) description() string {
+	if len(m.request) == 0 {
+		return "a call to " + m.method
+	}
+	return fmt.Sprintf("a call to %s with a request matching %d matchers", m.method, len(m.request))
+}
+
+type callCountMatcher struct {
+	count int
+	call  callMatcher
+}
+
+func (m *callCountMatcher) Match(actual interface{}) (bool, error) {
+	calls, ok := actual.([]Call)
+	if !ok {
+		return false, fmt.Errorf("HaveCallsTo expects a []remotebackend.Call, got:\n%s", format.Object(actual, 1))
+	}
+	return len(Filter(calls, &m.call)) == m.count, nil
+}
+
+func (m *callCountMatcher) FailureMessage(actual interface{}) string {
+	return fmt.Sprintf("Expected %d of the calls\n%s\nto be %s", m.count, listCalls(actual), m.call.description())
+}
+
+func (m *callCountMatcher) NegatedFailureMessage(actual interface{}) string {
+	return fmt.Sprintf("Expected other than %d of the calls\n%s\nto be %s", m.count, listCalls(actual), m.call.description())
+}
+
+func listCalls(actual interface{}) string {
+	calls, _ := actual.([]Call)
+	if len(calls) == 0 {
+		return "\t(none)"
+	}
+	lines := make([]string, len(calls))
+	for i, call := range calls {
+		lines[i] = "\t" + call.String()
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,42 @@
+package remotebackend_test
+
+import (
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/remotebackend"
+	"google.golang.org/grpc/codes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Call matchers", func() {
+	calls := []remotebackend.Call{
+		{Method: "GetByName", Request: &remotebackend.GetByNameRequest{Name: "/some-json"}, Code: codes.NotFound},
+		{Method: "Set", Request: &remotebackend.SetRequest{Name: "/some-json", Type: "json", Data: []byte(`{"key": "value"}`)}},
+		{Method: "Set", Request: &remotebackend.SetRequest{Name: "/other", Type: "value", Data: []byte(`"value"`)}},
+	}
+
+	It("matches calls by method and request", func() {
+		Expect(calls).To(ContainElement(remotebackend.CallTo("GetByName")))
+		Expect(calls).To(ContainElement(remotebackend.CallTo("Set", HaveField("Type", "json"), HaveField("Data", MatchJSON(`{"key":"value"}`)))))
+		Expect(calls).NotTo(ContainElement(remotebackend.CallTo("Set", HaveField("Type", "password"))))
+		Expect(calls).NotTo(ContainElement(remotebackend.CallTo("Delete")))
+	})
+
+	It("counts matching calls", func() {
+		Expect(calls).To(remotebackend.HaveCallsTo(2, "Set"))
+		Expect(calls).To(remotebackend.HaveCallsTo(1, "Set", HaveField("Name", "/some-json")))
+		Expect(calls).To(remotebackend.HaveCallsTo(0, "Delete"))
+		Expect(calls).NotTo(remotebackend.HaveCallsTo(1, "Set"))
+	})
+
+	It("lists the calls when a count does not match", func() {
+		message := remotebackend.HaveCallsTo(1, "Delete").FailureMessage(calls)
+		Expect(message).To(ContainSubstring(`Set(Name: /some-json, Type: json, Data: "{\"key\": \"value\"}") OK`))
+		Expect(message).To(ContainSubstring(`GetByName(Name: /some-json) NotFound`))
+	})
+
+	It("returns the methods in order", func() {
+		Expect(remotebackend.Methods(calls)).To(Equal([]string{"GetByName", "Set", "Set"}))
+		Expect(remotebackend.Filter(calls, remotebackend.CallTo("Set", HaveField("Name", "/other")))).To(Equal(calls[2:]))
+	})
+})
@@ -0,0 +1,1353 @@
+// The remote backend service CredHub calls when it is configured to keep
+// credentials and permissions in a remote backend rather than its own
+// database. Keep it in step with CredHub's, and regenerate the Go code from
+// it with go generate.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: remote_backend.proto
+
+package remotebackend
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetByNameRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Requester     string                 `protobuf:"bytes,2,opt,name=requester,proto3" json:"requester,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetByNameRequest) Reset() {
+	*x = GetByNameRequest{}
+	mi := &file_remote_backend_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetByNameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetByNameRequest) ProtoMessage() {}
+
+func (x *GetByNameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_backend_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetByNameRequest.ProtoReflect.Descriptor instead.
+func (*GetByNameRequest) Descriptor() ([]byte, []int) {
+	return file_remote_backend_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetByNameRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GetByNameRequest) GetRequester() string {
+	if x != nil {
+		return x.Requester
+	}
+	return ""
+}
+
+type GetByIdRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Requester     string                 `protobuf:"bytes,2,opt,name=requester,proto3" json:"requester,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetByIdRequest) Reset() {
+	*x = GetByIdRequest{}
+	mi := &file_remote_backend_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetByIdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetByIdRequest) ProtoMessage() {}
+
+func (x *GetByIdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_backend_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetByIdRequest.ProtoReflect.Descriptor instead.
+func (*GetByIdRequest) Descriptor() ([]byte, []int) {
+	return file_remote_backend_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetByIdRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetByIdRequest) GetRequester() string {
+	if x != nil {
+		return x.Requester
+	}
+	return ""
+}
+
+type GetNVersionsRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Name      string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Requester string                 `protobuf:"bytes,2,opt,name=requester,proto3" json:"requester,omitempty"`
+	// Zero asks for every version.
+	NumberOfVersions int32 `protobuf:"varint,3,opt,name=number_of_versions,json=numberOfVersions,proto3" json:"number_of_versions,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetNVersionsRequest) Reset() {
+	*x = GetNVersionsRequest{}
+	mi := &file_remote_backend_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNVersionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNVersionsRequest) ProtoMessage() {}
+
+func (x *GetNVersionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_backend_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNVersionsRequest.ProtoReflect.Descriptor instead.
+func (*GetNVersionsRequest) Descriptor() ([]byte, []int) {
+	return file_remote_backend_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetNVersionsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GetNVersionsRequest) GetRequester() string {
+	if x != nil {
+		return x.Requester
+	}
+	return ""
+}
+
+func (x *GetNVersionsRequest) GetNumberOfVersions() int32 {
+	if x != nil {
+		return x.NumberOfVersions
+	}
+	return 0
+}
+
+type GetResponse struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Name                 string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type                 string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Data                 []byte                 `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	Id                   string                 `protobuf:"bytes,4,opt,name=id,proto3" json:"id,omitempty"`
+	VersionCreatedAt     string                 `protobuf:"bytes,5,opt,name=version_created_at,json=versionCreatedAt,proto3" json:"version_created_at,omitempty"`
+	GenerationParameters []byte                 `protobuf:"bytes,6,opt,name=generation_parameters,json=generationParameters,proto3" json:"generation_parameters,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *GetResponse) Reset() {
+	*x = GetResponse{}
+	mi := &file_remote_backend_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetResponse) ProtoMessage() {}
+
+func (x *GetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_backend_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetResponse.ProtoReflect.Descriptor instead.
+func (*GetResponse) Descriptor() ([]byte, []int) {
+	return file_remote_backend_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GetResponse) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *GetResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *GetResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetResponse) GetVersionCreatedAt() string {
+	if x != nil {
+		return x.VersionCreatedAt
+	}
+	return ""
+}
+
+func (x *GetResponse) GetGenerationParameters() []byte {
+	if x != nil {
+		return x.GenerationParameters
+	}
+	return nil
+}
+
+type GetNVersionsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Newest first.
+	Versions      []*GetResponse `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNVersionsResponse) Reset() {
+	*x = GetNVersionsResponse{}
+	mi := &file_remote_backend_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNVersionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNVersionsResponse) ProtoMessage() {}
+
+func (x *GetNVersionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_backend_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNVersionsResponse.ProtoReflect.Descriptor instead.
+func (*GetNVersionsResponse) Descriptor() ([]byte, []int) {
+	return file_remote_backend_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetNVersionsResponse) GetVersions() []*GetResponse {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+type FindContainingNameRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Requester     string                 `protobuf:"bytes,2,opt,name=requester,proto3" json:"requester,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindContainingNameRequest) Reset() {
+	*x = FindContainingNameRequest{}
+	mi := &file_remote_backend_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindContainingNameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindContainingNameRequest) ProtoMessage() {}
+
+func (x *FindContainingNameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_backend_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindContainingNameRequest.ProtoReflect.Descriptor instead.
+func (*FindContainingNameRequest) Descriptor() ([]byte, []int) {
+	return file_remote_backend_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *FindContainingNameRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FindContainingNameRequest) GetRequester() string {
+	if x != nil {
+		return x.Requester
+	}
+	return ""
+}
+
+type FindStartingWithPathRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Requester     string                 `protobuf:"bytes,2,opt,name=requester,proto3" json:"requester,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindStartingWithPathRequest) Reset() {
+	*x = FindStartingWithPathRequest{}
+	mi := &file_remote_backend_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindStartingWithPathRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindStartingWithPathRequest) ProtoMessage() {}
+
+func (x *FindStartingWithPathRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_backend_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindStartingWithPathRequest.ProtoReflect.Descriptor instead.
+func (*FindStartingWithPathRequest) Descriptor() ([]byte, []int) {
+	return file_remote_backend_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *FindStartingWithPathRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FindStartingWithPathRequest) GetRequester() string {
+	if x != nil {
+		return x.Requester
+	}
+	return ""
+}
+
+type FindResult struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Name             string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	VersionCreatedAt string                 `protobuf:"bytes,2,opt,name=version_created_at,json=versionCreatedAt,proto3" json:"version_created_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *FindResult) Reset() {
+	*x = FindResult{}
+	mi := &file_remote_backend_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindResult) ProtoMessage() {}
+
+func (x *FindResult) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_backend_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindResult.ProtoReflect.Descriptor instead.
+func (*FindResult) Descriptor() ([]byte, []int) {
+	return file_remote_backend_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *FindResult) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FindResult) GetVersionCreatedAt() string {
+	if x != nil {
+		return x.VersionCreatedAt
+	}
+	return ""
+}
+
+type FindResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*FindResult          `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindResponse) Reset() {
+	*x = FindResponse{}
+	mi := &file_remote_backend_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindResponse) ProtoMessage() {}
+
+func (x *FindResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_backend_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindResponse.ProtoReflect.Descriptor instead.
+func (*FindResponse) Descriptor() ([]byte, []int) {
+	return file_remote_backend_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *FindResponse) GetResults() []*FindResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type SetRequest struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Name                 string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type                 string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Data                 []byte                 `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	Requester            string                 `protobuf:"bytes,4,opt,name=requester,proto3" json:"requester,omitempty"`
+	GenerationParameters []byte                 `protobuf:"bytes,5,opt,name=generation_parameters,json=generationParameters,proto3" json:"generation_parameters,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *SetRequest) Reset() {
+	*x = SetRequest{}
+	mi := &file_remote_backend_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRequest) ProtoMessage() {}
+
+func (x *SetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_backend_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRequest.ProtoReflect.Descriptor instead.
+func (*SetRequest) Descriptor() ([]byte, []int) {
+	return file_remote_backend_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SetRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SetRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *SetRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *SetRequest) GetRequester() string {
+	if x != nil {
+		return x.Requester
+	}
+	return ""
+}
+
+func (x *SetRequest) GetGenerationParameters() []byte {
+	if x != nil {
+		return x.GenerationParameters
+	}
+	return nil
+}
+
+type SetResponse struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Name                 string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type                 string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Data                 []byte                 `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	Id                   string                 `protobuf:"bytes,4,opt,name=id,proto3" json:"id,omitempty"`
+	VersionCreatedAt     string                 `protobuf:"bytes,5,opt,name=version_created_at,json=versionCreatedAt,proto3" json:"version_created_at,omitempty"`
+	GenerationParameters []byte                 `protobuf:"bytes,6,opt,name=generation_parameters,json=generationParameters,proto3" json:"generation_parameters,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *SetResponse) Reset() {
+	*x = SetResponse{}
+	mi := &file_remote_backend_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetResponse) ProtoMessage() {}
+
+func (x *SetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_backend_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetResponse.ProtoReflect.Descriptor instead.
+func (*SetResponse) Descriptor() ([]byte, []int) {
+	return file_remote_backend_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SetResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SetResponse) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *SetResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *SetResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SetResponse) GetVersionCreatedAt() string {
+	if x != nil {
+		return x.VersionCreatedAt
+	}
+	return ""
+}
+
+func (x *SetResponse) GetGenerationParameters() []byte {
+	if x != nil {
+		return x.GenerationParameters
+	}
+	return nil
+}
+
+type DeleteByNameRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Requester     string                 `protobuf:"bytes,2,opt,name=requester,proto3" json:"requester,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteByNameRequest) Reset() {
+	*x = DeleteByNameRequest{}
+	mi := &file_remote_backend_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteByNameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteByNameRequest) ProtoMessage() {}
+
+func (x *DeleteByNameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_backend_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteByNameRequest.ProtoReflect.Descriptor instead.
+func (*DeleteByNameRequest) Descriptor() ([]byte, []int) {
+	return file_remote_backend_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DeleteByNameRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DeleteByNameRequest) GetRequester() string {
+	if x != nil {
+		return x.Requester
+	}
+	return ""
+}
+
+type DeleteByNameResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteByNameResponse) Reset() {
+	*x = DeleteByNameResponse{}
+	mi := &file_remote_backend_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteByNameResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteByNameResponse) ProtoMessage() {}
+
+func (x *DeleteByNameResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_backend_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteByNameResponse.ProtoReflect.Descriptor instead.
+func (*DeleteByNameResponse) Descriptor() ([]byte, []int) {
+	return file_remote_backend_proto_rawDescGZIP(), []int{12}
+}
+
+type PermissionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Actor         string                 `protobuf:"bytes,2,opt,name=actor,proto3" json:"actor,omitempty"`
+	Operations    []string               `protobuf:"bytes,3,rep,name=operations,proto3" json:"operations,omitempty"`
+	Requester     string                 `protobuf:"bytes,4,opt,name=requester,proto3" json:"requester,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PermissionsRequest) Reset() {
+	*x = PermissionsRequest{}
+	mi := &file_remote_backend_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PermissionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PermissionsRequest) ProtoMessage() {}
+
+func (x *PermissionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_backend_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PermissionsRequest.ProtoReflect.Descriptor instead.
+func (*PermissionsRequest) Descriptor() ([]byte, []int) {
+	return file_remote_backend_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PermissionsRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *PermissionsRequest) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *PermissionsRequest) GetOperations() []string {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+func (x *PermissionsRequest) GetRequester() string {
+	if x != nil {
+		return x.Requester
+	}
+	return ""
+}
+
+type PermissionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Actor         string                 `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`
+	Operations    []string               `protobuf:"bytes,4,rep,name=operations,proto3" json:"operations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PermissionsResponse) Reset() {
+	*x = PermissionsResponse{}
+	mi := &file_remote_backend_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PermissionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PermissionsResponse) ProtoMessage() {}
+
+func (x *PermissionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_backend_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PermissionsResponse.ProtoReflect.Descriptor instead.
+func (*PermissionsResponse) Descriptor() ([]byte, []int) {
+	return file_remote_backend_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *PermissionsResponse) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *PermissionsResponse) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *PermissionsResponse) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *PermissionsResponse) GetOperations() []string {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+type PermissionByUUIDRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Requester     string                 `protobuf:"bytes,2,opt,name=requester,proto3" json:"requester,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PermissionByUUIDRequest) Reset() {
+	*x = PermissionByUUIDRequest{}
+	mi := &file_remote_backend_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PermissionByUUIDRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PermissionByUUIDRequest) ProtoMessage() {}
+
+func (x *PermissionByUUIDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_backend_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PermissionByUUIDRequest.ProtoReflect.Descriptor instead.
+func (*PermissionByUUIDRequest) Descriptor() ([]byte, []int) {
+	return file_remote_backend_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *PermissionByUUIDRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *PermissionByUUIDRequest) GetRequester() string {
+	if x != nil {
+		return x.Requester
+	}
+	return ""
+}
+
+type FindPermissionByPathAndActorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Actor         string                 `protobuf:"bytes,2,opt,name=actor,proto3" json:"actor,omitempty"`
+	Requester     string                 `protobuf:"bytes,3,opt,name=requester,proto3" json:"requester,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindPermissionByPathAndActorRequest) Reset() {
+	*x = FindPermissionByPathAndActorRequest{}
+	mi := &file_remote_backend_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindPermissionByPathAndActorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindPermissionByPathAndActorRequest) ProtoMessage() {}
+
+func (x *FindPermissionByPathAndActorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_backend_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindPermissionByPathAndActorRequest.ProtoReflect.Descriptor instead.
+func (*FindPermissionByPathAndActorRequest) Descriptor() ([]byte, []int) {
+	return file_remote_backend_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *FindPermissionByPathAndActorRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FindPermissionByPathAndActorRequest) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *FindPermissionByPathAndActorRequest) GetRequester() string {
+	if x != nil {
+		return x.Requester
+	}
+	return ""
+}
+
+type PutPermissionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Actor         string                 `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`
+	Operations    []string               `protobuf:"bytes,4,rep,name=operations,proto3" json:"operations,omitempty"`
+	Requester     string                 `protobuf:"bytes,5,opt,name=requester,proto3" json:"requester,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutPermissionsRequest) Reset() {
+	*x = PutPermissionsRequest{}
+	mi := &file_remote_backend_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutPermissionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutPermissionsRequest) ProtoMessage() {}
+
+func (x *PutPermissionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_backend_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutPermissionsRequest.ProtoReflect.Descriptor instead.
+func (*PutPermissionsRequest) Descriptor() ([]byte, []int) {
+	return file_remote_backend_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PutPermissionsRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *PutPermissionsRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *PutPermissionsRequest) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *PutPermissionsRequest) GetOperations() []string {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+func (x *PutPermissionsRequest) GetRequester() string {
+	if x != nil {
+		return x.Requester
+	}
+	return ""
+}
+
+type PatchPermissionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Operations    []string               `protobuf:"bytes,2,rep,name=operations,proto3" json:"operations,omitempty"`
+	Requester     string                 `protobuf:"bytes,3,opt,name=requester,proto3" json:"requester,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PatchPermissionsRequest) Reset() {
+	*x = PatchPermissionsRequest{}
+	mi := &file_remote_backend_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PatchPermissionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatchPermissionsRequest) ProtoMessage() {}
+
+func (x *PatchPermissionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_backend_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatchPermissionsRequest.ProtoReflect.Descriptor instead.
+func (*PatchPermissionsRequest) Descriptor() ([]byte, []int) {
+	return file_remote_backend_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PatchPermissionsRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *PatchPermissionsRequest) GetOperations() []string {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+func (x *PatchPermissionsRequest) GetRequester() string {
+	if x != nil {
+		return x.Requester
+	}
+	return ""
+}
+
+var File_remote_backend_proto protoreflect.FileDescriptor
+
+const file_remote_backend_proto_rawDesc = "" +
+	"\n" +
+	"\x14remote_backend.proto\x12\x13credhub.remote.grpc\"D\n" +
+	"\x10GetByNameRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1c\n" +
+	"\trequester\x18\x02 \x01(\tR\trequester\">\n" +
+	"\x0eGetByIdRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1c\n" +
+	"\trequester\x18\x02 \x01(\tR\trequester\"u\n" +
+	"\x13GetNVersionsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1c\n" +
+	"\trequester\x18\x02 \x01(\tR\trequester\x12,\n" +
+	"\x12number_of_versions\x18\x03 \x01(\x05R\x10numberOfVersions\"\xbc\x01\n" +
+	"\vGetResponse\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x12\n" +
+	"\x04data\x18\x03 \x01(\fR\x04data\x12\x0e\n" +
+	"\x02id\x18\x04 \x01(\tR\x02id\x12,\n" +
+	"\x12version_created_at\x18\x05 \x01(\tR\x10versionCreatedAt\x123\n" +
+	"\x15generation_parameters\x18\x06 \x01(\fR\x14generationParameters\"T\n" +
+	"\x14GetNVersionsResponse\x12<\n" +
+	"\bversions\x18\x01 \x03(\v2 .credhub.remote.grpc.GetResponseR\bversions\"M\n" +
+	"\x19FindContainingNameRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1c\n" +
+	"\trequester\x18\x02 \x01(\tR\trequester\"O\n" +
+	"\x1bFindStartingWithPathRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x1c\n" +
+	"\trequester\x18\x02 \x01(\tR\trequester\"N\n" +
+	"\n" +
+	"FindResult\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12,\n" +
+	"\x12version_created_at\x18\x02 \x01(\tR\x10versionCreatedAt\"I\n" +
+	"\fFindResponse\x129\n" +
+	"\aresults\x18\x01 \x03(\v2\x1f.credhub.remote.grpc.FindResultR\aresults\"\x9b\x01\n" +
+	"\n" +
+	"SetRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x12\n" +
+	"\x04data\x18\x03 \x01(\fR\x04data\x12\x1c\n" +
+	"\trequester\x18\x04 \x01(\tR\trequester\x123\n" +
+	"\x15generation_parameters\x18\x05 \x01(\fR\x14generationParameters\"\xbc\x01\n" +
+	"\vSetResponse\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x12\n" +
+	"\x04data\x18\x03 \x01(\fR\x04data\x12\x0e\n" +
+	"\x02id\x18\x04 \x01(\tR\x02id\x12,\n" +
+	"\x12version_created_at\x18\x05 \x01(\tR\x10versionCreatedAt\x123\n" +
+	"\x15generation_parameters\x18\x06 \x01(\fR\x14generationParameters\"G\n" +
+	"\x13DeleteByNameRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1c\n" +
+	"\trequester\x18\x02 \x01(\tR\trequester\"\x16\n" +
+	"\x14DeleteByNameResponse\"|\n" +
+	"\x12PermissionsRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x14\n" +
+	"\x05actor\x18\x02 \x01(\tR\x05actor\x12\x1e\n" +
+	"\n" +
+	"operations\x18\x03 \x03(\tR\n" +
+	"operations\x12\x1c\n" +
+	"\trequester\x18\x04 \x01(\tR\trequester\"s\n" +
+	"\x13PermissionsResponse\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x14\n" +
+	"\x05actor\x18\x03 \x01(\tR\x05actor\x12\x1e\n" +
+	"\n" +
+	"operations\x18\x04 \x03(\tR\n" +
+	"operations\"K\n" +
+	"\x17PermissionByUUIDRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x1c\n" +
+	"\trequester\x18\x02 \x01(\tR\trequester\"m\n" +
+	"#FindPermissionByPathAndActorRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x14\n" +
+	"\x05actor\x18\x02 \x01(\tR\x05actor\x12\x1c\n" +
+	"\trequester\x18\x03 \x01(\tR\trequester\"\x93\x01\n" +
+	"\x15PutPermissionsRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x14\n" +
+	"\x05actor\x18\x03 \x01(\tR\x05actor\x12\x1e\n" +
+	"\n" +
+	"operations\x18\x04 \x03(\tR\n" +
+	"operations\x12\x1c\n" +
+	"\trequester\x18\x05 \x01(\tR\trequester\"k\n" +
+	"\x17PatchPermissionsRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x1e\n" +
+	"\n" +
+	"operations\x18\x02 \x03(\tR\n" +
+	"operations\x12\x1c\n" +
+	"\trequester\x18\x03 \x01(\tR\trequester2\xb7\n" +
+	"\n" +
+	"\x11CredentialService\x12T\n" +
+	"\tGetByName\x12%.credhub.remote.grpc.GetByNameRequest\x1a .credhub.remote.grpc.GetResponse\x12P\n" +
+	"\aGetById\x12#.credhub.remote.grpc.GetByIdRequest\x1a .credhub.remote.grpc.GetResponse\x12c\n" +
+	"\fGetNVersions\x12(.credhub.remote.grpc.GetNVersionsRequest\x1a).credhub.remote.grpc.GetNVersionsResponse\x12g\n" +
+	"\x12FindContainingName\x12..credhub.remote.grpc.FindContainingNameRequest\x1a!.credhub.remote.grpc.FindResponse\x12k\n" +
+	"\x14FindStartingWithPath\x120.credhub.remote.grpc.FindStartingWithPathRequest\x1a!.credhub.remote.grpc.FindResponse\x12H\n" +
+	"\x03Set\x12\x1f.credhub.remote.grpc.SetRequest\x1a .credhub.remote.grpc.SetResponse\x12]\n" +
+	"\x06Delete\x12(.credhub.remote.grpc.DeleteByNameRequest\x1a).credhub.remote.grpc.DeleteByNameResponse\x12b\n" +
+	"\rAddPermission\x12'.credhub.remote.grpc.PermissionsRequest\x1a(.credhub.remote.grpc.PermissionsResponse\x12m\n" +
+	"\x13GetPermissionByUUID\x12,.credhub.remote.grpc.PermissionByUUIDRequest\x1a(.credhub.remote.grpc.PermissionsResponse\x12\x82\x01\n" +
+	"\x1cFindPermissionByPathAndActor\x128.credhub.remote.grpc.FindPermissionByPathAndActorRequest\x1a(.credhub.remote.grpc.PermissionsResponse\x12f\n" +
+	"\x0ePutPermissions\x12*.credhub.remote.grpc.PutPermissionsRequest\x1a(.credhub.remote.grpc.PermissionsResponse\x12j\n" +
+	"\x10PatchPermissions\x12,.credhub.remote.grpc.PatchPermissionsRequest\x1a(.credhub.remote.grpc.PermissionsResponse\x12j\n" +
+	"\x10DeletePermission\x12,.credhub.remote.grpc.PermissionByUUIDRequest\x1a(.credhub.remote.grpc.PermissionsResponseB\x7f\n" +
+	"$org.cloudfoundry.credhub.remote.grpcP\x01ZUgithub.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/remotebackendb\x06proto3"
+
+var (
+	file_remote_backend_proto_rawDescOnce sync.Once
+	file_remote_backend_proto_rawDescData []byte
+)
+
+func file_remote_backend_proto_rawDescGZIP() []byte {
+	file_remote_backend_proto_rawDescOnce.Do(func() {
+		file_remote_backend_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_remote_backend_proto_rawDesc), len(file_remote_backend_proto_rawDesc)))
+	})
+	return file_remote_backend_proto_rawDescData
+}
+
+var file_remote_backend_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_remote_backend_proto_goTypes = []any{
+	(*GetByNameRequest)(nil),                    // 0: credhub.remote.grpc.GetByNameRequest
+	(*GetByIdRequest)(nil),                      // 1: credhub.remote.grpc.GetByIdRequest
+	(*GetNVersionsRequest)(nil),                 // 2: credhub.remote.grpc.GetNVersionsRequest
+	(*GetResponse)(nil),                         // 3: credhub.remote.grpc.GetResponse
+	(*GetNVersionsResponse)(nil),                // 4: credhub.remote.grpc.GetNVersionsResponse
+	(*FindContainingNameRequest)(nil),           // 5: credhub.remote.grpc.FindContainingNameRequest
+	(*FindStartingWithPathRequest)(nil),         // 6: credhub.remote.grpc.FindStartingWithPathRequest
+	(*FindResult)(nil),                          // 7: credhub.remote.grpc.FindResult
+	(*FindResponse)(nil),                        // 8: credhub.remote.grpc.FindResponse
+	(*SetRequest)(nil),                          // 9: credhub.remote.grpc.SetRequest
+	(*SetResponse)(nil),                         // 10: credhub.remote.grpc.SetResponse
+	(*DeleteByNameRequest)(nil),                 // 11: credhub.remote.grpc.DeleteByNameRequest
+	(*DeleteByNameResponse)(nil),                // 12: credhub.remote.grpc.DeleteByNameResponse
+	(*PermissionsRequest)(nil),                  // 13: credhub.remote.grpc.PermissionsRequest
+	(*PermissionsResponse)(nil),                 // 14: credhub.remote.grpc.PermissionsResponse
+	(*PermissionByUUIDRequest)(nil),             // 15: credhub.remote.grpc.PermissionByUUIDRequest
+	(*FindPermissionByPathAndActorRequest)(nil), // 16: credhub.remote.grpc.FindPermissionByPathAndActorRequest
+	(*PutPermissionsRequest)(nil),               // 17: credhub.remote.grpc.PutPermissionsRequest
+	(*PatchPermissionsRequest)(nil),             // 18: credhub.remote.grpc.PatchPermissionsRequest
+}
+var file_remote_backend_proto_depIdxs = []int32{
+	3,  // 0: credhub.remote.grpc.GetNVersionsResponse.versions:type_name -> credhub.remote.grpc.GetResponse
+	7,  // 1: credhub.remote.grpc.FindResponse.results:type_name -> credhub.remote.grpc.FindResult
+	0,  // 2: credhub.remote.grpc.CredentialService.GetByName:input_type -> credhub.remote.grpc.GetByNameRequest
+	1,  // 3: credhub.remote.grpc.CredentialService.GetById:input_type -> credhub.remote.grpc.GetByIdRequest
+	2,  // 4: credhub.remote.grpc.CredentialService.GetNVersions:input_type -> credhub.remote.grpc.GetNVersionsRequest
+	5,  // 5: credhub.remote.grpc.CredentialService.FindContainingName:input_type -> credhub.remote.grpc.FindContainingNameRequest
+	6,  // 6: credhub.remote.grpc.CredentialService.FindStartingWithPath:input_type -> credhub.remote.grpc.FindStartingWithPathRequest
+	9,  // 7: credhub.remote.grpc.CredentialService.Set:input_type -> credhub.remote.grpc.SetRequest
+	11, // 8: credhub.remote.grpc.CredentialService.Delete:input_type -> credhub.remote.grpc.DeleteByNameRequest
+	13, // 9: credhub.remote.grpc.CredentialService.AddPermission:input_type -> credhub.remote.grpc.PermissionsRequest
+	15, // 10: credhub.remote.grpc.CredentialService.GetPermissionByUUID:input_type -> credhub.remote.grpc.PermissionByUUIDRequest
+	16, // 11: credhub.remote.grpc.CredentialService.FindPermissionByPathAndActor:input_type -> credhub.remote.grpc.FindPermissionByPathAndActorRequest
+	17, // 12: credhub.remote.grpc.CredentialService.PutPermissions:input_type -> credhub.remote.grpc.PutPermissionsRequest
+	18, // 13: credhub.remote.grpc.CredentialService.PatchPermissions:input_type -> credhub.remote.grpc.PatchPermissionsRequest
+	15, // 14: credhub.remote.grpc.CredentialService.DeletePermission:input_type -> credhub.remote.grpc.PermissionByUUIDRequest
+	3,  // 15: credhub.remote.grpc.CredentialService.GetByName:output_type -> credhub.remote.grpc.GetResponse
+	3,  // 16: credhub.remote.grpc.CredentialService.GetById:output_type -> credhub.remote.grpc.GetResponse
+	4,  // 17: credhub.remote.grpc.CredentialService.GetNVersions:output_type -> credhub.remote.grpc.GetNVersionsResponse
+	8,  // 18: credhub.remote.grpc.CredentialService.FindContainingName:output_type -> credhub.remote.grpc.FindResponse
+	8,  // 19: credhub.remote.grpc.CredentialService.FindStartingWithPath:output_type -> credhub.remote.grpc.FindResponse
+	10, // 20: credhub.remote.grpc.CredentialService.Set:output_type -> credhub.remote.grpc.SetResponse
+	12, // 21: credhub.remote.grpc.CredentialService.Delete:output_type -> credhub.remote.grpc.DeleteByNameResponse
+	14, // 22: credhub.remote.grpc.CredentialService.AddPermission:output_type -> credhub.remote.grpc.PermissionsResponse
+	14, // 23: credhub.remote.grpc.CredentialService.GetPermissionByUUID:output_type -> credhub.remote.grpc.PermissionsResponse
+	14, // 24: credhub.remote.grpc.CredentialService.FindPermissionByPathAndActor:output_type -> credhub.remote.grpc.PermissionsResponse
+	14, // 25: credhub.remote.grpc.CredentialService.PutPermissions:output_type -> credhub.remote.grpc.PermissionsResponse
+	14, // 26: credhub.remote.grpc.CredentialService.PatchPermissions:output_type -> credhub.remote.grpc.PermissionsResponse
+	14, // 27: credhub.remote.grpc.CredentialService.DeletePermission:output_type -> credhub.remote.grpc.PermissionsResponse
+	15, // [15:28] is the sub-list for method output_type
+	2,  // [2:15] is the sub-list for method input_type
+	2,  // [2:2] is the sub-list for extension type_name
+	2,  // [2:2] is the sub-list for extension extendee
+	0,  // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_remote_backend_proto_init() }
+func file_remote_backend_proto_init() {
+	if File_remote_backend_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_remote_backend_proto_rawDesc), len(file_remote_backend_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   19,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_remote_backend_proto_goTypes,
+		DependencyIndexes: file_remote_backend_proto_depIdxs,
+		MessageInfos:      file_remote_backend_proto_msgTypes,
+	}.Build()
+	File_remote_backend_proto = out.File
+	file_remote_backend_proto_goTypes = nil
+	file_remote_backend_proto_depIdxs = nil
+}
@@ -0,0 +1,582 @@
+// The remote backend service CredHub calls when it is configured to keep
+// credentials and permissions in a remote backend rather than its own
+// database. Keep it in step with CredHub's, and regenerate the Go code from
+// it with go generate.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.0
+// - protoc             v5.29.3
+// source: remote_backend.proto
+
+package remotebackend
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CredentialService_GetByName_FullMethodName                    = "/credhub.remote.grpc.CredentialService/GetByName"
+	CredentialService_GetById_FullMethodName                      = "/credhub.remote.grpc.CredentialService/GetById"
+	CredentialService_GetNVersions_FullMethodName                 = "/credhub.remote.grpc.CredentialService/GetNVersions"
+	CredentialService_FindContainingName_FullMethodName           = "/credhub.remote.grpc.CredentialService/FindContainingName"
+	CredentialService_FindStartingWithPath_FullMethodName         = "/credhub.remote.grpc.CredentialService/FindStartingWithPath"
+	CredentialService_Set_FullMethodName                          = "/credhub.remote.grpc.CredentialService/Set"
+	CredentialService_Delete_FullMethodName                       = "/credhub.remote.grpc.CredentialService/Delete"
+	CredentialService_AddPermission_FullMethodName                = "/credhub.remote.grpc.CredentialService/AddPermission"
+	CredentialService_GetPermissionByUUID_FullMethodName          = "/credhub.remote.grpc.CredentialService/GetPermissionByUUID"
+	CredentialService_FindPermissionByPathAndActor_FullMethodName = "/credhub.remote.grpc.CredentialService/FindPermissionByPathAndActor"
+	CredentialService_PutPermissions_FullMethodName               = "/credhub.remote.grpc.CredentialService/PutPermissions"
+	CredentialService_PatchPermissions_FullMethodName             = "/credhub.remote.grpc.CredentialService/PatchPermissions"
+	CredentialService_DeletePermission_FullMethodName             = "/credhub.remote.grpc.CredentialService/DeletePermission"
+)
+
+// CredentialServiceClient is the client API for CredentialService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CredentialServiceClient interface {
+	GetByName(ctx context.Context, in *GetByNameRequest, opts ...grpc.CallOption) (*GetResponse, error)
+	GetById(ctx context.Context, in *GetByIdRequest, opts ...grpc.CallOption) (*GetResponse, error)
+	GetNVersions(ctx context.Context, in *GetNVersionsRequest, opts ...grpc.CallOption) (*GetNVersionsResponse, error)
+	FindContainingName(ctx context.Context, in *FindContainingNameRequest, opts ...grpc.CallOption) (*FindResponse, error)
+	FindStartingWithPath(ctx context.Context, in *FindStartingWithPathRequest, opts ...grpc.CallOption) (*FindResponse, error)
+	Set(ctx context.Context, in *SetRequest, opts ...grpc.CallOption) (*SetResponse, error)
+	Delete(ctx context.Context, in *DeleteByNameRequest, opts ...grpc.CallOption) (*DeleteByNameResponse, error)
+	AddPermission(ctx context.Context, in *PermissionsRequest, opts ...grpc.CallOption) (*PermissionsResponse, error)
+	GetPermissionByUUID(ctx context.Context, in *PermissionByUUIDRequest, opts ...grpc.CallOption) (*PermissionsResponse, error)
+	FindPermissionByPathAndActor(ctx context.Context, in *FindPermissionByPathAndActorRequest, opts ...grpc.CallOption) (*PermissionsResponse, error)
+	PutPermissions(ctx context.Context, in *PutPermissionsRequest, opts ...grpc.CallOption) (*PermissionsResponse, error)
+	PatchPermissions(ctx context.Context, in *PatchPermissionsRequest, opts ...grpc.CallOption) (*PermissionsResponse, error)
+	DeletePermission(ctx context.Context, in *PermissionByUUIDRequest, opts ...grpc.CallOption) (*PermissionsResponse, error)
+}
+
+type credentialServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCredentialServiceClient(cc grpc.ClientConnInterface) CredentialServiceClient {
+	return &credentialServiceClient{cc}
+}
+
+func (c *credentialServiceClient) GetByName(ctx context.Context, in *GetByNameRequest, opts ...grpc.CallOption) (*GetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetResponse)
+	err := c.cc.Invoke(ctx, CredentialService_GetByName_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *credentialServiceClient) GetById(ctx context.Context, in *GetByIdRequest, opts ...grpc.CallOption) (*GetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetResponse)
+	err := c.cc.Invoke(ctx, CredentialService_GetById_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *credentialServiceClient) GetNVersions(ctx context.Context, in *GetNVersionsRequest, opts ...grpc.CallOption) (*GetNVersionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetNVersionsResponse)
+	err := c.cc.Invoke(ctx, CredentialService_GetNVersions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *credentialServiceClient) FindContainingName(ctx context.Context, in *FindContainingNameRequest, opts ...grpc.CallOption) (*FindResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FindResponse)
+	err := c.cc.Invoke(ctx, CredentialService_FindContainingName_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *credentialServiceClient) FindStartingWithPath(ctx context.Context, in *FindStartingWithPathRequest, opts ...grpc.CallOption) (*FindResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FindResponse)
+	err := c.cc.Invoke(ctx, CredentialService_FindStartingWithPath_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *credentialServiceClient) Set(ctx context.Context, in *SetRequest, opts ...grpc.CallOption) (*SetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetResponse)
+	err := c.cc.Invoke(ctx, CredentialService_Set_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *credentialServiceClient) Delete(ctx context.Context, in *DeleteByNameRequest, opts ...grpc.CallOption) (*DeleteByNameResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteByNameResponse)
+	err := c.cc.Invoke(ctx, CredentialService_Delete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *credentialServiceClient) AddPermission(ctx context.Context, in *PermissionsRequest, opts ...grpc.CallOption) (*PermissionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PermissionsResponse)
+	err := c.cc.Invoke(ctx, CredentialService_AddPermission_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *credentialServiceClient) GetPermissionByUUID(ctx context.Context, in *PermissionByUUIDRequest, opts ...grpc.CallOption) (*PermissionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PermissionsResponse)
+	err := c.cc.Invoke(ctx, CredentialService_GetPermissionByUUID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *credentialServiceClient) FindPermissionByPathAndActor(ctx context.Context, in *FindPermissionByPathAndActorRequest, opts ...grpc.CallOption) (*PermissionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PermissionsResponse)
+	err := c.cc.Invoke(ctx, CredentialService_FindPermissionByPathAndActor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *credentialServiceClient) PutPermissions(ctx context.Context, in *PutPermissionsRequest, opts ...grpc.CallOption) (*PermissionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PermissionsResponse)
+	err := c.cc.Invoke(ctx, CredentialService_PutPermissions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *credentialServiceClient) PatchPermissions(ctx context.Context, in *PatchPermissionsRequest, opts ...grpc.CallOption) (*PermissionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PermissionsResponse)
+	err := c.cc.Invoke(ctx, CredentialService_PatchPermissions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *credentialServiceClient) DeletePermission(ctx context.Context, in *PermissionByUUIDRequest, opts ...grpc.CallOption) (*PermissionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PermissionsResponse)
+	err := c.cc.Invoke(ctx, CredentialService_DeletePermission_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CredentialServiceServer is the server API for CredentialService service.
+// All implementations must embed UnimplementedCredentialServiceServer
+// for forward compatibility.
+type CredentialServiceServer interface {
+	GetByName(context.Context, *GetByNameRequest) (*GetResponse, error)
+	GetById(context.Context, *GetByIdRequest) (*GetResponse, error)
+	GetNVersions(context.Context, *GetNVersionsRequest) (*GetNVersionsResponse, error)
+	FindContainingName(context.Context, *FindContainingNameRequest) (*FindResponse, error)
+	FindStartingWithPath(context.Context, *FindStartingWithPathRequest) (*FindResponse, error)
+	Set(context.Context, *SetRequest) (*SetResponse, error)
+	Delete(context.Context, *DeleteByNameRequest) (*DeleteByNameResponse, error)
+	AddPermission(context.Context, *PermissionsRequest) (*PermissionsResponse, error)
+	GetPermissionByUUID(context.Context, *PermissionByUUIDRequest) (*PermissionsResponse, error)
+	FindPermissionByPathAndActor(context.Context, *FindPermissionByPathAndActorRequest) (*PermissionsResponse, error)
+	PutPermissions(context.Context, *PutPermissionsRequest) (*PermissionsResponse, error)
+	PatchPermissions(context.Context, *PatchPermissionsRequest) (*PermissionsResponse, error)
+	DeletePermission(context.Context, *PermissionByUUIDRequest) (*PermissionsResponse, error)
+	mustEmbedUnimplementedCredentialServiceServer()
+}
+
+// UnimplementedCredentialServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCredentialServiceServer struct{}
+
+func (UnimplementedCredentialServiceServer) GetByName(context.Context, *GetByNameRequest) (*GetResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetByName not implemented")
+}
+func (UnimplementedCredentialServiceServer) GetById(context.Context, *GetByIdRequest) (*GetResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetById not implemented")
+}
+func (UnimplementedCredentialServiceServer) GetNVersions(context.Context, *GetNVersionsRequest) (*GetNVersionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetNVersions not implemented")
+}
+func (UnimplementedCredentialServiceServer) FindContainingName(context.Context, *FindContainingNameRequest) (*FindResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method FindContainingName not implemented")
+}
+func (UnimplementedCredentialServiceServer) FindStartingWithPath(context.Context, *FindStartingWithPathRequest) (*FindResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method FindStartingWithPath not implemented")
+}
+func (UnimplementedCredentialServiceServer) Set(context.Context, *SetRequest) (*SetResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Set not implemented")
+}
+func (UnimplementedCredentialServiceServer) Delete(context.Context, *DeleteByNameRequest) (*DeleteByNameResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedCredentialServiceServer) AddPermission(context.Context, *PermissionsRequest) (*PermissionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AddPermission not implemented")
+}
+func (UnimplementedCredentialServiceServer) GetPermissionByUUID(context.Context, *PermissionByUUIDRequest) (*PermissionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetPermissionByUUID not implemented")
+}
+func (UnimplementedCredentialServiceServer) FindPermissionByPathAndActor(context.Context, *FindPermissionByPathAndActorRequest) (*PermissionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method FindPermissionByPathAndActor not implemented")
+}
+func (UnimplementedCredentialServiceServer) PutPermissions(context.Context, *PutPermissionsRequest) (*PermissionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method PutPermissions not implemented")
+}
+func (UnimplementedCredentialServiceServer) PatchPermissions(context.Context, *PatchPermissionsRequest) (*PermissionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method PatchPermissions not implemented")
+}
+func (UnimplementedCredentialServiceServer) DeletePermission(context.Context, *PermissionByUUIDRequest) (*PermissionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeletePermission not implemented")
+}
+func (UnimplementedCredentialServiceServer) mustEmbedUnimplementedCredentialServiceServer() {}
+func (UnimplementedCredentialServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeCredentialServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CredentialServiceServer will
+// result in compilation errors.
+type UnsafeCredentialServiceServer interface {
+	mustEmbedUnimplementedCredentialServiceServer()
+}
+
+func RegisterCredentialServiceServer(s grpc.ServiceRegistrar, srv CredentialServiceServer) {
+	// If the following call panics, it indicates UnimplementedCredentialServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CredentialService_ServiceDesc, srv)
+}
+
+func _CredentialService_GetByName_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetByNameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CredentialServiceServer).GetByName(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CredentialService_GetByName_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CredentialServiceServer).GetByName(ctx, req.(*GetByNameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CredentialService_GetById_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetByIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CredentialServiceServer).GetById(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CredentialService_GetById_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CredentialServiceServer).GetById(ctx, req.(*GetByIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CredentialService_GetNVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CredentialServiceServer).GetNVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CredentialService_GetNVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CredentialServiceServer).GetNVersions(ctx, req.(*GetNVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CredentialService_FindContainingName_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindContainingNameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CredentialServiceServer).FindContainingName(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CredentialService_FindContainingName_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CredentialServiceServer).FindContainingName(ctx, req.(*FindContainingNameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CredentialService_FindStartingWithPath_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindStartingWithPathRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CredentialServiceServer).FindStartingWithPath(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CredentialService_FindStartingWithPath_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CredentialServiceServer).FindStartingWithPath(ctx, req.(*FindStartingWithPathRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CredentialService_Set_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CredentialServiceServer).Set(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CredentialService_Set_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CredentialServiceServer).Set(ctx, req.(*SetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CredentialService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteByNameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CredentialServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CredentialService_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CredentialServiceServer).Delete(ctx, req.(*DeleteByNameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CredentialService_AddPermission_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PermissionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CredentialServiceServer).AddPermission(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CredentialService_AddPermission_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CredentialServiceServer).AddPermission(ctx, req.(*PermissionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CredentialService_GetPermissionByUUID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PermissionByUUIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CredentialServiceServer).GetPermissionByUUID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CredentialService_GetPermissionByUUID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CredentialServiceServer).GetPermissionByUUID(ctx, req.(*PermissionByUUIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CredentialService_FindPermissionByPathAndActor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindPermissionByPathAndActorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CredentialServiceServer).FindPermissionByPathAndActor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CredentialService_FindPermissionByPathAndActor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CredentialServiceServer).FindPermissionByPathAndActor(ctx, req.(*FindPermissionByPathAndActorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CredentialService_PutPermissions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutPermissionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CredentialServiceServer).PutPermissions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CredentialService_PutPermissions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CredentialServiceServer).PutPermissions(ctx, req.(*PutPermissionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CredentialService_PatchPermissions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PatchPermissionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CredentialServiceServer).PatchPermissions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CredentialService_PatchPermissions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CredentialServiceServer).PatchPermissions(ctx, req.(*PatchPermissionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CredentialService_DeletePermission_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PermissionByUUIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CredentialServiceServer).DeletePermission(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CredentialService_DeletePermission_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CredentialServiceServer).DeletePermission(ctx, req.(*PermissionByUUIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CredentialService_ServiceDesc is the grpc.ServiceDesc for CredentialService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CredentialService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "credhub.remote.grpc.CredentialService",
+	HandlerType: (*CredentialServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetByName",
+			Handler:    _CredentialService_GetByName_Handler,
+		},
+		{
+			MethodName: "GetById",
+			Handler:    _CredentialService_GetById_Handler,
+		},
+		{
+			MethodName: "GetNVersions",
+			Handler:    _CredentialService_GetNVersions_Handler,
+		},
+		{
+			MethodName: "FindContainingName",
+			Handler:    _CredentialService_FindContainingName_Handler,
+		},
+		{
+			MethodName: "FindStartingWithPath",
+			Handler:    _CredentialService_FindStartingWithPath_Handler,
+		},
+		{
+			MethodName: "Set",
+			Handler:    _CredentialService_Set_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _CredentialService_Delete_Handler,
+		},
+		{
+			MethodName: "AddPermission",
+			Handler:    _CredentialService_AddPermission_Handler,
+		},
+		{
+			MethodName: "GetPermissionByUUID",
+			Handler:    _CredentialService_GetPermissionByUUID_Handler,
+		},
+		{
+			MethodName: "FindPermissionByPathAndActor",
+			Handler:    _CredentialService_FindPermissionByPathAndActor_Handler,
+		},
+		{
+			MethodName: "PutPermissions",
+			Handler:    _CredentialService_PutPermissions_Handler,
+		},
+		{
+			MethodName: "PatchPermissions",
+			Handler:    _CredentialService_PatchPermissions_Handler,
+		},
+		{
+			MethodName: "DeletePermission",
+			Handler:    _CredentialService_DeletePermission_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "remote_backend.proto",
+}
@@ -0,0 +1,237 @@
+package remotebackend
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// ServiceName is the fully qualified name of the CredentialService.
+const ServiceName = "credhub.remote.grpc.CredentialService"
+
+// Options configures a Backend.
+type Options struct {
+	// SocketPath is the unix socket to listen on. A stale socket file left
+	// behind by an earlier run is removed.
+	SocketPath string
+	// Certificate, when set, makes the backend serve TLS, as CredHub expects
+	// when it is configured with a CA for its remote backend.
+	Certificate *tls.Certificate
+	// Seed is stored before the backend starts serving, and again on every
+	// Reset.
+	Seed []*GetResponse
+}
+
+// Call is a request the backend received.
+type Call struct {
+	Method   string
+	Request  proto.Message
+	Response proto.Message
+	Code     codes.Code
+	Time     time.Time
+}
+
+// Fault makes calls to a method fail with a status instead of reaching the
+// store.
+type Fault struct {
+	Code    codes.Code
+	Message string
+	// Times is how many calls fail; zero fails every call until the faults
+	// are cleared.
+	Times int
+}
+
+// Backend is an in-memory implementation of CredHub's remote backend
+// service, which logs every call and can be made slow or failing.
+type Backend struct {
+	options Options
+	store   *store
+	server  *grpc.Server
+
+	mu        sync.Mutex
+	calls     []Call
+	faults    map[string]*Fault
+	latencies map[string]time.Duration
+}
+
+// Start listens on the socket and serves until Stop is called.
+func Start(options Options) (*Backend, error) {
+	if options.SocketPath == "" {
+		return nil, errors.New("remotebackend: a socket path is required")
+	}
+	if err := os.Remove(options.SocketPath); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	listener, err := net.Listen("unix", options.SocketPath)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Backend{
+		options:   options,
+		store:     newStore(),
+		faults:    map[string]*Fault{},
+		latencies: map[string]time.Duration{},
+	}
+	b.seed()
+
+	serverOptions := []grpc.ServerOption{grpc.UnaryInterceptor(b.intercept)}
+	if options.Certificate != nil {
+		serverOptions = append(serverOptions, grpc.Creds(credentials.NewTLS(&tls.Config{
+			Certificates: []tls.Certificate{*options.Certificate},
+		})))
+	}
+	b.server = grpc.NewServer(serverOptions...)
+	RegisterCredentialServiceServer(b.server, &service{store: b.store})
+	go b.server.Serve(listener)
+
+	return b, nil
+}
+
+// Stop stops serving and removes the socket.
+func (b *Backend) Stop() {
+	b.server.Stop()
+	os.Remove(b.options.SocketPath)
+}
+
+// Reset empties the store and stores the seed again. The call log and faults
+// are kept.
+func (b *Backend) Reset() {
+	b.store.reset()
+	b.seed()
+}
+
+// Put stores a credential version directly, without a call being logged.
+func (b *Backend) Put(version *GetResponse) *GetResponse {
+	return b.store.put(version)
+}
+
+func (b *Backend) seed() {
+	for _, version := range b.options.Seed {
+		b.store.put(version)
+	}
+}
+
+// Calls returns the calls received since the log was last reset, oldest
+// first.
+func (b *Backend) Calls() []Call {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]Call(nil), b.calls...)
+}
+
+func (b *Backend) ResetCalls() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.calls = nil
+}
+
+// InjectFault makes calls to method, e.g. "GetByName", fail. An empty method
+// matches every method that has no fault of its own.
+func (b *Backend) InjectFault(method string, fault Fault) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.faults[method] = &fault
+}
+
+// SetLatency delays every call to method by latency. An empty method matches
+// every method that has no latency of its own.
+func (b *Backend) SetLatency(method string, latency time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.latencies[method] = latency
+}
+
+// ClearFaults removes every fault and latency.
+func (b *Backend) ClearFaults() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.faults = map[string]*Fault{}
+	b.latencies = map[string]time.Duration{}
+}
+
+// takeFault returns the latency and fault for a call to method, counting the
+// call against the fault.
+func (b *Backend) takeFault(method string) (time.Duration, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	latency, ok := b.latencies[method]
+	if !ok {
+		latency = b.latencies[""]
+	}
+
+	key := method
+	fault := b.faults[key]
+	if fault == nil {
+		key = ""
+		fault = b.faults[key]
+	}
+	if fault == nil {
+		return latency, nil
+	}
+	if fault.Times > 0 {
+		fault.Times--
+		if fault.Times == 0 {
+			delete(b.faults, key)
+		}
+	}
+	return latency, status.Error(fault.Code, fault.Message)
+}
+
+func (b *Backend) intercept(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	method := path.Base(info.FullMethod)
+	call := Call{Method: method, Request: req.(proto.Message), Time: time.Now()}
+
+	latency, err := b.takeFault(method)
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-ctx.Done():
+			err = status.FromContextError(ctx.Err()).Err()
+		}
+	}
+
+	var resp interface{}
+	if err == nil {
+		resp, err = handler(ctx, req)
+	}
+	if err == nil {
+		call.Response = resp.(proto.Message)
+	}
+	call.Code = status.Code(err)
+
+	b.mu.Lock()
+	b.calls = append(b.calls, call)
+	b.mu.Unlock()
+
+	return resp, err
+}
+
+// Dial connects to a backend's socket. config is nil for a backend that does
+// not serve TLS.
+func Dial(socketPath string, config *tls.Config) (*grpc.ClientConn, error) {
+	transport := insecure.NewCredentials()
+	if config != nil {
+		transport = credentials.NewTLS(config)
+	}
+	return grpc.NewClient("unix://"+socketPath, grpc.WithTransportCredentials(transport))
+}
+
+// FullMethod is the name grpc uses for a method of the CredentialService,
+// for use with (*grpc.ClientConn).Invoke.
+func FullMethod(method string) string {
+	return "/" + ServiceName + "/" + method
+}
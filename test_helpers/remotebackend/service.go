@@ -0,0 +1,65 @@
+package remotebackend
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative remote_backend.proto
+
+import (
+	"context"
+)
+
+// service serves the CredentialService from a store.
+type service struct {
+	UnimplementedCredentialServiceServer
+	store *store
+}
+
+func (s *service) GetByName(_ context.Context, req *GetByNameRequest) (*GetResponse, error) {
+	return s.store.getByName(req)
+}
+
+func (s *service) GetById(_ context.Context, req *GetByIdRequest) (*GetResponse, error) {
+	return s.store.getByID(req)
+}
+
+func (s *service) GetNVersions(_ context.Context, req *GetNVersionsRequest) (*GetNVersionsResponse, error) {
+	return s.store.getNVersions(req)
+}
+
+func (s *service) FindContainingName(_ context.Context, req *FindContainingNameRequest) (*FindResponse, error) {
+	return s.store.findContainingName(req)
+}
+
+func (s *service) FindStartingWithPath(_ context.Context, req *FindStartingWithPathRequest) (*FindResponse, error) {
+	return s.store.findStartingWithPath(req)
+}
+
+func (s *service) Set(_ context.Context, req *SetRequest) (*SetResponse, error) {
+	return s.store.set(req)
+}
+
+func (s *service) Delete(_ context.Context, req *DeleteByNameRequest) (*DeleteByNameResponse, error) {
+	return s.store.delete(req)
+}
+
+func (s *service) AddPermission(_ context.Context, req *PermissionsRequest) (*PermissionsResponse, error) {
+	return s.store.addPermission(req)
+}
+
+func (s *service) GetPermissionByUUID(_ context.Context, req *PermissionByUUIDRequest) (*PermissionsResponse, error) {
+	return s.store.getPermissionByUUID(req)
+}
+
+func (s *service) FindPermissionByPathAndActor(_ context.Context, req *FindPermissionByPathAndActorRequest) (*PermissionsResponse, error) {
+	return s.store.findPermissionByPathAndActor(req)
+}
+
+func (s *service) PutPermissions(_ context.Context, req *PutPermissionsRequest) (*PermissionsResponse, error) {
+	return s.store.putPermissions(req)
+}
+
+func (s *service) PatchPermissions(_ context.Context, req *PatchPermissionsRequest) (*PermissionsResponse, error) {
+	return s.store.patchPermissions(req)
+}
+
+func (s *service) DeletePermission(_ context.Context, req *PermissionByUUIDRequest) (*PermissionsResponse, error) {
+	return s.store.deletePermission(req)
+}
@@ -0,0 +1,282 @@
+package remotebackend
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	errCredentialNotFound = status.Error(codes.NotFound, "credential not found")
+	errPermissionNotFound = status.Error(codes.NotFound, "permission not found")
+	errPermissionExists   = status.Error(codes.AlreadyExists, "permission already exists")
+)
+
+// DefaultSeed is the credential the remote_backend suite expects the backend
+// to hold before any spec runs.
+var DefaultSeed = []*GetResponse{{
+	Name: "/my-value",
+	Type: "value",
+	Data: []byte(`"test-value"`),
+	Id:   "00000000-0000-0000-0000-000000000001",
+}}
+
+// store keeps credential versions and permissions in memory. Names and paths
+// are matched case-insensitively, as CredHub matches them.
+type store struct {
+	mu          sync.Mutex
+	versions    map[string][]*GetResponse
+	byID        map[string]*GetResponse
+	permissions map[string]*PermissionsResponse
+	written     map[*GetResponse]int
+	writes      int
+}
+
+func newStore() *store {
+	s := &store{}
+	s.reset()
+	return s
+}
+
+func (s *store) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.versions = map[string][]*GetResponse{}
+	s.byID = map[string]*GetResponse{}
+	s.permissions = map[string]*PermissionsResponse{}
+	s.written = map[*GetResponse]int{}
+}
+
+// put stores a copy of a version as it is, generating an id and timestamp
+// if it has none, and returns another copy.
+func (s *store) put(version *GetResponse) *GetResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := clone(version)
+	if stored.Id == "" {
+		stored.Id = uuid.NewString()
+	}
+	if stored.VersionCreatedAt == "" {
+		stored.VersionCreatedAt = time.Now().UTC().Format(time.RFC3339Nano)
+	}
+	key := strings.ToLower(stored.Name)
+	s.versions[key] = append(s.versions[key], stored)
+	s.byID[stored.Id] = stored
+	s.writes++
+	s.written[stored] = s.writes
+	return clone(stored)
+}
+
+func (s *store) set(req *SetRequest) (*SetResponse, error) {
+	if req.Name == "" || req.Type == "" {
+		return nil, status.Error(codes.InvalidArgument, "name and type are required")
+	}
+	version := s.put(&GetResponse{
+		Name:                 req.Name,
+		Type:                 req.Type,
+		Data:                 req.Data,
+		GenerationParameters: req.GenerationParameters,
+	})
+	return &SetResponse{
+		Name:                 version.Name,
+		Type:                 version.Type,
+		Data:                 version.Data,
+		Id:                   version.Id,
+		VersionCreatedAt:     version.VersionCreatedAt,
+		GenerationParameters: version.GenerationParameters,
+	}, nil
+}
+
+func (s *store) getByName(req *GetByNameRequest) (*GetResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	versions := s.versions[strings.ToLower(req.Name)]
+	if len(versions) == 0 {
+		return nil, errCredentialNotFound
+	}
+	return clone(versions[len(versions)-1]), nil
+}
+
+func (s *store) getByID(req *GetByIdRequest) (*GetResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	version, ok := s.byID[req.Id]
+	if !ok {
+		return nil, errCredentialNotFound
+	}
+	return clone(version), nil
+}
+
+func (s *store) getNVersions(req *GetNVersionsRequest) (*GetNVersionsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	versions := s.versions[strings.ToLower(req.Name)]
+	if len(versions) == 0 {
+		return nil, errCredentialNotFound
+	}
+	if req.NumberOfVersions < 0 {
+		return nil, status.Error(codes.InvalidArgument, "number of versions must not be negative")
+	}
+
+	response := &GetNVersionsResponse{}
+	for i := len(versions) - 1; i >= 0; i-- {
+		if req.NumberOfVersions > 0 && len(response.Versions) == int(req.NumberOfVersions) {
+			break
+		}
+		response.Versions = append(response.Versions, clone(versions[i]))
+	}
+	return response, nil
+}
+
+func (s *store) delete(req *DeleteByNameRequest) (*DeleteByNameResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := strings.ToLower(req.Name)
+	versions := s.versions[key]
+	if len(versions) == 0 {
+		return nil, errCredentialNotFound
+	}
+	for _, version := range versions {
+		delete(s.byID, version.Id)
+		delete(s.written, version)
+	}
+	delete(s.versions, key)
+	return &DeleteByNameResponse{}, nil
+}
+
+func (s *store) findContainingName(req *FindContainingNameRequest) (*FindResponse, error) {
+	needle := strings.ToLower(req.Name)
+	return s.find(func(key string) bool {
+		return strings.Contains(key, needle)
+	}), nil
+}
+
+func (s *store) findStartingWithPath(req *FindStartingWithPathRequest) (*FindResponse, error) {
+	prefix := strings.ToLower(req.Path)
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	if !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	return s.find(func(key string) bool {
+		return strings.HasPrefix(key, prefix)
+	}), nil
+}
+
+// find returns the latest version of every matching credential, most
+// recently written first.
+func (s *store) find(matches func(key string) bool) *FindResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var latest []*GetResponse
+	for key, versions := range s.versions {
+		if matches(key) {
+			latest = append(latest, versions[len(versions)-1])
+		}
+	}
+	sort.Slice(latest, func(i, j int) bool {
+		return s.written[latest[i]] > s.written[latest[j]]
+	})
+
+	response := &FindResponse{}
+	for _, version := range latest {
+		response.Results = append(response.Results, &FindResult{Name: version.Name, VersionCreatedAt: version.VersionCreatedAt})
+	}
+	return response
+}
+
+func (s *store) addPermission(req *PermissionsRequest) (*PermissionsResponse, error) {
+	if req.Path == "" || req.Actor == "" || len(req.Operations) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "path, actor and operations are required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.findPermissionLocked(req.Path, req.Actor) != nil {
+		return nil, errPermissionExists
+	}
+	permission := &PermissionsResponse{
+		Uuid:       uuid.NewString(),
+		Path:       req.Path,
+		Actor:      req.Actor,
+		Operations: append([]string(nil), req.Operations...),
+	}
+	s.permissions[permission.Uuid] = permission
+	return clone(permission), nil
+}
+
+func (s *store) getPermissionByUUID(req *PermissionByUUIDRequest) (*PermissionsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	permission, ok := s.permissions[req.Uuid]
+	if !ok {
+		return nil, errPermissionNotFound
+	}
+	return clone(permission), nil
+}
+
+func (s *store) findPermissionByPathAndActor(req *FindPermissionByPathAndActorRequest) (*PermissionsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	permission := s.findPermissionLocked(req.Path, req.Actor)
+	if permission == nil {
+		return nil, errPermissionNotFound
+	}
+	return clone(permission), nil
+}
+
+func (s *store) putPermissions(req *PutPermissionsRequest) (*PermissionsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	permission, ok := s.permissions[req.Uuid]
+	if !ok {
+		return nil, errPermissionNotFound
+	}
+	if permission.Path != req.Path || permission.Actor != req.Actor {
+		return nil, status.Error(codes.InvalidArgument, "the path and actor of a permission cannot be changed")
+	}
+	permission.Operations = append([]string(nil), req.Operations...)
+	return clone(permission), nil
+}
+
+func (s *store) patchPermissions(req *PatchPermissionsRequest) (*PermissionsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	permission, ok := s.permissions[req.Uuid]
+	if !ok {
+		return nil, errPermissionNotFound
+	}
+	permission.Operations = append([]string(nil), req.Operations...)
+	return clone(permission), nil
+}
+
+func (s *store) deletePermission(req *PermissionByUUIDRequest) (*PermissionsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	permission, ok := s.permissions[req.Uuid]
+	if !ok {
+		return nil, errPermissionNotFound
+	}
+	delete(s.permissions, req.Uuid)
+	return clone(permission), nil
+}
+
+func (s *store) findPermissionLocked(path, actor string) *PermissionsResponse {
+	for _, permission := range s.permissions {
+		if strings.EqualFold(permission.Path, path) && permission.Actor == actor {
+			return permission
+		}
+	}
+	return nil
+}
+
+func clone[M proto.Message](m M) M {
+	return proto.Clone(m).(M)
+}
@@ -0,0 +1,261 @@
+package reporting
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+type Driver string
+
+const (
+	CLI      Driver = "cli"
+	GoClient Driver = "go-client"
+	HTTP     Driver = "http"
+)
+
+var Drivers = []Driver{CLI, GoClient, HTTP}
+
+var CredentialTypes = []string{"value", "json", "password", "user", "certificate", "rsa", "ssh"}
+
+// Endpoint identifies a CredHub API endpoint. Operation distinguishes calls
+// that share a method and path, e.g. generate and regenerate on POST /api/v1/data.
+type Endpoint struct {
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	Operation string `json:"operation,omitempty"`
+}
+
+func (e Endpoint) String() string {
+	if e.Operation == "" {
+		return e.Method + " " + e.Path
+	}
+	return e.Method + " " + e.Path + " [" + e.Operation + "]"
+}
+
+type catalogueEntry struct {
+	Endpoint Endpoint
+	Types    []string
+}
+
+var generatableTypes = []string{"password", "user", "certificate", "rsa", "ssh"}
+
+// catalogue lists the endpoints we expect the suites to cover. Endpoints with
+// Types are expected to be covered once per credential type.
+var catalogue = []catalogueEntry{
+	{Endpoint{"GET", "/api/v1/data", "get"}, CredentialTypes},
+	{Endpoint{"GET", "/api/v1/data/{id}", "get-by-id"}, CredentialTypes},
+	{Endpoint{"GET", "/api/v1/data", "find"}, nil},
+	{Endpoint{"PUT", "/api/v1/data", "set"}, CredentialTypes},
+	{Endpoint{"POST", "/api/v1/data", "generate"}, generatableTypes},
+	{Endpoint{"POST", "/api/v1/data", "regenerate"}, generatableTypes},
+	{Endpoint{"DELETE", "/api/v1/data", "delete"}, nil},
+	{Endpoint{"POST", "/api/v1/bulk-regenerate", ""}, nil},
+	{Endpoint{"POST", "/api/v1/interpolate", ""}, nil},
+	{Endpoint{"GET", "/api/v1/certificates", ""}, nil},
+	{Endpoint{"GET", "/api/v1/certificates/{id}/versions", ""}, nil},
+	{Endpoint{"POST", "/api/v1/certificates/{id}/versions", ""}, nil},
+	{Endpoint{"DELETE", "/api/v1/certificates/{id}/versions/{id}", ""}, nil},
+	{Endpoint{"POST", "/api/v1/certificates/{id}/regenerate", ""}, nil},
+	{Endpoint{"PUT", "/api/v1/certificates/{id}/update_transitional_version", ""}, nil},
+	{Endpoint{"GET", "/api/v1/permissions", ""}, nil},
+	{Endpoint{"POST", "/api/v1/permissions", ""}, nil},
+	{Endpoint{"DELETE", "/api/v1/permissions", ""}, nil},
+	{Endpoint{"GET", "/api/v2/permissions", ""}, nil},
+	{Endpoint{"POST", "/api/v2/permissions", ""}, nil},
+	{Endpoint{"GET", "/api/v2/permissions/{id}", ""}, nil},
+	{Endpoint{"PUT", "/api/v2/permissions/{id}", ""}, nil},
+	{Endpoint{"PATCH", "/api/v2/permissions/{id}", ""}, nil},
+	{Endpoint{"DELETE", "/api/v2/permissions/{id}", ""}, nil},
+	{Endpoint{"GET", "/api/v1/key-usage", ""}, nil},
+	{Endpoint{"GET", "/info", ""}, nil},
+	{Endpoint{"GET", "/version", ""}, nil},
+	{Endpoint{"GET", "/health", ""}, nil},
+}
+
+// Catalogue returns every catalogued endpoint.
+func Catalogue() []Endpoint {
+	endpoints := make([]Endpoint, len(catalogue))
+	for i, entry := range catalogue {
+		endpoints[i] = entry.Endpoint
+	}
+	return endpoints
+}
+
+var idSegment = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// NormalizePath strips trailing slashes and replaces UUID segments with {id}.
+func NormalizePath(path string) string {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	segments := strings.Split(strings.TrimRight(path, "/"), "/")
+	for i, segment := range segments {
+		if idSegment.MatchString(segment) {
+			segments[i] = "{id}"
+		}
+	}
+	normalized := strings.Join(segments, "/")
+	if normalized == "" {
+		return "/"
+	}
+	return normalized
+}
+
+// Classify maps a request onto an Endpoint. It returns false for requests
+// that are not made against the CredHub API, e.g. UAA token requests.
+func Classify(method string, u *url.URL, body []byte) (Endpoint, bool) {
+	path := NormalizePath(u.Path)
+	if !isCredHubPath(path) {
+		return Endpoint{}, false
+	}
+
+	endpoint := Endpoint{Method: strings.ToUpper(method), Path: path}
+	switch {
+	case path == "/api/v1/data" && endpoint.Method == "GET":
+		query := u.Query()
+		if query.Has("name-like") || query.Has("path") {
+			endpoint.Operation = "find"
+		} else {
+			endpoint.Operation = "get"
+		}
+	case path == "/api/v1/data/{id}" && endpoint.Method == "GET":
+		endpoint.Operation = "get-by-id"
+	case path == "/api/v1/data" && endpoint.Method == "PUT":
+		endpoint.Operation = "set"
+	case path == "/api/v1/data" && endpoint.Method == "POST":
+		var request struct {
+			Regenerate bool `json:"regenerate"`
+		}
+		if json.Unmarshal(body, &request) == nil && request.Regenerate {
+			endpoint.Operation = "regenerate"
+		} else {
+			endpoint.Operation = "generate"
+		}
+	case path == "/api/v1/data" && endpoint.Method == "DELETE":
+		endpoint.Operation = "delete"
+	}
+
+	return endpoint, true
+}
+
+func isCredHubPath(path string) bool {
+	return strings.HasPrefix(path, "/api/") || path == "/info" || path == "/version" || path == "/health"
+}
+
+// CredentialType extracts the credential type from a CredHub request or
+// response body, returning "" when the body does not describe a credential.
+func CredentialType(bodies ...[]byte) string {
+	for _, body := range bodies {
+		var parsed struct {
+			Type string `json:"type"`
+			Data []struct {
+				Type string `json:"type"`
+			} `json:"data"`
+		}
+		if json.Unmarshal(body, &parsed) != nil {
+			continue
+		}
+		if isCredentialType(parsed.Type) {
+			return parsed.Type
+		}
+		if len(parsed.Data) > 0 && isCredentialType(parsed.Data[0].Type) {
+			return parsed.Data[0].Type
+		}
+	}
+	return ""
+}
+
+func isCredentialType(credentialType string) bool {
+	for _, t := range CredentialTypes {
+		if t == credentialType {
+			return true
+		}
+	}
+	return false
+}
+
+var cliOutputType = regexp.MustCompile(`(?m)^\s*"?type"?:\s*"?([a-z]+)"?`)
+
+var cliEndpoints = map[string]Endpoint{
+	"get":               {"GET", "/api/v1/data", "get"},
+	"set":               {"PUT", "/api/v1/data", "set"},
+	"generate":          {"POST", "/api/v1/data", "generate"},
+	"regenerate":        {"POST", "/api/v1/data", "regenerate"},
+	"find":              {"GET", "/api/v1/data", "find"},
+	"export":            {"GET", "/api/v1/data", "find"},
+	"import":            {"PUT", "/api/v1/data", "set"},
+	"delete":            {"DELETE", "/api/v1/data", "delete"},
+	"bulk-regenerate":   {"POST", "/api/v1/bulk-regenerate", ""},
+	"interpolate":       {"POST", "/api/v1/interpolate", ""},
+	"get-permission":    {"GET", "/api/v2/permissions", ""},
+	"set-permission":    {"POST", "/api/v2/permissions", ""},
+	"delete-permission": {"DELETE", "/api/v2/permissions/{id}", ""},
+}
+
+// ClassifyCommand maps credhub CLI arguments onto the Endpoint the command
+// exercises, using the command's output to find the credential type when it
+// is not given on the command line.
+func ClassifyCommand(args []string, stdout []byte) (Call, bool) {
+	if len(args) == 0 {
+		return Call{}, false
+	}
+
+	command := args[0]
+	call := Call{Driver: CLI, Command: command}
+
+	if command == "curl" {
+		method := flagValue(args, "-X", "--method")
+		if method == "" {
+			method = "GET"
+		}
+		body := flagValue(args, "-d", "--data")
+		u, err := url.Parse(flagValue(args, "-p", "--path"))
+		if err != nil {
+			return Call{}, false
+		}
+		endpoint, ok := Classify(method, u, []byte(body))
+		if !ok {
+			return Call{}, false
+		}
+		call.Endpoint = endpoint
+		call.CredentialType = CredentialType([]byte(body), stdout)
+		return call, true
+	}
+
+	endpoint, ok := cliEndpoints[command]
+	if !ok {
+		return Call{}, false
+	}
+	if command == "get" && flagValue(args, "--id") != "" {
+		endpoint = Endpoint{"GET", "/api/v1/data/{id}", "get-by-id"}
+	}
+	call.Endpoint = endpoint
+
+	call.CredentialType = flagValue(args, "-t", "--type")
+	if call.CredentialType == "" && endpoint.Operation != "find" && endpoint.Operation != "delete" {
+		if match := cliOutputType.FindSubmatch(stdout); match != nil && isCredentialType(string(match[1])) {
+			call.CredentialType = string(match[1])
+		}
+	}
+
+	return call, true
+}
+
+func flagValue(args []string, names ...string) string {
+	for i, arg := range args {
+		for _, name := range names {
+			if arg == name && i+1 < len(args) {
+				return args[i+1]
+			}
+			if strings.HasPrefix(arg, name+"=") {
+				return strings.TrimPrefix(arg, name+"=")
+			}
+			if len(name) == 2 && strings.HasPrefix(arg, name) && len(arg) > 2 && !strings.HasPrefix(arg, "--") {
+				return arg[2:]
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,100 @@
+package reporting_test
+
+import (
+	"net/url"
+
+	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/reporting"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Endpoints", func() {
+	Describe("NormalizePath", func() {
+		It("replaces ids and trims trailing slashes", func() {
+			Expect(NormalizePath("/api/v1/certificates/")).To(Equal("/api/v1/certificates"))
+			Expect(NormalizePath("api/v1/certificates/0b5d5d8b-7f2a-4a43-9c56-f2bd0a8a2f3e/versions")).To(Equal("/api/v1/certificates/{id}/versions"))
+			Expect(NormalizePath("/")).To(Equal("/"))
+		})
+	})
+
+	Describe("Classify", func() {
+		classify := func(method, rawURL, body string) (Endpoint, bool) {
+			u, err := url.Parse(rawURL)
+			Expect(err).NotTo(HaveOccurred())
+			return Classify(method, u, []byte(body))
+		}
+
+		It("distinguishes operations on the data endpoint", func() {
+			endpoint, ok := classify("GET", "/api/v1/data?name=/foo", "")
+			Expect(ok).To(BeTrue())
+			Expect(endpoint).To(Equal(Endpoint{"GET", "/api/v1/data", "get"}))
+
+			endpoint, _ = classify("GET", "/api/v1/data?path=/foo", "")
+			Expect(endpoint.Operation).To(Equal("find"))
+
+			endpoint, _ = classify("post", "/api/v1/data", `{"name":"/foo","regenerate":true}`)
+			Expect(endpoint).To(Equal(Endpoint{"POST", "/api/v1/data", "regenerate"}))
+
+			endpoint, _ = classify("POST", "/api/v1/data", `{"name":"/foo","type":"password"}`)
+			Expect(endpoint.Operation).To(Equal("generate"))
+
+			endpoint, _ = classify("GET", "/api/v1/data/0b5d5d8b-7f2a-4a43-9c56-f2bd0a8a2f3e", "")
+			Expect(endpoint).To(Equal(Endpoint{"GET", "/api/v1/data/{id}", "get-by-id"}))
+		})
+
+		It("ignores requests outside the CredHub API", func() {
+			_, ok := classify("POST", "https://uaa.example.com/oauth/token", "")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("CredentialType", func() {
+		It("reads the type from a credential or a list of credentials", func() {
+			Expect(CredentialType([]byte(`{"type":"ssh"}`))).To(Equal("ssh"))
+			Expect(CredentialType(nil, []byte(`{"data":[{"type":"rsa"}]}`))).To(Equal("rsa"))
+			Expect(CredentialType([]byte(`{"type":"unknown"}`))).To(BeEmpty())
+			Expect(CredentialType([]byte(`not json`))).To(BeEmpty())
+		})
+	})
+
+	Describe("ClassifyCommand", func() {
+		It("uses the type flag when given", func() {
+			call, ok := ClassifyCommand([]string{"generate", "-n", "foo", "-t", "user"}, nil)
+			Expect(ok).To(BeTrue())
+			Expect(call.Driver).To(Equal(CLI))
+			Expect(call.Command).To(Equal("generate"))
+			Expect(call.Endpoint).To(Equal(Endpoint{"POST", "/api/v1/data", "generate"}))
+			Expect(call.CredentialType).To(Equal("user"))
+		})
+
+		It("falls back to the type in the command output", func() {
+			call, _ := ClassifyCommand([]string{"regenerate", "-n", "foo"}, []byte("id: 1\nname: /foo\ntype: rsa\n"))
+			Expect(call.CredentialType).To(Equal("rsa"))
+
+			call, _ = ClassifyCommand([]string{"get", "-n", "foo", "-j"}, []byte("{\n  \"id\": \"1\",\n  \"type\": \"json\"\n}"))
+			Expect(call.CredentialType).To(Equal("json"))
+		})
+
+		It("classifies curl by its method, path and body", func() {
+			call, ok := ClassifyCommand([]string{"curl", "-XPOST", "-p", "/api/v1/interpolate", "-d", "{}"}, nil)
+			Expect(ok).To(BeTrue())
+			Expect(call.Endpoint).To(Equal(Endpoint{"POST", "/api/v1/interpolate", ""}))
+
+			call, _ = ClassifyCommand([]string{"curl", "-p", "api/v1/certificates?name=/foo"}, nil)
+			Expect(call.Endpoint).To(Equal(Endpoint{"GET", "/api/v1/certificates", ""}))
+		})
+
+		It("classifies get by id", func() {
+			call, _ := ClassifyCommand([]string{"get", "--id", "some-id"}, nil)
+			Expect(call.Endpoint.Operation).To(Equal("get-by-id"))
+		})
+
+		It("ignores commands that do not call the API", func() {
+			_, ok := ClassifyCommand([]string{"login", "-s", "https://example.com"}, nil)
+			Expect(ok).To(BeFalse())
+			_, ok = ClassifyCommand(nil, nil)
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
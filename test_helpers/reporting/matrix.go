@@ -0,0 +1,201 @@
+package reporting
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// MatrixRow counts the specs covering one endpoint and credential type,
+// broken down by driver.
+type MatrixRow struct {
+	Endpoint       Endpoint       `json:"endpoint"`
+	CredentialType string         `json:"credential_type,omitempty"`
+	Specs          map[Driver]int `json:"specs"`
+}
+
+// Gap is an endpoint and credential type that a driver never exercised.
+type Gap struct {
+	Endpoint       Endpoint `json:"endpoint"`
+	CredentialType string   `json:"credential_type,omitempty"`
+	Driver         Driver   `json:"driver"`
+}
+
+func (g Gap) String() string {
+	if g.CredentialType == "" {
+		return fmt.Sprintf("%s has no %s coverage", g.Endpoint, g.Driver)
+	}
+	return fmt.Sprintf("%s has no %s coverage for type %s", g.Endpoint, g.Driver, g.CredentialType)
+}
+
+type matrixKey struct {
+	endpoint       Endpoint
+	credentialType string
+}
+
+// Matrix is the endpoint x credential type x driver coverage of a set of specs.
+type Matrix struct {
+	rows map[matrixKey]map[Driver]int
+}
+
+func NewMatrix() *Matrix {
+	return &Matrix{rows: map[matrixKey]map[Driver]int{}}
+}
+
+// AddSpec adds the calls made by a single spec to the matrix.
+func (m *Matrix) AddSpec(calls []Call) {
+	seen := map[Call]bool{}
+	for _, call := range calls {
+		key := Call{Driver: call.Driver, Endpoint: call.Endpoint, CredentialType: call.CredentialType}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		m.add(matrixKey{call.Endpoint, call.CredentialType}, call.Driver, 1)
+	}
+}
+
+func (m *Matrix) add(key matrixKey, driver Driver, count int) {
+	if m.rows[key] == nil {
+		m.rows[key] = map[Driver]int{}
+	}
+	m.rows[key][driver] += count
+}
+
+// Merge adds the rows of other to m.
+func (m *Matrix) Merge(other *Matrix) {
+	for key, specs := range other.rows {
+		for driver, count := range specs {
+			m.add(key, driver, count)
+		}
+	}
+}
+
+func (m *Matrix) Rows() []MatrixRow {
+	rows := make([]MatrixRow, 0, len(m.rows))
+	for key, specs := range m.rows {
+		rows = append(rows, MatrixRow{Endpoint: key.endpoint, CredentialType: key.credentialType, Specs: specs})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Endpoint.String() != rows[j].Endpoint.String() {
+			return rows[i].Endpoint.String() < rows[j].Endpoint.String()
+		}
+		return rows[i].CredentialType < rows[j].CredentialType
+	})
+	return rows
+}
+
+// Gaps lists every catalogued endpoint and credential type that at least one
+// driver never exercised.
+func (m *Matrix) Gaps() []Gap {
+	var gaps []Gap
+	for _, entry := range catalogue {
+		types := entry.Types
+		if types == nil {
+			types = []string{""}
+		}
+		for _, credentialType := range types {
+			for _, driver := range Drivers {
+				if m.count(entry.Endpoint, credentialType, driver) == 0 {
+					gaps = append(gaps, Gap{Endpoint: entry.Endpoint, CredentialType: credentialType, Driver: driver})
+				}
+			}
+		}
+	}
+	return gaps
+}
+
+func (m *Matrix) count(endpoint Endpoint, credentialType string, driver Driver) int {
+	if credentialType != "" {
+		return m.rows[matrixKey{endpoint, credentialType}][driver]
+	}
+	total := 0
+	for key, specs := range m.rows {
+		if key.endpoint == endpoint {
+			total += specs[driver]
+		}
+	}
+	return total
+}
+
+func (m *Matrix) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Rows []MatrixRow `json:"rows"`
+		Gaps []Gap       `json:"gaps"`
+	}{m.Rows(), m.Gaps()})
+}
+
+func (m *Matrix) UnmarshalJSON(data []byte) error {
+	var parsed struct {
+		Rows []MatrixRow `json:"rows"`
+	}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return err
+	}
+	m.rows = map[matrixKey]map[Driver]int{}
+	for _, row := range parsed.Rows {
+		for driver, count := range row.Specs {
+			m.add(matrixKey{row.Endpoint, row.CredentialType}, driver, count)
+		}
+	}
+	return nil
+}
+
+// WriteText renders the matrix as a table followed by the list of gaps.
+func (m *Matrix) WriteText(w io.Writer) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := []string{"ENDPOINT", "TYPE"}
+	for _, driver := range Drivers {
+		header = append(header, strings.ToUpper(string(driver)))
+	}
+	fmt.Fprintln(table, strings.Join(header, "\t"))
+
+	for _, row := range m.Rows() {
+		credentialType := row.CredentialType
+		if credentialType == "" {
+			credentialType = "-"
+		}
+		columns := []string{row.Endpoint.String(), credentialType}
+		for _, driver := range Drivers {
+			columns = append(columns, fmt.Sprint(row.Specs[driver]))
+		}
+		fmt.Fprintln(table, strings.Join(columns, "\t"))
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "GAPS")
+	for _, gap := range m.Gaps() {
+		fmt.Fprintln(w, "  "+gap.String())
+	}
+	return nil
+}
+
+// LoadMatrices merges every suite's coverage file found in dir.
+func LoadMatrices(dir string) (*Matrix, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+coverageSuffix))
+	if err != nil {
+		return nil, err
+	}
+
+	merged := NewMatrix()
+	for _, file := range files {
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		matrix := NewMatrix()
+		if err := json.Unmarshal(contents, matrix); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %s", file, err)
+		}
+		merged.Merge(matrix)
+	}
+	return merged, nil
+}
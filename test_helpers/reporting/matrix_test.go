@@ -0,0 +1,147 @@
+package reporting_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/reporting"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Matrix", func() {
+	var (
+		generate   = Endpoint{"POST", "/api/v1/data", "generate"}
+		regenerate = Endpoint{"POST", "/api/v1/data", "regenerate"}
+		bulk       = Endpoint{"POST", "/api/v1/bulk-regenerate", ""}
+	)
+
+	It("counts each spec once per endpoint, type and driver", func() {
+		matrix := NewMatrix()
+		matrix.AddSpec([]Call{
+			{Driver: CLI, Endpoint: generate, CredentialType: "password", Count: 3},
+			{Driver: CLI, Command: "generate", Endpoint: generate, CredentialType: "password"},
+		})
+		matrix.AddSpec([]Call{
+			{Driver: GoClient, Endpoint: generate, CredentialType: "password"},
+		})
+
+		Expect(matrix.Rows()).To(ConsistOf(MatrixRow{
+			Endpoint:       generate,
+			CredentialType: "password",
+			Specs:          map[Driver]int{CLI: 1, GoClient: 1},
+		}))
+	})
+
+	It("reports catalogued endpoints that a driver never exercised", func() {
+		matrix := NewMatrix()
+		matrix.AddSpec([]Call{
+			{Driver: CLI, Endpoint: bulk},
+			{Driver: HTTP, Endpoint: bulk},
+			{Driver: CLI, Endpoint: regenerate, CredentialType: "password"},
+		})
+
+		gaps := matrix.Gaps()
+		Expect(gaps).To(ContainElement(Gap{Endpoint: bulk, Driver: GoClient}))
+		Expect(gaps).NotTo(ContainElement(Gap{Endpoint: bulk, Driver: CLI}))
+		Expect(gaps).To(ContainElement(Gap{Endpoint: regenerate, CredentialType: "user", Driver: CLI}))
+		Expect(gaps).NotTo(ContainElement(Gap{Endpoint: regenerate, CredentialType: "password", Driver: CLI}))
+		Expect(Gap{Endpoint: bulk, Driver: GoClient}.String()).To(Equal("POST /api/v1/bulk-regenerate has no go-client coverage"))
+	})
+
+	It("round-trips through JSON and merges", func() {
+		matrix := NewMatrix()
+		matrix.AddSpec([]Call{{Driver: CLI, Endpoint: bulk}})
+
+		encoded, err := json.Marshal(matrix)
+		Expect(err).NotTo(HaveOccurred())
+
+		decoded := NewMatrix()
+		Expect(json.Unmarshal(encoded, decoded)).To(Succeed())
+		decoded.Merge(matrix)
+
+		Expect(decoded.Rows()).To(ConsistOf(MatrixRow{Endpoint: bulk, Specs: map[Driver]int{CLI: 2}}))
+	})
+
+	It("renders a table followed by the gaps", func() {
+		matrix := NewMatrix()
+		matrix.AddSpec([]Call{{Driver: HTTP, Endpoint: generate, CredentialType: "ssh"}})
+
+		var out bytes.Buffer
+		Expect(matrix.WriteText(&out)).To(Succeed())
+
+		lines := strings.Split(out.String(), "\n")
+		Expect(lines[0]).To(MatchRegexp(`^ENDPOINT\s+TYPE\s+CLI\s+GO-CLIENT\s+HTTP$`))
+		Expect(lines[1]).To(MatchRegexp(`^POST /api/v1/data \[generate\]\s+ssh\s+0\s+0\s+1$`))
+		Expect(out.String()).To(ContainSubstring("GAPS\n"))
+		Expect(out.String()).To(ContainSubstring("POST /api/v1/data [generate] has no cli coverage for type ssh"))
+	})
+
+	It("loads and merges every suite's coverage file in a directory", func() {
+		dir, err := os.MkdirTemp("", "reporting")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		for _, suite := range []string{"one", "two"} {
+			matrix := NewMatrix()
+			matrix.AddSpec([]Call{{Driver: CLI, Endpoint: bulk}})
+			encoded, err := json.Marshal(matrix)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.WriteFile(filepath.Join(dir, suite+"-coverage.json"), encoded, 0644)).To(Succeed())
+		}
+
+		merged, err := LoadMatrices(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(merged.Rows()).To(ConsistOf(MatrixRow{Endpoint: bulk, Specs: map[Driver]int{CLI: 2}}))
+	})
+})
+
+var _ = Describe("Recording", func() {
+	BeforeEach(func() {
+		Reset()
+	})
+
+	It("records CLI commands", func() {
+		RecordCommand([]string{"set", "-n", "foo", "-t", "value", "-v", "bar"}, nil)
+		RecordCommand([]string{"set", "-n", "bar", "-t", "value", "-v", "bar"}, nil)
+		RecordCommand([]string{"login"}, nil)
+
+		Expect(Calls()).To(ConsistOf(Call{
+			Driver:         CLI,
+			Command:        "set",
+			Endpoint:       Endpoint{"PUT", "/api/v1/data", "set"},
+			CredentialType: "value",
+			Count:          2,
+		}))
+	})
+
+	It("records requests sent through a transport without consuming the bodies", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			Expect(string(body)).To(Equal(`{"name":"/foo","regenerate":true}`))
+			w.Write([]byte(`{"name":"/foo","type":"certificate"}`))
+		}))
+		defer server.Close()
+
+		client := &http.Client{Transport: NewTransport(GoClient, nil)}
+		resp, err := client.Post(server.URL+"/api/v1/data", "application/json", strings.NewReader(`{"name":"/foo","regenerate":true}`))
+		Expect(err).NotTo(HaveOccurred())
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(body)).To(Equal(`{"name":"/foo","type":"certificate"}`))
+
+		Expect(Calls()).To(ConsistOf(Call{
+			Driver:         GoClient,
+			Endpoint:       Endpoint{"POST", "/api/v1/data", "regenerate"},
+			CredentialType: "certificate",
+			Count:          1,
+		}))
+	})
+})
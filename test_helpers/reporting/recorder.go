@@ -0,0 +1,89 @@
+package reporting
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// Call is a single CredHub endpoint exercised by a spec through one driver.
+type Call struct {
+	Driver         Driver   `json:"driver"`
+	Command        string   `json:"command,omitempty"`
+	Endpoint       Endpoint `json:"endpoint"`
+	CredentialType string   `json:"credential_type,omitempty"`
+	Count          int      `json:"count"`
+}
+
+func (c Call) key() Call {
+	c.Count = 0
+	return c
+}
+
+type recorder struct {
+	mutex sync.Mutex
+	calls map[Call]int
+	order []Call
+}
+
+var current = &recorder{calls: map[Call]int{}}
+
+// Reset discards the calls recorded so far. It is called before each spec.
+func Reset() {
+	current.mutex.Lock()
+	defer current.mutex.Unlock()
+
+	current.calls = map[Call]int{}
+	current.order = nil
+}
+
+// Record adds a call to the current spec's record.
+func Record(call Call) {
+	current.mutex.Lock()
+	defer current.mutex.Unlock()
+
+	key := call.key()
+	if _, ok := current.calls[key]; !ok {
+		current.order = append(current.order, key)
+	}
+	current.calls[key]++
+}
+
+// RecordCommand records the endpoint exercised by a credhub CLI invocation.
+func RecordCommand(args []string, stdout []byte) {
+	if call, ok := ClassifyCommand(args, stdout); ok {
+		Record(call)
+	}
+}
+
+// RecordRequest records the endpoint exercised by an HTTP request made
+// through the Go client or a raw HTTP helper.
+func RecordRequest(driver Driver, req *http.Request, requestBody, responseBody []byte) {
+	endpoint, ok := Classify(req.Method, req.URL, requestBody)
+	if !ok {
+		return
+	}
+	Record(Call{
+		Driver:         driver,
+		Endpoint:       endpoint,
+		CredentialType: CredentialType(requestBody, responseBody),
+	})
+}
+
+// Calls returns the calls recorded for the current spec, with Count set to
+// the number of times each was made.
+func Calls() []Call {
+	current.mutex.Lock()
+	defer current.mutex.Unlock()
+
+	calls := make([]Call, 0, len(current.order))
+	for _, key := range current.order {
+		call := key
+		call.Count = current.calls[key]
+		calls = append(calls, call)
+	}
+	sort.SliceStable(calls, func(i, j int) bool {
+		return calls[i].Endpoint.String() < calls[j].Endpoint.String()
+	})
+	return calls
+}
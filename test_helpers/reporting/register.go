@@ -0,0 +1,130 @@
+package reporting
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/reporters"
+)
+
+const (
+	callsEntryName = "credhub-calls"
+	coverageSuffix = "-coverage.json"
+)
+
+// Register adds the reporting hooks to a suite. It must be called at the top
+// level of the suite, e.g. `var _ = reporting.Register("Integration Suite", dir)`.
+//
+// Each spec is annotated with the CredHub calls it made. After the suite,
+// JUnit and JSON reports plus the suite's coverage are written to the
+// directory returned by reportDir, and the coverage matrix for every suite
+// reported into that directory so far is regenerated. Nothing is written
+// when reportDir returns "".
+func Register(suiteName string, reportDir func() string) bool {
+	BeforeEach(func() {
+		Reset()
+	})
+
+	AfterEach(func() {
+		AddReportEntry(callsEntryName, Calls(), ReportEntryVisibilityNever)
+	})
+
+	ReportAfterSuite("credhub reporting", func(report Report) {
+		dir := reportDir()
+		if dir == "" {
+			return
+		}
+		if err := writeReports(dir, suiteName, report); err != nil {
+			Fail(fmt.Sprintf("failed to write reports: %s", err))
+		}
+	})
+
+	return true
+}
+
+// SpecCalls returns the CredHub calls recorded against a spec.
+func SpecCalls(spec SpecReport) ([]Call, error) {
+	var calls []Call
+	for _, entry := range spec.ReportEntries {
+		if entry.Name != callsEntryName {
+			continue
+		}
+		// Entries are only available as decoded JSON when running in parallel,
+		// so round-trip through JSON either way.
+		encoded, err := json.Marshal(entry.Value.GetRawValue())
+		if err != nil {
+			return nil, err
+		}
+		var entryCalls []Call
+		if err := json.Unmarshal(encoded, &entryCalls); err != nil {
+			return nil, err
+		}
+		calls = append(calls, entryCalls...)
+	}
+	return calls, nil
+}
+
+// MatrixFromReport builds the coverage matrix of a suite report.
+func MatrixFromReport(report Report) (*Matrix, error) {
+	matrix := NewMatrix()
+	for _, spec := range report.SpecReports {
+		calls, err := SpecCalls(spec)
+		if err != nil {
+			return nil, err
+		}
+		matrix.AddSpec(calls)
+	}
+	return matrix, nil
+}
+
+var nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+
+func writeReports(dir, suiteName string, report Report) error {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	prefix := filepath.Join(dir, strings.Trim(nonAlphanumeric.ReplaceAllString(strings.ToLower(suiteName), "-"), "-"))
+
+	if err := reporters.GenerateJUnitReport(report, prefix+"-junit.xml"); err != nil {
+		return err
+	}
+	if err := reporters.GenerateJSONReport(report, prefix+"-report.json"); err != nil {
+		return err
+	}
+
+	matrix, err := MatrixFromReport(report)
+	if err != nil {
+		return err
+	}
+	encoded, err := json.MarshalIndent(matrix, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(prefix+coverageSuffix, encoded, 0644); err != nil {
+		return err
+	}
+
+	merged, err := LoadMatrices(dir)
+	if err != nil {
+		return err
+	}
+	encoded, err = json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "coverage-matrix.json"), encoded, 0644); err != nil {
+		return err
+	}
+
+	text, err := os.Create(filepath.Join(dir, "coverage-matrix.txt"))
+	if err != nil {
+		return err
+	}
+	defer text.Close()
+	return merged.WriteText(text)
+}
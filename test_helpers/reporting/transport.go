@@ -0,0 +1,53 @@
+package reporting
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+)
+
+type recordingTransport struct {
+	driver Driver
+	inner  http.RoundTripper
+}
+
+// NewTransport wraps inner so that every request it sends is recorded
+// against the given driver. A nil inner uses http.DefaultTransport.
+func NewTransport(driver Driver, inner http.RoundTripper) http.RoundTripper {
+	if inner == nil {
+		inner = http.DefaultTransport
+	}
+	return &recordingTransport{driver: driver, inner: inner}
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestBody, err := ReadBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.inner.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	responseBody, err := ReadBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	RecordRequest(t.driver, req, requestBody, responseBody)
+	return resp, nil
+}
+
+// ReadBody reads body and replaces it with an equivalent reader so that it
+// can still be consumed by the caller.
+func ReadBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	contents, err := io.ReadAll(*body)
+	(*body).Close()
+	*body = io.NopCloser(bytes.NewReader(contents))
+	return contents, err
+}
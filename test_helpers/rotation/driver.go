@@ -0,0 +1,344 @@
+package rotation
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/certificates"
+)
+
+// ErrNotRotating is returned by phases that need a rotation in progress when
+// the CA has no transitional version.
+var ErrNotRotating = errors.New("the CA has no transitional version")
+
+// Driver creates certificates, steps CAs through rotation, and keeps the
+// model of what CredHub should then hold.
+type Driver struct {
+	client      *certificates.Client
+	concatenate bool
+	certs       map[string]*certificate
+	// order is the order certificates were created in, parents first.
+	order []string
+}
+
+// New creates a driver that sends its requests over transport.
+// concatenateCAs is whether CredHub is configured with concatenate_cas.
+func New(transport certificates.Transport, concatenateCAs bool) *Driver {
+	return &Driver{client: certificates.New(transport), concatenate: concatenateCAs, certs: map[string]*certificate{}}
+}
+
+// RootCA generates a self-signed CA.
+func (d *Driver) RootCA(name string) error {
+	return d.generate(name, "", map[string]interface{}{"is_ca": true, "self_sign": true})
+}
+
+// IntermediateCA generates a CA signed by parent.
+func (d *Driver) IntermediateCA(name, parent string) error {
+	return d.generate(name, parent, map[string]interface{}{"is_ca": true, "ca": parent})
+}
+
+// Leaf generates a certificate signed by parent.
+func (d *Driver) Leaf(name, parent string) error {
+	return d.generate(name, parent, map[string]interface{}{"ca": parent})
+}
+
+func (d *Driver) generate(name, parent string, parameters map[string]interface{}) error {
+	var signer *certificate
+	if parent != "" {
+		signer = d.certs[parent]
+		if signer == nil {
+			return fmt.Errorf("%s was not created by the driver", parent)
+		}
+	}
+	parameters["common_name"] = path.Base(name)
+
+	var version certificates.Version
+	err := d.client.Call(http.MethodPost, "/api/v1/data", nil, map[string]interface{}{
+		"name":       name,
+		"type":       "certificate",
+		"parameters": parameters,
+	}, &version)
+	if err != nil {
+		return err
+	}
+
+	c := &certificate{name: name, parent: parent, ca: parameters["is_ca"] == true}
+	c.versions = []Version{{ID: version.ID, Certificate: version.Value.Certificate, Signer: d.signerFor(c, version.Value.Certificate)}}
+	metadata, err := d.client.Get(name)
+	if err != nil {
+		return err
+	}
+	c.id = metadata.ID
+	d.certs[name] = c
+	d.order = append(d.order, name)
+	return nil
+}
+
+// signerFor is the certificate that signs a new version of c.
+func (d *Driver) signerFor(c *certificate, self string) string {
+	if c.parent == "" {
+		return self
+	}
+	signing, _ := d.certs[c.parent].signing()
+	return signing.Certificate
+}
+
+// RegenerateCA adds a version to a CA, transitional or not. If CredHub
+// refuses, the model is unchanged.
+func (d *Driver) RegenerateCA(name string, transitional bool) error {
+	c, err := d.cert(name)
+	if err != nil {
+		return err
+	}
+
+	version, err := d.client.Regenerate(c.id, transitional)
+	if err != nil {
+		return err
+	}
+
+	c.versions = append(c.versions, Version{ID: version.ID, Certificate: version.Value.Certificate, Signer: d.signerFor(c, version.Value.Certificate)})
+	if transitional {
+		c.transitional = version.ID
+		return d.transitionalChanged(name)
+	}
+	return nil
+}
+
+// SetTransitional marks a version of a CA as transitional, or, given "",
+// marks none. If CredHub refuses, the model is unchanged.
+func (d *Driver) SetTransitional(name, versionID string) error {
+	c, err := d.cert(name)
+	if err != nil {
+		return err
+	}
+
+	if _, err := d.client.SetTransitional(c.id, versionID); err != nil {
+		return err
+	}
+
+	c.transitional = versionID
+	return d.transitionalChanged(name)
+}
+
+// transitionalChanged adds the version concatenate_cas gives each
+// certificate a CA signs directly when its transitional version changes.
+func (d *Driver) transitionalChanged(name string) error {
+	if !d.concatenate {
+		return nil
+	}
+	for _, child := range d.children(name) {
+		latest := child.latest()
+		child.versions = append(child.versions, Version{Certificate: latest.Certificate, Signer: latest.Signer})
+		if err := d.learnIDs(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BulkRegenerate regenerates every certificate a CA signs, directly or not.
+func (d *Driver) BulkRegenerate(name string) error {
+	if _, err := d.cert(name); err != nil {
+		return err
+	}
+
+	var response struct {
+		Regenerated []string `json:"regenerated_credentials"`
+	}
+	err := d.client.Call(http.MethodPost, "/api/v1/bulk-regenerate", nil, map[string]interface{}{"signed_by": name}, &response)
+	if err != nil {
+		return err
+	}
+
+	expected := d.descendants(name)
+	var expectedNames []string
+	for _, c := range expected {
+		expectedNames = append(expectedNames, c.name)
+		current, err := d.current(c)
+		if err != nil {
+			return err
+		}
+		c.versions = append(c.versions, Version{ID: current.ID, Certificate: current.Value.Certificate, Signer: d.signerFor(c, current.Value.Certificate)})
+	}
+
+	sort.Strings(expectedNames)
+	sort.Strings(response.Regenerated)
+	if fmt.Sprint(response.Regenerated) != fmt.Sprint(expectedNames) {
+		return fmt.Errorf("bulk regenerate of %s regenerated %v, expected %v", name, response.Regenerated, expectedNames)
+	}
+	return nil
+}
+
+// Start begins rotating a CA: its new version is trusted, but does not sign.
+func (d *Driver) Start(name string) error {
+	return d.RegenerateCA(name, true)
+}
+
+// Switch makes the new version of a CA sign, keeps trusting the old one,
+// and regenerates everything the CA signs.
+func (d *Driver) Switch(name string) error {
+	c, err := d.cert(name)
+	if err != nil {
+		return err
+	}
+	if c.transitional == "" {
+		return ErrNotRotating
+	}
+	previous, _ := c.signing()
+	if err := d.SetTransitional(name, previous.ID); err != nil {
+		return err
+	}
+	return d.BulkRegenerate(name)
+}
+
+// Finish stops trusting the old version of a CA. Finishing a rotation that
+// was only started makes the new version sign without regenerating anything
+// the CA signs, which is how an operator abandons a rotation.
+func (d *Driver) Finish(name string) error {
+	return d.SetTransitional(name, "")
+}
+
+// RollBack undoes a Switch: the old version of the CA signs again, the new
+// one is trusted, and everything the CA signs is regenerated.
+func (d *Driver) RollBack(name string) error {
+	c, err := d.cert(name)
+	if err != nil {
+		return err
+	}
+	if c.transitional == "" {
+		return ErrNotRotating
+	}
+	if err := d.SetTransitional(name, c.latest().ID); err != nil {
+		return err
+	}
+	return d.BulkRegenerate(name)
+}
+
+// Versions are the versions of a certificate the model holds, oldest first.
+func (d *Driver) Versions(name string) []Version {
+	if c, ok := d.certs[name]; ok {
+		return append([]Version(nil), c.versions...)
+	}
+	return nil
+}
+
+// Signing is the version a CA signs with.
+func (d *Driver) Signing(name string) Version {
+	if c, ok := d.certs[name]; ok {
+		signing, _ := c.signing()
+		return signing
+	}
+	return Version{}
+}
+
+// Check compares every certificate the driver created with the model.
+func (d *Driver) Check() ([]Mismatch, error) {
+	var mismatches []Mismatch
+	for _, name := range d.order {
+		c := d.certs[name]
+		seen, err := d.observe(c)
+		if err != nil {
+			return nil, err
+		}
+		mismatches = append(mismatches, c.compare(d.certs[c.parent], d.concatenate, seen)...)
+	}
+	return mismatches, nil
+}
+
+// Cleanup deletes every certificate the driver created.
+func (d *Driver) Cleanup() error {
+	var errs []error
+	for i := len(d.order) - 1; i >= 0; i-- {
+		query := url.Values{"name": {d.order[i]}}
+		if err := d.client.Call(http.MethodDelete, "/api/v1/data", query, nil, nil); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (d *Driver) cert(name string) (*certificate, error) {
+	c, ok := d.certs[name]
+	if !ok {
+		return nil, fmt.Errorf("%s was not created by the driver", name)
+	}
+	return c, nil
+}
+
+func (d *Driver) children(name string) []*certificate {
+	var children []*certificate
+	for _, candidate := range d.order {
+		if d.certs[candidate].parent == name {
+			children = append(children, d.certs[candidate])
+		}
+	}
+	return children
+}
+
+// descendants are the certificates a CA signs, directly or not, parents
+// before the certificates they sign.
+func (d *Driver) descendants(name string) []*certificate {
+	var descendants []*certificate
+	for queue := []string{name}; len(queue) > 0; queue = queue[1:] {
+		for _, child := range d.children(queue[0]) {
+			descendants = append(descendants, child)
+			if child.ca {
+				queue = append(queue, child.name)
+			}
+		}
+	}
+	return descendants
+}
+
+func (d *Driver) current(c *certificate) (certificates.Version, error) {
+	versions, err := d.client.Versions(c.id)
+	if err != nil {
+		return certificates.Version{}, err
+	}
+	if len(versions) == 0 {
+		return certificates.Version{}, fmt.Errorf("%s has no versions", c.name)
+	}
+	return versions[0], nil
+}
+
+// learnIDs fills in the ids of versions CredHub created on its own, when it
+// holds as many versions as the model.
+func (d *Driver) learnIDs(c *certificate) error {
+	metadata, err := d.client.Get(c.name)
+	if err != nil {
+		return err
+	}
+	if len(metadata.Versions) != len(c.versions) {
+		return nil
+	}
+	for i := range c.versions {
+		if c.versions[i].ID == "" {
+			c.versions[i].ID = metadata.Versions[len(metadata.Versions)-1-i].ID
+		}
+	}
+	return nil
+}
+
+func (d *Driver) observe(c *certificate) (observed, error) {
+	metadata, err := d.client.Get(c.name)
+	if err != nil {
+		return observed{}, err
+	}
+	current, err := d.current(c)
+	if err != nil {
+		return observed{}, err
+	}
+
+	seen := observed{signedBy: metadata.SignedBy, certificate: current.Value.Certificate, ca: current.Value.CA}
+	for _, version := range metadata.Versions {
+		seen.versionIDs = append(seen.versionIDs, version.ID)
+		if version.Transitional {
+			seen.transitional = append(seen.transitional, version.ID)
+		}
+	}
+	return seen, nil
+}
@@ -0,0 +1,299 @@
+package rotation_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type fakeVersion struct {
+	id           string
+	certificate  string
+	key          *ecdsa.PrivateKey
+	ca           string
+	transitional bool
+}
+
+type fakeCertificate struct {
+	id       string
+	name     string
+	caName   string
+	isCA     bool
+	versions []*fakeVersion
+}
+
+// fakeCredHub implements the certificate endpoints the driver uses, following
+// the rotation model, with switches to misbehave.
+type fakeCredHub struct {
+	concatenate bool
+	certs       map[string]*fakeCertificate
+	ids         int
+
+	// signWithTransitional signs with the transitional version instead of
+	// the latest other one.
+	signWithTransitional bool
+	// bulkNotRecursive only regenerates the direct children of a CA.
+	bulkNotRecursive bool
+}
+
+func newFakeCredHub(concatenate bool) *fakeCredHub {
+	return &fakeCredHub{concatenate: concatenate, certs: map[string]*fakeCertificate{}}
+}
+
+func (f *fakeCredHub) nextID() string {
+	f.ids++
+	return fmt.Sprintf("id-%d", f.ids)
+}
+
+func (f *fakeCredHub) byID(id string) *fakeCertificate {
+	for _, c := range f.certs {
+		if c.id == id {
+			return c
+		}
+	}
+	return nil
+}
+
+func (c *fakeCertificate) latest() *fakeVersion {
+	return c.versions[len(c.versions)-1]
+}
+
+func (c *fakeCertificate) transitional() *fakeVersion {
+	for _, v := range c.versions {
+		if v.transitional {
+			return v
+		}
+	}
+	return nil
+}
+
+func (f *fakeCredHub) signing(c *fakeCertificate) *fakeVersion {
+	if f.signWithTransitional && c.transitional() != nil {
+		return c.transitional()
+	}
+	for i := len(c.versions) - 1; i >= 0; i-- {
+		if !c.versions[i].transitional {
+			return c.versions[i]
+		}
+	}
+	return nil
+}
+
+func (f *fakeCredHub) caField(signer *fakeVersion, ca *fakeCertificate) string {
+	field := signer.certificate
+	if f.concatenate {
+		if transitional := ca.transitional(); transitional != nil && transitional != signer {
+			field += transitional.certificate
+		}
+	}
+	return field
+}
+
+func (f *fakeCredHub) newVersion(c *fakeCertificate) *fakeVersion {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(int64(f.ids + 1)),
+		Subject:               pkix.Name{CommonName: c.name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  c.isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	parent, signerKey := template, key
+	var signer *fakeVersion
+	if c.caName != "" {
+		signer = f.signing(f.certs[c.caName])
+		parsed, _ := pem.Decode([]byte(signer.certificate))
+		parent, err = x509.ParseCertificate(parsed.Bytes)
+		if err != nil {
+			panic(err)
+		}
+		signerKey = signer.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signerKey)
+	if err != nil {
+		panic(err)
+	}
+	version := &fakeVersion{id: f.nextID(), key: key, certificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))}
+	if signer == nil {
+		version.ca = version.certificate
+	} else {
+		version.ca = f.caField(signer, f.certs[c.caName])
+	}
+	c.versions = append(c.versions, version)
+	return version
+}
+
+// transitionalChanged gives direct children a version with the new ca field.
+func (f *fakeCredHub) transitionalChanged(ca *fakeCertificate) {
+	if !f.concatenate {
+		return
+	}
+	for _, child := range f.certs {
+		if child.caName != ca.name {
+			continue
+		}
+		latest := child.latest()
+		signer := f.signerOf(latest, ca)
+		child.versions = append(child.versions, &fakeVersion{id: f.nextID(), key: latest.key, certificate: latest.certificate, ca: f.caField(signer, ca)})
+	}
+}
+
+func (f *fakeCredHub) signerOf(version *fakeVersion, ca *fakeCertificate) *fakeVersion {
+	parsed, _ := pem.Decode([]byte(version.certificate))
+	child, _ := x509.ParseCertificate(parsed.Bytes)
+	for _, candidate := range ca.versions {
+		block, _ := pem.Decode([]byte(candidate.certificate))
+		parent, _ := x509.ParseCertificate(block.Bytes)
+		if child.CheckSignatureFrom(parent) == nil {
+			return candidate
+		}
+	}
+	panic("no signer")
+}
+
+func (f *fakeCredHub) regenerateSignedBy(name string) []string {
+	var regenerated []string
+	for _, child := range f.sorted() {
+		if child.caName != name {
+			continue
+		}
+		f.newVersion(child)
+		regenerated = append(regenerated, child.name)
+		if child.isCA && !f.bulkNotRecursive {
+			regenerated = append(regenerated, f.regenerateSignedBy(child.name)...)
+		}
+	}
+	return regenerated
+}
+
+func (f *fakeCredHub) sorted() []*fakeCertificate {
+	var certs []*fakeCertificate
+	for i := 1; i <= f.ids; i++ {
+		if c := f.byID(fmt.Sprintf("cert-%d", i)); c != nil {
+			certs = append(certs, c)
+		}
+	}
+	return certs
+}
+
+func versionJSON(v *fakeVersion) map[string]interface{} {
+	return map[string]interface{}{
+		"id":           v.id,
+		"type":         "certificate",
+		"transitional": v.transitional,
+		"value":        map[string]interface{}{"ca": v.ca, "certificate": v.certificate},
+	}
+}
+
+func (f *fakeCredHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body map[string]interface{}
+	json.NewDecoder(r.Body).Decode(&body)
+	respond := func(status int, response interface{}) {
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(response)
+	}
+	name := r.URL.Query().Get("name")
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v1/data":
+		parameters := body["parameters"].(map[string]interface{})
+		c := &fakeCertificate{id: fmt.Sprintf("cert-%d", f.ids+1), name: body["name"].(string), isCA: parameters["is_ca"] == true}
+		f.ids++
+		if ca, ok := parameters["ca"].(string); ok {
+			c.caName = ca
+		}
+		f.certs[c.name] = c
+		respond(http.StatusOK, versionJSON(f.newVersion(c)))
+
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v1/data":
+		c := f.certs[name]
+		if c == nil {
+			respond(http.StatusNotFound, map[string]string{"error": "not found"})
+			return
+		}
+		respond(http.StatusOK, map[string]interface{}{"data": []interface{}{versionJSON(c.latest())}})
+
+	case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/data":
+		delete(f.certs, name)
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v1/certificates":
+		c := f.certs[name]
+		var versions []interface{}
+		for i := len(c.versions) - 1; i >= 0; i-- {
+			versions = append(versions, map[string]interface{}{"id": c.versions[i].id, "transitional": c.versions[i].transitional})
+		}
+		signedBy := c.caName
+		if signedBy == "" {
+			signedBy = c.name
+		}
+		respond(http.StatusOK, map[string]interface{}{"certificates": []interface{}{
+			map[string]interface{}{"id": c.id, "name": c.name, "signed_by": signedBy, "versions": versions},
+		}})
+
+	case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/versions"):
+		c := f.byID(strings.Split(r.URL.Path, "/")[4])
+		var versions []interface{}
+		for i := len(c.versions) - 1; i >= 0; i-- {
+			versions = append(versions, versionJSON(c.versions[i]))
+		}
+		respond(http.StatusOK, versions)
+
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/regenerate"):
+		c := f.byID(strings.Split(r.URL.Path, "/")[4])
+		transitional := body["set_as_transitional"] == true
+		if transitional && c.transitional() != nil {
+			respond(http.StatusBadRequest, map[string]string{"error": "The maximum number of transitional versions for a given CA is 1."})
+			return
+		}
+		version := f.newVersion(c)
+		version.transitional = transitional
+		if transitional {
+			f.transitionalChanged(c)
+		}
+		respond(http.StatusOK, versionJSON(version))
+
+	case r.Method == http.MethodPut && strings.HasSuffix(r.URL.Path, "/update_transitional_version"):
+		c := f.byID(strings.Split(r.URL.Path, "/")[4])
+		for _, v := range c.versions {
+			v.transitional = v.id == body["version"]
+		}
+		f.transitionalChanged(c)
+		respond(http.StatusOK, []interface{}{})
+
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v1/bulk-regenerate":
+		respond(http.StatusOK, map[string]interface{}{"regenerated_credentials": f.regenerateSignedBy(body["signed_by"].(string))})
+
+	default:
+		respond(http.StatusNotFound, map[string]string{"error": r.Method + " " + r.URL.Path})
+	}
+}
+
+// Request serves the driver's requests without a network.
+func (f *fakeCredHub) Request(method string, pathStr string, query url.Values, body interface{}, checkServerErr bool) (*http.Response, error) {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	request := httptest.NewRequest(method, pathStr+"?"+query.Encode(), bytes.NewReader(encoded))
+	recorder := httptest.NewRecorder()
+	f.ServeHTTP(recorder, request)
+	return recorder.Result(), nil
+}
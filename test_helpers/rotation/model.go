@@ -0,0 +1,203 @@
+// Package rotation drives CredHub through the documented CA rotation phases
+// and checks, after every step, that each CA's signing and transitional
+// versions and each certificate's ca field are what the rotation model
+// predicts.
+//
+// The model follows the CredHub rotation documentation:
+//
+//   - A CA signs with its latest version that is not transitional.
+//   - Regenerating a CA as transitional adds a version that is trusted but
+//     does not sign, and a CA can have only one transitional version.
+//   - Bulk regenerating a CA regenerates every certificate it signs, and
+//     every certificate those sign in turn, with the signing version of
+//     their CA.
+//   - A certificate's ca field holds the CA version that signed it. With
+//     concatenate_cas it also holds the CA's transitional version, and
+//     every change to a CA's transitional version adds a version to the
+//     certificates it signs directly.
+package rotation
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+)
+
+// Version is one version of a certificate.
+type Version struct {
+	ID          string
+	Certificate string
+	// Signer is the certificate of the CA version that signed this one, or
+	// the certificate itself when it is self-signed.
+	Signer string
+}
+
+type certificate struct {
+	name   string
+	parent string
+	ca     bool
+	id     string
+	// versions are oldest first.
+	versions     []Version
+	transitional string
+}
+
+func (c *certificate) latest() Version {
+	return c.versions[len(c.versions)-1]
+}
+
+// signing is the version a CA signs with: its latest that is not
+// transitional.
+func (c *certificate) signing() (Version, bool) {
+	for i := len(c.versions) - 1; i >= 0; i-- {
+		if c.versions[i].ID != c.transitional {
+			return c.versions[i], true
+		}
+	}
+	return Version{}, false
+}
+
+func (c *certificate) version(id string) (Version, bool) {
+	for _, version := range c.versions {
+		if version.ID == id {
+			return version, true
+		}
+	}
+	return Version{}, false
+}
+
+// Mismatch is a way CredHub's state differs from the model.
+type Mismatch struct {
+	Certificate string
+	Problem     string
+}
+
+func (m Mismatch) String() string {
+	return m.Certificate + ": " + m.Problem
+}
+
+// observed is what CredHub reports for one certificate.
+type observed struct {
+	signedBy     string
+	versionIDs   []string
+	transitional []string
+	certificate  string
+	ca           string
+}
+
+// compare checks one certificate's observed state against the model.
+func (c *certificate) compare(parent *certificate, concatenate bool, seen observed) []Mismatch {
+	var mismatches []Mismatch
+	report := func(format string, args ...interface{}) {
+		mismatches = append(mismatches, Mismatch{Certificate: c.name, Problem: fmt.Sprintf(format, args...)})
+	}
+
+	expectedSigner := c.parent
+	if expectedSigner == "" {
+		expectedSigner = c.name
+	}
+	if seen.signedBy != expectedSigner {
+		report("signed_by is %q, expected %q", seen.signedBy, expectedSigner)
+	}
+
+	if len(seen.versionIDs) != len(c.versions) {
+		report("has %d versions, expected %d", len(seen.versionIDs), len(c.versions))
+	}
+
+	var expectedTransitional []string
+	if c.transitional != "" {
+		expectedTransitional = []string{c.transitional}
+	}
+	if fmt.Sprint(seen.transitional) != fmt.Sprint(expectedTransitional) {
+		report("transitional versions are %v, expected %v", seen.transitional, expectedTransitional)
+	}
+
+	latest := c.latest()
+	if seen.certificate != latest.Certificate {
+		report("current certificate is not the one the model expects")
+	}
+	if err := verify(seen.certificate, latest.Signer); err != nil {
+		report("current certificate is not signed by the expected CA version: %s", err)
+	}
+
+	cas := split(seen.ca)
+	if !contains(cas, latest.Signer) {
+		report("ca field does not hold the CA version that signed it")
+	}
+	if parent == nil {
+		return mismatches
+	}
+
+	if !concatenate {
+		if len(cas) != 1 {
+			report("ca field holds %d certificates, expected only its signer", len(cas))
+		}
+		return mismatches
+	}
+
+	trusted := []string{latest.Signer}
+	if signing, ok := parent.signing(); ok {
+		trusted = append(trusted, signing.Certificate)
+	}
+	if transitional, ok := parent.version(parent.transitional); ok {
+		trusted = append(trusted, transitional.Certificate)
+		if !contains(cas, transitional.Certificate) {
+			report("ca field does not hold the transitional version of %s", parent.name)
+		}
+	}
+	for _, ca := range cas {
+		if !contains(trusted, ca) {
+			report("ca field holds a version of %s that is neither signing nor transitional", parent.name)
+		}
+	}
+	return mismatches
+}
+
+// split splits concatenated PEM certificates.
+func split(certificates string) []string {
+	var blocks []string
+	rest := []byte(certificates)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return blocks
+		}
+		blocks = append(blocks, string(pem.EncodeToMemory(block)))
+	}
+}
+
+// contains reports whether certificates holds certificate, comparing the
+// PEM blocks rather than the surrounding whitespace.
+func contains(certificates []string, certificate string) bool {
+	want := split(certificate)
+	if len(want) != 1 {
+		return false
+	}
+	for _, candidate := range certificates {
+		if blocks := split(candidate); len(blocks) == 1 && blocks[0] == want[0] {
+			return true
+		}
+	}
+	return false
+}
+
+func verify(certificate, signer string) error {
+	child, err := parse(certificate)
+	if err != nil {
+		return err
+	}
+	parent, err := parse(signer)
+	if err != nil {
+		return err
+	}
+	return child.CheckSignatureFrom(parent)
+}
+
+func parse(certificate string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(certificate))
+	if block == nil {
+		return nil, fmt.Errorf("not a PEM certificate")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
@@ -0,0 +1,96 @@
+package rotation_test
+
+import (
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/certificates"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/rotation"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Driver", func() {
+	for _, concatenate := range []bool{false, true} {
+		concatenate := concatenate
+
+		Context("with concatenate_cas "+map[bool]string{false: "off", true: "on"}[concatenate], func() {
+			var (
+				credhub *fakeCredHub
+				driver  *rotation.Driver
+			)
+
+			BeforeEach(func() {
+				credhub = newFakeCredHub(concatenate)
+				driver = rotation.New(certificates.HTTP{Client: credhub}, concatenate)
+				Expect(driver.RootCA("/root")).To(Succeed())
+				Expect(driver.IntermediateCA("/intermediate", "/root")).To(Succeed())
+				Expect(driver.Leaf("/leaf", "/intermediate")).To(Succeed())
+				Expect(driver.Leaf("/root-leaf", "/root")).To(Succeed())
+			})
+
+			check := func() []rotation.Mismatch {
+				mismatches, err := driver.Check()
+				Expect(err).NotTo(HaveOccurred())
+				return mismatches
+			}
+
+			It("agrees with a CredHub that follows the model through every phase", func() {
+				Expect(check()).To(BeEmpty())
+				for _, ca := range []string{"/root", "/intermediate", "/root"} {
+					original := driver.Signing(ca)
+
+					Expect(driver.Start(ca)).To(Succeed())
+					Expect(check()).To(BeEmpty())
+					Expect(driver.Signing(ca)).To(Equal(original))
+
+					Expect(driver.Switch(ca)).To(Succeed())
+					Expect(check()).To(BeEmpty())
+					Expect(driver.Signing(ca)).NotTo(Equal(original))
+
+					Expect(driver.Finish(ca)).To(Succeed())
+					Expect(check()).To(BeEmpty())
+				}
+			})
+
+			It("rolls a switch back", func() {
+				original := driver.Signing("/root")
+				Expect(driver.Start("/root")).To(Succeed())
+				Expect(driver.Switch("/root")).To(Succeed())
+				Expect(driver.RollBack("/root")).To(Succeed())
+				Expect(check()).To(BeEmpty())
+				Expect(driver.Signing("/root")).To(Equal(original))
+			})
+
+			It("keeps the model when CredHub refuses a second transitional version", func() {
+				Expect(driver.Start("/root")).To(Succeed())
+				err := driver.Start("/root")
+				Expect(err).To(BeAssignableToTypeOf(&certificates.StatusError{}))
+				Expect(err.(*certificates.StatusError).Status).To(Equal(400))
+				Expect(check()).To(BeEmpty())
+			})
+
+			It("refuses phases that need a rotation in progress", func() {
+				Expect(driver.Switch("/root")).To(MatchError(rotation.ErrNotRotating))
+				Expect(driver.RollBack("/root")).To(MatchError(rotation.ErrNotRotating))
+			})
+
+			It("deletes everything it created", func() {
+				Expect(driver.Cleanup()).To(Succeed())
+				Expect(credhub.certs).To(BeEmpty())
+			})
+
+			It("reports a CredHub that signs with the transitional version", func() {
+				credhub.signWithTransitional = true
+				Expect(driver.Start("/root")).To(Succeed())
+				Expect(driver.Leaf("/new-leaf", "/root")).To(Succeed())
+
+				Expect(check()).To(ContainElement(HaveField("Certificate", "/new-leaf")))
+			})
+
+			It("reports a bulk regenerate that does not recurse", func() {
+				credhub.bulkNotRecursive = true
+				Expect(driver.Start("/root")).To(Succeed())
+				Expect(driver.Switch("/root")).To(MatchError(ContainSubstring("expected [/intermediate /leaf /root-leaf]")))
+				Expect(check()).To(ContainElement(HaveField("Certificate", "/leaf")))
+			})
+		})
+	}
+})
@@ -0,0 +1,186 @@
+package roundtrip
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/snapshot"
+)
+
+// ValueField is one field of a structured value, compared on its own so
+// that the field lost is named.
+func ValueField(key string) snapshot.Field {
+	return snapshot.Field("value." + key)
+}
+
+// TransitionalCA is the ca field of a certificate signed by a CA that has a
+// transitional version. Compare reports it apart from other certificates'
+// ca, which must survive.
+var TransitionalCA = ValueField("ca") + " signed by a transitional CA"
+
+// Loses is what a round trip is known not to carry. Export writes only the
+// latest version of each credential, so the history before it is lost, and
+// import sets it as a new version: with a new id and creation time, never
+// transitional. Import leaves CredHub to hash a user's password afresh, and
+// fills in the ca of a certificate signed by a CA with a transitional
+// version from the CA's current version rather than the one that signed it.
+func Loses() []snapshot.Field {
+	return []snapshot.Field{
+		snapshot.VersionCount,
+		snapshot.ID,
+		snapshot.CreatedAt,
+		snapshot.Transitional,
+		ValueField("password_hash"),
+		TransitionalCA,
+	}
+}
+
+// Latest is a snapshot of only the latest version of each credential.
+func Latest(s snapshot.Snapshot) snapshot.Snapshot {
+	latest := snapshot.Snapshot{Path: s.Path, Credentials: map[string]snapshot.Credential{}, Grants: s.Grants}
+	for name, credential := range s.Credentials {
+		if n := len(credential.Versions); n > 0 {
+			credential.Versions = credential.Versions[n-1:]
+		}
+		latest.Credentials[name] = credential
+	}
+	return latest
+}
+
+// Compare compares the latest version of each credential before a round
+// trip with the latest after it, and the number of versions of each. Values
+// of structured types other than json are compared field by field, and the
+// ca of a certificate signed by a CA with a transitional version before the
+// round trip is reported as TransitionalCA.
+func Compare(before, after snapshot.Snapshot) []snapshot.Difference {
+	signedByTransitional := map[string]bool{}
+	for _, credential := range before.Credentials {
+		for _, version := range credential.Versions {
+			if version.Transitional {
+				for _, name := range credential.Signs {
+					signedByTransitional[name] = true
+				}
+			}
+		}
+	}
+
+	var differences []snapshot.Difference
+	for _, difference := range snapshot.Diff(Latest(before), Latest(after)) {
+		if difference.Field != snapshot.Value || before.Credentials[difference.Name].Type == "json" {
+			differences = append(differences, difference)
+			continue
+		}
+		for _, field := range valueDifferences(difference) {
+			if field.Field == ValueField("ca") && signedByTransitional[field.Name] {
+				field.Field = TransitionalCA
+			}
+			differences = append(differences, field)
+		}
+	}
+
+	for _, name := range before.Names() {
+		was, is := before.Credentials[name], after.Credentials[name]
+		if _, ok := after.Credentials[name]; ok && len(was.Versions) != len(is.Versions) {
+			differences = append(differences, snapshot.Difference{
+				Name: name, Field: snapshot.VersionCount, Version: -1,
+				Before: len(was.Versions), After: len(is.Versions),
+			})
+		}
+	}
+
+	sort.SliceStable(differences, func(i, j int) bool {
+		return differences[i].Name < differences[j].Name
+	})
+	return differences
+}
+
+// valueDifferences splits a difference between two structured values into
+// one difference for each field that differs.
+func valueDifferences(difference snapshot.Difference) []snapshot.Difference {
+	was, wasMap := difference.Before.(map[string]interface{})
+	is, isMap := difference.After.(map[string]interface{})
+	if !wasMap || !isMap {
+		return []snapshot.Difference{difference}
+	}
+
+	keys := map[string]bool{}
+	for key := range was {
+		keys[key] = true
+	}
+	for key := range is {
+		keys[key] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	var differences []snapshot.Difference
+	for _, key := range sorted {
+		if reflect.DeepEqual(was[key], is[key]) {
+			continue
+		}
+		field := difference
+		field.Field = ValueField(key)
+		field.Before, field.After = was[key], is[key]
+		differences = append(differences, field)
+	}
+	return differences
+}
+
+// Unexpected are the differences in fields other than those allowed.
+func Unexpected(differences []snapshot.Difference, allowed ...snapshot.Field) []snapshot.Difference {
+	skip := map[snapshot.Field]bool{}
+	for _, field := range allowed {
+		skip[field] = true
+	}
+	var unexpected []snapshot.Difference
+	for _, difference := range differences {
+		if !skip[difference.Field] {
+			unexpected = append(unexpected, difference)
+		}
+	}
+	return unexpected
+}
+
+// Lost is the names of the credentials each field was lost on.
+func Lost(differences []snapshot.Difference) map[snapshot.Field][]string {
+	lost := map[snapshot.Field][]string{}
+	seen := map[[2]string]bool{}
+	for _, difference := range differences {
+		key := [2]string{difference.Name, string(difference.Field)}
+		if !seen[key] {
+			seen[key] = true
+			lost[difference.Field] = append(lost[difference.Field], difference.Name)
+		}
+	}
+	return lost
+}
+
+// FormatLost lists each field lost, one per line in order of field, with how
+// many credentials lost it and the first few of them.
+func FormatLost(lost map[snapshot.Field][]string) string {
+	fields := make([]string, 0, len(lost))
+	for field := range lost {
+		fields = append(fields, string(field))
+	}
+	sort.Strings(fields)
+
+	lines := make([]string, len(fields))
+	for i, field := range fields {
+		names := lost[snapshot.Field(field)]
+		shown := names
+		if len(shown) > 3 {
+			shown = shown[:3]
+		}
+		more := ""
+		if len(names) > len(shown) {
+			more = fmt.Sprintf(" and %d more", len(names)-len(shown))
+		}
+		lines[i] = fmt.Sprintf("%s: %d credentials, %s%s", field, len(names), strings.Join(shown, ", "), more)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,91 @@
+package roundtrip_test
+
+import (
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/roundtrip"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/snapshot"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Compare", func() {
+	var before, after snapshot.Snapshot
+
+	BeforeEach(func() {
+		before = snapshot.Snapshot{Path: "/root", Credentials: map[string]snapshot.Credential{
+			"/root/user": {Name: "/root/user", Type: "user", Versions: []snapshot.Version{
+				{ID: "1", Value: map[string]interface{}{"username": "old", "password": "p", "password_hash": "h1"}},
+				{ID: "2", Value: map[string]interface{}{"username": "u", "password": "p", "password_hash": "h2"}, Metadata: map[string]interface{}{"team": "a"}},
+			}},
+			"/root/json": {Name: "/root/json", Type: "json", Versions: []snapshot.Version{
+				{ID: "3", Value: map[string]interface{}{"a": "b"}},
+			}},
+			"/root/ca": {Name: "/root/ca", Type: "certificate", Signs: []string{"/root/leaf"}, Versions: []snapshot.Version{
+				{ID: "4", Value: map[string]interface{}{"certificate": "c"}, Transitional: true},
+			}},
+			"/root/leaf": {Name: "/root/leaf", Type: "certificate", SignedBy: "/root/ca", Versions: []snapshot.Version{
+				{ID: "8", Value: map[string]interface{}{"certificate": "l", "ca": "signing"}},
+			}},
+		}}
+		after = snapshot.Snapshot{Path: "/root", Credentials: map[string]snapshot.Credential{
+			"/root/user": {Name: "/root/user", Type: "user", Versions: []snapshot.Version{
+				{ID: "5", Value: map[string]interface{}{"username": "u", "password": "p", "password_hash": "h3"}, Metadata: map[string]interface{}{"team": "a"}},
+			}},
+			"/root/json": {Name: "/root/json", Type: "json", Versions: []snapshot.Version{
+				{ID: "6", Value: map[string]interface{}{"a": "b"}},
+			}},
+			"/root/ca": {Name: "/root/ca", Type: "certificate", Signs: []string{"/root/leaf"}, Versions: []snapshot.Version{
+				{ID: "7", Value: map[string]interface{}{"certificate": "c"}},
+			}},
+			"/root/leaf": {Name: "/root/leaf", Type: "certificate", SignedBy: "/root/ca", Versions: []snapshot.Version{
+				{ID: "9", Value: map[string]interface{}{"certificate": "l", "ca": "current"}},
+			}},
+		}}
+	})
+
+	It("compares the latest versions, with structured values field by field, and the history", func() {
+		differences := roundtrip.Compare(before, after)
+
+		Expect(roundtrip.Unexpected(differences, roundtrip.Loses()...)).To(BeEmpty())
+		Expect(roundtrip.Lost(differences)).To(Equal(map[snapshot.Field][]string{
+			snapshot.ID:                           {"/root/ca", "/root/json", "/root/leaf", "/root/user"},
+			snapshot.Transitional:                 {"/root/ca"},
+			snapshot.VersionCount:                 {"/root/user"},
+			roundtrip.ValueField("password_hash"): {"/root/user"},
+			roundtrip.TransitionalCA:              {"/root/leaf"},
+		}))
+	})
+
+	It("expects the ca of other certificates to survive", func() {
+		before.Credentials["/root/ca"].Versions[0].Transitional = false
+
+		unexpected := roundtrip.Unexpected(roundtrip.Compare(before, after), roundtrip.Loses()...)
+		Expect(snapshot.Format(unexpected)).To(Equal(`/root/leaf versions[0].value.ca: "signing" -> "current"`))
+	})
+
+	It("reports fields that must survive as unexpected", func() {
+		after.Credentials["/root/json"].Versions[0].Value = map[string]interface{}{"a": "c"}
+		after.Credentials["/root/user"].Versions[0].Metadata = nil
+		delete(after.Credentials, "/root/ca")
+
+		unexpected := roundtrip.Unexpected(roundtrip.Compare(before, after), roundtrip.Loses()...)
+		Expect(snapshot.Format(unexpected)).To(Equal(`/root/ca presence: true -> false
+/root/json versions[0].value: {"a":"b"} -> {"a":"c"}
+/root/user versions[0].metadata: {"team":"a"} -> null`))
+	})
+
+	It("keeps only the latest version in Latest", func() {
+		latest := roundtrip.Latest(before)
+		Expect(latest.Credentials["/root/user"].Versions).To(HaveLen(1))
+		Expect(latest.Credentials["/root/user"].Versions[0].ID).To(Equal("2"))
+		Expect(before.Credentials["/root/user"].Versions).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("FormatLost", func() {
+	It("lists each field with a count and the first few names", func() {
+		Expect(roundtrip.FormatLost(map[snapshot.Field][]string{
+			snapshot.VersionCount: {"/a", "/b", "/c", "/d", "/e"},
+			snapshot.ID:           {"/a"},
+		})).To(Equal("id: 1 credentials, /a\nversion_count: 5 credentials, /a, /b, /c and 2 more"))
+	})
+})
@@ -0,0 +1,182 @@
+// Package roundtrip checks what survives exporting credentials with credhub
+// export and importing them with credhub import, as done to migrate between
+// foundations. It builds a randomised corpus of every credential type, with
+// metadata, several versions, chains of certificates and names with special
+// characters, and compares snapshots taken before the export and after the
+// import, separating what is known to be lost from what must survive.
+package roundtrip
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strings"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials/generate"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/certificates"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/conformance"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/metadata"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/snapshot"
+)
+
+// Entry is one credential of a corpus.
+type Entry struct {
+	Name string
+	Type conformance.Type
+	// Versions is how many versions are written. Only the last has Metadata.
+	Versions int
+	Metadata credentials.Metadata
+	// Generated entries are certificates CredHub generates, signed by CA, the
+	// name of an earlier entry, or self-signed when CA is empty. Other
+	// entries are set with values of their type.
+	Generated bool
+	CA        string
+	IsCA      bool
+	// Transitional gives a CA a transitional version once every entry has
+	// been written.
+	Transitional bool
+}
+
+// segments are what Corpus builds names from: characters every client must
+// accept in names, as names.Cases has them, in awkward combinations, and
+// characters that mean something in the YAML export writes, which CredHub
+// may reject.
+var segments = []string{
+	"plain", "with-dashes", "with_underscores", "with.dots", "MiXeD-Case",
+	"v1.2.3", "0123456789", "-leading-dash", "trailing_", "a.-_b",
+	"with:colon", "key: value", "#hash", "not#comment", "it's", `say-"hi"`,
+	"with space", "- dash", "&anchor", "*alias", "!tag", "{flow}", "[list]",
+	"|block", ">folded", "ünïcödé", "日本語", "🔑",
+}
+
+// plain matches names of only the characters every client must accept.
+var plain = regexp.MustCompile(`^[A-Za-z0-9/._-]*$`)
+
+// Corpus returns a chain of certificates under root, a root CA with a
+// transitional version signing an intermediate CA and a leaf, and a leaf of
+// the intermediate, followed by size entries of random types, versions,
+// metadata and names.
+func Corpus(r *rand.Rand, root string, size int) []Entry {
+	certificate := typeNamed("certificate")
+	rootCA := root + "/chain/root-ca"
+	intermediate := root + "/chain/intermediate-ca"
+	corpus := []Entry{
+		{Name: rootCA, Type: certificate, Versions: 2, Metadata: metadata.Flat(), Generated: true, IsCA: true, Transitional: true},
+		{Name: intermediate, Type: certificate, Versions: 1, Generated: true, CA: rootCA, IsCA: true},
+		{Name: root + "/chain/root-leaf", Type: certificate, Versions: 1, Metadata: metadata.Nested(2), Generated: true, CA: rootCA},
+		{Name: root + "/chain/intermediate-leaf", Type: certificate, Versions: 2, Generated: true, CA: intermediate},
+	}
+
+	for i := 0; i < size; i++ {
+		name := root
+		for depth := 1 + r.Intn(3); depth > 0; depth-- {
+			name += "/" + segments[r.Intn(len(segments))]
+		}
+		corpus = append(corpus, Entry{
+			// The index keeps names unique.
+			Name:     fmt.Sprintf("%s-%d", name, i),
+			Type:     conformance.Types[r.Intn(len(conformance.Types))],
+			Versions: 1 + r.Intn(3),
+			Metadata: randomMetadata(r),
+		})
+	}
+	return corpus
+}
+
+func randomMetadata(r *rand.Rand) credentials.Metadata {
+	switch r.Intn(4) {
+	case 0:
+		return nil
+	case 1:
+		return metadata.Flat()
+	case 2:
+		return metadata.Nested(1 + r.Intn(4))
+	}
+	return credentials.Metadata{"index": float64(r.Intn(1000)), "tags": []interface{}{"round", "trip"}}
+}
+
+func typeNamed(name string) conformance.Type {
+	for _, t := range conformance.Types {
+		if t.Name == name {
+			return t
+		}
+	}
+	panic("no credential type named " + name)
+}
+
+// Client is the part of the CredHub client Write uses.
+type Client interface {
+	snapshot.Client
+	SetCredential(name, credType string, value interface{}, options ...credhub.SetOption) (credentials.Credential, error)
+	GenerateCredential(name, credType string, gen interface{}, overwrite credhub.Mode, options ...credhub.GenerateOption) (credentials.Credential, error)
+}
+
+// Write writes a corpus in order, each entry's versions oldest first, then
+// gives CAs their transitional versions through certs. It returns the
+// entries written: an entry whose name has characters CredHub need not
+// accept is left out when CredHub rejects its first version.
+func Write(client Client, certs *certificates.Client, corpus []Entry) ([]Entry, error) {
+	var written []Entry
+	for _, entry := range corpus {
+		for version := 1; version <= entry.Versions; version++ {
+			var m credentials.Metadata
+			if version == entry.Versions {
+				m = entry.Metadata
+			}
+			err := write(client, entry, version, m)
+			var rejected *credhub.Error
+			if version == 1 && !plain.MatchString(entry.Name) && errors.As(err, &rejected) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("writing version %d of %s: %s", version, entry.Name, err)
+			}
+			if version == entry.Versions {
+				written = append(written, entry)
+			}
+		}
+	}
+
+	for _, entry := range written {
+		if !entry.Transitional {
+			continue
+		}
+		certificate, err := certs.Get(entry.Name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := certs.Regenerate(certificate.ID, true); err != nil {
+			return nil, fmt.Errorf("regenerating %s as transitional: %s", entry.Name, err)
+		}
+	}
+	return written, nil
+}
+
+func write(client Client, entry Entry, version int, m credentials.Metadata) error {
+	if entry.Generated {
+		parameters := generate.Certificate{
+			CommonName: entry.Name[strings.LastIndex(entry.Name, "/")+1:],
+			Ca:         entry.CA,
+			IsCA:       entry.IsCA,
+			SelfSign:   entry.CA == "",
+		}
+		_, err := client.GenerateCredential(entry.Name, entry.Type.Name, parameters, credhub.Overwrite, func(options *credhub.GenerateOptions) error {
+			options.Metadata = m
+			return nil
+		})
+		return err
+	}
+
+	value, err := entry.Type.Value(version)
+	if err != nil {
+		return err
+	}
+	_, err = client.SetCredential(entry.Name, entry.Type.Name, value, func(options *credhub.SetOptions) error {
+		options.Metadata = m
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,219 @@
+package roundtrip_test
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/url"
+	"strings"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials/generate"
+	"code.cloudfoundry.org/credhub-cli/credhub/permissions"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/certificates"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/conformance"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/roundtrip"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Corpus", func() {
+	corpus := func(seed int64) []roundtrip.Entry {
+		return roundtrip.Corpus(rand.New(rand.NewSource(seed)), "/root", 200)
+	}
+
+	It("is the same for the same seed", func() {
+		Expect(names(corpus(1))).To(Equal(names(corpus(1))))
+		Expect(names(corpus(1))).NotTo(Equal(names(corpus(2))))
+	})
+
+	It("covers every credential type, with and without metadata and history", func() {
+		types := map[string]bool{}
+		var withMetadata, withoutMetadata, withHistory int
+		for _, entry := range corpus(1) {
+			types[entry.Type.Name] = true
+			if entry.Metadata == nil {
+				withoutMetadata++
+			} else {
+				withMetadata++
+			}
+			if entry.Versions > 1 {
+				withHistory++
+			}
+		}
+		Expect(types).To(HaveLen(len(conformance.Types)))
+		Expect(withMetadata).NotTo(BeZero())
+		Expect(withoutMetadata).NotTo(BeZero())
+		Expect(withHistory).NotTo(BeZero())
+	})
+
+	It("gives unique names under root, some with characters YAML treats specially", func() {
+		seen := map[string]bool{}
+		special := map[string]bool{}
+		for _, name := range names(corpus(1)) {
+			Expect(name).To(MatchRegexp(`^/root(/[^/]+)+$`))
+			Expect(seen).NotTo(HaveKey(name))
+			seen[name] = true
+			for _, character := range []string{":", "#", "'", `"`, " ", "ü"} {
+				if strings.Contains(name, character) {
+					special[character] = true
+				}
+			}
+		}
+		Expect(special).To(HaveLen(6))
+	})
+
+	It("names a CA only after writing it, and gives one a transitional version", func() {
+		written := map[string]bool{}
+		var transitional int
+		for _, entry := range corpus(1) {
+			if entry.CA != "" {
+				Expect(written).To(HaveKey(entry.CA))
+			}
+			if entry.Transitional {
+				Expect(entry.IsCA).To(BeTrue())
+				transitional++
+			}
+			written[entry.Name] = true
+		}
+		Expect(transitional).To(Equal(1))
+	})
+})
+
+var _ = Describe("Write", func() {
+	It("writes each entry's versions oldest first with metadata on the last, then transitional versions", func() {
+		client := &fakeClient{}
+		transport := &fakeTransport{}
+		corpus := roundtrip.Corpus(rand.New(rand.NewSource(1)), "/root", 3)
+		corpus[4].Versions = 2
+
+		written, err := roundtrip.Write(client, certificates.New(transport), corpus)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names(written)).To(Equal(names(corpus)))
+
+		Expect(client.calls[0]).To(Equal(call{name: corpus[0].Name, generated: true}))
+		Expect(client.calls[1]).To(Equal(call{name: corpus[0].Name, generated: true, metadata: string(encode(corpus[0].Metadata))}))
+		Expect(client.parameters[2]).To(Equal(generate.Certificate{CommonName: "intermediate-ca", Ca: corpus[0].Name, IsCA: true}))
+
+		var last call
+		for _, c := range client.calls {
+			if c.name == corpus[4].Name {
+				last = c
+			}
+		}
+		Expect(last.generated).To(BeFalse())
+		Expect(last.metadata).To(Equal(string(encode(corpus[4].Metadata))))
+
+		Expect(transport.requests).To(Equal([]string{
+			"GET /api/v1/certificates name=" + url.QueryEscape(corpus[0].Name),
+			`POST /api/v1/certificates/ca-id/regenerate {"set_as_transitional":true}`,
+		}))
+	})
+
+	It("leaves out entries CredHub rejects only when their names have special characters", func() {
+		entry := func(name string) roundtrip.Entry {
+			return roundtrip.Entry{Name: name, Type: conformance.Types[0], Versions: 2}
+		}
+		client := &fakeClient{reject: map[string]bool{"/root/with:colon": true}}
+		written, err := roundtrip.Write(client, certificates.New(&fakeTransport{}), []roundtrip.Entry{entry("/root/with:colon"), entry("/root/plain")})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names(written)).To(Equal([]string{"/root/plain"}))
+		Expect(client.calls).To(HaveLen(3))
+
+		client.reject["/root/plain"] = true
+		_, err = roundtrip.Write(client, certificates.New(&fakeTransport{}), []roundtrip.Entry{entry("/root/plain")})
+		Expect(err).To(MatchError(ContainSubstring("writing version 1 of /root/plain")))
+	})
+})
+
+type call struct {
+	name      string
+	generated bool
+	metadata  string
+}
+
+type fakeClient struct {
+	calls      []call
+	parameters []interface{}
+	// reject are the names the client refuses to write.
+	reject map[string]bool
+}
+
+func (f *fakeClient) SetCredential(name, credType string, value interface{}, options ...credhub.SetOption) (credentials.Credential, error) {
+	var set credhub.SetOptions
+	for _, option := range options {
+		option(&set)
+	}
+	f.calls = append(f.calls, call{name: name, metadata: string(encode(set.Metadata))})
+	f.parameters = append(f.parameters, value)
+	if f.reject[name] {
+		return credentials.Credential{}, &credhub.Error{Name: "The credential name is invalid."}
+	}
+	return credentials.Credential{}, nil
+}
+
+func (f *fakeClient) GenerateCredential(name, credType string, gen interface{}, overwrite credhub.Mode, options ...credhub.GenerateOption) (credentials.Credential, error) {
+	var generated credhub.GenerateOptions
+	for _, option := range options {
+		option(&generated)
+	}
+	f.calls = append(f.calls, call{name: name, generated: true, metadata: string(encode(generated.Metadata))})
+	f.parameters = append(f.parameters, gen)
+	return credentials.Credential{}, nil
+}
+
+func (f *fakeClient) FindByPath(string) (credentials.FindResults, error) {
+	return credentials.FindResults{}, nil
+}
+
+func (f *fakeClient) GetAllVersions(string) ([]credentials.Credential, error) {
+	return nil, nil
+}
+
+func (f *fakeClient) GetCertificateMetadataByName(string) (credentials.CertificateMetadata, error) {
+	return credentials.CertificateMetadata{}, nil
+}
+
+func (f *fakeClient) GetPermissions(string) ([]permissions.V1_Permission, error) {
+	return nil, nil
+}
+
+func (f *fakeClient) GetPermissionByPathActor(string, string) (*permissions.Permission, error) {
+	return nil, &credhub.NotFoundError{}
+}
+
+type fakeTransport struct {
+	requests []string
+}
+
+func (f *fakeTransport) Do(method, path string, query url.Values, body interface{}) (int, []byte, error) {
+	request := method + " " + path
+	if len(query) > 0 {
+		request += " " + query.Encode()
+	}
+	if body != nil {
+		request += " " + string(encode(body))
+	}
+	f.requests = append(f.requests, request)
+	if method == "GET" {
+		return 200, []byte(`{"certificates":[{"id":"ca-id","name":"` + query.Get("name") + `"}]}`), nil
+	}
+	return 200, []byte(`{}`), nil
+}
+
+func encode(value interface{}) []byte {
+	if m, ok := value.(credentials.Metadata); ok && m == nil {
+		return nil
+	}
+	encoded, err := json.Marshal(value)
+	Expect(err).NotTo(HaveOccurred())
+	return encoded
+}
+
+func names(corpus []roundtrip.Entry) []string {
+	var names []string
+	for _, entry := range corpus {
+		names = append(names, entry.Name)
+	}
+	return names
+}
@@ -0,0 +1,143 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Field is a part of a credential's state a diff compares.
+type Field string
+
+const (
+	Presence             Field = "presence"
+	Type                 Field = "type"
+	VersionCount         Field = "version_count"
+	ID                   Field = "id"
+	CreatedAt            Field = "created_at"
+	Value                Field = "value"
+	Metadata             Field = "metadata"
+	Transitional         Field = "transitional"
+	CertificateAuthority Field = "certificate_authority"
+	SelfSigned           Field = "self_signed"
+	ExpiryDate           Field = "expiry_date"
+	SignedBy             Field = "signed_by"
+	Signs                Field = "signs"
+	Permissions          Field = "permissions"
+	// Grants is the v2 permissions on a path, which is the Name of its
+	// difference.
+	Grants Field = "grants"
+)
+
+// Difference is one field of one credential, or the grants on one path,
+// that changed. Version is the index of the version, oldest first, for
+// fields of a version and -1 otherwise.
+type Difference struct {
+	Name    string      `json:"name"`
+	Field   Field       `json:"field"`
+	Version int         `json:"version"`
+	Before  interface{} `json:"before"`
+	After   interface{} `json:"after"`
+}
+
+func (d Difference) String() string {
+	field := string(d.Field)
+	if d.Version >= 0 {
+		field = fmt.Sprintf("versions[%d].%s", d.Version, d.Field)
+	}
+	return fmt.Sprintf("%s %s: %s -> %s", d.Name, field, show(d.Before), show(d.After))
+}
+
+func show(value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	if len(encoded) > 120 {
+		return string(encoded[:117]) + "..."
+	}
+	return string(encoded)
+}
+
+// Format lists differences one per line.
+func Format(differences []Difference) string {
+	lines := make([]string, len(differences))
+	for i, difference := range differences {
+		lines[i] = difference.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Diff compares two snapshots field by field, except for the ignored fields,
+// and returns what changed in order of credential name, followed by the
+// grants that changed in order of path.
+func Diff(before, after Snapshot, ignore ...Field) []Difference {
+	ignored := map[Field]bool{}
+	for _, field := range ignore {
+		ignored[field] = true
+	}
+
+	var differences []Difference
+	add := func(name string, field Field, version int, was, is interface{}) {
+		if ignored[field] || reflect.DeepEqual(was, is) {
+			return
+		}
+		differences = append(differences, Difference{Name: name, Field: field, Version: version, Before: was, After: is})
+	}
+
+	seen := map[string]bool{}
+	var names []string
+	for _, name := range append(before.Names(), after.Names()...) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		was, wasPresent := before.Credentials[name]
+		is, isPresent := after.Credentials[name]
+		if !wasPresent || !isPresent {
+			add(name, Presence, -1, wasPresent, isPresent)
+			continue
+		}
+
+		add(name, Type, -1, was.Type, is.Type)
+		add(name, SignedBy, -1, was.SignedBy, is.SignedBy)
+		add(name, Signs, -1, was.Signs, is.Signs)
+		add(name, Permissions, -1, was.Permissions, is.Permissions)
+		add(name, VersionCount, -1, len(was.Versions), len(is.Versions))
+
+		for i := 0; i < len(was.Versions) && i < len(is.Versions); i++ {
+			wasVersion, isVersion := was.Versions[i], is.Versions[i]
+			add(name, ID, i, wasVersion.ID, isVersion.ID)
+			add(name, CreatedAt, i, wasVersion.CreatedAt, isVersion.CreatedAt)
+			add(name, Value, i, wasVersion.Value, isVersion.Value)
+			add(name, Metadata, i, wasVersion.Metadata, isVersion.Metadata)
+			add(name, Transitional, i, wasVersion.Transitional, isVersion.Transitional)
+			add(name, CertificateAuthority, i, wasVersion.CertificateAuthority, isVersion.CertificateAuthority)
+			add(name, SelfSigned, i, wasVersion.SelfSigned, isVersion.SelfSigned)
+			add(name, ExpiryDate, i, wasVersion.ExpiryDate, isVersion.ExpiryDate)
+		}
+	}
+
+	paths := map[string]bool{}
+	for path := range before.Grants {
+		paths[path] = true
+	}
+	for path := range after.Grants {
+		paths[path] = true
+	}
+	sortedPaths := make([]string, 0, len(paths))
+	for path := range paths {
+		sortedPaths = append(sortedPaths, path)
+	}
+	sort.Strings(sortedPaths)
+	for _, path := range sortedPaths {
+		add(path, Grants, -1, before.Grants[path], after.Grants[path])
+	}
+	return differences
+}
@@ -0,0 +1,222 @@
+// Package snapshot records everything CredHub holds for the credentials
+// under a path, so that state can be compared before and after an operation
+// such as a backup and restore that must not change it.
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"code.cloudfoundry.org/credhub-cli/credhub/permissions"
+)
+
+// Client is the part of the CredHub client a snapshot is taken with.
+type Client interface {
+	FindByPath(path string) (credentials.FindResults, error)
+	GetAllVersions(name string) ([]credentials.Credential, error)
+	GetCertificateMetadataByName(name string) (credentials.CertificateMetadata, error)
+	GetPermissions(name string) ([]permissions.V1_Permission, error)
+	GetPermissionByPathActor(path string, actor string) (*permissions.Permission, error)
+}
+
+// Snapshot is the state of the credentials under Path, by name, and the
+// permissions that cover them.
+type Snapshot struct {
+	Path        string                `json:"path"`
+	Credentials map[string]Credential `json:"credentials"`
+	// Grants are the v2 permissions on each path that covers a credential
+	// under Path: its name, and the wildcard paths like /path/* above it. A
+	// path nobody is granted anything on is left out.
+	Grants map[string][]Permission `json:"grants"`
+}
+
+// Credential is every version of a credential, its place in a chain of
+// certificates, and who may do what with it.
+type Credential struct {
+	Name     string    `json:"name"`
+	Type     string    `json:"type"`
+	Versions []Version `json:"versions"`
+	// SignedBy and Signs link certificates to their CA and the
+	// certificates it signs.
+	SignedBy string   `json:"signed_by,omitempty"`
+	Signs    []string `json:"signs,omitempty"`
+	// Permissions are those granted on exactly this name, sorted by actor.
+	Permissions []Permission `json:"permissions"`
+}
+
+// Version is one version of a credential, oldest first in Credential. The
+// certificate fields are only set for certificates.
+type Version struct {
+	ID                   string                 `json:"id"`
+	CreatedAt            string                 `json:"created_at"`
+	Value                interface{}            `json:"value"`
+	Metadata             map[string]interface{} `json:"metadata"`
+	Transitional         bool                   `json:"transitional,omitempty"`
+	CertificateAuthority bool                   `json:"certificate_authority,omitempty"`
+	SelfSigned           bool                   `json:"self_signed,omitempty"`
+	ExpiryDate           string                 `json:"expiry_date,omitempty"`
+}
+
+// Permission is the operations an actor has, sorted.
+type Permission struct {
+	Actor      string   `json:"actor"`
+	Operations []string `json:"operations"`
+}
+
+// Take records the credentials under path. CredHub only looks v2
+// permissions up by path and actor, so the grants recorded are those of the
+// actors given and of every actor with a v1 permission on a credential.
+func Take(client Client, path string, actors ...string) (Snapshot, error) {
+	snapshot := Snapshot{Path: path, Credentials: map[string]Credential{}, Grants: map[string][]Permission{}}
+
+	results, err := client.FindByPath(path)
+	if err != nil {
+		return snapshot, fmt.Errorf("finding credentials under %s: %s", path, err)
+	}
+
+	for _, found := range results.Credentials {
+		credential, err := take(client, found.Name)
+		if err != nil {
+			return snapshot, fmt.Errorf("recording %s: %s", found.Name, err)
+		}
+		snapshot.Credentials[found.Name] = credential
+	}
+
+	seen := map[string]bool{}
+	for _, actor := range actors {
+		seen[actor] = true
+	}
+	for _, name := range snapshot.Names() {
+		for _, permission := range snapshot.Credentials[name].Permissions {
+			if !seen[permission.Actor] {
+				seen[permission.Actor] = true
+				actors = append(actors, permission.Actor)
+			}
+		}
+	}
+	for _, covering := range coveringPaths(path, snapshot.Names()) {
+		for _, actor := range actors {
+			permission, err := client.GetPermissionByPathActor(covering, actor)
+			var notFound *credhub.NotFoundError
+			if errors.As(err, &notFound) {
+				continue
+			}
+			if err != nil {
+				return snapshot, fmt.Errorf("recording the permissions of %s on %s: %s", actor, covering, err)
+			}
+			snapshot.Grants[covering] = append(snapshot.Grants[covering], sorted(permission.Actor, permission.Operations))
+		}
+		sortByActor(snapshot.Grants[covering])
+	}
+	return snapshot, nil
+}
+
+// coveringPaths are the paths a permission can be granted on that cover a
+// credential under path: each name, and /* under path and every path above
+// it and above each name.
+func coveringPaths(path string, names []string) []string {
+	seen := map[string]bool{}
+	var paths []string
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	wildcards := func(name string) {
+		for i := len(name); i > 0; i = strings.LastIndex(name[:i], "/") {
+			add(name[:i] + "/*")
+		}
+		add("/*")
+	}
+
+	wildcards(strings.TrimSuffix(path, "/"))
+	for _, name := range names {
+		add(name)
+		wildcards(name[:strings.LastIndex(name, "/")])
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func take(client Client, name string) (Credential, error) {
+	credential := Credential{Name: name}
+
+	versions, err := client.GetAllVersions(name)
+	if err != nil {
+		return credential, err
+	}
+	for i := len(versions) - 1; i >= 0; i-- {
+		version := versions[i]
+		credential.Type = version.Type
+		credential.Versions = append(credential.Versions, Version{
+			ID:        version.Id,
+			CreatedAt: version.VersionCreatedAt,
+			Value:     version.Value,
+			Metadata:  version.Metadata,
+		})
+	}
+
+	if credential.Type == "certificate" {
+		metadata, err := client.GetCertificateMetadataByName(name)
+		if err != nil {
+			return credential, err
+		}
+		credential.SignedBy = metadata.SignedBy
+		credential.Signs = append([]string(nil), metadata.Signs...)
+		sort.Strings(credential.Signs)
+
+		byID := map[string]credentials.CertificateMetadataVersion{}
+		for _, version := range metadata.Versions {
+			byID[version.Id] = version
+		}
+		for i, version := range credential.Versions {
+			certificate, ok := byID[version.ID]
+			if !ok {
+				return credential, fmt.Errorf("certificate version %s is not listed in the certificate's metadata", version.ID)
+			}
+			credential.Versions[i].Transitional = certificate.Transitional
+			credential.Versions[i].CertificateAuthority = certificate.CertificateAuthority
+			credential.Versions[i].SelfSigned = certificate.SelfSigned
+			credential.Versions[i].ExpiryDate = certificate.ExpiryDate
+		}
+	}
+
+	granted, err := client.GetPermissions(name)
+	if err != nil {
+		return credential, err
+	}
+	credential.Permissions = []Permission{}
+	for _, permission := range granted {
+		credential.Permissions = append(credential.Permissions, sorted(permission.Actor, permission.Operations))
+	}
+	sortByActor(credential.Permissions)
+
+	return credential, nil
+}
+
+func sorted(actor string, operations []string) Permission {
+	operations = append([]string(nil), operations...)
+	sort.Strings(operations)
+	return Permission{Actor: actor, Operations: operations}
+}
+
+func sortByActor(permissions []Permission) {
+	sort.Slice(permissions, func(i, j int) bool {
+		return permissions[i].Actor < permissions[j].Actor
+	})
+}
+
+// Names are the names of the credentials in the snapshot, sorted.
+func (s Snapshot) Names() []string {
+	names := make([]string, 0, len(s.Credentials))
+	for name := range s.Credentials {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
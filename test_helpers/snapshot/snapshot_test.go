@@ -0,0 +1,219 @@
+package snapshot_test
+
+import (
+	"encoding/json"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"code.cloudfoundry.org/credhub-cli/credhub/permissions"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/snapshot"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type fakeClient struct {
+	versions     map[string][]credentials.Credential
+	certificates map[string]credentials.CertificateMetadata
+	permissions  map[string][]permissions.V1_Permission
+	grants       map[string][]permissions.Permission
+}
+
+func (f *fakeClient) FindByPath(path string) (credentials.FindResults, error) {
+	var results credentials.FindResults
+	for name := range f.versions {
+		results.Credentials = append(results.Credentials, struct {
+			Name             string `json:"name" yaml:"name"`
+			VersionCreatedAt string `json:"version_created_at" yaml:"version_created_at"`
+		}{Name: name})
+	}
+	return results, nil
+}
+
+func (f *fakeClient) GetAllVersions(name string) ([]credentials.Credential, error) {
+	return f.versions[name], nil
+}
+
+func (f *fakeClient) GetCertificateMetadataByName(name string) (credentials.CertificateMetadata, error) {
+	return f.certificates[name], nil
+}
+
+func (f *fakeClient) GetPermissions(name string) ([]permissions.V1_Permission, error) {
+	return f.permissions[name], nil
+}
+
+func (f *fakeClient) GetPermissionByPathActor(path string, actor string) (*permissions.Permission, error) {
+	for _, permission := range f.grants[path] {
+		if permission.Actor == actor {
+			return &permission, nil
+		}
+	}
+	return nil, &credhub.NotFoundError{Description: "The request could not be completed because the permission does not exist or you do not have sufficient authorization."}
+}
+
+func version(id, credType string, value interface{}, metadata credentials.Metadata) credentials.Credential {
+	credential := credentials.Credential{Value: value}
+	credential.Id = id
+	credential.Type = credType
+	credential.VersionCreatedAt = "2026-10-19T00:00:0" + id + "Z"
+	credential.Metadata = metadata
+	return credential
+}
+
+var _ = Describe("Snapshot", func() {
+	var client *fakeClient
+
+	BeforeEach(func() {
+		client = &fakeClient{
+			versions: map[string][]credentials.Credential{
+				"/ca":   {version("3", "certificate", "ca-new", nil), version("1", "certificate", "ca-old", nil)},
+				"/leaf": {version("2", "certificate", "leaf", nil)},
+				"/json": {version("4", "json", map[string]interface{}{"a": "b"}, credentials.Metadata{"owner": "team"})},
+			},
+			certificates: map[string]credentials.CertificateMetadata{
+				"/ca": {Name: "/ca", Signs: []string{"/leaf"}, Versions: []credentials.CertificateMetadataVersion{
+					{Id: "3", Transitional: true, CertificateAuthority: true, SelfSigned: true},
+					{Id: "1", CertificateAuthority: true, SelfSigned: true},
+				}},
+				"/leaf": {Name: "/leaf", SignedBy: "/ca", Versions: []credentials.CertificateMetadataVersion{{Id: "2"}}},
+			},
+			permissions: map[string][]permissions.V1_Permission{
+				"/json": {
+					{Actor: "mtls-app:b", Operations: []string{"write", "read"}},
+					{Actor: "mtls-app:a", Operations: []string{"read"}},
+				},
+			},
+			grants: map[string][]permissions.Permission{
+				"/*": {
+					{Actor: "mtls-app:reader", Operations: []string{"read"}, Path: "/*"},
+					{Actor: "mtls-app:unknown", Operations: []string{"read"}, Path: "/*"},
+				},
+				"/json": {
+					{Actor: "mtls-app:b", Operations: []string{"write", "read"}, Path: "/json"},
+					{Actor: "mtls-app:a", Operations: []string{"read"}, Path: "/json"},
+				},
+			},
+		}
+	})
+
+	take := func() snapshot.Snapshot {
+		taken, err := snapshot.Take(client, "/", "mtls-app:reader")
+		Expect(err).NotTo(HaveOccurred())
+		return taken
+	}
+
+	Describe("Take", func() {
+		It("records every version oldest first, with certificate links and flags", func() {
+			taken := take()
+			Expect(taken.Names()).To(Equal([]string{"/ca", "/json", "/leaf"}))
+
+			ca := taken.Credentials["/ca"]
+			Expect(ca.Type).To(Equal("certificate"))
+			Expect(ca.Signs).To(Equal([]string{"/leaf"}))
+			Expect(ca.Versions).To(HaveLen(2))
+			Expect(ca.Versions[0].ID).To(Equal("1"))
+			Expect(ca.Versions[0].Transitional).To(BeFalse())
+			Expect(ca.Versions[1].ID).To(Equal("3"))
+			Expect(ca.Versions[1].Transitional).To(BeTrue())
+			Expect(ca.Versions[1].CertificateAuthority).To(BeTrue())
+
+			Expect(taken.Credentials["/leaf"].SignedBy).To(Equal("/ca"))
+		})
+
+		It("records metadata and sorted permissions", func() {
+			credential := take().Credentials["/json"]
+			Expect(credential.Versions[0].Metadata).To(Equal(map[string]interface{}{"owner": "team"}))
+			Expect(credential.Permissions).To(Equal([]snapshot.Permission{
+				{Actor: "mtls-app:a", Operations: []string{"read"}},
+				{Actor: "mtls-app:b", Operations: []string{"read", "write"}},
+			}))
+		})
+
+		It("records the grants covering the credentials of the given actors and those with permissions", func() {
+			Expect(take().Grants).To(Equal(map[string][]snapshot.Permission{
+				"/*": {{Actor: "mtls-app:reader", Operations: []string{"read"}}},
+				"/json": {
+					{Actor: "mtls-app:a", Operations: []string{"read"}},
+					{Actor: "mtls-app:b", Operations: []string{"read", "write"}},
+				},
+			}))
+		})
+
+		It("records the grants on every wildcard path above a credential and no others", func() {
+			client.versions = map[string][]credentials.Credential{"/dir/sub/value": {version("5", "value", "v", nil)}}
+			client.grants = map[string][]permissions.Permission{}
+			for _, path := range []string{"/*", "/dir/*", "/dir/sub/*", "/dir/sub/value", "/dir/sub/value/*", "/dir/su*", "/other/*", "/dir/other/*"} {
+				client.grants[path] = []permissions.Permission{{Actor: "mtls-app:reader", Operations: []string{"read"}, Path: path}}
+			}
+
+			taken, err := snapshot.Take(client, "/dir", "mtls-app:reader")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(taken.Grants).To(HaveLen(4))
+			Expect(taken.Grants).To(HaveKey("/*"))
+			Expect(taken.Grants).To(HaveKey("/dir/*"))
+			Expect(taken.Grants).To(HaveKey("/dir/sub/*"))
+			Expect(taken.Grants).To(HaveKey("/dir/sub/value"))
+		})
+
+		It("fails when a certificate version has no metadata", func() {
+			client.certificates["/leaf"] = credentials.CertificateMetadata{Name: "/leaf"}
+			_, err := snapshot.Take(client, "/")
+			Expect(err).To(MatchError(ContainSubstring("recording /leaf")))
+		})
+
+		It("survives a JSON round trip unchanged", func() {
+			taken := take()
+			encoded, err := json.Marshal(taken)
+			Expect(err).NotTo(HaveOccurred())
+			var decoded snapshot.Snapshot
+			Expect(json.Unmarshal(encoded, &decoded)).To(Succeed())
+			Expect(snapshot.Diff(taken, decoded)).To(BeEmpty())
+		})
+	})
+
+	Describe("Diff", func() {
+		It("finds nothing between identical snapshots", func() {
+			Expect(snapshot.Diff(take(), take())).To(BeEmpty())
+		})
+
+		It("reports lost versions, links, flags and permissions", func() {
+			before := take()
+			client.versions["/ca"] = client.versions["/ca"][:1]
+			client.certificates["/ca"] = credentials.CertificateMetadata{Name: "/ca", Versions: []credentials.CertificateMetadataVersion{{Id: "3", CertificateAuthority: true, SelfSigned: true}}}
+			client.certificates["/leaf"] = credentials.CertificateMetadata{Name: "/leaf", Versions: []credentials.CertificateMetadataVersion{{Id: "2"}}}
+			delete(client.permissions, "/json")
+			client.grants["/*"] = client.grants["/*"][1:]
+			after := take()
+
+			differences := snapshot.Diff(before, after)
+			Expect(snapshot.Format(differences)).To(Equal(`/ca signs: ["/leaf"] -> null
+/ca version_count: 2 -> 1
+/ca versions[0].id: "1" -> "3"
+/ca versions[0].created_at: "2026-10-19T00:00:01Z" -> "2026-10-19T00:00:03Z"
+/ca versions[0].value: "ca-old" -> "ca-new"
+/json permissions: [{"actor":"mtls-app:a","operations":["read"]},{"actor":"mtls-app:b","operations":["read","write"]}] -> []
+/leaf signed_by: "/ca" -> ""
+/* grants: [{"actor":"mtls-app:reader","operations":["read"]}] -> null
+/json grants: [{"actor":"mtls-app:a","operations":["read"]},{"actor":"mtls-app:b","operations":["read","write"]}] -> null`))
+		})
+
+		It("reports missing and extra credentials", func() {
+			before := take()
+			delete(client.versions, "/json")
+			client.versions["/new"] = []credentials.Credential{version("5", "value", "v", nil)}
+
+			Expect(snapshot.Diff(before, take())).To(Equal([]snapshot.Difference{
+				{Name: "/json", Field: snapshot.Presence, Version: -1, Before: true, After: false},
+				{Name: "/new", Field: snapshot.Presence, Version: -1, Before: false, After: true},
+				{Name: "/json", Field: snapshot.Grants, Version: -1, Before: before.Grants["/json"], After: []snapshot.Permission(nil)},
+			}))
+		})
+
+		It("skips ignored fields", func() {
+			before := take()
+			client.versions["/json"] = []credentials.Credential{version("9", "json", map[string]interface{}{"a": "b"}, credentials.Metadata{"owner": "team"})}
+
+			Expect(snapshot.Diff(before, take())).To(HaveLen(2))
+			Expect(snapshot.Diff(before, take(), snapshot.ID, snapshot.CreatedAt)).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,111 @@
+package soak
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// HealthSample is one scrape of the server's health and, if configured, its
+// memory usage.
+type HealthSample struct {
+	Time        time.Time `json:"time"`
+	Status      string    `json:"status"`
+	LatencyMs   float64   `json:"latency_ms"`
+	MemoryBytes float64   `json:"memory_bytes,omitempty"`
+	Err         string    `json:"error,omitempty"`
+}
+
+// Trend is the least-squares slope of a metric over the run, per hour.
+type Trend struct {
+	First   float64 `json:"first"`
+	Last    float64 `json:"last"`
+	Max     float64 `json:"max"`
+	PerHour float64 `json:"per_hour"`
+	Samples int     `json:"samples"`
+}
+
+type healthResponse struct {
+	Status string `json:"status"`
+}
+
+// metricResponse is the body of a Spring Boot actuator metric, e.g.
+// /actuator/metrics/jvm.memory.used.
+type metricResponse struct {
+	Measurements []struct {
+		Statistic string  `json:"statistic"`
+		Value     float64 `json:"value"`
+	} `json:"measurements"`
+}
+
+func scrapeHealth(client Client, healthPath, memoryPath string) HealthSample {
+	sample := HealthSample{Time: time.Now()}
+
+	resp, err := client.Request(http.MethodGet, healthPath, nil, nil, true)
+	sample.LatencyMs = float64(time.Since(sample.Time)) / float64(time.Millisecond)
+	if err != nil {
+		sample.Err = err.Error()
+		return sample
+	}
+	var health healthResponse
+	err = json.NewDecoder(resp.Body).Decode(&health)
+	resp.Body.Close()
+	if err != nil {
+		sample.Err = err.Error()
+		return sample
+	}
+	sample.Status = health.Status
+
+	if memoryPath == "" {
+		return sample
+	}
+	resp, err = client.Request(http.MethodGet, memoryPath, nil, nil, true)
+	if err != nil {
+		sample.Err = err.Error()
+		return sample
+	}
+	var metric metricResponse
+	err = json.NewDecoder(resp.Body).Decode(&metric)
+	resp.Body.Close()
+	if err != nil {
+		sample.Err = err.Error()
+		return sample
+	}
+	for _, measurement := range metric.Measurements {
+		if measurement.Statistic == "VALUE" {
+			sample.MemoryBytes = measurement.Value
+		}
+	}
+	return sample
+}
+
+// NewTrend fits a line through the values taken at times.
+func NewTrend(times []time.Time, values []float64) Trend {
+	trend := Trend{Samples: len(values)}
+	if len(values) == 0 {
+		return trend
+	}
+	trend.First, trend.Last = values[0], values[len(values)-1]
+
+	var meanX, meanY float64
+	for i, value := range values {
+		if value > trend.Max {
+			trend.Max = value
+		}
+		meanX += times[i].Sub(times[0]).Hours()
+		meanY += value
+	}
+	meanX /= float64(len(values))
+	meanY /= float64(len(values))
+
+	var covariance, variance float64
+	for i, value := range values {
+		dx := times[i].Sub(times[0]).Hours() - meanX
+		covariance += dx * (value - meanY)
+		variance += dx * dx
+	}
+	if variance > 0 {
+		trend.PerHour = covariance / variance
+	}
+	return trend
+}
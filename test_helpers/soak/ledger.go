@@ -0,0 +1,240 @@
+package soak
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"code.cloudfoundry.org/credhub-cli/credhub/permissions"
+)
+
+// Invariants checked against the server's state.
+const (
+	VersionsLost      = "versions lost"
+	UnexpectedVersion = "unexpected version"
+	TypeDrift         = "type drift"
+	FindMismatch      = "find inconsistent with get"
+	PermissionDrift   = "permission drift"
+	CALinkLost        = "certificate lost its CA link"
+)
+
+// Violation is an invariant that did not hold for a credential or
+// permission.
+type Violation struct {
+	Invariant string `json:"invariant"`
+	Subject   string `json:"subject"`
+	Detail    string `json:"detail"`
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s: %s", v.Invariant, v.Subject, v.Detail)
+}
+
+type expectedCredential struct {
+	credType string
+	versions []string
+	ca       string
+}
+
+// Ledger is what the workload expects the server to hold: every version it
+// wrote to each credential since the credential was last deleted, the CA of
+// each signed certificate, and every permission it wrote.
+type Ledger struct {
+	credentials map[string]*expectedCredential
+	permissions map[string]permissions.Permission
+}
+
+func NewLedger() *Ledger {
+	return &Ledger{
+		credentials: map[string]*expectedCredential{},
+		permissions: map[string]permissions.Permission{},
+	}
+}
+
+// RecordVersion records a version written to a credential. ca is the name of
+// the CA that signed it, for certificates that are not self-signed.
+func (l *Ledger) RecordVersion(credential credentials.Credential, ca string) {
+	expected := l.credentials[credential.Name]
+	if expected == nil {
+		expected = &expectedCredential{credType: credential.Type}
+		l.credentials[credential.Name] = expected
+	}
+	expected.versions = append(expected.versions, credential.Id)
+	expected.ca = ca
+}
+
+func (l *Ledger) Forget(name string) {
+	delete(l.credentials, name)
+}
+
+func (l *Ledger) RecordPermission(permission permissions.Permission) {
+	l.permissions[permission.UUID] = permission
+}
+
+func (l *Ledger) ForgetPermission(uuid string) {
+	delete(l.permissions, uuid)
+}
+
+// Names returns the names of the credentials the ledger expects to exist.
+func (l *Ledger) Names() []string {
+	names := make([]string, 0, len(l.credentials))
+	for name := range l.credentials {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PermissionUUIDs returns the uuids of the permissions the ledger expects to
+// exist.
+func (l *Ledger) PermissionUUIDs() []string {
+	uuids := make([]string, 0, len(l.permissions))
+	for uuid := range l.permissions {
+		uuids = append(uuids, uuid)
+	}
+	sort.Strings(uuids)
+	return uuids
+}
+
+// Observation is the server's state, as read back through the API.
+type Observation struct {
+	// Versions of each credential, newest first, as returned by a get of all
+	// versions.
+	Versions map[string][]credentials.Credential
+	// Found are the names returned by a find on the workload's path.
+	Found []string
+	// Permissions by uuid, nil when the permission could not be read.
+	Permissions map[string]*permissions.Permission
+	// Certificates are the certificate metadata of each certificate.
+	Certificates map[string]credentials.CertificateMetadata
+}
+
+// Check compares an observation of the server with the ledger.
+func (l *Ledger) Check(observed Observation) []Violation {
+	var violations []Violation
+	add := func(invariant, subject, format string, args ...interface{}) {
+		violations = append(violations, Violation{invariant, subject, fmt.Sprintf(format, args...)})
+	}
+
+	found := map[string]bool{}
+	for _, name := range observed.Found {
+		found[name] = true
+		if l.credentials[name] == nil {
+			add(FindMismatch, name, "found, but it was deleted")
+		}
+	}
+
+	for _, name := range l.Names() {
+		expected := l.credentials[name]
+		versions := observed.Versions[name]
+
+		if !found[name] {
+			add(FindMismatch, name, "not found, but it has %d versions", len(versions))
+		}
+
+		ids := map[string]bool{}
+		for _, version := range versions {
+			ids[version.Id] = true
+			if version.Type != expected.credType {
+				add(TypeDrift, name, "version %s is a %s, expected a %s", version.Id, version.Type, expected.credType)
+			}
+		}
+
+		var lost []string
+		for _, id := range expected.versions {
+			if !ids[id] {
+				lost = append(lost, id)
+			}
+		}
+		if len(lost) > 0 {
+			add(VersionsLost, name, "%d of %d versions are missing: %s", len(lost), len(expected.versions), strings.Join(lost, ", "))
+		}
+		if len(versions) > len(expected.versions)-len(lost) {
+			add(UnexpectedVersion, name, "has %d versions, only %d were written", len(versions), len(expected.versions))
+		}
+		if len(versions) > 0 && len(expected.versions) > 0 && versions[0].Id != expected.versions[len(expected.versions)-1] {
+			add(VersionsLost, name, "latest version is %s, expected %s", versions[0].Id, expected.versions[len(expected.versions)-1])
+		}
+
+		if expected.ca != "" {
+			violations = append(violations, l.checkCALink(name, expected.ca, observed)...)
+		}
+	}
+
+	for _, uuid := range l.PermissionUUIDs() {
+		expected := l.permissions[uuid]
+		actual := observed.Permissions[uuid]
+		switch {
+		case actual == nil:
+			add(PermissionDrift, uuid, "permission for %s on %s is missing", expected.Actor, expected.Path)
+		case actual.Actor != expected.Actor || actual.Path != expected.Path || !sameOperations(actual.Operations, expected.Operations):
+			add(PermissionDrift, uuid, "expected %s on %s to have %v, got %s on %s with %v",
+				expected.Actor, expected.Path, expected.Operations, actual.Actor, actual.Path, actual.Operations)
+		}
+	}
+
+	return violations
+}
+
+// checkCALink verifies that a certificate's metadata still names its CA, that
+// the CA still lists it, and that its latest version embeds one of the CA's
+// certificates.
+func (l *Ledger) checkCALink(name, ca string, observed Observation) []Violation {
+	var violations []Violation
+	add := func(format string, args ...interface{}) {
+		violations = append(violations, Violation{CALinkLost, name, fmt.Sprintf(format, args...)})
+	}
+
+	metadata := observed.Certificates[name]
+	if metadata.SignedBy != ca {
+		add("signed by %q, expected %q", metadata.SignedBy, ca)
+	}
+	if !contains(observed.Certificates[ca].Signs, name) {
+		add("%s does not list it among the certificates it signs", ca)
+	}
+
+	versions := observed.Versions[name]
+	if len(versions) == 0 {
+		return violations
+	}
+	embedded := valueField(versions[0], "ca")
+	for _, caVersion := range observed.Versions[ca] {
+		if embedded != "" && embedded == valueField(caVersion, "certificate") {
+			return violations
+		}
+	}
+	add("latest version embeds a CA certificate that is not a version of %s", ca)
+	return violations
+}
+
+func valueField(credential credentials.Credential, field string) string {
+	value, _ := credential.Value.(map[string]interface{})
+	s, _ := value[field].(string)
+	return strings.TrimSpace(s)
+}
+
+func sameOperations(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sortedA := append([]string(nil), a...)
+	sortedB := append([]string(nil), b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
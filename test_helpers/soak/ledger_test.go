@@ -0,0 +1,140 @@
+package soak_test
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"code.cloudfoundry.org/credhub-cli/credhub/permissions"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/soak"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func version(name, id, credType string, value interface{}) credentials.Credential {
+	credential := credentials.Credential{Value: value}
+	credential.Name, credential.Id, credential.Type = name, id, credType
+	return credential
+}
+
+var _ = Describe("Ledger", func() {
+	var (
+		ledger     *soak.Ledger
+		observed   soak.Observation
+		permission permissions.Permission
+	)
+
+	BeforeEach(func() {
+		ledger = soak.NewLedger()
+		ledger.RecordVersion(version("/soak/password", "p1", "password", "a"), "")
+		ledger.RecordVersion(version("/soak/password", "p2", "password", "b"), "")
+		ledger.RecordVersion(version("/soak/ca", "c1", "certificate", map[string]interface{}{"certificate": "ca-1"}), "")
+		ledger.RecordVersion(version("/soak/leaf", "l1", "certificate", map[string]interface{}{"ca": "ca-1"}), "/soak/ca")
+		permission = permissions.Permission{UUID: "u1", Actor: "uaa-client:soak", Path: "/soak/*", Operations: []string{"read", "write"}}
+		ledger.RecordPermission(permission)
+
+		observedPermission := permission
+		observedPermission.Operations = []string{"write", "read"}
+		observed = soak.Observation{
+			Versions: map[string][]credentials.Credential{
+				"/soak/password": {version("/soak/password", "p2", "password", "b"), version("/soak/password", "p1", "password", "a")},
+				"/soak/ca":       {version("/soak/ca", "c1", "certificate", map[string]interface{}{"certificate": "ca-1\n"})},
+				"/soak/leaf":     {version("/soak/leaf", "l1", "certificate", map[string]interface{}{"ca": "ca-1"})},
+			},
+			Found:       []string{"/soak/ca", "/soak/leaf", "/soak/password"},
+			Permissions: map[string]*permissions.Permission{"u1": &observedPermission},
+			Certificates: map[string]credentials.CertificateMetadata{
+				"/soak/ca":   {Name: "/soak/ca", Signs: []string{"/soak/leaf"}},
+				"/soak/leaf": {Name: "/soak/leaf", SignedBy: "/soak/ca"},
+			},
+		}
+	})
+
+	invariants := func(violations []soak.Violation) []string {
+		var names []string
+		for _, violation := range violations {
+			names = append(names, violation.Invariant)
+		}
+		return names
+	}
+
+	It("finds nothing when the server matches", func() {
+		Expect(ledger.Check(observed)).To(BeEmpty())
+	})
+
+	It("reports lost versions", func() {
+		observed.Versions["/soak/password"] = observed.Versions["/soak/password"][:1]
+		Expect(invariants(ledger.Check(observed))).To(Equal([]string{soak.VersionsLost}))
+	})
+
+	It("reports a stale latest version", func() {
+		passwords := observed.Versions["/soak/password"]
+		passwords[0], passwords[1] = passwords[1], passwords[0]
+		Expect(invariants(ledger.Check(observed))).To(Equal([]string{soak.VersionsLost}))
+	})
+
+	It("reports versions that were never written", func() {
+		observed.Versions["/soak/password"] = append(observed.Versions["/soak/password"], version("/soak/password", "p0", "password", "z"))
+		Expect(invariants(ledger.Check(observed))).To(Equal([]string{soak.UnexpectedVersion}))
+	})
+
+	It("reports a change of type", func() {
+		observed.Versions["/soak/password"][1].Type = "value"
+		Expect(invariants(ledger.Check(observed))).To(Equal([]string{soak.TypeDrift}))
+	})
+
+	It("reports find results that disagree with gets", func() {
+		observed.Found = []string{"/soak/ca", "/soak/leaf", "/soak/deleted"}
+		Expect(ledger.Check(observed)).To(ConsistOf(
+			soak.Violation{Invariant: soak.FindMismatch, Subject: "/soak/deleted", Detail: "found, but it was deleted"},
+			soak.Violation{Invariant: soak.FindMismatch, Subject: "/soak/password", Detail: "not found, but it has 2 versions"},
+		))
+	})
+
+	It("forgets deleted credentials", func() {
+		ledger.Forget("/soak/password")
+		delete(observed.Versions, "/soak/password")
+		observed.Found = observed.Found[:2]
+		Expect(ledger.Check(observed)).To(BeEmpty())
+	})
+
+	It("reports permissions that changed or disappeared", func() {
+		observed.Permissions["u1"].Operations = []string{"read"}
+		Expect(invariants(ledger.Check(observed))).To(Equal([]string{soak.PermissionDrift}))
+
+		observed.Permissions["u1"] = nil
+		Expect(invariants(ledger.Check(observed))).To(Equal([]string{soak.PermissionDrift}))
+	})
+
+	It("reports certificates that lost their CA", func() {
+		observed.Certificates["/soak/leaf"] = credentials.CertificateMetadata{Name: "/soak/leaf"}
+		observed.Certificates["/soak/ca"] = credentials.CertificateMetadata{Name: "/soak/ca"}
+		observed.Versions["/soak/leaf"][0].Value = map[string]interface{}{"ca": "another-ca"}
+
+		violations := ledger.Check(observed)
+		Expect(invariants(violations)).To(Equal([]string{soak.CALinkLost, soak.CALinkLost, soak.CALinkLost}))
+		Expect(violations[0].Subject).To(Equal("/soak/leaf"))
+	})
+
+	It("accepts a certificate signed by an earlier version of its CA", func() {
+		ledger.RecordVersion(version("/soak/ca", "c2", "certificate", map[string]interface{}{"certificate": "ca-2"}), "")
+		observed.Versions["/soak/ca"] = append([]credentials.Credential{
+			version("/soak/ca", "c2", "certificate", map[string]interface{}{"certificate": "ca-2"}),
+		}, observed.Versions["/soak/ca"]...)
+		Expect(ledger.Check(observed)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("NewTrend", func() {
+	It("fits the hourly slope", func() {
+		start := time.Now()
+		times := []time.Time{start, start.Add(30 * time.Minute), start.Add(time.Hour), start.Add(2 * time.Hour)}
+		trend := soak.NewTrend(times, []float64{100, 110, 120, 140})
+		Expect(trend).To(Equal(soak.Trend{First: 100, Last: 140, Max: 140, PerHour: 20, Samples: 4}))
+	})
+
+	It("has no slope without samples spread over time", func() {
+		Expect(soak.NewTrend(nil, nil)).To(Equal(soak.Trend{}))
+		Expect(soak.NewTrend([]time.Time{time.Now()}, []float64{5}).PerHour).To(BeZero())
+	})
+})
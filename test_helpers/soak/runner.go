@@ -0,0 +1,142 @@
+package soak
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+const maxRecordedErrors = 100
+
+// Options configures a soak run.
+type Options struct {
+	Duration       time.Duration
+	CheckInterval  time.Duration
+	HealthInterval time.Duration
+	// HealthPath is scraped every HealthInterval when set, e.g. "/health".
+	HealthPath string
+	// MemoryPath is an actuator metric reporting memory in use, scraped with
+	// the health, e.g. "/actuator/metrics/jvm.memory.used".
+	MemoryPath string
+	// Log receives a line per check.
+	Log io.Writer
+}
+
+// Finding is a violation, with when it was first seen.
+type Finding struct {
+	Violation
+	Iteration int       `json:"iteration"`
+	Time      time.Time `json:"time"`
+}
+
+// Report summarises a soak run.
+type Report struct {
+	StartedAt       time.Time      `json:"started_at"`
+	DurationSeconds float64        `json:"duration_seconds"`
+	Iterations      int            `json:"iterations"`
+	Checks          int            `json:"checks"`
+	ErrorCount      int            `json:"error_count"`
+	Errors          []string       `json:"errors,omitempty"`
+	Findings        []Finding      `json:"findings,omitempty"`
+	Health          []HealthSample `json:"health,omitempty"`
+	HealthLatency   Trend          `json:"health_latency_ms"`
+	Memory          Trend          `json:"memory_bytes"`
+	Unhealthy       int            `json:"unhealthy_samples"`
+}
+
+// Run iterates the workload until the duration has passed, checking the
+// ledger against the server every CheckInterval and once more at the end.
+// Each violation is reported once, when first seen.
+func Run(workload *Workload, client Client, opts Options) Report {
+	log := opts.Log
+	if log == nil {
+		log = io.Discard
+	}
+
+	report := Report{StartedAt: time.Now()}
+	seen := map[Violation]bool{}
+	deadline := report.StartedAt.Add(opts.Duration)
+	nextCheck := report.StartedAt.Add(opts.CheckInterval)
+	var nextHealth time.Time
+
+	check := func(iteration int) {
+		report.Checks++
+		observed, err := workload.Observe()
+		if err != nil {
+			report.addError(fmt.Errorf("check after iteration %d: %s", iteration, err))
+			return
+		}
+		violations := workload.Ledger().Check(observed)
+		newFindings := 0
+		for _, violation := range violations {
+			if !seen[violation] {
+				seen[violation] = true
+				newFindings++
+				report.Findings = append(report.Findings, Finding{Violation: violation, Iteration: iteration, Time: time.Now()})
+			}
+		}
+		fmt.Fprintf(log, "%s iteration %d: %d violations (%d new), %d errors so far\n",
+			time.Now().Format(time.RFC3339), iteration, len(violations), newFindings, report.ErrorCount)
+	}
+
+	for report.Iterations = 0; time.Now().Before(deadline); {
+		report.Iterations++
+		if err := workload.Iterate(report.Iterations); err != nil {
+			report.addError(fmt.Errorf("iteration %d: %s", report.Iterations, err))
+		}
+
+		now := time.Now()
+		if !now.Before(nextCheck) {
+			check(report.Iterations)
+			nextCheck = now.Add(opts.CheckInterval)
+		}
+		if opts.HealthPath != "" && !now.Before(nextHealth) {
+			report.Health = append(report.Health, scrapeHealth(client, opts.HealthPath, opts.MemoryPath))
+			nextHealth = now.Add(opts.HealthInterval)
+		}
+	}
+	check(report.Iterations)
+
+	report.DurationSeconds = time.Since(report.StartedAt).Seconds()
+	report.summariseHealth()
+	return report
+}
+
+func (r *Report) addError(err error) {
+	r.ErrorCount++
+	if len(r.Errors) < maxRecordedErrors {
+		r.Errors = append(r.Errors, err.Error())
+	}
+}
+
+func (r *Report) summariseHealth() {
+	var latencyTimes, memoryTimes []time.Time
+	var latencies, memory []float64
+	for _, sample := range r.Health {
+		if sample.Err != "" || sample.Status != "UP" {
+			r.Unhealthy++
+		}
+		if sample.Err != "" {
+			continue
+		}
+		latencyTimes = append(latencyTimes, sample.Time)
+		latencies = append(latencies, sample.LatencyMs)
+		if sample.MemoryBytes > 0 {
+			memoryTimes = append(memoryTimes, sample.Time)
+			memory = append(memory, sample.MemoryBytes)
+		}
+	}
+	r.HealthLatency = NewTrend(latencyTimes, latencies)
+	r.Memory = NewTrend(memoryTimes, memory)
+}
+
+// WriteFile writes the report to path as JSON.
+func (r Report) WriteFile(path string) error {
+	encoded, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, encoded, 0644)
+}
@@ -0,0 +1,234 @@
+package soak
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"code.cloudfoundry.org/credhub-cli/credhub"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials/generate"
+	"code.cloudfoundry.org/credhub-cli/credhub/credentials/values"
+	"code.cloudfoundry.org/credhub-cli/credhub/permissions"
+)
+
+// Client is the part of *credhub.CredHub the workload uses.
+type Client interface {
+	GenerateCredential(name, credType string, gen interface{}, overwrite credhub.Mode, options ...credhub.GenerateOption) (credentials.Credential, error)
+	SetCredential(name, credType string, value interface{}, options ...credhub.SetOption) (credentials.Credential, error)
+	GetAllVersions(name string) ([]credentials.Credential, error)
+	FindByPath(path string) (credentials.FindResults, error)
+	Delete(name string) error
+	AddPermission(path string, actor string, ops []string) (*permissions.Permission, error)
+	UpdatePermission(uuid string, path string, actor string, ops []string) (*permissions.Permission, error)
+	GetPermissionByUUID(uuid string) (*permissions.Permission, error)
+	DeletePermission(uuid string) (*permissions.Permission, error)
+	GetCertificateMetadataByName(name string) (credentials.CertificateMetadata, error)
+	Request(method string, pathStr string, query url.Values, body interface{}, checkServerErr bool) (*http.Response, error)
+}
+
+const (
+	// caRotationInterval is how many iterations pass between regenerations
+	// of the CA, which are each followed by a regeneration of its leaf.
+	caRotationInterval = 10
+	// recreateInterval is how many iterations pass between deleting and
+	// recreating the value credential, so that deletes are exercised too.
+	recreateInterval = 25
+)
+
+var permissionOperations = [][]string{
+	{"read"},
+	{"read", "write"},
+	{"read", "write", "delete"},
+}
+
+// Workload writes the same mix of credentials as the backup and restore
+// suite (password, CA and signed certificate, ssh, rsa, user, json and
+// value) under a single path, plus permissions on that path, and records
+// everything it writes in a Ledger.
+type Workload struct {
+	client Client
+	root   string
+	ledger *Ledger
+
+	password, ca, certificate, ssh, rsa, user, json, value string
+	permissionUUIDs                                        []string
+}
+
+func NewWorkload(client Client, root string) *Workload {
+	name := func(suffix string) string {
+		return root + "/" + suffix
+	}
+	return &Workload{
+		client:      client,
+		root:        root,
+		ledger:      NewLedger(),
+		password:    name("password"),
+		ca:          name("ca"),
+		certificate: name("certificate"),
+		ssh:         name("ssh"),
+		rsa:         name("rsa"),
+		user:        name("user"),
+		json:        name("json"),
+		value:       name("value"),
+	}
+}
+
+func (w *Workload) Ledger() *Ledger {
+	return w.ledger
+}
+
+// Setup creates every credential and the permissions.
+func (w *Workload) Setup() error {
+	steps := []func() error{
+		w.generatePassword, w.generateCA, w.generateCertificate, w.generateSSH,
+		w.generateRSA, w.generateUser, func() error { return w.setJSON(0) }, func() error { return w.setValue(0) },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+
+	for i, operations := range permissionOperations {
+		permission, err := w.client.AddPermission(w.root+"/*", fmt.Sprintf("uaa-client:soak-%d", i), operations)
+		if err != nil {
+			return err
+		}
+		w.permissionUUIDs = append(w.permissionUUIDs, permission.UUID)
+		w.ledger.RecordPermission(*permission)
+	}
+	return nil
+}
+
+// Iterate writes a new version of every credential, and periodically
+// rotates the CA, recreates a credential and changes a permission.
+func (w *Workload) Iterate(iteration int) error {
+	steps := []func() error{
+		w.generatePassword, w.generateSSH, w.generateRSA, w.generateUser,
+		func() error { return w.setJSON(iteration) },
+		func() error { return w.setValue(iteration) },
+	}
+	if iteration%caRotationInterval == 0 {
+		steps = append(steps, w.generateCA)
+	}
+	steps = append(steps, w.generateCertificate)
+	if iteration%recreateInterval == 0 {
+		steps = append(steps, w.recreateValue)
+	}
+	steps = append(steps, func() error { return w.rotatePermission(iteration) })
+
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Observe reads back everything the ledger expects to exist.
+func (w *Workload) Observe() (Observation, error) {
+	observed := Observation{
+		Versions:     map[string][]credentials.Credential{},
+		Permissions:  map[string]*permissions.Permission{},
+		Certificates: map[string]credentials.CertificateMetadata{},
+	}
+
+	found, err := w.client.FindByPath(w.root)
+	if err != nil {
+		return observed, err
+	}
+	for _, credential := range found.Credentials {
+		observed.Found = append(observed.Found, credential.Name)
+	}
+
+	for _, name := range w.ledger.Names() {
+		// A credential that cannot be read has no versions, which the ledger
+		// reports as lost.
+		observed.Versions[name], _ = w.client.GetAllVersions(name)
+	}
+	for _, name := range []string{w.ca, w.certificate} {
+		observed.Certificates[name], _ = w.client.GetCertificateMetadataByName(name)
+	}
+	for _, uuid := range w.ledger.PermissionUUIDs() {
+		observed.Permissions[uuid], _ = w.client.GetPermissionByUUID(uuid)
+	}
+	return observed, nil
+}
+
+// Cleanup deletes everything the workload created.
+func (w *Workload) Cleanup() {
+	for _, uuid := range w.permissionUUIDs {
+		w.client.DeletePermission(uuid)
+	}
+	found, err := w.client.FindByPath(w.root)
+	if err != nil {
+		return
+	}
+	for _, credential := range found.Credentials {
+		w.client.Delete(credential.Name)
+	}
+}
+
+func (w *Workload) record(ca string) func(credentials.Credential, error) error {
+	return func(credential credentials.Credential, err error) error {
+		if err != nil {
+			return err
+		}
+		w.ledger.RecordVersion(credential, ca)
+		return nil
+	}
+}
+
+func (w *Workload) generatePassword() error {
+	return w.record("")(w.client.GenerateCredential(w.password, "password", generate.Password{}, credhub.Overwrite))
+}
+
+func (w *Workload) generateCA() error {
+	return w.record("")(w.client.GenerateCredential(w.ca, "certificate", generate.Certificate{CommonName: "soak-ca", IsCA: true, SelfSign: true}, credhub.Overwrite))
+}
+
+func (w *Workload) generateCertificate() error {
+	return w.record(w.ca)(w.client.GenerateCredential(w.certificate, "certificate", generate.Certificate{CommonName: "soak-leaf", Ca: w.ca}, credhub.Overwrite))
+}
+
+func (w *Workload) generateSSH() error {
+	return w.record("")(w.client.GenerateCredential(w.ssh, "ssh", generate.SSH{KeyLength: 2048}, credhub.Overwrite))
+}
+
+func (w *Workload) generateRSA() error {
+	return w.record("")(w.client.GenerateCredential(w.rsa, "rsa", generate.RSA{KeyLength: 2048}, credhub.Overwrite))
+}
+
+func (w *Workload) generateUser() error {
+	return w.record("")(w.client.GenerateCredential(w.user, "user", generate.User{}, credhub.Overwrite))
+}
+
+func (w *Workload) setJSON(iteration int) error {
+	return w.record("")(w.client.SetCredential(w.json, "json", values.JSON{"test": "secret", "iteration": iteration}))
+}
+
+func (w *Workload) setValue(iteration int) error {
+	return w.record("")(w.client.SetCredential(w.value, "value", values.Value(fmt.Sprintf("some-value-%d", iteration))))
+}
+
+func (w *Workload) recreateValue() error {
+	if err := w.client.Delete(w.value); err != nil {
+		return err
+	}
+	w.ledger.Forget(w.value)
+	return w.setValue(0)
+}
+
+func (w *Workload) rotatePermission(iteration int) error {
+	uuid := w.permissionUUIDs[iteration%len(w.permissionUUIDs)]
+	current := w.ledger.permissions[uuid]
+	operations := permissionOperations[(iteration/len(w.permissionUUIDs))%len(permissionOperations)]
+
+	permission, err := w.client.UpdatePermission(uuid, current.Path, current.Actor, operations)
+	if err != nil {
+		return err
+	}
+	w.ledger.RecordPermission(*permission)
+	return nil
+}
@@ -2,14 +2,20 @@ package test_helpers
 
 import (
 	"encoding/json"
-	. "github.com/onsi/ginkgo/v2"
-	. "github.com/onsi/gomega"
-	. "github.com/onsi/gomega/gexec"
+	"errors"
 	"io/ioutil"
 	"math/rand"
 	"os"
 	"os/exec"
 	"path"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/certificates"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/history"
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/reporting"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gexec"
 )
 
 var (
@@ -34,9 +40,29 @@ func RunCommand(args ...string) *Session {
 	Expect(err).NotTo(HaveOccurred())
 	<-session.Exited
 
+	reporting.RecordCommand(args, session.Out.Contents())
+	history.RecordCommand(args, session.ExitCode(), session.Out.Contents())
 	return session
 }
 
+// RunCLI runs the CLI and returns its standard output, or an error with
+// what it printed to standard error when it exits non-zero.
+func RunCLI(args ...string) ([]byte, error) {
+	session := RunCommand(args...)
+	if session.ExitCode() != 0 {
+		return nil, errors.New(strings.TrimSpace(string(session.Err.Contents())))
+	}
+	return session.Out.Contents(), nil
+}
+
+// CertificatesClient returns a client for the certificates endpoints that
+// sends its requests with `credhub curl`, as the CLI suites are logged in.
+func CertificatesClient() *certificates.Client {
+	return certificates.New(certificates.CLI{Run: func(args ...string) []byte {
+		return RunCommand(args...).Out.Contents()
+	}})
+}
+
 type BoshConfig struct {
 	Environment  string `json:"bosh_environment"`
 	Client       string `json:"bosh_client"`
@@ -44,18 +70,84 @@ type BoshConfig struct {
 	CaCertPath   string `json:"bosh_ca_cert_path"`
 }
 
+// BBRConfig configures the local stand-in for a BOSH director, which the
+// bbr_integration_test suite uses when no bosh section is set. It runs the
+// scripts, or dumps the Postgres database at DatabaseURL, and without either
+// runs the scripts the bbr-credhubdb job installs on the CredHub VM. Skip
+// skips the suite, which otherwise fails when there is nothing to run.
+type BBRConfig struct {
+	BackupScript  string `json:"backup_script"`
+	RestoreScript string `json:"restore_script"`
+	DatabaseURL   string `json:"database_url"`
+	Skip          bool   `json:"skip"`
+}
+
+// PerfConfig configures the perf suite. The tolerances are pointers so that
+// a tolerance of zero can be told apart from one that is not set.
+type PerfConfig struct {
+	Concurrency         int            `json:"concurrency"`
+	Duration            string         `json:"duration"`
+	Mix                 map[string]int `json:"mix"`
+	ReportPath          string         `json:"report_path"`
+	BaselinePath        string         `json:"baseline_path"`
+	LatencyTolerance    *float64       `json:"latency_tolerance"`
+	ThroughputTolerance *float64       `json:"throughput_tolerance"`
+	ErrorRateTolerance  *float64       `json:"error_rate_tolerance"`
+}
+
+type SoakConfig struct {
+	Duration         string `json:"duration"`
+	CheckInterval    string `json:"check_interval"`
+	HealthInterval   string `json:"health_interval"`
+	HealthPath       string `json:"health_path"`
+	MemoryMetricPath string `json:"memory_metric_path"`
+	ReportPath       string `json:"report_path"`
+}
+
+// RemoteBackendConfig configures the in-repo stand-in for CredHub's remote
+// backend, which the remote_backend suite starts when SocketPath is set.
+type RemoteBackendConfig struct {
+	SocketPath string `json:"socket_path"`
+	CertPath   string `json:"cert_path"`
+	KeyPath    string `json:"key_path"`
+}
+
+// ParityConfig configures the parity suite, which compares the CredHub at
+// api_url, using its built-in database, with one using a remote backend.
+type ParityConfig struct {
+	RemoteApiUrl     string `json:"remote_api_url"`
+	ReportPath       string `json:"report_path"`
+	FailOnDifference bool   `json:"fail_on_difference"`
+}
+
+// UAAAdminConfig holds a UAA client allowed to create and delete UAA clients
+// and users, which test_helpers/actors mints identities with. Without it
+// only certificate identities can be minted.
+type UAAAdminConfig struct {
+	Client string `json:"client"`
+	Secret string `json:"secret"`
+}
+
 type Config struct {
-	Bosh           *BoshConfig `json:"bosh"`
-	ApiUrl         string      `json:"api_url"`
-	ApiUsername    string      `json:"api_username"`
-	ApiPassword    string      `json:"api_password"`
-	CredentialRoot string      `json:"credential_root"`
-	UAACa          string      `json:"uaa_ca"`
-	DirectorHost   string      `json:"director_host"`
-	ClientName     string      `json:"client_name"`
-	ClientSecret   string      `json:"client_secret"`
-	DeploymentName string      `json:"deployment_name"`
-	ConcatenateCas bool        `json:"concatenate_cas"`
+	Bosh           *BoshConfig          `json:"bosh"`
+	BBR            *BBRConfig           `json:"bbr"`
+	Perf           *PerfConfig          `json:"perf"`
+	Soak           *SoakConfig          `json:"soak"`
+	RemoteBackend  *RemoteBackendConfig `json:"remote_backend"`
+	Parity         *ParityConfig        `json:"parity"`
+	UAAAdmin       *UAAAdminConfig      `json:"uaa_admin"`
+	ApiUrl         string               `json:"api_url"`
+	ApiUsername    string               `json:"api_username"`
+	ApiPassword    string               `json:"api_password"`
+	CredentialRoot string               `json:"credential_root"`
+	UAACa          string               `json:"uaa_ca"`
+	DirectorHost   string               `json:"director_host"`
+	ClientName     string               `json:"client_name"`
+	ClientSecret   string               `json:"client_secret"`
+	DeploymentName string               `json:"deployment_name"`
+	ConcatenateCas bool                 `json:"concatenate_cas"`
+	ReportDir      string               `json:"report_dir"`
+	ContractSpec   string               `json:"contract_spec"`
 }
 
 func LoadConfig() (Config, error) {
@@ -0,0 +1,316 @@
+package vcap
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// Case is a VCAP_SERVICES document together with the credentials that must
+// exist before it is interpolated.
+type Case struct {
+	Name        string
+	Document    map[string]interface{}
+	Credentials []Credential
+}
+
+// Store creates a store of the case's credentials.
+func (c Case) Store() Store {
+	return NewStore(c.Credentials)
+}
+
+// Readable is the path under a case's root that holds the credentials the
+// interpolating actor may read. Unreadable credentials are created outside it.
+const Readable = "readable"
+
+// Builder builds cases with credentials under one root path.
+type Builder struct {
+	root  string
+	count int
+}
+
+// NewBuilder creates a builder for credentials under root.
+func NewBuilder(root string) *Builder {
+	return &Builder{root: strings.TrimSuffix(root, "/")}
+}
+
+func (b *Builder) name(readable bool, kind string) string {
+	b.count++
+	if readable {
+		return fmt.Sprintf("%s/%s/%s-%d", b.root, Readable, kind, b.count)
+	}
+	return fmt.Sprintf("%s/unreadable/%s-%d", b.root, kind, b.count)
+}
+
+// JSON is a readable json credential with a nested value.
+func (b *Builder) JSON(depth int) Credential {
+	name := b.name(true, "json")
+	return Credential{Name: name, Type: "json", Value: nested(name, depth)}
+}
+
+// Unreadable is a json credential the interpolating actor cannot read.
+func (b *Builder) Unreadable() Credential {
+	name := b.name(false, "json")
+	return Credential{Name: name, Type: "json", Value: nested(name, 1), Unreadable: true}
+}
+
+// Missing is a credential that is never created.
+func (b *Builder) Missing() Credential {
+	return Credential{Name: b.name(true, "missing")}
+}
+
+// NonJSON is a readable credential of a type other than json.
+func (b *Builder) NonJSON(credentialType string) Credential {
+	name := b.name(true, credentialType)
+	var value interface{}
+	switch credentialType {
+	case "value", "password":
+		value = "secret-" + name
+	case "user":
+		value = map[string]interface{}{"username": "user", "password": "secret-" + name}
+	default:
+		panic("vcap: no value for credential type " + credentialType)
+	}
+	return Credential{Name: name, Type: credentialType, Value: value}
+}
+
+// Binding is a service binding whose credentials refer to the credential.
+func Binding(name string, credential Credential) map[string]interface{} {
+	return map[string]interface{}{
+		"name":        name,
+		"label":       "service",
+		"tags":        []interface{}{"credhub"},
+		"credentials": map[string]interface{}{"credhub-ref": Ref(credential.Name)},
+	}
+}
+
+// Plain is a service binding with literal credentials.
+func Plain(name string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":        name,
+		"label":       "service",
+		"credentials": map[string]interface{}{"uri": "https://" + name + ".example.com"},
+	}
+}
+
+// Ref is the credhub-ref string for a credential name.
+func Ref(name string) string {
+	return "((" + name + "))"
+}
+
+func nested(name string, depth int) interface{} {
+	value := map[string]interface{}{
+		"name":    name,
+		"port":    float64(5432),
+		"enabled": true,
+		"hosts":   []interface{}{"a.example.com", "b.example.com"},
+		"none":    nil,
+	}
+	if depth > 0 {
+		value["nested"] = nested(name, depth-1)
+	}
+	return value
+}
+
+// Scenarios are the document shapes every interpolation spec covers.
+func Scenarios(root string) []Case {
+	b := NewBuilder(root)
+	var cases []Case
+
+	{
+		first, second, third := b.JSON(0), b.JSON(0), b.JSON(0)
+		cases = append(cases, Case{
+			Name: "multiple services",
+			Document: map[string]interface{}{
+				"p-mysql":  []interface{}{Binding("mysql", first)},
+				"p-rabbit": []interface{}{Binding("rabbit", second)},
+				"p-redis":  []interface{}{Binding("redis", third)},
+			},
+			Credentials: []Credential{first, second, third},
+		})
+	}
+
+	{
+		first, second := b.JSON(0), b.JSON(0)
+		cases = append(cases, Case{
+			Name: "multiple bindings of one service",
+			Document: map[string]interface{}{
+				"p-mysql": []interface{}{
+					Binding("mysql-1", first),
+					Plain("mysql-2"),
+					Binding("mysql-3", second),
+					Binding("mysql-4", first),
+				},
+			},
+			Credentials: []Credential{first, second},
+		})
+	}
+
+	{
+		deep := b.JSON(8)
+		cases = append(cases, Case{
+			Name:        "nested credential values",
+			Document:    map[string]interface{}{"p-config": []interface{}{Binding("config", deep)}},
+			Credentials: []Credential{deep},
+		})
+	}
+
+	{
+		credential := b.JSON(0)
+		relative := credential
+		relative.Name = strings.TrimPrefix(credential.Name, "/")
+		upper := credential
+		upper.Name = strings.ToUpper(credential.Name)
+		cases = append(cases, Case{
+			Name: "references by relative and differently cased names",
+			Document: map[string]interface{}{
+				"p-mysql": []interface{}{Binding("relative", relative), Binding("upper", upper)},
+			},
+			Credentials: []Credential{credential},
+		})
+	}
+
+	{
+		credential, decoy := b.JSON(0), b.Missing()
+		cases = append(cases, Case{
+			Name: "references outside binding credentials",
+			Document: map[string]interface{}{
+				"credhub-ref": Ref(decoy.Name),
+				"p-mysql": []interface{}{
+					Binding("mysql", credential),
+					map[string]interface{}{
+						"name":        "nested-ref",
+						"credentials": map[string]interface{}{"inner": map[string]interface{}{"credhub-ref": Ref(decoy.Name)}},
+					},
+					map[string]interface{}{"name": "top-level-ref", "credhub-ref": Ref(decoy.Name)},
+					map[string]interface{}{"name": "number-ref", "credentials": map[string]interface{}{"credhub-ref": float64(1)}},
+					map[string]interface{}{"name": "array-credentials", "credentials": []interface{}{Ref(decoy.Name)}},
+					Ref(decoy.Name),
+				},
+				"p-object": map[string]interface{}{"credentials": map[string]interface{}{"credhub-ref": Ref(decoy.Name)}},
+			},
+			Credentials: []Credential{credential},
+		})
+	}
+
+	{
+		document := map[string]interface{}{}
+		credentials := []Credential{}
+		for i := 0; i < 8; i++ {
+			credential := b.JSON(2)
+			credentials = append(credentials, credential)
+		}
+		padding := strings.Repeat("x", 4096)
+		for i := 0; i < 40; i++ {
+			bindings := []interface{}{}
+			for j := 0; j < 8; j++ {
+				binding := Binding(fmt.Sprintf("service-%d-%d", i, j), credentials[(i+j)%len(credentials)])
+				binding["padding"] = padding
+				bindings = append(bindings, binding)
+			}
+			document[fmt.Sprintf("service-%d", i)] = bindings
+		}
+		cases = append(cases, Case{Name: "very large payload", Document: document, Credentials: credentials})
+	}
+
+	for _, credentialType := range []string{"value", "password", "user"} {
+		credential, other := b.NonJSON(credentialType), b.JSON(0)
+		cases = append(cases, Case{
+			Name: "a reference to a " + credentialType + " credential",
+			Document: map[string]interface{}{
+				"p-mysql": []interface{}{Binding("mysql", other), Binding("broken", credential)},
+			},
+			Credentials: []Credential{credential, other},
+		})
+	}
+
+	{
+		missing, other := b.Missing(), b.JSON(0)
+		cases = append(cases, Case{
+			Name: "a missing reference",
+			Document: map[string]interface{}{
+				"p-mysql": []interface{}{Binding("mysql", other), Binding("missing", missing)},
+			},
+			Credentials: []Credential{other},
+		})
+	}
+
+	{
+		unreadable, other := b.Unreadable(), b.JSON(0)
+		cases = append(cases, Case{
+			Name: "a reference without read permission",
+			Document: map[string]interface{}{
+				"p-mysql": []interface{}{Binding("mysql", other), Binding("unreadable", unreadable)},
+			},
+			Credentials: []Credential{unreadable, other},
+		})
+	}
+
+	return cases
+}
+
+// Random builds a document of up to the given number of services and
+// bindings per service. Roughly half the bindings refer to credentials, and
+// when broken is set one of them refers to a credential that cannot be
+// interpolated.
+func Random(r *rand.Rand, root string, services, bindings int, broken bool) Case {
+	b := NewBuilder(root)
+	document := map[string]interface{}{}
+	var credentials []Credential
+	var refs []map[string]interface{}
+
+	for i := 0; i < 1+r.Intn(services); i++ {
+		list := []interface{}{}
+		for j := 0; j < 1+r.Intn(bindings); j++ {
+			name := fmt.Sprintf("binding-%d-%d", i, j)
+			if r.Intn(2) == 0 {
+				list = append(list, Plain(name))
+				continue
+			}
+			var credential Credential
+			if len(credentials) > 0 && r.Intn(4) == 0 {
+				credential = credentials[r.Intn(len(credentials))]
+			} else {
+				credential = b.JSON(r.Intn(4))
+				credentials = append(credentials, credential)
+			}
+			binding := Binding(name, credential)
+			refs = append(refs, binding)
+			list = append(list, binding)
+		}
+		document[fmt.Sprintf("service-%d", i)] = list
+	}
+
+	if broken {
+		var credential Credential
+		switch r.Intn(3) {
+		case 0:
+			credential = b.Missing()
+		case 1:
+			credential = b.Unreadable()
+			credentials = append(credentials, credential)
+		default:
+			credential = b.NonJSON([]string{"value", "password", "user"}[r.Intn(3)])
+			credentials = append(credentials, credential)
+		}
+		labels := sortedKeys(document)
+		label := labels[r.Intn(len(labels))]
+		document[label] = append(document[label].([]interface{}), Binding("broken", credential))
+	}
+
+	return Case{
+		Name:        fmt.Sprintf("random document of %d services with %d references", len(document), len(refs)),
+		Document:    document,
+		Credentials: credentials,
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,136 @@
+// Package vcap generates VCAP_SERVICES documents with credhub-ref
+// references, and interpolates them the way CredHub's /api/v1/interpolate
+// should, so specs can compare the two.
+package vcap
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Credential is a credential a document refers to.
+type Credential struct {
+	Name  string
+	Type  string
+	Value interface{}
+	// Unreadable credentials are ones the interpolating actor has no read
+	// permission on.
+	Unreadable bool
+}
+
+// Store holds credentials by name, the way CredHub looks them up: with a
+// leading slash and case-insensitively.
+type Store map[string]Credential
+
+// NewStore creates a store of the credentials.
+func NewStore(credentials []Credential) Store {
+	store := Store{}
+	for _, credential := range credentials {
+		store[normalize(credential.Name)] = credential
+	}
+	return store
+}
+
+func normalize(name string) string {
+	if !strings.HasPrefix(name, "/") {
+		name = "/" + name
+	}
+	return strings.ToLower(name)
+}
+
+// ErrorKind is why an interpolation failed.
+type ErrorKind int
+
+const (
+	// NotFound is a reference to a credential that does not exist or the
+	// actor cannot read. CredHub does not say which.
+	NotFound ErrorKind = iota
+	// NotJSON is a reference to a credential that is not of type json.
+	NotJSON
+)
+
+// Error is the reference that failed an interpolation. CredHub stops at the
+// first one, so the whole request fails.
+type Error struct {
+	Kind ErrorKind
+	Name string
+}
+
+func (e *Error) Error() string {
+	if e.Kind == NotJSON {
+		return fmt.Sprintf("%s is not a json credential", e.Name)
+	}
+	return fmt.Sprintf("%s does not exist or cannot be read", e.Name)
+}
+
+// Status is the status CredHub responds with.
+func (e *Error) Status() int {
+	if e.Kind == NotJSON {
+		return http.StatusBadRequest
+	}
+	return http.StatusNotFound
+}
+
+// Interpolate replaces the credentials of every binding that has a
+// credhub-ref with the value of the credential it refers to. Only
+// "credhub-ref" strings directly inside a binding's "credentials" object are
+// references; a binding is an object in an array under a top-level key, and
+// anything else is left as it is. The document itself is not changed.
+func Interpolate(document interface{}, store Store) (interface{}, error) {
+	document = clone(document)
+	services, ok := document.(map[string]interface{})
+	if !ok {
+		return document, nil
+	}
+	for _, label := range sortedKeys(services) {
+		bindings, ok := services[label].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, binding := range bindings {
+			properties, ok := binding.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			credentials, ok := properties["credentials"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ref, ok := credentials["credhub-ref"].(string)
+			if !ok {
+				continue
+			}
+
+			name := strings.TrimSuffix(strings.TrimPrefix(ref, "(("), "))")
+			credential, found := store[normalize(name)]
+			switch {
+			case !found || credential.Unreadable:
+				return nil, &Error{Kind: NotFound, Name: name}
+			case credential.Type != "json":
+				return nil, &Error{Kind: NotJSON, Name: name}
+			}
+			properties["credentials"] = clone(credential.Value)
+		}
+	}
+	return document, nil
+}
+
+func clone(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			copied[key] = clone(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(value))
+		for i, item := range value {
+			copied[i] = clone(item)
+		}
+		return copied
+	default:
+		return value
+	}
+}
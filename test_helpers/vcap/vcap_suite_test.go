@@ -0,0 +1,13 @@
+package vcap_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestVCAP(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "VCAP Suite")
+}
@@ -0,0 +1,150 @@
+package vcap_test
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/credhub-acceptance-tests/test_helpers/vcap"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Interpolate", func() {
+	var store vcap.Store
+
+	BeforeEach(func() {
+		store = vcap.NewStore([]vcap.Credential{
+			{Name: "/db", Type: "json", Value: map[string]interface{}{"password": "secret"}},
+			{Name: "/plain", Type: "value", Value: "secret"},
+			{Name: "/hidden", Type: "json", Value: map[string]interface{}{}, Unreadable: true},
+		})
+	})
+
+	document := func(ref interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"p-mysql": []interface{}{
+				map[string]interface{}{"name": "mysql", "credentials": map[string]interface{}{"credhub-ref": ref, "other": "dropped"}},
+				map[string]interface{}{"name": "plain", "credentials": map[string]interface{}{"uri": "kept"}},
+			},
+		}
+	}
+
+	It("replaces the credentials of a binding with the referenced value", func() {
+		input := document("((/db))")
+		result, err := vcap.Interpolate(input, store)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(map[string]interface{}{
+			"p-mysql": []interface{}{
+				map[string]interface{}{"name": "mysql", "credentials": map[string]interface{}{"password": "secret"}},
+				map[string]interface{}{"name": "plain", "credentials": map[string]interface{}{"uri": "kept"}},
+			},
+		}))
+		Expect(input).To(Equal(document("((/db))")))
+	})
+
+	It("looks up relative and differently cased names", func() {
+		for _, ref := range []string{"((db))", "((/DB))", "/db"} {
+			_, err := vcap.Interpolate(document(ref), store)
+			Expect(err).NotTo(HaveOccurred(), ref)
+		}
+	})
+
+	It("ignores references that are not strings", func() {
+		result, err := vcap.Interpolate(document(float64(1)), store)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(document(float64(1))))
+	})
+
+	It("ignores references outside binding credentials", func() {
+		input := map[string]interface{}{
+			"credhub-ref": "((/missing))",
+			"p-object":    map[string]interface{}{"credentials": map[string]interface{}{"credhub-ref": "((/missing))"}},
+			"p-mysql": []interface{}{
+				"((/missing))",
+				map[string]interface{}{"credhub-ref": "((/missing))"},
+				map[string]interface{}{"credentials": map[string]interface{}{"nested": map[string]interface{}{"credhub-ref": "((/missing))"}}},
+			},
+		}
+		result, err := vcap.Interpolate(input, store)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(input))
+	})
+
+	DescribeTable("failing references",
+		func(ref string, kind vcap.ErrorKind, status int) {
+			_, err := vcap.Interpolate(document(ref), store)
+			var refErr *vcap.Error
+			Expect(err).To(BeAssignableToTypeOf(refErr))
+			refErr = err.(*vcap.Error)
+			Expect(refErr.Kind).To(Equal(kind))
+			Expect(refErr.Status()).To(Equal(status))
+			Expect(refErr.Name).To(Equal(strings.Trim(ref, "()")))
+		},
+		Entry("missing", "((/missing))", vcap.NotFound, http.StatusNotFound),
+		Entry("unreadable", "((/hidden))", vcap.NotFound, http.StatusNotFound),
+		Entry("not json", "((/plain))", vcap.NotJSON, http.StatusBadRequest),
+	)
+})
+
+var _ = Describe("Scenarios", func() {
+	It("names every credential under the root", func() {
+		for _, c := range vcap.Scenarios("/vcap/root") {
+			for _, credential := range c.Credentials {
+				Expect(credential.Name).To(HavePrefix("/vcap/root/"), c.Name)
+				if credential.Unreadable {
+					Expect(credential.Name).NotTo(HavePrefix("/vcap/root/"+vcap.Readable+"/"), c.Name)
+				} else {
+					Expect(credential.Name).To(HavePrefix("/vcap/root/"+vcap.Readable+"/"), c.Name)
+				}
+			}
+		}
+	})
+
+	It("covers successful and failing interpolations", func() {
+		outcomes := map[string]error{}
+		for _, c := range vcap.Scenarios("/vcap/root") {
+			_, err := vcap.Interpolate(c.Document, c.Store())
+			outcomes[c.Name] = err
+		}
+		Expect(outcomes).To(HaveKeyWithValue("multiple services", BeNil()))
+		Expect(outcomes).To(HaveKeyWithValue("references outside binding credentials", BeNil()))
+		Expect(outcomes).To(HaveKeyWithValue("references by relative and differently cased names", BeNil()))
+		Expect(outcomes).To(HaveKeyWithValue("a reference to a value credential", MatchError(ContainSubstring("not a json credential"))))
+		Expect(outcomes).To(HaveKeyWithValue("a missing reference", MatchError(ContainSubstring("does not exist"))))
+		Expect(outcomes).To(HaveKeyWithValue("a reference without read permission", MatchError(ContainSubstring("cannot be read"))))
+	})
+
+	It("builds a large payload", func() {
+		for _, c := range vcap.Scenarios("/vcap/root") {
+			if c.Name == "very large payload" {
+				body, err := json.Marshal(c.Document)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(body)).To(BeNumerically(">", 1<<20))
+				return
+			}
+		}
+		Fail("no large payload scenario")
+	})
+})
+
+var _ = Describe("Random", func() {
+	It("is reproducible from the seed", func() {
+		first := vcap.Random(rand.New(rand.NewSource(7)), "/root", 5, 5, true)
+		second := vcap.Random(rand.New(rand.NewSource(7)), "/root", 5, 5, true)
+		Expect(first).To(Equal(second))
+	})
+
+	It("fails only when broken", func() {
+		for seed := int64(0); seed < 50; seed++ {
+			valid := vcap.Random(rand.New(rand.NewSource(seed)), "/root", 4, 4, false)
+			_, err := vcap.Interpolate(valid.Document, valid.Store())
+			Expect(err).NotTo(HaveOccurred())
+
+			broken := vcap.Random(rand.New(rand.NewSource(seed)), "/root", 4, 4, true)
+			_, err = vcap.Interpolate(broken.Document, broken.Store())
+			Expect(err).To(HaveOccurred())
+		}
+	})
+})